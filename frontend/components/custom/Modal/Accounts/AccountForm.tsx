@@ -95,6 +95,8 @@ export function AccountForm({
               <SelectItem value="icici_credit">
                 ICICI Bank (Credit Card)
               </SelectItem>
+              <SelectItem value="kotak">Kotak Mahindra Bank</SelectItem>
+              <SelectItem value="idfc">IDFC First Bank</SelectItem>
               <SelectItem value="investment">Investment Account</SelectItem>
               <SelectItem value="others">Others</SelectItem>
             </SelectContent>
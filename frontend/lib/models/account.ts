@@ -6,6 +6,8 @@ export type BankType =
   | "hdfc"
   | "icici"
   | "icici_credit"
+  | "kotak"
+  | "idfc"
   | "others";
 export type Currency = "inr" | "usd";
 
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"expenses/internal/config"
+	"expenses/internal/models"
+	"expenses/internal/service"
+	"expenses/pkg/logger"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type AppImportController struct {
+	*BaseController
+	appImportService service.AppImportServiceInterface
+}
+
+func NewAppImportController(cfg *config.Config, appImportService service.AppImportServiceInterface) *AppImportController {
+	return &AppImportController{
+		BaseController:   NewBaseController(cfg),
+		appImportService: appImportService,
+	}
+}
+
+// CreateAppImport queues the export of another personal finance app to be
+// imported.
+func (c *AppImportController) CreateAppImport(ctx *gin.Context) {
+	userId := c.GetAuthenticatedUserId(ctx)
+	logger.Infof("Creating app import for user %d", userId)
+
+	var form models.AppImportForm
+	if err := ctx.ShouldBindWith(&form, binding.FormMultipart); err != nil {
+		c.SendError(ctx, http.StatusBadRequest, fmt.Sprintf("Failed to parse form data: %v", err))
+		return
+	}
+
+	file, err := form.File.Open()
+	if err != nil {
+		c.SendError(ctx, http.StatusBadRequest, fmt.Sprintf("failed to open file: %v", err))
+		return
+	}
+	defer file.Close()
+	fileBytes, err := io.ReadAll(file)
+	if err != nil {
+		c.SendError(ctx, http.StatusBadRequest, fmt.Sprintf("failed to read file: %v", err))
+		return
+	}
+
+	appImport, err := c.appImportService.ImportApp(ctx, models.AppImportInput{
+		Source:           form.Source,
+		Currency:         form.Currency,
+		OriginalFilename: form.File.Filename,
+		FileBytes:        fileBytes,
+	}, userId)
+	if err != nil {
+		logger.Errorf("Error creating app import: %v", err)
+		c.HandleError(ctx, err)
+		return
+	}
+
+	logger.Infof("App import created successfully with ID %d for user %d", appImport.Id, userId)
+	c.SendSuccess(ctx, http.StatusCreated, "Import uploaded successfully and processing has begun", appImport)
+}
+
+func (c *AppImportController) GetAppImport(ctx *gin.Context) {
+	userId := c.GetAuthenticatedUserId(ctx)
+	importId, err := strconv.ParseInt(ctx.Param("importId"), 10, 64)
+	if err != nil {
+		c.SendError(ctx, http.StatusBadRequest, "invalid import id")
+		return
+	}
+
+	logger.Infof("Fetching app import %d for user %d", importId, userId)
+	appImport, err := c.appImportService.GetAppImport(ctx, importId, userId)
+	if err != nil {
+		logger.Errorf("Error fetching app import: %v", err)
+		c.HandleError(ctx, err)
+		return
+	}
+	c.SendSuccess(ctx, http.StatusOK, "Import fetched successfully", appImport)
+}
+
+func (c *AppImportController) ListAppImports(ctx *gin.Context) {
+	userId := c.GetAuthenticatedUserId(ctx)
+	logger.Infof("Fetching app imports for user %d", userId)
+	appImports, err := c.appImportService.ListAppImports(ctx, userId)
+	if err != nil {
+		logger.Errorf("Error fetching app imports: %v", err)
+		c.HandleError(ctx, err)
+		return
+	}
+	logger.Infof("Successfully fetched %d app imports for user %d", len(appImports), userId)
+	c.SendSuccess(ctx, http.StatusOK, "Imports fetched successfully", appImports)
+}
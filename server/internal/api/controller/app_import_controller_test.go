@@ -0,0 +1,69 @@
+package controller_test
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppImportController", func() {
+	waitForImportDone := func(testHelper *TestHelper, importId float64) map[string]any {
+		var data map[string]any
+		Eventually(func() string {
+			resp, response := testHelper.MakeRequest(http.MethodGet, "/import/"+strconv.FormatFloat(importId, 'f', 0, 64), nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			data = response["data"].(map[string]any)
+			return data["status"].(string)
+		}, 30*time.Second, 500*time.Millisecond).Should(Equal("done"))
+		return data
+	}
+
+	Describe("CreateAppImport", func() {
+		It("should import accounts, categories and transactions of a Splitwise export", func() {
+			testHelper := createUniqueUser(baseURL)
+			export := "Date,Description,Category,Cost,Currency,Jane Roe,John Doe\n" +
+				"2024-03-02,Dinner,Dining out,2400.00,INR,1200.00,-1200.00\n" +
+				"2024-03-05,Cab,Taxi,600.00,INR,-300.00,300.00\n"
+			resp, response := testHelper.MakeMultipartRequest(http.MethodPost, "/import", map[string]any{
+				"source":            "splitwise",
+				"original_filename": "splitwise.csv",
+				"file":              []byte(export),
+			})
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			importId := response["data"].(map[string]any)["id"].(float64)
+
+			data := waitForImportDone(testHelper, importId)
+			Expect(data["accounts_created"]).To(Equal(1.0))
+			Expect(data["categories_created"]).To(Equal(2.0))
+			Expect(data["inserted_count"]).To(Equal(2.0))
+
+			resp, response = testHelper.MakeRequest(http.MethodGet, "/import", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(response["data"].([]any)).To(HaveLen(1))
+		})
+
+		It("should reject an unknown source", func() {
+			resp, _ := testUser1.MakeMultipartRequest(http.MethodPost, "/import", map[string]any{
+				"source":            "mint",
+				"original_filename": "mint.csv",
+				"file":              []byte("a,b\n"),
+			})
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should require authentication", func() {
+			resp, _ := testHelperUnauthenticated.MakeRequest(http.MethodGet, "/import", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("GetAppImport", func() {
+		It("should return not found for imports of other users", func() {
+			resp, _ := testUser2.MakeRequest(http.MethodGet, "/import/999999", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+})
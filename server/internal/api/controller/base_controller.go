@@ -37,6 +37,9 @@ func (b *BaseController) HandleError(ctx *gin.Context, err error) {
 		response := gin.H{
 			"message": authErr.Message,
 		}
+		if authErr.Data != nil {
+			response["data"] = authErr.Data
+		}
 		if b.cfg.IsDev() || b.cfg.IsTest() {
 			response["error"] = authErr.Err.Error()
 			response["stack"] = authErr.Stack
@@ -80,6 +80,20 @@ func (h *TestHelper) MakeRequest(method, reqUrl string, body any) (*http.Respons
 }
 
 // MakeMultipartRequest sends a multipart/form-data request with file and fields
+// Download makes a GET request and returns the raw response body, for
+// endpoints that send files instead of JSON.
+func (h *TestHelper) Download(reqUrl string) (*http.Response, []byte) {
+	req, err := http.NewRequest(http.MethodGet, h.BaseURL+reqUrl, nil)
+	Expect(err).NotTo(HaveOccurred())
+	h.setCookies(req)
+	resp, err := h.Client.Do(req)
+	Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	Expect(err).NotTo(HaveOccurred())
+	return resp, body
+}
+
 func (h *TestHelper) MakeMultipartRequest(method, url string, fields map[string]any) (*http.Response, map[string]any) {
 	// Use provided original_filename if available so uploaded file has correct name
 	filename := "file.csv"
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"expenses/internal/config"
+	"expenses/internal/models"
+	"expenses/internal/service"
+	"expenses/pkg/logger"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ParserTemplateController struct {
+	*BaseController
+	templateService service.ParserTemplateServiceInterface
+}
+
+func NewParserTemplateController(cfg *config.Config, templateService service.ParserTemplateServiceInterface) *ParserTemplateController {
+	return &ParserTemplateController{
+		BaseController:  NewBaseController(cfg),
+		templateService: templateService,
+	}
+}
+
+func (c *ParserTemplateController) CreateParserTemplate(ctx *gin.Context) {
+	var input models.CreateParserTemplateInput
+	if err := c.BindJSON(ctx, &input); err != nil {
+		logger.Errorf("Failed to bind JSON: %v", err)
+		return
+	}
+	logger.Infof("Creating new parser template for user %d", input.CreatedBy)
+	template, err := c.templateService.CreateParserTemplate(ctx, input)
+	if err != nil {
+		logger.Errorf("Error creating parser template: %v", err)
+		c.HandleError(ctx, err)
+		return
+	}
+	logger.Infof("Parser template created successfully with Id %d for user %d", template.Id, input.CreatedBy)
+	c.SendSuccess(ctx, http.StatusCreated, "Parser template created successfully", template)
+}
+
+func (c *ParserTemplateController) GetParserTemplate(ctx *gin.Context) {
+	userId := c.GetAuthenticatedUserId(ctx)
+	logger.Infof("Fetching parser template details for user %d", userId)
+	templateId, err := strconv.ParseInt(ctx.Param("templateId"), 10, 64)
+	if err != nil {
+		c.SendError(ctx, http.StatusBadRequest, "invalid parser template id")
+		return
+	}
+	template, err := c.templateService.GetParserTemplateById(ctx, templateId, userId)
+	if err != nil {
+		logger.Errorf("Error getting parser template: %v", err)
+		c.HandleError(ctx, err)
+		return
+	}
+	logger.Infof("Parser template retrieved successfully with Id %d for user %d", template.Id, userId)
+	c.SendSuccess(ctx, http.StatusOK, "Parser template retrieved successfully", template)
+}
+
+func (c *ParserTemplateController) ListParserTemplates(ctx *gin.Context) {
+	userId := c.GetAuthenticatedUserId(ctx)
+	logger.Infof("Fetching parser templates for user %d", userId)
+	templates, err := c.templateService.ListParserTemplates(ctx, userId)
+	if err != nil {
+		logger.Errorf("Error listing parser templates: %v", err)
+		c.HandleError(ctx, err)
+		return
+	}
+	logger.Infof("Parser templates retrieved successfully for user %d", userId)
+	c.SendSuccess(ctx, http.StatusOK, "Parser templates retrieved successfully", templates)
+}
+
+func (c *ParserTemplateController) UpdateParserTemplate(ctx *gin.Context) {
+	userId := c.GetAuthenticatedUserId(ctx)
+	logger.Infof("Starting parser template update for user %d", userId)
+	templateId, err := strconv.ParseInt(ctx.Param("templateId"), 10, 64)
+	if err != nil {
+		c.SendError(ctx, http.StatusBadRequest, "invalid parser template id")
+		return
+	}
+	var input models.UpdateParserTemplateInput
+	if err := c.BindJSON(ctx, &input); err != nil {
+		logger.Errorf("Failed to bind JSON: %v", err)
+		return
+	}
+	template, err := c.templateService.UpdateParserTemplate(ctx, templateId, userId, input)
+	if err != nil {
+		logger.Errorf("Error updating parser template: %v", err)
+		c.HandleError(ctx, err)
+		return
+	}
+	logger.Infof("Parser template updated successfully with Id %d for user %d", template.Id, userId)
+	c.SendSuccess(ctx, http.StatusOK, "Parser template updated successfully", template)
+}
+
+func (c *ParserTemplateController) DeleteParserTemplate(ctx *gin.Context) {
+	userId := c.GetAuthenticatedUserId(ctx)
+	logger.Infof("Starting parser template deletion for user %d", userId)
+	templateId, err := strconv.ParseInt(ctx.Param("templateId"), 10, 64)
+	if err != nil {
+		c.SendError(ctx, http.StatusBadRequest, "invalid parser template id")
+		return
+	}
+	if err := c.templateService.DeleteParserTemplate(ctx, templateId, userId); err != nil {
+		logger.Errorf("Error deleting parser template: %v", err)
+		c.HandleError(ctx, err)
+		return
+	}
+	logger.Infof("Parser template deleted successfully with Id %d for user %d", templateId, userId)
+	c.SendSuccess(ctx, http.StatusNoContent, "", nil)
+}
@@ -0,0 +1,115 @@
+package controller_test
+
+import (
+	"net/http"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParserTemplateController", func() {
+	var (
+		testHelper *TestHelper
+		accountId  float64
+		metadata   map[string]any
+	)
+
+	BeforeEach(func() {
+		testHelper = createUniqueUser(baseURL)
+		accountId = createAccount(testHelper, "Template Account", 1000)
+		metadata = map[string]any{
+			"skip_rows": 0,
+			"column_mapping": map[string]string{
+				"txn_date": "Date",
+				"name":     "Narration",
+				"amount":   "Amount",
+			},
+		}
+	})
+
+	createTemplate := func(name string) map[string]any {
+		resp, response := testHelper.MakeRequest(http.MethodPost, "/parser-template", map[string]any{
+			"name":       name,
+			"account_id": int64(accountId),
+			"metadata":   metadata,
+		})
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		return response["data"].(map[string]any)
+	}
+
+	It("should create, fetch, list, update and delete a template", func() {
+		template := createTemplate("Bank export")
+		Expect(template["name"]).To(Equal("Bank export"))
+		Expect(template["account_id"]).To(Equal(accountId))
+		Expect(template["metadata"].(map[string]any)["column_mapping"]).To(HaveKeyWithValue("txn_date", "Date"))
+		templatePath := "/parser-template/" + strconv.FormatFloat(template["id"].(float64), 'f', 0, 64)
+
+		resp, response := testHelper.MakeRequest(http.MethodGet, templatePath, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(response["data"].(map[string]any)["name"]).To(Equal("Bank export"))
+
+		resp, response = testHelper.MakeRequest(http.MethodGet, "/parser-template", nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(response["data"]).To(HaveLen(1))
+
+		resp, response = testHelper.MakeRequest(http.MethodPatch, templatePath, map[string]any{"name": "Renamed", "account_id": 0})
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(response["data"].(map[string]any)["name"]).To(Equal("Renamed"))
+		Expect(response["data"].(map[string]any)["account_id"]).To(BeNil())
+
+		resp, _ = testHelper.MakeRequest(http.MethodDelete, templatePath, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+		resp, response = testHelper.MakeRequest(http.MethodGet, templatePath, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(response["message"]).To(Equal("parser template not found"))
+	})
+
+	It("should reject metadata without the required columns", func() {
+		resp, response := testHelper.MakeRequest(http.MethodPost, "/parser-template", map[string]any{
+			"name":     "Incomplete",
+			"metadata": map[string]any{"column_mapping": map[string]string{"name": "Narration"}},
+		})
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(response["message"]).To(Equal("invalid parser template metadata"))
+	})
+
+	It("should reject a second template for the same account", func() {
+		createTemplate("First export")
+		resp, response := testHelper.MakeRequest(http.MethodPost, "/parser-template", map[string]any{
+			"name":       "Second export",
+			"account_id": int64(accountId),
+			"metadata":   metadata,
+		})
+		Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+		Expect(response["message"]).To(Equal("account already has a parser template"))
+	})
+
+	It("should unbind the template when its account is deleted", func() {
+		template := createTemplate("Closed account export")
+		templatePath := "/parser-template/" + strconv.FormatFloat(template["id"].(float64), 'f', 0, 64)
+
+		resp, _ := testHelper.MakeRequest(http.MethodDelete, "/account/"+strconv.FormatFloat(accountId, 'f', 0, 64), nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+		resp, response := testHelper.MakeRequest(http.MethodGet, templatePath, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(response["data"].(map[string]any)["account_id"]).To(BeNil())
+	})
+
+	It("should not show templates of other users", func() {
+		template := createTemplate("Private export")
+		templatePath := "/parser-template/" + strconv.FormatFloat(template["id"].(float64), 'f', 0, 64)
+
+		resp, response := testUser1.MakeRequest(http.MethodGet, templatePath, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(response["message"]).To(Equal("parser template not found"))
+	})
+
+	It("should return bad request for invalid template id format", func() {
+		resp, response := testHelper.MakeRequest(http.MethodGet, "/parser-template/abc", nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(response["message"]).To(Equal("invalid parser template id"))
+	})
+})
@@ -279,6 +279,53 @@ func (rc *RuleController) ExecuteRules(c *gin.Context) {
 	rc.SendSuccess(c, http.StatusAccepted, "Rule execution started", nil)
 }
 
+func (rc *RuleController) ReorderRules(c *gin.Context) {
+	userId := rc.GetAuthenticatedUserId(c)
+	logger.Infof("Reordering rules for user %d", userId)
+
+	var request models.ReorderRulesRequest
+	if err := rc.BindJSON(c, &request); err != nil {
+		logger.Errorf("Failed to bind JSON: %v", err)
+		return
+	}
+
+	rules, err := rc.ruleService.ReorderRules(c, userId, request)
+	if err != nil {
+		logger.Errorf("Error reordering rules: %v", err)
+		rc.HandleError(c, err)
+		return
+	}
+
+	logger.Infof("Rules reordered successfully for user %d", userId)
+	rc.SendSuccess(c, http.StatusOK, "Rules reordered successfully", rules)
+}
+
+// PreviewRules returns the changes rules would make to transactions without
+// applying them.
+func (rc *RuleController) PreviewRules(c *gin.Context) {
+	userId := rc.GetAuthenticatedUserId(c)
+	logger.Infof("Previewing rules for user %d", userId)
+
+	var request models.PreviewRulesRequest
+	if err := rc.BindJSON(c, &request); err != nil {
+		logger.Errorf("Failed to bind JSON: %v", err)
+		return
+	}
+	if request.Rule != nil {
+		request.Rule.Rule.CreatedBy = userId
+	}
+
+	response, err := rc.ruleEngineService.PreviewRules(c, userId, request)
+	if err != nil {
+		logger.Errorf("Error previewing rules: %v", err)
+		rc.HandleError(c, err)
+		return
+	}
+
+	logger.Infof("Rule preview for user %d found %d changesets", userId, len(response.Changesets))
+	rc.SendSuccess(c, http.StatusOK, "Rules previewed successfully", response)
+}
+
 // parseIdFromParam retrieves an Id from a URL parameter.
 // It sends an error response and returns false if parsing fails.
 func (rc *RuleController) parseIdFromParam(c *gin.Context, paramName string) (int64, bool) {
@@ -86,6 +86,67 @@ var _ = Describe("RuleController", func() {
 				ruleId = int64(rule["id"].(float64))
 			})
 
+			It("should create a rule with nested condition groups", func() {
+				input := models.CreateRuleRequest{
+					Rule: models.CreateBaseRuleRequest{
+						Name:          "Grouped Rule",
+						EffectiveFrom: now,
+					},
+					Actions: []models.CreateRuleActionRequest{
+						{ActionType: models.RuleFieldAmount, ActionValue: "100"},
+					},
+					ConditionGroups: []models.CreateRuleConditionGroupRequest{
+						{
+							Logic: models.ConditionLogicOr,
+							Conditions: []models.CreateRuleConditionRequest{
+								{ConditionType: models.RuleFieldName, ConditionValue: "AMZN", ConditionOperator: models.OperatorContains},
+								{ConditionType: models.RuleFieldName, ConditionValue: "AMAZON", ConditionOperator: models.OperatorContains},
+							},
+							Groups: []models.CreateRuleConditionGroupRequest{
+								{
+									Logic: models.ConditionLogicNot,
+									Conditions: []models.CreateRuleConditionRequest{
+										{ConditionType: models.RuleFieldAmount, ConditionValue: "500", ConditionOperator: models.OperatorLower},
+									},
+								},
+							},
+						},
+					},
+				}
+				resp, response := testUser1.MakeRequest(http.MethodPost, "/rule", input)
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+				data := response["data"].(map[string]any)
+				groupedRuleId := int64(data["rule"].(map[string]any)["id"].(float64))
+
+				resp, response = testUser1.MakeRequest(http.MethodGet, "/rule/"+strconv.FormatInt(groupedRuleId, 10), nil)
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				data = response["data"].(map[string]any)
+				Expect(data["conditions"]).To(BeEmpty())
+				groups := data["condition_groups"].([]any)
+				Expect(groups).To(HaveLen(1))
+				group := groups[0].(map[string]any)
+				Expect(group["logic"]).To(Equal("OR"))
+				Expect(group["conditions"]).To(HaveLen(2))
+				Expect(group["groups"].([]any)[0].(map[string]any)["logic"]).To(Equal("NOT"))
+			})
+
+			It("should reject an empty condition group", func() {
+				input := models.CreateRuleRequest{
+					Rule: models.CreateBaseRuleRequest{
+						Name:          "Empty Group Rule",
+						EffectiveFrom: now,
+					},
+					Actions: []models.CreateRuleActionRequest{
+						{ActionType: models.RuleFieldAmount, ActionValue: "100"},
+					},
+					ConditionGroups: []models.CreateRuleConditionGroupRequest{
+						{Logic: models.ConditionLogicAnd},
+					},
+				}
+				resp, _ := testUser1.MakeRequest(http.MethodPost, "/rule", input)
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+
 			It("should handle multiple actions of same type", func() {
 				input := models.CreateRuleRequest{
 					Rule: models.CreateBaseRuleRequest{
@@ -1220,6 +1281,16 @@ var _ = Describe("RuleController", func() {
 			Expect(rule["description"]).To(Equal(newDesc))
 		})
 
+		It("should handle partial updates (only stop_processing)", func() {
+			stop := true
+			update := models.UpdateRuleRequest{StopProcessing: &stop}
+			url := "/rule/" + strconv.FormatInt(ruleId, 10)
+			resp, response := testUser1.MakeRequest(http.MethodPatch, url, update)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			rule := response["data"].(map[string]any)
+			Expect(rule["stop_processing"]).To(Equal(true))
+		})
+
 		It("should handle partial updates (only effective_from)", func() {
 			newTime := now.Add(-time.Hour)
 			update := models.UpdateRuleRequest{EffectiveFrom: &newTime}
@@ -1692,7 +1763,7 @@ var _ = Describe("RuleController", func() {
 			})
 
 			It("should handle updating only condition value", func() {
-				val := "Updated condition value only"
+				val := "250.50"
 				update := models.UpdateRuleConditionRequest{
 					ConditionValue: &val,
 				}
@@ -1700,7 +1771,17 @@ var _ = Describe("RuleController", func() {
 				resp, response := testUser1.MakeRequest(http.MethodPatch, url, update)
 				Expect(resp.StatusCode).To(Equal(http.StatusOK))
 				condition := response["data"].(map[string]any)
-				Expect(condition["condition_value"]).To(Equal("Updated condition value only"))
+				Expect(condition["condition_value"]).To(Equal("250.50"))
+			})
+
+			It("should reject a value that does not suit the stored condition type", func() {
+				val := "Not an amount"
+				update := models.UpdateRuleConditionRequest{
+					ConditionValue: &val,
+				}
+				url := "/rule/" + strconv.FormatInt(ruleId, 10) + "/condition/" + strconv.FormatInt(conditionId, 10)
+				resp, _ := testUser1.MakeRequest(http.MethodPatch, url, update)
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
 			})
 
 			It("should handle updating only condition operator", func() {
@@ -2104,6 +2185,103 @@ var _ = Describe("RuleController", func() {
 		})
 	})
 
+	Describe("PreviewRules", func() {
+		previewRule := func(conditionValue string) *models.CreateRuleRequest {
+			return &models.CreateRuleRequest{
+				Rule: models.CreateBaseRuleRequest{
+					Name:          "Preview Rule",
+					EffectiveFrom: now.Add(-365 * 24 * time.Hour),
+				},
+				Actions: []models.CreateRuleActionRequest{
+					{ActionType: models.RuleFieldDescription, ActionValue: "Previewed"},
+				},
+				Conditions: []models.CreateRuleConditionRequest{
+					{ConditionType: models.RuleFieldName, ConditionValue: conditionValue, ConditionOperator: models.OperatorContains},
+				},
+			}
+		}
+
+		It("should return the changes of an unsaved rule without applying them", func() {
+			input := models.PreviewRulesRequest{Rule: previewRule("a"), Limit: 20}
+			resp, response := testUser1.MakeRequest(http.MethodPost, "/rule/preview", input)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(response["message"]).To(Equal("Rules previewed successfully"))
+
+			data := response["data"].(map[string]any)
+			Expect(data["total_rules"]).To(Equal(float64(1)))
+			Expect(data["processed_transactions"]).To(BeNumerically("<=", 20))
+			changesets := data["changesets"].([]any)
+			for _, changeset := range changesets {
+				txnId := int64(changeset.(map[string]any)["transaction_id"].(float64))
+				resp, txnResponse := testUser1.MakeRequest(http.MethodGet, "/transaction/"+strconv.FormatInt(txnId, 10), nil)
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				Expect(txnResponse["data"].(map[string]any)["description"]).NotTo(Equal("Previewed"))
+			}
+		})
+
+		It("should return bad request for an invalid unsaved rule", func() {
+			rule := previewRule("a")
+			rule.Conditions[0].ConditionOperator = models.OperatorGreater
+			resp, _ := testUser1.MakeRequest(http.MethodPost, "/rule/preview", models.PreviewRulesRequest{Rule: rule})
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return bad request for a limit over the maximum", func() {
+			resp, _ := testUser1.MakeRequest(http.MethodPost, "/rule/preview", models.PreviewRulesRequest{Rule: previewRule("a"), Limit: 501})
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return unauthorized without authentication", func() {
+			resp, _ := testHelperUnauthenticated.MakeRequest(http.MethodPost, "/rule/preview", models.PreviewRulesRequest{})
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("ReorderRules", func() {
+		It("should run the listed rules first and return all rules in order", func() {
+			firstId, _, _ := createTestRule()
+			secondId, _, _ := createTestRule()
+
+			input := models.ReorderRulesRequest{RuleIds: []int64{secondId, firstId}}
+			resp, response := testUser1.MakeRequest(http.MethodPut, "/rule/order", input)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(response["message"]).To(Equal("Rules reordered successfully"))
+
+			rules := response["data"].([]any)
+			Expect(len(rules)).To(BeNumerically(">=", 2))
+			Expect(int64(rules[0].(map[string]any)["id"].(float64))).To(Equal(secondId))
+			Expect(rules[0].(map[string]any)["priority"]).To(Equal(float64(1)))
+			Expect(int64(rules[1].(map[string]any)["id"].(float64))).To(Equal(firstId))
+			Expect(rules[1].(map[string]any)["priority"]).To(Equal(float64(2)))
+		})
+
+		It("should return bad request for duplicate rule ids", func() {
+			ruleId, _, _ = createTestRule()
+			input := models.ReorderRulesRequest{RuleIds: []int64{ruleId, ruleId}}
+			resp, _ := testUser1.MakeRequest(http.MethodPut, "/rule/order", input)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return bad request for an empty list", func() {
+			input := models.ReorderRulesRequest{RuleIds: []int64{}}
+			resp, _ := testUser1.MakeRequest(http.MethodPut, "/rule/order", input)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return not found for rules of another user", func() {
+			ruleId, _, _ = createTestRule()
+			input := models.ReorderRulesRequest{RuleIds: []int64{ruleId}}
+			resp, _ := testUser2.MakeRequest(http.MethodPut, "/rule/order", input)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return unauthorized without authentication", func() {
+			input := models.ReorderRulesRequest{RuleIds: []int64{1}}
+			resp, _ := testHelperUnauthenticated.MakeRequest(http.MethodPut, "/rule/order", input)
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
 	Describe("ExecuteRules", func() {
 		// Helper to get a transaction by ID for verification
 		getTestTransaction := func(id int64, user *TestHelper) map[string]any {
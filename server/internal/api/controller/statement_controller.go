@@ -8,15 +8,28 @@ import (
 	"expenses/pkg/logger"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 )
 
+// statementEventHeartbeat is how often an idle event stream is kept alive.
+const statementEventHeartbeat = 15 * time.Second
+
+// statementEventPoll is how often an event stream reads the stored status of
+// its statement, to notice changes made by another server.
+const statementEventPoll = 5 * time.Second
+
+// statementRulesWait is how long an event stream waits for the rules event
+// after the import is done before it ends.
+const statementRulesWait = time.Minute
+
 type StatementController struct {
 	*BaseController
 	statementService   service.StatementServiceInterface
@@ -70,6 +83,7 @@ func (s *StatementController) CreateStatement(ctx *gin.Context) {
 		BankType:         form.BankType,
 		Metadata:         form.Metadata,
 		Password:         form.Password,
+		Force:            form.Force,
 		OriginalFilename: fileName,
 		FileBytes:        fileBytes,
 	}
@@ -100,7 +114,7 @@ func (s *StatementController) PreviewStatement(ctx *gin.Context) {
 		return
 	}
 
-	preview, err := s.statementService.PreviewStatement(ctx, fileBytes, fileName, form.SkipRows, form.RowSize, form.Password)
+	preview, err := s.statementService.PreviewStatement(ctx, fileBytes, fileName, form.SkipRows, form.RowSize, form.Password, form.SheetSelector)
 	if err != nil {
 		logger.Errorf("Error previewing statement: %v", err)
 		s.HandleError(ctx, err)
@@ -161,3 +175,205 @@ func (s *StatementController) GetStatementStatus(ctx *gin.Context) {
 	logger.Infof("Successfully fetched statement %d for user %d", statementId, userID)
 	s.SendSuccess(ctx, http.StatusOK, "Statement fetched successfully", statement)
 }
+
+func (s *StatementController) GetStatementErrors(ctx *gin.Context) {
+	userID := s.GetAuthenticatedUserId(ctx)
+	statementId, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.Errorf("Failed to parse statement_id: %v", err)
+		s.SendError(ctx, http.StatusBadRequest, "Invalid statement_id")
+		return
+	}
+
+	logger.Infof("Fetching row errors of statement %d for user %d", statementId, userID)
+	rowErrors, err := s.statementService.GetStatementRowErrors(ctx, statementId, userID)
+	if err != nil {
+		logger.Errorf("Error fetching statement row errors: %v", err)
+		s.HandleError(ctx, err)
+		return
+	}
+	logger.Infof("Successfully fetched %d row errors of statement %d for user %d", len(rowErrors), statementId, userID)
+	s.SendSuccess(ctx, http.StatusOK, "Statement errors fetched successfully", rowErrors)
+}
+
+// GetParsers lists the statement formats that can be uploaded, with what each
+// of them needs from the user.
+func (s *StatementController) GetParsers(ctx *gin.Context) {
+	parsers := s.statementService.ListParsers(ctx)
+	s.SendSuccess(ctx, http.StatusOK, "Parsers fetched successfully", parsers)
+}
+
+// GetUpcomingCardDues lists the credit card bills that are still to be paid
+// across the user's accounts.
+func (s *StatementController) GetUpcomingCardDues(ctx *gin.Context) {
+	userID := s.GetAuthenticatedUserId(ctx)
+	logger.Infof("Fetching upcoming card dues for user %d", userID)
+	dues, err := s.statementService.ListUpcomingCardDues(ctx, userID)
+	if err != nil {
+		logger.Errorf("Error fetching upcoming card dues: %v", err)
+		s.HandleError(ctx, err)
+		return
+	}
+	logger.Infof("Successfully fetched %d upcoming card dues for user %d", len(dues), userID)
+	s.SendSuccess(ctx, http.StatusOK, "Upcoming card dues fetched successfully", dues)
+}
+
+func (s *StatementController) RevertStatement(ctx *gin.Context) {
+	userID := s.GetAuthenticatedUserId(ctx)
+	statementId, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.Errorf("Failed to parse statement_id: %v", err)
+		s.SendError(ctx, http.StatusBadRequest, "Invalid statement_id")
+		return
+	}
+
+	logger.Infof("Reverting statement %d for user %d", statementId, userID)
+	statement, err := s.statementService.RevertStatement(ctx, statementId, userID)
+	if err != nil {
+		logger.Errorf("Error reverting statement: %v", err)
+		s.HandleError(ctx, err)
+		return
+	}
+	logger.Infof("Successfully reverted statement %d for user %d", statementId, userID)
+	s.SendSuccess(ctx, http.StatusOK, "Statement reverted successfully", statement)
+}
+
+func (s *StatementController) ReprocessStatement(ctx *gin.Context) {
+	userID := s.GetAuthenticatedUserId(ctx)
+	statementId, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.Errorf("Failed to parse statement_id: %v", err)
+		s.SendError(ctx, http.StatusBadRequest, "Invalid statement_id")
+		return
+	}
+
+	// The body is optional: without one the statement is parsed again with
+	// the options it was uploaded with.
+	var input models.ReprocessStatementInput
+	if ctx.Request.ContentLength > 0 {
+		if err := s.BindJSON(ctx, &input); err != nil {
+			logger.Errorf("Failed to bind reprocess input: %v", err)
+			return
+		}
+	}
+
+	logger.Infof("Reprocessing statement %d for user %d", statementId, userID)
+	statement, err := s.statementService.ReprocessStatement(ctx, statementId, userID, input)
+	if err != nil {
+		logger.Errorf("Error reprocessing statement: %v", err)
+		s.HandleError(ctx, err)
+		return
+	}
+	logger.Infof("Statement %d queued for reprocessing for user %d", statementId, userID)
+	s.SendSuccess(ctx, http.StatusAccepted, "Statement queued for reprocessing", statement)
+}
+
+func (s *StatementController) GetStatementFile(ctx *gin.Context) {
+	userID := s.GetAuthenticatedUserId(ctx)
+	statementId, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.Errorf("Failed to parse statement_id: %v", err)
+		s.SendError(ctx, http.StatusBadRequest, "Invalid statement_id")
+		return
+	}
+
+	logger.Infof("Downloading file of statement %d for user %d", statementId, userID)
+	statement, fileBytes, err := s.statementService.GetStatementFile(ctx, statementId, userID)
+	if err != nil {
+		logger.Errorf("Error fetching statement file: %v", err)
+		s.HandleError(ctx, err)
+		return
+	}
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": statement.OriginalFilename}))
+	ctx.Data(http.StatusOK, "application/octet-stream", fileBytes)
+}
+
+// StreamStatementEvents streams the progress of a statement as server-sent
+// events: its current state first, then every status change, the parsed row
+// counts and the end of the rule run for the imported transactions. The stream
+// ends once nothing more will happen or the client disconnects.
+//
+// Events are only published by the server that runs the statement's jobs, so
+// the stream also polls the stored status. Status changes made elsewhere are
+// sent from there, and progress and rules events of jobs run by another server
+// are not; without the rules event the stream ends statementRulesWait after
+// the import is done.
+func (s *StatementController) StreamStatementEvents(ctx *gin.Context) {
+	userID := s.GetAuthenticatedUserId(ctx)
+	statementId, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		logger.Errorf("Failed to parse statement_id: %v", err)
+		s.SendError(ctx, http.StatusBadRequest, "Invalid statement_id")
+		return
+	}
+
+	statement, events, unsubscribe, err := s.statementService.WatchStatement(ctx, statementId, userID)
+	if err != nil {
+		logger.Errorf("Error watching statement: %v", err)
+		s.HandleError(ctx, err)
+		return
+	}
+	defer unsubscribe()
+
+	logger.Infof("Streaming events of statement %d for user %d", statementId, userID)
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	current := models.NewStatementStatusEvent(statement)
+	ctx.SSEvent(string(current.Type), current)
+	ctx.Writer.Flush()
+	// A statement that is no longer being processed has nothing to report.
+	if statement.Status != models.StatementStatusPending && statement.Status != models.StatementStatusProcessing {
+		return
+	}
+
+	heartbeat := time.NewTicker(statementEventHeartbeat)
+	defer heartbeat.Stop()
+	poll := time.NewTicker(statementEventPoll)
+	defer poll.Stop()
+	lastStatus := statement.Status
+	var doneAt time.Time
+	sendStatus := func(event models.StatementEvent) {
+		ctx.SSEvent(string(event.Type), event)
+		lastStatus = event.Status
+		if event.Status == models.StatementStatusDone && doneAt.IsZero() {
+			doneAt = time.Now()
+		}
+	}
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			if event.Type == models.StatementEventStatus {
+				sendStatus(event)
+			} else {
+				ctx.SSEvent(string(event.Type), event)
+			}
+			return !event.IsFinal()
+		case <-poll.C:
+			if lastStatus == models.StatementStatusDone {
+				return time.Since(doneAt) < statementRulesWait
+			}
+			latest, err := s.statementService.GetStatementStatus(ctx, statementId, userID)
+			if err != nil {
+				logger.Warnf("Failed to poll statement %d for its event stream: %v", statementId, err)
+				return true
+			}
+			if latest.Status == lastStatus {
+				return true
+			}
+			event := models.NewStatementStatusEvent(latest)
+			sendStatus(event)
+			return !event.IsFinal()
+		case <-heartbeat.C:
+			// Comments keep proxies from closing an idle connection.
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
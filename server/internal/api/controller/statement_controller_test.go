@@ -58,7 +58,10 @@ func waitForStatementDone(testHelper *TestHelper, statementId float64) map[strin
 }
 
 func uploadStatement(testHelper *TestHelper, accountId float64, filename string) float64 {
+	// The title row keeps files with different names distinct, since a file
+	// can only be uploaded once per account.
 	xlsxData := [][]string{
+		{filename},
 		{"Txn Date", "Details", "Ref No.", "Debit", "Credit", "Balance"},
 		{"1 Aug 2022", "TEST TRANSACTION", "123", "100.00", "", "1000.00"},
 		{"Computer Generated Statement"},
@@ -547,6 +550,263 @@ var _ = Describe("StatementController", func() {
 		})
 	})
 
+	Describe("GetStatementErrors", func() {
+		It("should list the rows that failed to parse", func() {
+			testHelper := createUniqueUser(baseURL)
+			accountId := createAccount(testHelper, "Row Errors Account", 1000)
+			xlsxData := [][]string{
+				{"Txn Date", "Details", "Ref No.", "Debit", "Credit", "Balance"},
+				{"1 Aug 2022", "TEST TRANSACTION", "123", "100.00", "", "1000.00"},
+				{"1 Aug 2022", "BROKEN TRANSACTION", "124", "abc", "", "900.00"},
+			}
+			statementInput := map[string]any{
+				"account_id":        int64(accountId),
+				"original_filename": "row_errors.xlsx",
+				"file_type":         "excel",
+				"file":              utils.CreateXLSXFile(xlsxData),
+			}
+			resp, response := testHelper.MakeMultipartRequest(http.MethodPost, "/statement", statementInput)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			statementId := response["data"].(map[string]any)["id"].(float64)
+			data := waitForStatementDone(testHelper, statementId)
+			Expect(data["message"]).To(Equal("Processed 2 transactions: 1 inserted, 0 duplicates, 1 failed"))
+			Expect(data["failed_count"]).To(Equal(1.0))
+
+			resp, response = testHelper.MakeRequest(http.MethodGet, "/statement/"+strconv.FormatFloat(statementId, 'f', 0, 64)+"/errors", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			rowErrors := response["data"].([]any)
+			Expect(rowErrors).To(HaveLen(1))
+			rowError := rowErrors[0].(map[string]any)
+			Expect(rowError["line_number"]).To(Equal(3.0))
+			Expect(rowError["raw_row"]).To(ContainSubstring("BROKEN TRANSACTION"))
+			Expect(rowError["reason"]).To(ContainSubstring("abc"))
+		})
+
+		It("should return an empty list for a statement without errors", func() {
+			resp, response := testUser1.MakeRequest(http.MethodGet, "/statement/1/errors", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(response["data"]).To(BeEmpty())
+		})
+
+		It("should return not found for a statement of another user", func() {
+			resp, response := testUser1.MakeRequest(http.MethodGet, "/statement/4/errors", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			Expect(response["message"]).To(Equal("statement not found"))
+		})
+
+		It("should return bad request for invalid statement id format", func() {
+			resp, response := testUser1.MakeRequest(http.MethodGet, "/statement/abc/errors", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(response["message"]).To(Equal("Invalid statement_id"))
+		})
+	})
+
+	Describe("GetUpcomingCardDues", func() {
+		It("should list the card bills that are still due", func() {
+			testHelper := createUniqueUser(baseURL)
+			accountId := createAccount(testHelper, "Card Account", 0)
+			dueDate := time.Now().AddDate(0, 0, 7)
+			xlsxData := [][]string{
+				{"Total Payment Due", "Minimum Payment Due", "Statement Period", "Payment Due Date", ""},
+				{"7,977.00", "400.00", "15 Oct 2025 - 14 Nov 2025", dueDate.Format("02 Jan 2006"), ""},
+				{"Date", "Transaction Details", "", "Amount (INR)", "Debit/Credit"},
+				{"14 Nov '25", "Restro,MUMBAI", "", "₹ 7,977.00", "Debit"},
+			}
+			statementInput := map[string]any{
+				"account_id":        int64(accountId),
+				"original_filename": "card.xlsx",
+				"file":              utils.CreateXLSXFile(xlsxData),
+			}
+			resp, response := testHelper.MakeMultipartRequest(http.MethodPost, "/statement", statementInput)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			statementId := response["data"].(map[string]any)["id"].(float64)
+			data := waitForStatementDone(testHelper, statementId)
+			Expect(data["total_due"]).To(Equal(7977.0))
+
+			resp, response = testHelper.MakeRequest(http.MethodGet, "/statement/dues", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			dues := response["data"].([]any)
+			Expect(dues).To(HaveLen(1))
+			due := dues[0].(map[string]any)
+			Expect(due["statement_id"]).To(Equal(statementId))
+			Expect(due["account_name"]).To(Equal("Card Account"))
+			Expect(due["minimum_due"]).To(Equal(400.0))
+			Expect(due["payment_due_date"]).To(HavePrefix(dueDate.Format("2006-01-02")))
+		})
+
+		It("should return an empty list when no bills are due", func() {
+			resp, response := testUser1.MakeRequest(http.MethodGet, "/statement/dues", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(response["data"]).To(BeEmpty())
+		})
+
+		It("should return unauthorized for unauthenticated user", func() {
+			resp, _ := testHelperUnauthenticated.MakeRequest(http.MethodGet, "/statement/dues", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("GetParsers", func() {
+		It("should list the registered parsers with their requirements", func() {
+			resp, response := testUser1.MakeRequest(http.MethodGet, "/statement/parsers", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			parsers := response["data"].([]any)
+			byType := make(map[string]map[string]any)
+			for _, p := range parsers {
+				info := p.(map[string]any)
+				byType[info["bank_type"].(string)] = info
+			}
+			Expect(byType).To(HaveKey("hdfc"))
+			Expect(byType["hdfc"]["name"]).To(Equal("HDFC Bank"))
+			Expect(byType["hdfc"]["extensions"]).To(ContainElement(".pdf"))
+			Expect(byType["hdfc"]["needs_password"]).To(BeTrue())
+			Expect(byType["others"]["needs_metadata"]).To(BeTrue())
+			Expect(byType["mt940"]["capabilities"].(map[string]any)["balances"]).To(BeTrue())
+		})
+
+		It("should return unauthorized for unauthenticated user", func() {
+			resp, _ := testHelperUnauthenticated.MakeRequest(http.MethodGet, "/statement/parsers", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("RevertStatement", func() {
+		It("should delete the imported transactions and mark the statement as reverted", func() {
+			testHelper := createUniqueUser(baseURL)
+			accountId := createAccount(testHelper, "Revert Account", 1000)
+			statementId := uploadStatement(testHelper, accountId, "revert.xlsx")
+			statementPath := "/statement/" + strconv.FormatFloat(statementId, 'f', 0, 64)
+
+			resp, response := testHelper.MakeRequest(http.MethodGet, "/transaction?statement_id="+strconv.FormatFloat(statementId, 'f', 0, 64), nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(response["data"].(map[string]any)["transactions"]).To(HaveLen(1))
+
+			resp, response = testHelper.MakeRequest(http.MethodDelete, statementPath, nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(response["data"].(map[string]any)["status"]).To(Equal("reverted"))
+
+			resp, response = testHelper.MakeRequest(http.MethodGet, "/transaction?statement_id="+strconv.FormatFloat(statementId, 'f', 0, 64), nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(response["data"].(map[string]any)["transactions"]).To(BeEmpty())
+
+			resp, response = testHelper.MakeRequest(http.MethodDelete, statementPath, nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+			Expect(response["message"]).To(Equal("statement is already reverted"))
+		})
+
+		It("should return not found when reverting a statement of another user", func() {
+			resp, response := testUser1.MakeRequest(http.MethodDelete, "/statement/4", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			Expect(response["message"]).To(Equal("statement not found"))
+		})
+
+		It("should return bad request for invalid statement id format", func() {
+			resp, response := testUser1.MakeRequest(http.MethodDelete, "/statement/abc", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(response["message"]).To(Equal("Invalid statement_id"))
+		})
+	})
+
+	Describe("Duplicate uploads", func() {
+		It("should reject a file that was already uploaded unless forced", func() {
+			testHelper := createUniqueUser(baseURL)
+			accountId := createAccount(testHelper, "Duplicate Upload Account", 1000)
+			statementId := uploadStatement(testHelper, accountId, "monthly.xlsx")
+
+			_, fileBytes := testHelper.Download("/statement/" + strconv.FormatFloat(statementId, 'f', 0, 64) + "/file")
+			statementInput := map[string]any{
+				"account_id":        int64(accountId),
+				"original_filename": "monthly_copy.xlsx",
+				"file":              fileBytes,
+			}
+
+			resp, response := testHelper.MakeMultipartRequest(http.MethodPost, "/statement", statementInput)
+			Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+			Expect(response["message"]).To(Equal("statement file was already uploaded"))
+			Expect(response["data"].(map[string]any)["id"]).To(Equal(statementId))
+
+			statementInput["force"] = true
+			resp, response = testHelper.MakeMultipartRequest(http.MethodPost, "/statement", statementInput)
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			Expect(response["data"].(map[string]any)["duplicate_of"]).To(Equal(statementId))
+		})
+	})
+
+	Describe("StreamStatementEvents", func() {
+		It("should send the current state of a finished statement and close the stream", func() {
+			testHelper := createUniqueUser(baseURL)
+			accountId := createAccount(testHelper, "Events Account", 1000)
+			statementId := uploadStatement(testHelper, accountId, "events.xlsx")
+
+			resp, body := testHelper.Download("/statement/" + strconv.FormatFloat(statementId, 'f', 0, 64) + "/events")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.Header.Get("Content-Type")).To(HavePrefix("text/event-stream"))
+			Expect(string(body)).To(HavePrefix("event:status\ndata:"))
+			Expect(string(body)).To(ContainSubstring(`"status":"done"`))
+		})
+
+		It("should return not found for a statement of another user", func() {
+			resp, response := testUser1.MakeRequest(http.MethodGet, "/statement/4/events", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			Expect(response["message"]).To(Equal("statement not found"))
+		})
+
+		It("should return bad request for invalid statement id format", func() {
+			resp, response := testUser1.MakeRequest(http.MethodGet, "/statement/abc/events", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(response["message"]).To(Equal("Invalid statement_id"))
+		})
+	})
+
+	Describe("GetStatementFile", func() {
+		It("should download the uploaded file", func() {
+			testHelper := createUniqueUser(baseURL)
+			accountId := createAccount(testHelper, "Download Account", 1000)
+			statementId := uploadStatement(testHelper, accountId, "download.xlsx")
+
+			resp, body := testHelper.Download("/statement/" + strconv.FormatFloat(statementId, 'f', 0, 64) + "/file")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.Header.Get("Content-Disposition")).To(Equal(`attachment; filename=download.xlsx`))
+			// xlsx files are zip archives
+			Expect(string(body[:2])).To(Equal("PK"))
+		})
+
+		It("should return not found for a statement of another user", func() {
+			resp, response := testUser1.MakeRequest(http.MethodGet, "/statement/4/file", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			Expect(response["message"]).To(Equal("statement not found"))
+		})
+	})
+
+	Describe("ReprocessStatement", func() {
+		It("should import the stored file again", func() {
+			testHelper := createUniqueUser(baseURL)
+			accountId := createAccount(testHelper, "Reprocess Account", 1000)
+			statementId := uploadStatement(testHelper, accountId, "reprocess.xlsx")
+			statementPath := "/statement/" + strconv.FormatFloat(statementId, 'f', 0, 64)
+
+			resp, response := testHelper.MakeRequest(http.MethodPost, statementPath+"/reprocess", map[string]any{"bank_type": "sbi"})
+			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+			Expect(response["message"]).To(Equal("Statement queued for reprocessing"))
+			data := response["data"].(map[string]any)
+			Expect(data["status"]).To(Equal("pending"))
+			Expect(data["bank_type"]).To(Equal("sbi"))
+
+			data = waitForStatementDone(testHelper, statementId)
+			Expect(data["inserted_count"]).To(Equal(1.0))
+
+			resp, response = testHelper.MakeRequest(http.MethodGet, "/transaction?statement_id="+strconv.FormatFloat(statementId, 'f', 0, 64), nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(response["data"].(map[string]any)["transactions"]).To(HaveLen(1))
+		})
+
+		It("should return bad request for invalid statement id format", func() {
+			resp, response := testUser1.MakeRequest(http.MethodPost, "/statement/abc/reprocess", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(response["message"]).To(Equal("Invalid statement_id"))
+		})
+	})
+
 	Describe("PreviewStatement", func() {
 		It("should successfully preview a valid CSV statement file", func() {
 			fileContent := []byte(
@@ -574,6 +834,24 @@ var _ = Describe("StatementController", func() {
 			Expect(len(rows)).To(BeNumerically(">", 0))
 		})
 
+		It("should list the sheets of a workbook and preview the selected one", func() {
+			previewInput := map[string]any{
+				"file":              utils.CreateXLSXFile([][]string{{"Date", "Description", "Amount"}}),
+				"original_filename": "statement.xlsx",
+				"sheet_index":       0,
+			}
+			resp, response := testUser1.MakeMultipartRequest(http.MethodPost, "/statement/preview", previewInput)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			data := response["data"].(map[string]any)
+			Expect(data["sheets"]).To(Equal([]any{"Sheet1"}))
+			Expect(data["sheet"]).To(Equal("Sheet1"))
+
+			previewInput["sheet_name"] = "Ledger"
+			delete(previewInput, "sheet_index")
+			resp, _ = testUser1.MakeMultipartRequest(http.MethodPost, "/statement/preview", previewInput)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
 		It("should successfully preview with custom skip_rows parameter", func() {
 			fileContent := []byte(
 				"Header Line 1\n" +
@@ -5,6 +5,8 @@ import (
 	"expenses/internal/models"
 	"expenses/internal/service"
 	"expenses/pkg/logger"
+	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
 
@@ -147,3 +149,29 @@ func (t *TransactionController) ListTransactions(ctx *gin.Context) {
 	logger.Infof("Transactions retrieved successfully for user %d", userId)
 	t.SendSuccess(ctx, http.StatusOK, "Transactions retrieved successfully", transactions)
 }
+
+// ExportTransactionsQIF downloads the transactions of an account as a QIF file,
+// optionally limited to the dates between date_from and date_to.
+func (t *TransactionController) ExportTransactionsQIF(ctx *gin.Context) {
+	userId := t.GetAuthenticatedUserId(ctx)
+	accountId := t.parseInt64QueryParam(ctx, "account_id")
+	if accountId == nil {
+		t.SendError(ctx, http.StatusBadRequest, "account_id is required")
+		return
+	}
+
+	logger.Infof("Exporting transactions of account %d as QIF for user %d", *accountId, userId)
+	account, fileBytes, err := t.transactionService.ExportTransactionsQIF(ctx, userId, models.TransactionExportQuery{
+		AccountId: *accountId,
+		DateFrom:  t.parseTimeQueryParam(ctx, "date_from", "2006-01-02"),
+		DateTo:    t.parseTimeQueryParam(ctx, "date_to", "2006-01-02"),
+	})
+	if err != nil {
+		logger.Errorf("Error exporting transactions: %v", err)
+		t.HandleError(ctx, err)
+		return
+	}
+	fileName := fmt.Sprintf("%s.qif", account.Name)
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	ctx.Data(http.StatusOK, "application/qif", fileBytes)
+}
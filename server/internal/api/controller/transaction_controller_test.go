@@ -857,6 +857,41 @@ var _ = Describe("TransactionController", func() {
 		})
 	})
 
+	Describe("ExportTransactionsQIF", func() {
+		It("should download the transactions of an account as a QIF file", func() {
+			resp, response := testUser2.MakeRequest(http.MethodPost, "/account", models.CreateAccountInput{
+				Name:     "QIF Export Account",
+				BankType: models.BankTypeAxis,
+				Currency: models.CurrencyINR,
+			})
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			accountId := int64(response["data"].(map[string]any)["id"].(float64))
+
+			resp, _ = testUser2.MakeRequest(http.MethodPost, "/transaction", models.CreateBaseTransactionInput{
+				Name:      "QIF Export Transaction",
+				Amount:    floatPtr(42.50),
+				Date:      testDate,
+				AccountId: accountId,
+			})
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+			resp, body := testUser2.Download("/transaction/export/qif?account_id=" + strconv.FormatInt(accountId, 10) + "&date_from=2023-01-01&date_to=2023-01-31")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.Header.Get("Content-Disposition")).To(ContainSubstring("QIF Export Account.qif"))
+			Expect(string(body)).To(Equal("!Type:Bank\nD01/01/2023\nT-42.50\nPQIF Export Transaction\n^\n"))
+		})
+
+		It("should return error without an account id", func() {
+			resp, _ := testUser2.Download("/transaction/export/qif")
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return error for another user's account", func() {
+			resp, _ := testUser1.Download("/transaction/export/qif?account_id=3")
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
 	Describe("End to End cases", func() {
 		It("should create and manipulate new transactions without conflicting with seed data", func() {
 			input := models.CreateBaseTransactionInput{
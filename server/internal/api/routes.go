@@ -22,6 +22,8 @@ func Init(
 	ruleEngineService service.RuleEngineServiceInterface,
 	statementService service.StatementServiceInterface,
 	analyticsService service.AnalyticsServiceInterface,
+	parserTemplateService service.ParserTemplateServiceInterface,
+	appImportService service.AppImportServiceInterface,
 ) *gin.Engine {
 	router := gin.New()
 	if !cfg.IsTest() || cfg.LoggingLevel != "" {
@@ -32,7 +34,7 @@ func Init(
 		AllowOrigins:     []string{"http://localhost:3000", "https://neurospend.vercel.app"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition"},
 		AllowCredentials: true,
 		MaxAge:           6 * time.Hour,
 	}))
@@ -58,6 +60,8 @@ func Init(
 	ruleController := controller.NewRuleController(cfg, ruleService, ruleEngineService)
 	statementController := controller.NewStatementController(cfg, statementService)
 	analyticsController := controller.NewAnalyticsController(cfg, analyticsService)
+	parserTemplateController := controller.NewParserTemplateController(cfg, parserTemplateService)
+	appImportController := controller.NewAppImportController(cfg, appImportService)
 
 	api := router.Group("/api/v1")
 	{
@@ -102,6 +106,7 @@ func Init(
 		{
 			transaction.GET("", transactionController.ListTransactions)
 			transaction.POST("", transactionController.CreateTransaction)
+			transaction.GET("/export/qif", transactionController.ExportTransactionsQIF)
 			transaction.GET("/:transactionId", transactionController.GetTransaction)
 			transaction.PATCH("/:transactionId", transactionController.UpdateTransaction)
 			transaction.DELETE("/:transactionId", transactionController.DeleteTransaction)
@@ -113,7 +118,32 @@ func Init(
 			statement.POST("", statementController.CreateStatement)
 			statement.POST("/preview", statementController.PreviewStatement)
 			statement.GET("", statementController.GetStatements)
+			statement.GET("/dues", statementController.GetUpcomingCardDues)
+			statement.GET("/parsers", statementController.GetParsers)
 			statement.GET("/:id", statementController.GetStatementStatus)
+			statement.GET("/:id/errors", statementController.GetStatementErrors)
+			statement.GET("/:id/file", statementController.GetStatementFile)
+			statement.GET("/:id/events", statementController.StreamStatementEvents)
+			statement.POST("/:id/reprocess", statementController.ReprocessStatement)
+			statement.DELETE("/:id", statementController.RevertStatement)
+		}
+
+		// Routes for imports of other apps' exports
+		appImport := base.Group("/import", middleware.Protected(cfg))
+		{
+			appImport.POST("", appImportController.CreateAppImport)
+			appImport.GET("", appImportController.ListAppImports)
+			appImport.GET("/:importId", appImportController.GetAppImport)
+		}
+
+		// Parser template routes
+		parserTemplate := base.Group("/parser-template", middleware.ProtectedWithCreatedBy(cfg)...)
+		{
+			parserTemplate.GET("", parserTemplateController.ListParserTemplates)
+			parserTemplate.POST("", parserTemplateController.CreateParserTemplate)
+			parserTemplate.GET("/:templateId", parserTemplateController.GetParserTemplate)
+			parserTemplate.PATCH("/:templateId", parserTemplateController.UpdateParserTemplate)
+			parserTemplate.DELETE("/:templateId", parserTemplateController.DeleteParserTemplate)
 		}
 
 		// Rule routes
@@ -122,6 +152,8 @@ func Init(
 			rule.GET("", ruleController.ListRules)
 			rule.POST("", ruleController.CreateRule)
 			rule.POST("/execute", ruleController.ExecuteRules)
+			rule.POST("/preview", ruleController.PreviewRules)
+			rule.PUT("/order", ruleController.ReorderRules)
 			rule.GET("/:ruleId", ruleController.GetRuleById)
 			rule.PATCH("/:ruleId", ruleController.UpdateRule)
 			rule.DELETE("/:ruleId", ruleController.DeleteRule)
@@ -23,6 +23,12 @@ type Config struct {
 	RefreshTokenDuration time.Duration
 	CookieDomain         string
 	LoggingLevel         string
+	JobWorkers           int
+	JobMaxAttempts       int
+	JobPollInterval      time.Duration
+	JobVisibilityTimeout time.Duration
+	StorageBackend       string
+	StoragePath          string
 }
 
 func GetEnvironment() string {
@@ -55,6 +61,37 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("invalid REFRESH_TOKEN_DAYS: %w", err)
 	}
 	config.RefreshTokenDuration = time.Duration(refreshTokenDays) * 24 * time.Hour
+	config.JobWorkers, err = config.getEnvInt("JOB_WORKERS", 2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_WORKERS: %w", err)
+	}
+	config.JobMaxAttempts, err = config.getEnvInt("JOB_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_MAX_ATTEMPTS: %w", err)
+	}
+	jobPollSeconds, err := config.getEnvInt("JOB_POLL_INTERVAL_SECONDS", 2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_POLL_INTERVAL_SECONDS: %w", err)
+	}
+	config.JobPollInterval = time.Duration(jobPollSeconds) * time.Second
+	jobVisibilitySeconds, err := config.getEnvInt("JOB_VISIBILITY_TIMEOUT_SECONDS", 300)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_VISIBILITY_TIMEOUT_SECONDS: %w", err)
+	}
+	// Running jobs extend their lock every half timeout, which needs at least
+	// a whole second to tick on.
+	if jobVisibilitySeconds < 2 {
+		return nil, errors.New("invalid JOB_VISIBILITY_TIMEOUT_SECONDS: must be at least 2")
+	}
+	config.JobVisibilityTimeout = time.Duration(jobVisibilitySeconds) * time.Second
+	config.StorageBackend = strings.ToLower(os.Getenv("STORAGE_BACKEND"))
+	if config.StorageBackend == "" {
+		config.StorageBackend = "local"
+	}
+	config.StoragePath = os.Getenv("STORAGE_PATH")
+	if config.StoragePath == "" {
+		config.StoragePath = "data/files"
+	}
 	config.CookieDomain = os.Getenv("COOKIE_DOMAIN")
 	config.LoggingLevel = os.Getenv("LOGGING_LEVEL")
 	return config, nil
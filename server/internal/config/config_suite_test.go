@@ -24,6 +24,10 @@ var _ = Describe("Config", func() {
 		os.Unsetenv("DB_SCHEMA")
 		os.Unsetenv("ACCESS_TOKEN_HOURS")
 		os.Unsetenv("REFRESH_TOKEN_DAYS")
+		os.Unsetenv("JOB_WORKERS")
+		os.Unsetenv("JOB_MAX_ATTEMPTS")
+		os.Unsetenv("JOB_POLL_INTERVAL_SECONDS")
+		os.Unsetenv("JOB_VISIBILITY_TIMEOUT_SECONDS")
 	})
 
 	Context("when creating a new config", func() {
@@ -111,6 +115,33 @@ var _ = Describe("Config", func() {
 		})
 	})
 
+	Context("when job settings are invalid", func() {
+		BeforeEach(func() {
+			os.Setenv("JWT_SECRET", "test-secret")
+			os.Setenv("DB_SCHEMA", "test_schema")
+		})
+
+		DescribeTable("should return error for zero values",
+			func(key string) {
+				os.Setenv(key, "0")
+				_, err := NewConfig()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(key + " must be greater than 0"))
+			},
+			Entry("JOB_WORKERS", "JOB_WORKERS"),
+			Entry("JOB_MAX_ATTEMPTS", "JOB_MAX_ATTEMPTS"),
+			Entry("JOB_POLL_INTERVAL_SECONDS", "JOB_POLL_INTERVAL_SECONDS"),
+			Entry("JOB_VISIBILITY_TIMEOUT_SECONDS", "JOB_VISIBILITY_TIMEOUT_SECONDS"),
+		)
+
+		It("should return error for a visibility timeout below 2 seconds", func() {
+			os.Setenv("JOB_VISIBILITY_TIMEOUT_SECONDS", "1")
+			_, err := NewConfig()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("JOB_VISIBILITY_TIMEOUT_SECONDS: must be at least 2"))
+		})
+	})
+
 	Context("environment checks", func() {
 		BeforeEach(func() {
 			os.Setenv("JWT_SECRET", "test-secret")
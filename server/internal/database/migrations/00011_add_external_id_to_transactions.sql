@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE ${DB_SCHEMA}.transaction
+ADD COLUMN external_id VARCHAR(255) NULL;
+
+-- Identifier assigned by the bank (e.g. OFX FITID). Re-importing an overlapping
+-- statement must not create the same transaction twice on an account.
+CREATE UNIQUE INDEX idx_transaction_unique_external_id ON ${DB_SCHEMA}.transaction(
+    account_id,
+    external_id
+) WHERE deleted_at IS NULL AND external_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS ${DB_SCHEMA}.idx_transaction_unique_external_id;
+ALTER TABLE ${DB_SCHEMA}.transaction
+DROP COLUMN external_id;
+-- +goose StatementEnd
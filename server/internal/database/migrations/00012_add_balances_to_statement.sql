@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE ${DB_SCHEMA}.statement
+ADD COLUMN opening_balance DECIMAL(15, 2) NULL,
+ADD COLUMN closing_balance DECIMAL(15, 2) NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ${DB_SCHEMA}.statement
+DROP COLUMN opening_balance,
+DROP COLUMN closing_balance;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Parser format used for the statement, either detected from the file or
+-- taken from the upload request / account bank type.
+ALTER TABLE ${DB_SCHEMA}.statement
+ADD COLUMN detected_format VARCHAR(50) NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ${DB_SCHEMA}.statement
+DROP COLUMN detected_format;
+-- +goose StatementEnd
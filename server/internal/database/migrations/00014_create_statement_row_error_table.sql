@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Rows of a statement file that the parser could not import.
+CREATE TABLE IF NOT EXISTS ${DB_SCHEMA}.statement_row_error (
+    id SERIAL PRIMARY KEY,
+    statement_id INTEGER NOT NULL REFERENCES ${DB_SCHEMA}.statement(id) ON DELETE CASCADE,
+    line_number INTEGER NOT NULL,
+    raw_row TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_statement_row_error_statement_id
+ON ${DB_SCHEMA}.statement_row_error (statement_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ${DB_SCHEMA}.statement_row_error;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Transaction a rule created this one from (e.g. the counterpart of a
+-- transfer), so it can be removed together with its source.
+ALTER TABLE ${DB_SCHEMA}.transaction
+ADD COLUMN source_transaction_id INTEGER NULL REFERENCES ${DB_SCHEMA}.transaction(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transaction_source_transaction_id ON ${DB_SCHEMA}.transaction(source_transaction_id)
+WHERE source_transaction_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS ${DB_SCHEMA}.idx_transaction_source_transaction_id;
+ALTER TABLE ${DB_SCHEMA}.transaction
+DROP COLUMN source_transaction_id;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Outcome of the import: transactions inserted, skipped because they already
+-- existed, and rows that could not be parsed or inserted.
+ALTER TABLE ${DB_SCHEMA}.statement
+ADD COLUMN inserted_count INTEGER NULL,
+ADD COLUMN duplicate_count INTEGER NULL,
+ADD COLUMN failed_count INTEGER NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ${DB_SCHEMA}.statement
+DROP COLUMN inserted_count,
+DROP COLUMN duplicate_count,
+DROP COLUMN failed_count;
+-- +goose StatementEnd
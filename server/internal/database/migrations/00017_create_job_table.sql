@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Background work (statement parsing, rule execution) that has to survive
+-- restarts. Workers claim jobs with SELECT ... FOR UPDATE SKIP LOCKED and hold
+-- them until locked_until; jobs whose lock expires are picked up again.
+CREATE TABLE IF NOT EXISTS ${DB_SCHEMA}.job (
+    id BIGSERIAL PRIMARY KEY,
+    job_type VARCHAR(50) NOT NULL,
+    -- Row the job works on, e.g. the statement being parsed.
+    reference_id BIGINT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    run_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP NULL,
+    last_error TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_claim
+ON ${DB_SCHEMA}.job (run_at, id)
+WHERE status IN ('pending', 'running');
+
+CREATE INDEX IF NOT EXISTS idx_job_reference
+ON ${DB_SCHEMA}.job (job_type, reference_id)
+WHERE reference_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ${DB_SCHEMA}.job;
+-- +goose StatementEnd
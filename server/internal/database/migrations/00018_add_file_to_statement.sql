@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The uploaded file is kept in blob storage under file_key so the statement
+-- can be downloaded and reprocessed. bank_type and metadata are the parser
+-- options it was uploaded with.
+ALTER TABLE ${DB_SCHEMA}.statement
+ADD COLUMN content_hash VARCHAR(64) NULL,
+ADD COLUMN file_key VARCHAR(255) NULL,
+ADD COLUMN bank_type VARCHAR(50) NULL,
+ADD COLUMN metadata TEXT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ${DB_SCHEMA}.statement
+DROP COLUMN content_hash,
+DROP COLUMN file_key,
+DROP COLUMN bank_type,
+DROP COLUMN metadata;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A file can only be uploaded once per account. Uploads forced through
+-- anyway point to the earlier statement in duplicate_of and are not part of
+-- the unique index. Imports that failed or were reverted do not count, so the
+-- file can be uploaded again.
+ALTER TABLE ${DB_SCHEMA}.statement
+ADD COLUMN duplicate_of BIGINT NULL REFERENCES ${DB_SCHEMA}.statement(id);
+
+UPDATE ${DB_SCHEMA}.statement s
+SET duplicate_of = first.id
+FROM (
+    SELECT MIN(id) AS id, created_by, account_id, content_hash
+    FROM ${DB_SCHEMA}.statement
+    WHERE content_hash IS NOT NULL AND deleted_at IS NULL
+    AND status NOT IN ('error', 'reverted')
+    GROUP BY created_by, account_id, content_hash
+) first
+WHERE s.created_by = first.created_by
+AND s.account_id = first.account_id
+AND s.content_hash = first.content_hash
+AND s.deleted_at IS NULL
+AND s.id <> first.id;
+
+CREATE UNIQUE INDEX idx_statement_unique_content_hash
+ON ${DB_SCHEMA}.statement (created_by, account_id, content_hash)
+WHERE content_hash IS NOT NULL AND duplicate_of IS NULL AND deleted_at IS NULL
+AND status NOT IN ('error', 'reverted');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS ${DB_SCHEMA}.idx_statement_unique_content_hash;
+ALTER TABLE ${DB_SCHEMA}.statement
+DROP COLUMN duplicate_of;
+-- +goose StatementEnd
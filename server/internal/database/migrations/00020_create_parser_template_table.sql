@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Saved custom parser settings. A template bound to an account is used for
+-- statements uploaded to it without metadata.
+CREATE TABLE IF NOT EXISTS ${DB_SCHEMA}.parser_template (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    account_id INTEGER NULL REFERENCES ${DB_SCHEMA}.account(id) ON DELETE SET NULL,
+    metadata JSONB NOT NULL,
+    created_by INTEGER NOT NULL REFERENCES ${DB_SCHEMA}."user"(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_parser_template_name_created_by
+    ON ${DB_SCHEMA}.parser_template (name, created_by);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_parser_template_account_id
+    ON ${DB_SCHEMA}.parser_template (account_id)
+    WHERE account_id IS NOT NULL;
+
+CREATE TRIGGER update_parser_template_modtime
+BEFORE UPDATE ON ${DB_SCHEMA}.parser_template
+FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_parser_template_modtime ON ${DB_SCHEMA}.parser_template;
+DROP TABLE IF EXISTS ${DB_SCHEMA}.parser_template;
+-- +goose StatementEnd
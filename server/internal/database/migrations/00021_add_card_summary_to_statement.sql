@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Billing details printed on credit card statements. They stay NULL for bank
+-- account statements and for card statements that do not carry them.
+ALTER TABLE ${DB_SCHEMA}.statement
+ADD COLUMN statement_period_start DATE NULL,
+ADD COLUMN statement_period_end DATE NULL,
+ADD COLUMN total_due DECIMAL(15, 2) NULL,
+ADD COLUMN minimum_due DECIMAL(15, 2) NULL,
+ADD COLUMN payment_due_date DATE NULL;
+
+CREATE INDEX idx_statement_payment_due_date
+ON ${DB_SCHEMA}.statement (created_by, payment_due_date)
+WHERE payment_due_date IS NOT NULL AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS ${DB_SCHEMA}.idx_statement_payment_due_date;
+ALTER TABLE ${DB_SCHEMA}.statement
+DROP COLUMN statement_period_start,
+DROP COLUMN statement_period_end,
+DROP COLUMN total_due,
+DROP COLUMN minimum_due,
+DROP COLUMN payment_due_date;
+-- +goose StatementEnd
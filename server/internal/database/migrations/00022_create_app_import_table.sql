@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Imports of the exports of other personal finance apps. A single export can
+-- create accounts, categories and transactions, which are counted here.
+CREATE TABLE IF NOT EXISTS ${DB_SCHEMA}.app_import (
+    id SERIAL PRIMARY KEY,
+    source VARCHAR(50) NOT NULL, -- 'splitwise', 'ynab', 'money_manager', 'walnut'
+    original_filename VARCHAR(255) NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL, -- 'pending', 'processing', 'done', 'error'
+    message TEXT,
+    accounts_created INTEGER NOT NULL DEFAULT 0,
+    categories_created INTEGER NOT NULL DEFAULT 0,
+    inserted_count INTEGER NOT NULL DEFAULT 0,
+    duplicate_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    file_key VARCHAR(255) NOT NULL,
+    created_by INTEGER NOT NULL REFERENCES ${DB_SCHEMA}."user"(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_app_import_created_by
+    ON ${DB_SCHEMA}.app_import (created_by, created_at DESC);
+
+CREATE TRIGGER update_app_import_modtime
+BEFORE UPDATE ON ${DB_SCHEMA}.app_import
+FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS update_app_import_modtime ON ${DB_SCHEMA}.app_import;
+DROP TABLE IF EXISTS ${DB_SCHEMA}.app_import;
+-- +goose StatementEnd
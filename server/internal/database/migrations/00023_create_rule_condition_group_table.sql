@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Nested condition groups of a rule. Conditions without a group are combined
+-- with the top level groups by the condition logic of the rule.
+CREATE TABLE IF NOT EXISTS ${DB_SCHEMA}.rule_condition_group (
+    id SERIAL PRIMARY KEY,
+    rule_id INTEGER NOT NULL REFERENCES ${DB_SCHEMA}.rule(id) ON DELETE CASCADE,
+    parent_id INTEGER NULL REFERENCES ${DB_SCHEMA}.rule_condition_group(id) ON DELETE CASCADE,
+    logic VARCHAR(3) NOT NULL CHECK (logic IN ('AND', 'OR', 'NOT')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rule_condition_group_rule_id
+    ON ${DB_SCHEMA}.rule_condition_group (rule_id);
+
+ALTER TABLE ${DB_SCHEMA}.rule_condition
+ADD COLUMN group_id INTEGER NULL REFERENCES ${DB_SCHEMA}.rule_condition_group(id) ON DELETE CASCADE;
+
+CREATE TRIGGER update_rule_condition_group_modtime
+BEFORE UPDATE ON ${DB_SCHEMA}.rule_condition_group
+FOR EACH ROW
+EXECUTE FUNCTION update_modified_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ${DB_SCHEMA}.rule_condition
+DROP COLUMN IF EXISTS group_id;
+DROP TRIGGER IF EXISTS update_rule_condition_group_modtime ON ${DB_SCHEMA}.rule_condition_group;
+DROP TABLE IF EXISTS ${DB_SCHEMA}.rule_condition_group;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Rules run in ascending priority. Existing rules keep the order they were
+-- created in.
+ALTER TABLE ${DB_SCHEMA}.rule
+ADD COLUMN priority INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN stop_processing BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE ${DB_SCHEMA}.rule r
+SET priority = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY created_by ORDER BY id) AS position
+    FROM ${DB_SCHEMA}.rule
+) ordered
+WHERE r.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_rule_created_by_priority
+    ON ${DB_SCHEMA}.rule (created_by, priority);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS ${DB_SCHEMA}.idx_rule_created_by_priority;
+ALTER TABLE ${DB_SCHEMA}.rule
+DROP COLUMN stop_processing,
+DROP COLUMN priority;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+package errors
+
+import "net/http"
+
+func NewAppImportNotFoundError(err error) *AuthError {
+	return formatError(http.StatusNotFound, "import not found", err, "AppImportNotFound")
+}
+
+func NewAppImportBadRequestError(err error) *AuthError {
+	return formatError(http.StatusBadRequest, "invalid import request", err, "AppImportBadRequest")
+}
+
+func NewAppImportFileStoreError(err error) *AuthError {
+	return formatError(http.StatusInternalServerError, "failed to store import file", err, "AppImportFileStoreError")
+}
@@ -14,6 +14,7 @@ type AuthError struct {
 	Stack     []string // Stack trace
 	ErrorType string   // Type of error (e.g., "InvalidCredentials", "TokenGeneration")
 	Status    int      // HTTP status code
+	Data      any      // Optional details returned to the client
 }
 
 func (e *AuthError) Error() string {
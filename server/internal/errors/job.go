@@ -0,0 +1,15 @@
+package errors
+
+import "net/http"
+
+func NewJobCreateError(err error) *AuthError {
+	return formatError(http.StatusInternalServerError, "failed to queue job", err, "JobCreateError")
+}
+
+func NewJobGetError(err error) *AuthError {
+	return formatError(http.StatusInternalServerError, "failed to get job", err, "JobGetError")
+}
+
+func NewJobUpdateError(err error) *AuthError {
+	return formatError(http.StatusInternalServerError, "failed to update job", err, "JobUpdateError")
+}
@@ -0,0 +1,19 @@
+package errors
+
+import "net/http"
+
+func NewParserTemplateNotFoundError(err error) *AuthError {
+	return formatError(http.StatusNotFound, "parser template not found", err, "ParserTemplateNotFound")
+}
+
+func NewParserTemplateAlreadyExistsError(err error) *AuthError {
+	return formatError(http.StatusConflict, "parser template with this name already exists for this user", err, "ParserTemplateAlreadyExists")
+}
+
+func NewParserTemplateAccountTakenError(err error) *AuthError {
+	return formatError(http.StatusConflict, "account already has a parser template", err, "ParserTemplateAccountTaken")
+}
+
+func NewParserTemplateInvalidMetadataError(err error) *AuthError {
+	return formatError(http.StatusBadRequest, "invalid parser template metadata", err, "ParserTemplateInvalidMetadata")
+}
@@ -29,6 +29,18 @@ func NewRuleNoConditionsError(err error) *AuthError {
 	return formatError(http.StatusBadRequest, "a rule must have at least one condition.", err, "RuleNoConditions")
 }
 
+func NewRuleInvalidConditionGroupError(err error) *AuthError {
+	return formatError(http.StatusBadRequest, fmt.Sprintf("the condition group is invalid: %v", err), err, "InvalidConditionGroup")
+}
+
+func NewRuleInvalidOrderError(err error) *AuthError {
+	return formatError(http.StatusBadRequest, fmt.Sprintf("the rule order is invalid: %v", err), err, "InvalidRuleOrder")
+}
+
+func NewRuleInvalidPreviewError(err error) *AuthError {
+	return formatError(http.StatusBadRequest, fmt.Sprintf("the rule preview request is invalid: %v", err), err, "InvalidRulePreview")
+}
+
 func NewRuleInvalidActionTypeError(err error) *AuthError {
 	return formatError(http.StatusBadRequest, "the provided action type is invalid.", err, "InvalidActionType")
 }
@@ -22,6 +22,30 @@ func NewStatementBadRequestError(err error) *AuthError {
 	return formatError(http.StatusBadRequest, "invalid request", err, "StatementBadRequestError")
 }
 
+func NewStatementStillProcessingError(err error) *AuthError {
+	return formatError(http.StatusConflict, "statement is still being processed", err, "StatementStillProcessing")
+}
+
+func NewStatementAlreadyRevertedError(err error) *AuthError {
+	return formatError(http.StatusConflict, "statement is already reverted", err, "StatementAlreadyReverted")
+}
+
 func NewStatementPasswordRequiredError(err error) *AuthError {
 	return formatError(http.StatusBadRequest, "statement password required", err, "StatementPasswordRequired")
 }
+
+func NewStatementFileNotFoundError(err error) *AuthError {
+	return formatError(http.StatusNotFound, "statement file not found", err, "StatementFileNotFound")
+}
+
+func NewStatementFileStoreError(err error) *AuthError {
+	return formatError(http.StatusInternalServerError, "failed to store statement file", err, "StatementFileStoreError")
+}
+
+// NewStatementAlreadyUploadedError reports an upload of a file that was
+// already imported into the account. existing is the earlier statement.
+func NewStatementAlreadyUploadedError(err error, existing any) *AuthError {
+	authErr := formatError(http.StatusConflict, "statement file was already uploaded", err, "StatementAlreadyUploaded")
+	authErr.Data = existing
+	return authErr
+}
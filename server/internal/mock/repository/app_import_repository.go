@@ -0,0 +1,98 @@
+package mock_repository
+
+import (
+	"context"
+	"errors"
+	customErrors "expenses/internal/errors"
+	"expenses/internal/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+type MockAppImportRepository struct {
+	imports map[int64]models.AppImportResponse
+	nextId  int64
+	mu      sync.RWMutex
+}
+
+func NewMockAppImportRepository() *MockAppImportRepository {
+	return &MockAppImportRepository{
+		imports: make(map[int64]models.AppImportResponse),
+		nextId:  1,
+	}
+}
+
+func (m *MockAppImportRepository) CreateAppImport(ctx context.Context, input models.CreateAppImportInput) (models.AppImportResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	appImport := models.AppImportResponse{
+		Id:               m.nextId,
+		Source:           input.Source,
+		OriginalFilename: input.OriginalFilename,
+		Currency:         input.Currency,
+		Status:           input.Status,
+		FileKey:          input.FileKey,
+		CreatedBy:        input.CreatedBy,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	m.imports[m.nextId] = appImport
+	m.nextId++
+	return appImport, nil
+}
+
+func (m *MockAppImportRepository) GetAppImportById(ctx context.Context, importId int64, userId int64) (models.AppImportResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	appImport, ok := m.imports[importId]
+	if !ok || appImport.CreatedBy != userId {
+		return models.AppImportResponse{}, customErrors.NewAppImportNotFoundError(errors.New("import not found"))
+	}
+	return appImport, nil
+}
+
+func (m *MockAppImportRepository) ListAppImports(ctx context.Context, userId int64) ([]models.AppImportResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	appImports := make([]models.AppImportResponse, 0)
+	for _, appImport := range m.imports {
+		if appImport.CreatedBy == userId {
+			appImports = append(appImports, appImport)
+		}
+	}
+	sort.Slice(appImports, func(i, j int) bool { return appImports[i].Id > appImports[j].Id })
+	return appImports, nil
+}
+
+func (m *MockAppImportRepository) UpdateAppImport(ctx context.Context, importId int64, input models.UpdateAppImportInput) (models.AppImportResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	appImport, ok := m.imports[importId]
+	if !ok {
+		return models.AppImportResponse{}, customErrors.NewAppImportNotFoundError(errors.New("import not found"))
+	}
+	appImport.Status = input.Status
+	if input.Message != nil {
+		appImport.Message = input.Message
+	}
+	if input.AccountsCreated != nil {
+		appImport.AccountsCreated = *input.AccountsCreated
+	}
+	if input.CategoriesCreated != nil {
+		appImport.CategoriesCreated = *input.CategoriesCreated
+	}
+	if input.InsertedCount != nil {
+		appImport.InsertedCount = *input.InsertedCount
+	}
+	if input.DuplicateCount != nil {
+		appImport.DuplicateCount = *input.DuplicateCount
+	}
+	if input.FailedCount != nil {
+		appImport.FailedCount = *input.FailedCount
+	}
+	appImport.UpdatedAt = time.Now()
+	m.imports[importId] = appImport
+	return appImport, nil
+}
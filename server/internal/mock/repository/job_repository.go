@@ -0,0 +1,153 @@
+package mock_repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"expenses/internal/models"
+)
+
+type MockJobRepository struct {
+	mu          sync.Mutex
+	jobs        map[int64]models.Job
+	nextId      int64
+	createError error
+}
+
+func NewMockJobRepository() *MockJobRepository {
+	return &MockJobRepository{
+		jobs:   make(map[int64]models.Job),
+		nextId: 1,
+	}
+}
+
+func (m *MockJobRepository) CreateJob(ctx context.Context, input models.CreateJobInput) (models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.createError != nil {
+		return models.Job{}, m.createError
+	}
+	now := time.Now()
+	job := models.Job{
+		Id:          m.nextId,
+		JobType:     input.JobType,
+		ReferenceId: input.ReferenceId,
+		Payload:     input.Payload,
+		Status:      models.JobStatusPending,
+		MaxAttempts: input.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	m.jobs[job.Id] = job
+	m.nextId++
+	return job, nil
+}
+
+func (m *MockJobRepository) ClaimJob(ctx context.Context, jobTypes []models.JobType, visibilityTimeout time.Duration) (*models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+
+	ids := make([]int64, 0, len(m.jobs))
+	for id := range m.jobs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		job := m.jobs[id]
+		if !containsJobType(jobTypes, job.JobType) {
+			continue
+		}
+		due := job.Status == models.JobStatusPending && !job.RunAt.After(now)
+		expired := job.Status == models.JobStatusRunning && job.LockedUntil != nil && job.LockedUntil.Before(now)
+		if !due && !expired {
+			continue
+		}
+		lockedUntil := now.Add(visibilityTimeout)
+		job.Status = models.JobStatusRunning
+		job.Attempts++
+		job.LockedUntil = &lockedUntil
+		job.UpdatedAt = now
+		m.jobs[id] = job
+		return &job, nil
+	}
+	return nil, nil
+}
+
+func (m *MockJobRepository) ExtendJobLock(ctx context.Context, jobId int64, visibilityTimeout time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[jobId]
+	if !ok {
+		return errors.New("job not found")
+	}
+	lockedUntil := time.Now().Add(visibilityTimeout)
+	job.LockedUntil = &lockedUntil
+	m.jobs[jobId] = job
+	return nil
+}
+
+func (m *MockJobRepository) CompleteJob(ctx context.Context, jobId int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.jobs, jobId)
+	return nil
+}
+
+func (m *MockJobRepository) RetryJob(ctx context.Context, jobId int64, runAt time.Time, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[jobId]
+	if !ok {
+		return errors.New("job not found")
+	}
+	job.Status = models.JobStatusPending
+	job.RunAt = runAt
+	job.LockedUntil = nil
+	job.LastError = &lastError
+	m.jobs[jobId] = job
+	return nil
+}
+
+func (m *MockJobRepository) FailJob(ctx context.Context, jobId int64, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[jobId]
+	if !ok {
+		return errors.New("job not found")
+	}
+	job.Status = models.JobStatusFailed
+	job.LockedUntil = nil
+	job.LastError = &lastError
+	m.jobs[jobId] = job
+	return nil
+}
+
+// GetJob returns a job for assertions in tests.
+func (m *MockJobRepository) GetJob(jobId int64) (models.Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[jobId]
+	return job, ok
+}
+
+func containsJobType(jobTypes []models.JobType, jobType models.JobType) bool {
+	for _, t := range jobTypes {
+		if t == jobType {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCreateError sets the error to return from CreateJob.
+func (m *MockJobRepository) SetCreateError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.createError = err
+}
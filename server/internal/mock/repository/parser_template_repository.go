@@ -0,0 +1,137 @@
+package mock_repository
+
+import (
+	"context"
+	"errors"
+	customErrors "expenses/internal/errors"
+	"expenses/internal/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+type MockParserTemplateRepository struct {
+	templates map[int64]models.ParserTemplateResponse
+	nextId    int64
+	mu        sync.RWMutex
+}
+
+func NewMockParserTemplateRepository() *MockParserTemplateRepository {
+	return &MockParserTemplateRepository{
+		templates: make(map[int64]models.ParserTemplateResponse),
+		nextId:    1,
+	}
+}
+
+func (m *MockParserTemplateRepository) CreateParserTemplate(ctx context.Context, input models.CreateParserTemplateInput) (models.ParserTemplateResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkUnique(0, input.Name, input.AccountId, input.CreatedBy); err != nil {
+		return models.ParserTemplateResponse{}, err
+	}
+
+	now := time.Now()
+	template := models.ParserTemplateResponse{
+		Id:        m.nextId,
+		Name:      input.Name,
+		AccountId: input.AccountId,
+		Metadata:  input.Metadata,
+		CreatedBy: input.CreatedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	m.templates[m.nextId] = template
+	m.nextId++
+	return template, nil
+}
+
+func (m *MockParserTemplateRepository) GetParserTemplateById(ctx context.Context, templateId int64, userId int64) (models.ParserTemplateResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	template, ok := m.templates[templateId]
+	if !ok || template.CreatedBy != userId {
+		return models.ParserTemplateResponse{}, customErrors.NewParserTemplateNotFoundError(errors.New("parser template not found"))
+	}
+	return template, nil
+}
+
+func (m *MockParserTemplateRepository) GetParserTemplateByAccountId(ctx context.Context, accountId int64, userId int64) (*models.ParserTemplateResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, template := range m.templates {
+		if template.CreatedBy == userId && template.AccountId != nil && *template.AccountId == accountId {
+			return &template, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockParserTemplateRepository) ListParserTemplates(ctx context.Context, userId int64) ([]models.ParserTemplateResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	templates := make([]models.ParserTemplateResponse, 0)
+	for _, template := range m.templates {
+		if template.CreatedBy == userId {
+			templates = append(templates, template)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+func (m *MockParserTemplateRepository) UpdateParserTemplate(ctx context.Context, templateId int64, userId int64, input models.UpdateParserTemplateInput) (models.ParserTemplateResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	template, ok := m.templates[templateId]
+	if !ok || template.CreatedBy != userId {
+		return models.ParserTemplateResponse{}, customErrors.NewParserTemplateNotFoundError(errors.New("parser template not found"))
+	}
+
+	if input.Name != nil {
+		template.Name = *input.Name
+	}
+	if input.Metadata != nil {
+		template.Metadata = *input.Metadata
+	}
+	if input.AccountId != nil {
+		if *input.AccountId == 0 {
+			template.AccountId = nil
+		} else {
+			accountId := *input.AccountId
+			template.AccountId = &accountId
+		}
+	}
+	if err := m.checkUnique(templateId, template.Name, template.AccountId, userId); err != nil {
+		return models.ParserTemplateResponse{}, err
+	}
+	template.UpdatedAt = time.Now()
+	m.templates[templateId] = template
+	return template, nil
+}
+
+func (m *MockParserTemplateRepository) DeleteParserTemplate(ctx context.Context, templateId int64, userId int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	template, ok := m.templates[templateId]
+	if !ok || template.CreatedBy != userId {
+		return customErrors.NewParserTemplateNotFoundError(errors.New("parser template not found"))
+	}
+	delete(m.templates, templateId)
+	return nil
+}
+
+// checkUnique mirrors the unique indexes on the template name and account.
+func (m *MockParserTemplateRepository) checkUnique(templateId int64, name string, accountId *int64, userId int64) error {
+	for id, template := range m.templates {
+		if id == templateId {
+			continue
+		}
+		if template.CreatedBy == userId && template.Name == name {
+			return customErrors.NewParserTemplateAlreadyExistsError(errors.New("duplicate name"))
+		}
+		if accountId != nil && template.AccountId != nil && *template.AccountId == *accountId {
+			return customErrors.NewParserTemplateAccountTakenError(errors.New("duplicate account"))
+		}
+	}
+	return nil
+}
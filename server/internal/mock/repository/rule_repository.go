@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -15,10 +16,12 @@ type MockRuleRepository struct {
 	rules           map[int64]models.RuleResponse
 	actions         map[int64]models.RuleActionResponse
 	conditions      map[int64]models.RuleConditionResponse
+	groups          map[int64]models.RuleConditionGroupResponse
 	mappings        map[string]bool // key: "ruleId:transactionId"
 	nextRuleId      int64
 	nextActionId    int64
 	nextConditionId int64
+	nextGroupId     int64
 }
 
 func NewMockRuleRepository() *MockRuleRepository {
@@ -26,10 +29,12 @@ func NewMockRuleRepository() *MockRuleRepository {
 		rules:           make(map[int64]models.RuleResponse),
 		actions:         make(map[int64]models.RuleActionResponse),
 		conditions:      make(map[int64]models.RuleConditionResponse),
+		groups:          make(map[int64]models.RuleConditionGroupResponse),
 		mappings:        make(map[string]bool),
 		nextRuleId:      1,
 		nextActionId:    1,
 		nextConditionId: 1,
+		nextGroupId:     1,
 	}
 }
 
@@ -37,11 +42,13 @@ func (m *MockRuleRepository) CreateRule(ctx context.Context, req models.CreateBa
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	rule := models.RuleResponse{
-		Id:            m.nextRuleId,
-		Name:          req.Name,
-		Description:   req.Description,
-		EffectiveFrom: req.EffectiveFrom,
-		CreatedBy:     req.CreatedBy,
+		Id:             m.nextRuleId,
+		Name:           req.Name,
+		Description:    req.Description,
+		EffectiveFrom:  req.EffectiveFrom,
+		Priority:       req.Priority,
+		StopProcessing: req.StopProcessing,
+		CreatedBy:      req.CreatedBy,
 	}
 	m.rules[m.nextRuleId] = rule
 	m.nextRuleId++
@@ -77,6 +84,7 @@ func (m *MockRuleRepository) CreateRuleConditions(ctx context.Context, condition
 			ConditionType:     cond.ConditionType,
 			ConditionValue:    cond.ConditionValue,
 			ConditionOperator: cond.ConditionOperator,
+			GroupId:           cond.GroupId,
 		}
 		m.conditions[m.nextConditionId] = condition
 		result = append(result, condition)
@@ -85,6 +93,63 @@ func (m *MockRuleRepository) CreateRuleConditions(ctx context.Context, condition
 	return result, nil
 }
 
+// createGroup stores a group with its conditions and nested groups. The caller
+// must hold the lock.
+func (m *MockRuleRepository) createGroup(ruleId int64, parentId *int64, req models.CreateRuleConditionGroupRequest) models.RuleConditionGroupResponse {
+	group := models.RuleConditionGroupResponse{
+		Id:       m.nextGroupId,
+		RuleId:   ruleId,
+		ParentId: parentId,
+		Logic:    req.Logic,
+	}
+	m.groups[group.Id] = group
+	m.nextGroupId++
+
+	groupId := group.Id
+	group.Conditions = []models.RuleConditionResponse{}
+	for _, c := range req.Conditions {
+		condition := models.RuleConditionResponse{
+			Id:                m.nextConditionId,
+			RuleId:            ruleId,
+			ConditionType:     c.ConditionType,
+			ConditionValue:    c.ConditionValue,
+			ConditionOperator: c.ConditionOperator,
+			GroupId:           &groupId,
+		}
+		m.conditions[m.nextConditionId] = condition
+		group.Conditions = append(group.Conditions, condition)
+		m.nextConditionId++
+	}
+	group.Groups = []models.RuleConditionGroupResponse{}
+	for _, child := range req.Groups {
+		group.Groups = append(group.Groups, m.createGroup(ruleId, &groupId, child))
+	}
+	return group
+}
+
+func (m *MockRuleRepository) CreateRuleConditionGroups(ctx context.Context, ruleId int64, groups []models.CreateRuleConditionGroupRequest) ([]models.RuleConditionGroupResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := []models.RuleConditionGroupResponse{}
+	for _, g := range groups {
+		result = append(result, m.createGroup(ruleId, nil, g))
+	}
+	return result, nil
+}
+
+func (m *MockRuleRepository) ListRuleConditionGroupsByRuleId(ctx context.Context, ruleId int64) ([]models.RuleConditionGroupResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := []models.RuleConditionGroupResponse{}
+	for _, group := range m.groups {
+		if group.RuleId == ruleId {
+			result = append(result, group)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Id < result[j].Id })
+	return result, nil
+}
+
 func (m *MockRuleRepository) GetRule(ctx context.Context, id int64, userId int64) (models.RuleResponse, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -118,6 +183,13 @@ func (m *MockRuleRepository) ListRules(ctx context.Context, userId int64, query
 		filteredRules = append(filteredRules, rule)
 	}
 
+	sort.Slice(filteredRules, func(i, j int) bool {
+		if filteredRules[i].Priority != filteredRules[j].Priority {
+			return filteredRules[i].Priority < filteredRules[j].Priority
+		}
+		return filteredRules[i].Id < filteredRules[j].Id
+	})
+
 	total := len(filteredRules)
 
 	// If no pagination specified (PageSize <= 0), return all results
@@ -155,6 +227,52 @@ func (m *MockRuleRepository) ListRules(ctx context.Context, userId int64, query
 	}, nil
 }
 
+func (m *MockRuleRepository) GetNextRulePriority(ctx context.Context, userId int64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	priority := 0
+	for _, rule := range m.rules {
+		if rule.CreatedBy == userId && rule.Priority > priority {
+			priority = rule.Priority
+		}
+	}
+	return priority + 1, nil
+}
+
+func (m *MockRuleRepository) ReorderRules(ctx context.Context, userId int64, ruleIds []int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	position := make(map[int64]int, len(ruleIds))
+	for i, id := range ruleIds {
+		position[id] = i
+	}
+	var rules []models.RuleResponse
+	for _, rule := range m.rules {
+		if rule.CreatedBy == userId {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		pi, iListed := position[rules[i].Id]
+		pj, jListed := position[rules[j].Id]
+		if iListed != jListed {
+			return iListed
+		}
+		if iListed {
+			return pi < pj
+		}
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].Id < rules[j].Id
+	})
+	for i, rule := range rules {
+		rule.Priority = i + 1
+		m.rules[rule.Id] = rule
+	}
+	return nil
+}
+
 func (m *MockRuleRepository) ListRuleActionsByRuleId(ctx context.Context, ruleId int64) ([]models.RuleActionResponse, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -195,6 +313,9 @@ func (m *MockRuleRepository) UpdateRule(ctx context.Context, id int64, userId in
 	if req.EffectiveFrom != nil {
 		rule.EffectiveFrom = *req.EffectiveFrom
 	}
+	if req.StopProcessing != nil {
+		rule.StopProcessing = *req.StopProcessing
+	}
 	m.rules[id] = rule
 	return rule, nil
 }
@@ -277,6 +398,11 @@ func (m *MockRuleRepository) DeleteRule(ctx context.Context, id int64, userId in
 			delete(m.conditions, cid)
 		}
 	}
+	for gid, group := range m.groups {
+		if group.RuleId == id {
+			delete(m.groups, gid)
+		}
+	}
 	return nil
 }
 
@@ -315,16 +441,21 @@ func (m *MockRuleRepository) PutRuleActions(ctx context.Context, ruleId int64, a
 	return result, nil
 }
 
-func (m *MockRuleRepository) PutRuleConditions(ctx context.Context, ruleId int64, conditions []models.CreateRuleConditionRequest) ([]models.RuleConditionResponse, error) {
+func (m *MockRuleRepository) PutRuleConditions(ctx context.Context, ruleId int64, conditions []models.CreateRuleConditionRequest, groups []models.CreateRuleConditionGroupRequest) ([]models.RuleConditionResponse, []models.RuleConditionGroupResponse, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Delete existing conditions for this rule
+	// Delete existing conditions and groups for this rule
 	for id, cond := range m.conditions {
 		if cond.RuleId == ruleId {
 			delete(m.conditions, id)
 		}
 	}
+	for id, group := range m.groups {
+		if group.RuleId == ruleId {
+			delete(m.groups, id)
+		}
+	}
 
 	// Create new conditions
 	var result []models.RuleConditionResponse
@@ -340,5 +471,10 @@ func (m *MockRuleRepository) PutRuleConditions(ctx context.Context, ruleId int64
 		result = append(result, condition)
 		m.nextConditionId++
 	}
-	return result, nil
+
+	groupResult := []models.RuleConditionGroupResponse{}
+	for _, g := range groups {
+		groupResult = append(groupResult, m.createGroup(ruleId, nil, g))
+	}
+	return result, groupResult, nil
 }
@@ -3,8 +3,11 @@ package mock_repository
 import (
 	"context"
 	"errors"
+	customErrors "expenses/internal/errors"
 	"expenses/internal/models"
+	"sort"
 	"sync"
+	"time"
 )
 
 type MockStatementRepository struct {
@@ -12,6 +15,10 @@ type MockStatementRepository struct {
 	nextId               int64
 	mu                   sync.RWMutex
 	statementTxnMappings []statementTxnMapping
+	rowErrors            []models.StatementRowErrorResponse
+	nextRowErrorId       int64
+	txnRepo              *MockTransactionRepository
+	linkError            error
 }
 
 func NewMockStatementRepository() *MockStatementRepository {
@@ -19,6 +26,7 @@ func NewMockStatementRepository() *MockStatementRepository {
 		statements:           make(map[int64]models.StatementResponse),
 		nextId:               1,
 		statementTxnMappings: []statementTxnMapping{},
+		nextRowErrorId:       1,
 	}
 }
 
@@ -30,11 +38,16 @@ func (m *MockStatementRepository) CreateStatement(ctx context.Context, input mod
 		return models.StatementResponse{}, errors.New("filename cannot be empty")
 	}
 	fileType := input.FileType
-	if fileType != "csv" && fileType != "excel" {
+	if fileType != "csv" && fileType != "excel" && fileType != "ofx" && fileType != "pdf" && fileType != "xml" && fileType != "mt940" && fileType != "qif" {
 		return models.StatementResponse{}, errors.New("invalid file type")
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if input.ContentHash != nil && input.DuplicateOf == nil {
+		if _, ok := m.findByContentHash(input.CreatedBy, input.AccountId, *input.ContentHash); ok {
+			return models.StatementResponse{}, customErrors.NewStatementAlreadyUploadedError(errors.New("duplicate content hash"), nil)
+		}
+	}
 	id := m.nextId
 	m.nextId++
 	statement := models.StatementResponse{
@@ -45,6 +58,11 @@ func (m *MockStatementRepository) CreateStatement(ctx context.Context, input mod
 		FileType:         input.FileType,
 		Status:           input.Status,
 		Message:          input.Message,
+		ContentHash:      input.ContentHash,
+		FileKey:          input.FileKey,
+		BankType:         input.BankType,
+		Metadata:         input.Metadata,
+		DuplicateOf:      input.DuplicateOf,
 	}
 	m.statements[id] = statement
 	return statement, nil
@@ -65,6 +83,9 @@ func (m *MockStatementRepository) CreateStatementTxn(ctx context.Context, statem
 func (m *MockStatementRepository) CreateStatementTxns(ctx context.Context, statementId int64, transactionIds []int64) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if m.linkError != nil {
+		return m.linkError
+	}
 	for _, txId := range transactionIds {
 		m.statementTxnMappings = append(m.statementTxnMappings, statementTxnMapping{
 			StatementId:   statementId,
@@ -83,10 +104,88 @@ func (m *MockStatementRepository) UpdateStatementStatus(ctx context.Context, sta
 	}
 	statement.Status = input.Status
 	statement.Message = input.Message
+	if input.OpeningBalance != nil {
+		statement.OpeningBalance = input.OpeningBalance
+	}
+	if input.ClosingBalance != nil {
+		statement.ClosingBalance = input.ClosingBalance
+	}
+	if input.DetectedFormat != nil {
+		statement.DetectedFormat = input.DetectedFormat
+	}
+	if input.InsertedCount != nil {
+		statement.InsertedCount = input.InsertedCount
+	}
+	if input.DuplicateCount != nil {
+		statement.DuplicateCount = input.DuplicateCount
+	}
+	if input.FailedCount != nil {
+		statement.FailedCount = input.FailedCount
+	}
+	if input.BankType != nil {
+		statement.BankType = input.BankType
+	}
+	if input.Metadata != nil {
+		statement.Metadata = input.Metadata
+	}
+	if input.StatementPeriodStart != nil {
+		statement.StatementPeriodStart = input.StatementPeriodStart
+	}
+	if input.StatementPeriodEnd != nil {
+		statement.StatementPeriodEnd = input.StatementPeriodEnd
+	}
+	if input.TotalDue != nil {
+		statement.TotalDue = input.TotalDue
+	}
+	if input.MinimumDue != nil {
+		statement.MinimumDue = input.MinimumDue
+	}
+	if input.PaymentDueDate != nil {
+		statement.PaymentDueDate = input.PaymentDueDate
+	}
 	m.statements[statementId] = statement
 	return statement, nil
 }
 
+func (m *MockStatementRepository) ResetStatementImport(ctx context.Context, statementId int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mappings := m.statementTxnMappings[:0]
+	for _, mapping := range m.statementTxnMappings {
+		if mapping.StatementId != statementId {
+			mappings = append(mappings, mapping)
+		}
+	}
+	m.statementTxnMappings = mappings
+	rowErrors := m.rowErrors[:0]
+	for _, rowError := range m.rowErrors {
+		if rowError.StatementId != statementId {
+			rowErrors = append(rowErrors, rowError)
+		}
+	}
+	m.rowErrors = rowErrors
+	return nil
+}
+
+// FailStaleStatements fails every pending or processing statement, since the
+// mock does not track parse jobs.
+func (m *MockStatementRepository) FailStaleStatements(ctx context.Context, message string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int64
+	for id, statement := range m.statements {
+		if statement.Status != models.StatementStatusPending && statement.Status != models.StatementStatusProcessing {
+			continue
+		}
+		msg := message
+		statement.Status = models.StatementStatusError
+		statement.Message = &msg
+		m.statements[id] = statement
+		count++
+	}
+	return count, nil
+}
+
 func (m *MockStatementRepository) GetStatementByID(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -100,6 +199,54 @@ func (m *MockStatementRepository) GetStatementByID(ctx context.Context, statemen
 	return statement, nil
 }
 
+func (m *MockStatementRepository) GetStatementByContentHash(ctx context.Context, userId int64, accountId int64, contentHash string) (*models.StatementResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	statement, ok := m.findByContentHash(userId, accountId, contentHash)
+	if !ok {
+		return nil, nil
+	}
+	return &statement, nil
+}
+
+func (m *MockStatementRepository) findByContentHash(userId int64, accountId int64, contentHash string) (models.StatementResponse, bool) {
+	for _, statement := range m.statements {
+		if statement.CreatedBy == userId && statement.AccountId == accountId && statement.DuplicateOf == nil &&
+			statement.Status != models.StatementStatusError && statement.Status != models.StatementStatusReverted &&
+			statement.ContentHash != nil && *statement.ContentHash == contentHash {
+			return statement, true
+		}
+	}
+	return models.StatementResponse{}, false
+}
+
+func (m *MockStatementRepository) ListUpcomingCardStatements(ctx context.Context, userId int64, dueFrom time.Time) ([]models.StatementResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	latest := make(map[int64]models.StatementResponse)
+	for _, s := range m.statements {
+		if s.CreatedBy != userId || s.Status != models.StatementStatusDone || s.PaymentDueDate == nil || s.PaymentDueDate.Before(dueFrom) {
+			continue
+		}
+		current, ok := latest[s.AccountId]
+		if !ok || s.PaymentDueDate.After(*current.PaymentDueDate) ||
+			(s.PaymentDueDate.Equal(*current.PaymentDueDate) && s.Id > current.Id) {
+			latest[s.AccountId] = s
+		}
+	}
+	result := make([]models.StatementResponse, 0, len(latest))
+	for _, s := range latest {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].PaymentDueDate.Equal(*result[j].PaymentDueDate) {
+			return result[i].PaymentDueDate.Before(*result[j].PaymentDueDate)
+		}
+		return result[i].AccountId < result[j].AccountId
+	})
+	return result, nil
+}
+
 func (m *MockStatementRepository) ListStatementByUserId(ctx context.Context, userId int64, limit, offset int, query models.StatementListQuery) ([]models.StatementResponse, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -181,3 +328,132 @@ func (m *MockStatementRepository) CountStatementsByUserId(ctx context.Context, u
 	}
 	return count, nil
 }
+
+func (m *MockStatementRepository) CreateStatementRowErrors(ctx context.Context, statementId int64, rowErrors []models.StatementRowError) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, rowError := range rowErrors {
+		m.rowErrors = append(m.rowErrors, models.StatementRowErrorResponse{
+			Id:          m.nextRowErrorId,
+			StatementId: statementId,
+			LineNumber:  rowError.LineNumber,
+			RawRow:      rowError.RawRow,
+			Reason:      rowError.Reason,
+			CreatedAt:   time.Now(),
+		})
+		m.nextRowErrorId++
+	}
+	return nil
+}
+
+func (m *MockStatementRepository) ListStatementRowErrors(ctx context.Context, statementId int64) ([]models.StatementRowErrorResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := []models.StatementRowErrorResponse{}
+	for _, rowError := range m.rowErrors {
+		if rowError.StatementId == statementId {
+			result = append(result, rowError)
+		}
+	}
+	return result, nil
+}
+
+// SetTransactionRepository sets the transactions ListUnlinkedTransactions
+// looks through.
+func (m *MockStatementRepository) SetTransactionRepository(txnRepo *MockTransactionRepository) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.txnRepo = txnRepo
+}
+
+// SetLinkError sets the error to return from CreateStatementTxns.
+func (m *MockStatementRepository) SetLinkError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.linkError = err
+}
+
+// ListUnlinkedTransactions ignores since, as the mock does not record when
+// transactions were created.
+func (m *MockStatementRepository) ListUnlinkedTransactions(ctx context.Context, accountId int64, userId int64, since time.Time) ([]models.TransactionBaseResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := []models.TransactionBaseResponse{}
+	if m.txnRepo == nil {
+		return result, nil
+	}
+	linked := make(map[int64]bool, len(m.statementTxnMappings))
+	for _, mapping := range m.statementTxnMappings {
+		linked[mapping.TransactionId] = true
+	}
+	m.txnRepo.mu.RLock()
+	defer m.txnRepo.mu.RUnlock()
+	for id, txn := range m.txnRepo.transactions {
+		if txn.AccountId == accountId && txn.CreatedBy == userId && !linked[id] {
+			result = append(result, txn.TransactionBaseResponse)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Id < result[j].Id })
+	return result, nil
+}
+
+// GetPreviousStatement reads transaction dates from the repository set with
+// SetTransactionRepository, and finds nothing without one.
+func (m *MockStatementRepository) GetPreviousStatement(ctx context.Context, statementId int64, accountId int64, userId int64, before time.Time) (*models.PreviousStatement, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.txnRepo == nil {
+		return nil, nil
+	}
+	m.txnRepo.mu.RLock()
+	defer m.txnRepo.mu.RUnlock()
+	lastDates := make(map[int64]time.Time)
+	for _, mapping := range m.statementTxnMappings {
+		txn, ok := m.txnRepo.transactions[mapping.TransactionId]
+		if ok && txn.Date.After(lastDates[mapping.StatementId]) {
+			lastDates[mapping.StatementId] = txn.Date
+		}
+	}
+	var previous *models.PreviousStatement
+	for id, lastDate := range lastDates {
+		statement := m.statements[id]
+		if id == statementId || statement.AccountId != accountId || statement.CreatedBy != userId ||
+			statement.Status != models.StatementStatusDone || statement.ClosingBalance == nil || lastDate.After(before) {
+			continue
+		}
+		if previous == nil || lastDate.After(previous.LastTransactionDate) ||
+			(lastDate.Equal(previous.LastTransactionDate) && id > previous.StatementId) {
+			previous = &models.PreviousStatement{
+				StatementId:         id,
+				ClosingBalance:      *statement.ClosingBalance,
+				LastTransactionDate: lastDate,
+			}
+		}
+	}
+	return previous, nil
+}
+
+func (m *MockStatementRepository) DeleteStatementRowErrors(ctx context.Context, statementId int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rowErrors := m.rowErrors[:0]
+	for _, rowError := range m.rowErrors {
+		if rowError.StatementId != statementId {
+			rowErrors = append(rowErrors, rowError)
+		}
+	}
+	m.rowErrors = rowErrors
+	return nil
+}
+
+func (m *MockStatementRepository) ListStatementTransactionIds(ctx context.Context, statementId int64) ([]int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := []int64{}
+	for _, mapping := range m.statementTxnMappings {
+		if mapping.StatementId == statementId {
+			ids = append(ids, mapping.TransactionId)
+		}
+	}
+	return ids, nil
+}
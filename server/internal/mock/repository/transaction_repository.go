@@ -20,6 +20,7 @@ type MockTransactionRepository struct {
 	categoryMap                  map[int64][]int64
 	mu                           sync.RWMutex
 	statementTransactionMappings []statementTxnMapping // Use local struct for statement_id filtering
+	sourceTransactionIds         map[int64]int64       // transaction id -> id of the transaction it was created from
 }
 
 func NewMockTransactionRepository() *MockTransactionRepository {
@@ -28,6 +29,7 @@ func NewMockTransactionRepository() *MockTransactionRepository {
 		nextId:                       1,
 		categoryMap:                  make(map[int64][]int64),
 		statementTransactionMappings: []statementTxnMapping{},
+		sourceTransactionIds:         make(map[int64]int64),
 	}
 }
 
@@ -72,22 +74,25 @@ func (m *MockTransactionRepository) CreateTransaction(ctx context.Context, input
 
 	m.transactions[newId] = tx
 	m.categoryMap[newId] = categoryIds
+	if input.SourceTransactionId != nil {
+		m.sourceTransactionIds[newId] = *input.SourceTransactionId
+	}
 
 	return tx, nil
 }
 
-func (m *MockTransactionRepository) CreateTransactions(ctx context.Context, inputs []models.CreateBaseTransactionInput, categoryIds [][]int64) ([]models.TransactionResponse, error) {
+func (m *MockTransactionRepository) CreateTransactions(ctx context.Context, inputs []models.CreateBaseTransactionInput, categoryIds [][]int64) (models.BulkCreateTransactionsResult, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	result := models.BulkCreateTransactionsResult{Inserted: []models.TransactionResponse{}}
 	if len(inputs) != len(categoryIds) {
-		return nil, customErrors.NewTransactionAlreadyExistsError(nil)
+		return result, customErrors.NewTransactionAlreadyExistsError(nil)
 	}
 
-	results := make([]models.TransactionResponse, 0, len(inputs))
-
 	for i, input := range inputs {
-		// Check for duplicate transaction
+		// Skip duplicate transactions, as ON CONFLICT DO NOTHING does
+		duplicate := false
 		for _, tx := range m.transactions {
 			if tx.CreatedBy == input.CreatedBy &&
 				tx.Date.Format("2006-01-02") == input.Date.Format("2006-01-02") &&
@@ -99,10 +104,15 @@ func (m *MockTransactionRepository) CreateTransactions(ctx context.Context, inpu
 				}
 				inputDesc := input.Description
 				if existingDesc == inputDesc {
-					return nil, customErrors.NewTransactionAlreadyExistsError(nil)
+					duplicate = true
+					break
 				}
 			}
 		}
+		if duplicate {
+			result.Duplicates++
+			continue
+		}
 
 		// Create new transaction
 		newId := m.nextId
@@ -126,10 +136,10 @@ func (m *MockTransactionRepository) CreateTransactions(ctx context.Context, inpu
 		m.transactions[newId] = tx
 		m.categoryMap[newId] = categoryIds[i]
 
-		results = append(results, tx)
+		result.Inserted = append(result.Inserted, tx)
 	}
 
-	return results, nil
+	return result, nil
 }
 
 func (m *MockTransactionRepository) UpdateCategoryMapping(ctx context.Context, transactionId int64, userId int64, categoryIds []int64) error {
@@ -246,6 +256,26 @@ func (m *MockTransactionRepository) DeleteTransaction(ctx context.Context, trans
 	return nil
 }
 
+func (m *MockTransactionRepository) DeleteTransactions(ctx context.Context, transactionIds []int64, userId int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	toDelete := make(map[int64]bool, len(transactionIds))
+	for _, id := range transactionIds {
+		toDelete[id] = true
+	}
+	var deleted int64
+	for id, tx := range m.transactions {
+		if tx.CreatedBy != userId {
+			continue
+		}
+		if sourceId, ok := m.sourceTransactionIds[id]; toDelete[id] || (ok && toDelete[sourceId]) {
+			delete(m.transactions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
 func (m *MockTransactionRepository) ListTransactions(ctx context.Context, userId int64, query models.TransactionListQuery) (models.PaginatedTransactionsResponse, error) {
 	var result []models.TransactionResponse
 
@@ -10,9 +10,20 @@ const (
 	BankTypeHDFC        BankType = "hdfc"
 	BankTypeICICI       BankType = "icici"
 	BankTypeICICICredit BankType = "icici_credit"
+	BankTypeKotak       BankType = "kotak"
+	BankTypeIDFC        BankType = "idfc"
 	BankTypeOthers      BankType = "others"
 )
 
+// Statement formats that are not tied to a specific bank. These can be passed as
+// bank_type while uploading a statement to pick the parser explicitly.
+const (
+	BankTypeOFX     BankType = "ofx"
+	BankTypeCAMT053 BankType = "camt053"
+	BankTypeMT940   BankType = "mt940"
+	BankTypeQIF     BankType = "qif"
+)
+
 const (
 	CurrencyINR = "inr"
 	CurrencyUSD = "usd"
@@ -20,7 +31,7 @@ const (
 
 type CreateAccountInput struct {
 	Name         string   `json:"name" binding:"required"`
-	BankType     BankType `json:"bank_type" binding:"required,oneof=investment axis axis_credit sbi hdfc icici icici_credit others"`
+	BankType     BankType `json:"bank_type" binding:"required,oneof=investment axis axis_credit sbi hdfc icici icici_credit kotak idfc others"`
 	Currency     string   `json:"currency" binding:"required,oneof=inr usd"`
 	Balance      *float64 `json:"balance"`
 	CurrentValue *float64 `json:"current_value"`
@@ -29,7 +40,7 @@ type CreateAccountInput struct {
 
 type UpdateAccountInput struct {
 	Name         string   `json:"name,omitempty"`
-	BankType     BankType `json:"bank_type,omitempty" binding:"omitempty,oneof=investment axis axis_credit sbi hdfc icici icici_credit others"`
+	BankType     BankType `json:"bank_type,omitempty" binding:"omitempty,oneof=investment axis axis_credit sbi hdfc icici icici_credit kotak idfc others"`
 	Currency     string   `json:"currency,omitempty" binding:"omitempty,oneof=inr usd"`
 	Balance      *float64 `json:"balance,omitempty"`
 	CurrentValue *float64 `json:"current_value,omitempty"`
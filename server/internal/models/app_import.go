@@ -0,0 +1,84 @@
+package models
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+// AppImportSource is the personal finance app an export was made with.
+type AppImportSource string
+
+const (
+	AppImportSourceSplitwise    AppImportSource = "splitwise"
+	AppImportSourceYNAB         AppImportSource = "ynab"
+	AppImportSourceMoneyManager AppImportSource = "money_manager"
+	AppImportSourceWalnut       AppImportSource = "walnut"
+)
+
+type AppImportStatus string
+
+const (
+	AppImportStatusPending    AppImportStatus = "pending"
+	AppImportStatusProcessing AppImportStatus = "processing"
+	AppImportStatusDone       AppImportStatus = "done"
+	AppImportStatusError      AppImportStatus = "error"
+)
+
+type AppImportForm struct {
+	Source   AppImportSource       `form:"source" binding:"required,oneof=splitwise ynab money_manager walnut"`
+	Currency string                `form:"currency" binding:"omitempty,oneof=inr usd"`
+	File     *multipart.FileHeader `form:"file" binding:"required"`
+}
+
+type AppImportInput struct {
+	Source           AppImportSource `json:"source"`
+	Currency         string          `json:"currency"`
+	OriginalFilename string          `json:"original_filename"`
+	FileBytes        []byte          `json:"-"`
+}
+
+type CreateAppImportInput struct {
+	Source           AppImportSource `json:"source" binding:"required"`
+	OriginalFilename string          `json:"original_filename" binding:"required"`
+	Currency         string          `json:"currency" binding:"required"`
+	Status           AppImportStatus `json:"status" binding:"required"`
+	FileKey          string          `json:"file_key" binding:"required"`
+	CreatedBy        int64           `json:"created_by" binding:"required"`
+}
+
+type UpdateAppImportInput struct {
+	Status            AppImportStatus `json:"status" binding:"required,oneof=pending processing done error"`
+	Message           *string         `json:"message,omitempty"`
+	AccountsCreated   *int            `json:"accounts_created,omitempty"`
+	CategoriesCreated *int            `json:"categories_created,omitempty"`
+	InsertedCount     *int            `json:"inserted_count,omitempty"`
+	DuplicateCount    *int            `json:"duplicate_count,omitempty"`
+	FailedCount       *int            `json:"failed_count,omitempty"`
+}
+
+type AppImportResponse struct {
+	Id                int64           `json:"id"`
+	Source            AppImportSource `json:"source"`
+	OriginalFilename  string          `json:"original_filename"`
+	Currency          string          `json:"currency"`
+	Status            AppImportStatus `json:"status"`
+	Message           *string         `json:"message"`
+	AccountsCreated   int             `json:"accounts_created"`
+	CategoriesCreated int             `json:"categories_created"`
+	InsertedCount     int             `json:"inserted_count"`
+	DuplicateCount    int             `json:"duplicate_count"`
+	FailedCount       int             `json:"failed_count"`
+	FileKey           string          `json:"-"`
+	CreatedBy         int64           `json:"created_by"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// AppImportTransaction is a transaction read from the export of another app.
+// Its account and categories are named rather than referenced by id, and are
+// matched against the user's own when the export is imported.
+type AppImportTransaction struct {
+	CreateTransactionInput
+	AccountName   string
+	CategoryNames []string
+}
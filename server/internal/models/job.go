@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobType string
+type JobStatus string
+
+const (
+	JobTypeParseStatement JobType = "parse_statement"
+	JobTypeExecuteRules   JobType = "execute_rules"
+	JobTypeAppImport      JobType = "app_import"
+)
+
+const (
+	JobStatusPending JobStatus = "pending"
+	JobStatusRunning JobStatus = "running"
+	JobStatusFailed  JobStatus = "failed"
+)
+
+type CreateJobInput struct {
+	JobType     JobType         `json:"job_type"`
+	ReferenceId *int64          `json:"reference_id,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	MaxAttempts int             `json:"max_attempts"`
+}
+
+type Job struct {
+	Id          int64           `json:"id"`
+	JobType     JobType         `json:"job_type"`
+	ReferenceId *int64          `json:"reference_id"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      JobStatus       `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LockedUntil *time.Time      `json:"locked_until"`
+	LastError   *string         `json:"last_error"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// IsLastAttempt reports whether the job will not be retried if this attempt fails.
+func (j Job) IsLastAttempt() bool {
+	return j.Attempts >= j.MaxAttempts
+}
+
+// ParseStatementJobPayload is the work needed to parse an uploaded statement.
+// The file itself is read from blob storage under FileKey.
+type ParseStatementJobPayload struct {
+	StatementId int64                  `json:"statement_id"`
+	UserId      int64                  `json:"user_id"`
+	FileKey     string                 `json:"file_key"`
+	Input       ParseStatementJobInput `json:"input"`
+}
+
+// ParseStatementJobInput holds the upload options a statement job needs. Job
+// payloads are stored in the database, so the statement password is only
+// kept sealed with the server secret.
+type ParseStatementJobInput struct {
+	FileName         string `json:"file_name"`
+	AccountId        int64  `json:"account_id"`
+	OriginalFilename string `json:"original_filename"`
+	BankType         string `json:"bank_type,omitempty"`
+	Metadata         string `json:"metadata,omitempty"`
+	Force            bool   `json:"force,omitempty"`
+	Reprocess        bool   `json:"reprocess,omitempty"`
+	SealedPassword   string `json:"sealed_password,omitempty"`
+}
+
+type ExecuteRulesJobPayload struct {
+	UserId  int64               `json:"user_id"`
+	Request ExecuteRulesRequest `json:"request"`
+	// StatementId is set when the rules run for the transactions of an
+	// imported statement, so watchers of the statement are told when it ends.
+	StatementId *int64 `json:"statement_id,omitempty"`
+}
+
+// AppImportJobPayload is the work needed to import the export of another app.
+// The file itself is read from blob storage under FileKey.
+type AppImportJobPayload struct {
+	ImportId         int64           `json:"import_id"`
+	UserId           int64           `json:"user_id"`
+	FileKey          string          `json:"file_key"`
+	Source           AppImportSource `json:"source"`
+	Currency         string          `json:"currency"`
+	OriginalFilename string          `json:"original_filename"`
+}
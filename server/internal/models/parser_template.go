@@ -0,0 +1,33 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateParserTemplateInput is used for saving custom parser metadata
+type CreateParserTemplateInput struct {
+	Name      string          `json:"name" binding:"required" maxlength:"100"`
+	AccountId *int64          `json:"account_id,omitempty"`
+	Metadata  json.RawMessage `json:"metadata" binding:"required"`
+	CreatedBy int64           `json:"created_by" binding:"required"`
+}
+
+// UpdateParserTemplateInput is used for updating a parser template. An
+// account_id of 0 unbinds the template from its account.
+type UpdateParserTemplateInput struct {
+	Name      *string          `json:"name,omitempty" binding:"omitempty,min=1" maxlength:"100"`
+	AccountId *int64           `json:"account_id,omitempty"`
+	Metadata  *json.RawMessage `json:"metadata,omitempty"`
+}
+
+// ParserTemplateResponse is the response model for a parser template
+type ParserTemplateResponse struct {
+	Id        int64           `json:"id"`
+	Name      string          `json:"name"`
+	AccountId *int64          `json:"account_id"`
+	Metadata  json.RawMessage `json:"metadata"`
+	CreatedBy int64           `json:"created_by"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
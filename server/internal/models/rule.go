@@ -14,32 +14,61 @@ const (
 	RuleFieldDescription RuleFieldType = "description"
 	RuleFieldCategory    RuleFieldType = "category"
 	RuleFieldTransfer    RuleFieldType = "transfer"
+	RuleFieldAccount     RuleFieldType = "account"
+	RuleFieldDate        RuleFieldType = "date"
+	RuleFieldDayOfMonth  RuleFieldType = "day_of_month"
+	RuleFieldWeekday     RuleFieldType = "weekday"
 )
 
 const (
-	OperatorEquals   RuleOperator = "equals"
-	OperatorContains RuleOperator = "contains"
-	OperatorGreater  RuleOperator = "greater"
-	OperatorLower    RuleOperator = "lower"
+	OperatorEquals      RuleOperator = "equals"
+	OperatorContains    RuleOperator = "contains"
+	OperatorGreater     RuleOperator = "greater"
+	OperatorLower       RuleOperator = "lower"
+	OperatorRegex       RuleOperator = "regex"
+	OperatorStartsWith  RuleOperator = "starts_with"
+	OperatorEndsWith    RuleOperator = "ends_with"
+	OperatorNotContains RuleOperator = "not_contains"
+	OperatorNotEquals   RuleOperator = "not_equals"
+	OperatorBefore      RuleOperator = "before"
+	OperatorAfter       RuleOperator = "after"
+	OperatorBetween     RuleOperator = "between"
 )
 
+// RuleDateLayout is the layout of the values of date conditions. Conditions
+// with the between operator hold two values separated by a comma, such as
+// 2024-01-01,2024-03-31 for dates or 1,5 for days of the month; both ends are
+// included.
+const RuleDateLayout = "2006-01-02"
+
 const (
 	ConditionLogicAnd ConditionLogic = "AND"
 	ConditionLogicOr  ConditionLogic = "OR"
+	// ConditionLogicNot is only valid for condition groups. A NOT group
+	// matches when none of its conditions and groups match.
+	ConditionLogicNot ConditionLogic = "NOT"
 )
 
+// CreateBaseRuleRequest holds the fields of a new rule. New rules run after
+// the existing rules of the user, so Priority is set by the service.
 type CreateBaseRuleRequest struct {
 	Name           string          `json:"name" binding:"required,min=1,max=100"`
 	Description    *string         `json:"description,omitempty" binding:"omitempty,max=255"`
 	ConditionLogic *ConditionLogic `json:"condition_logic" binding:"omitempty,oneof=AND OR"`
 	EffectiveFrom  time.Time       `json:"effective_from" binding:"required"`
+	StopProcessing bool            `json:"stop_processing"`
+	Priority       int             `json:"-"`
 	CreatedBy      int64           `json:"created_by"`
 }
 
+// CreateRuleRequest holds the top level conditions of a rule and its nested
+// condition groups. The condition logic of the rule combines both, so a rule
+// needs either conditions or condition groups.
 type CreateRuleRequest struct {
-	Rule       CreateBaseRuleRequest        `json:"rule" binding:"required"`
-	Actions    []CreateRuleActionRequest    `json:"actions" binding:"required,min=1"`
-	Conditions []CreateRuleConditionRequest `json:"conditions" binding:"required,min=1"`
+	Rule            CreateBaseRuleRequest             `json:"rule" binding:"required"`
+	Actions         []CreateRuleActionRequest         `json:"actions" binding:"required,min=1"`
+	Conditions      []CreateRuleConditionRequest      `json:"conditions" binding:"required_without=ConditionGroups,omitempty,min=1"`
+	ConditionGroups []CreateRuleConditionGroupRequest `json:"condition_groups,omitempty" binding:"omitempty,dive"`
 }
 
 type UpdateRuleRequest struct {
@@ -47,21 +76,30 @@ type UpdateRuleRequest struct {
 	Description    *string         `json:"description,omitempty" binding:"omitempty,max=255"`
 	ConditionLogic *ConditionLogic `json:"condition_logic" binding:"omitempty,oneof=AND OR"`
 	EffectiveFrom  *time.Time      `json:"effective_from,omitempty"`
+	StopProcessing *bool           `json:"stop_processing,omitempty"`
 }
 
+// RuleResponse is a rule of a user. Rules run in ascending Priority, and a
+// matching rule with StopProcessing set keeps the rules after it from
+// touching the transaction.
 type RuleResponse struct {
 	Id             int64          `json:"id"`
 	Name           string         `json:"name"`
 	Description    *string        `json:"description"`
 	ConditionLogic ConditionLogic `json:"condition_logic"`
 	EffectiveFrom  time.Time      `json:"effective_from"`
+	Priority       int            `json:"priority"`
+	StopProcessing bool           `json:"stop_processing"`
 	CreatedBy      int64          `json:"created_by"`
 }
 
+// DescribeRuleResponse lists the top level conditions of a rule in
+// Conditions; conditions that belong to a group are listed in the group.
 type DescribeRuleResponse struct {
-	Rule       RuleResponse            `json:"rule"`
-	Actions    []RuleActionResponse    `json:"actions"`
-	Conditions []RuleConditionResponse `json:"conditions"`
+	Rule            RuleResponse                 `json:"rule"`
+	Actions         []RuleActionResponse         `json:"actions"`
+	Conditions      []RuleConditionResponse      `json:"conditions"`
+	ConditionGroups []RuleConditionGroupResponse `json:"condition_groups"`
 }
 
 type CreateRuleActionRequest struct {
@@ -87,6 +125,7 @@ type CreateRuleConditionRequest struct {
 	ConditionValue    string        `json:"condition_value" binding:"required,min=1,max=100"`
 	ConditionOperator RuleOperator  `json:"condition_operator" binding:"required"`
 	RuleId            int64         `json:"rule_id"`
+	GroupId           *int64        `json:"-"`
 }
 
 type UpdateRuleConditionRequest struct {
@@ -101,6 +140,27 @@ type RuleConditionResponse struct {
 	ConditionType     RuleFieldType `json:"condition_type"`
 	ConditionValue    string        `json:"condition_value"`
 	ConditionOperator RuleOperator  `json:"condition_operator"`
+	GroupId           *int64        `json:"group_id"`
+}
+
+// CreateRuleConditionGroupRequest is a node of the condition tree of a rule.
+// Its logic combines its conditions and nested groups.
+type CreateRuleConditionGroupRequest struct {
+	Logic      ConditionLogic                    `json:"logic" binding:"required,oneof=AND OR NOT"`
+	Conditions []CreateRuleConditionRequest      `json:"conditions,omitempty" binding:"omitempty,dive"`
+	Groups     []CreateRuleConditionGroupRequest `json:"groups,omitempty" binding:"omitempty,dive"`
+}
+
+// RuleConditionGroupResponse is a condition group of a rule. The repository
+// lists groups without their children, which the services nest by ParentId
+// and GroupId.
+type RuleConditionGroupResponse struct {
+	Id         int64                        `json:"id"`
+	RuleId     int64                        `json:"rule_id"`
+	ParentId   *int64                       `json:"parent_id"`
+	Logic      ConditionLogic               `json:"logic"`
+	Conditions []RuleConditionResponse      `json:"conditions"`
+	Groups     []RuleConditionGroupResponse `json:"groups"`
 }
 
 type PutRuleActionsRequest struct {
@@ -108,7 +168,8 @@ type PutRuleActionsRequest struct {
 }
 
 type PutRuleConditionsRequest struct {
-	Conditions []CreateRuleConditionRequest `json:"conditions" binding:"required,min=1,max=50"`
+	Conditions      []CreateRuleConditionRequest      `json:"conditions" binding:"required_without=ConditionGroups,omitempty,min=1,max=50"`
+	ConditionGroups []CreateRuleConditionGroupRequest `json:"condition_groups,omitempty" binding:"omitempty,dive"`
 }
 
 type PutRuleActionsResponse struct {
@@ -116,7 +177,14 @@ type PutRuleActionsResponse struct {
 }
 
 type PutRuleConditionsResponse struct {
-	Conditions []RuleConditionResponse `json:"conditions"`
+	Conditions      []RuleConditionResponse      `json:"conditions"`
+	ConditionGroups []RuleConditionGroupResponse `json:"condition_groups"`
+}
+
+// ReorderRulesRequest lists rule ids in the order they should run. Rules that
+// are not listed run after them, in their current order.
+type ReorderRulesRequest struct {
+	RuleIds []int64 `json:"rule_ids" binding:"required,min=1,max=500"`
 }
 
 type ExecuteRulesRequest struct {
@@ -132,6 +200,40 @@ type ExecuteRulesResponse struct {
 	ProcessedTxns int              `json:"processed_transactions"`
 }
 
+// PreviewRulesRequest previews an unsaved rule, or saved rules when Rule is
+// not set, against the given transactions or the Limit most recent ones.
+// Without rule ids all the rules of the user are previewed.
+type PreviewRulesRequest struct {
+	Rule           *CreateRuleRequest `json:"rule,omitempty"`
+	RuleIds        *[]int64           `json:"rule_ids,omitempty"`
+	TransactionIds *[]int64           `json:"transaction_ids,omitempty" binding:"omitempty,max=500"`
+	Limit          int                `json:"limit,omitempty" binding:"omitempty,min=1,max=500"`
+}
+
+// PreviewRulesResponse lists the changes the rules would make. Nothing is
+// written while previewing.
+type PreviewRulesResponse struct {
+	Changesets    []RuleChangeset `json:"changesets"`
+	TotalRules    int             `json:"total_rules"`
+	ProcessedTxns int             `json:"processed_transactions"`
+}
+
+type RuleChangeset struct {
+	TransactionId     int64           `json:"transaction_id"`
+	NameUpdate        *string         `json:"name_update,omitempty"`
+	DescriptionUpdate *string         `json:"description_update,omitempty"`
+	CategoryAdds      []int64         `json:"category_adds"`
+	Transfer          *RuleTransfer   `json:"transfer,omitempty"`
+	AppliedRules      []int64         `json:"applied_rules"`
+	UpdatedFields     []RuleFieldType `json:"updated_fields"`
+}
+
+// RuleTransfer is the transaction a transfer action creates in AccountId.
+type RuleTransfer struct {
+	AccountId int64   `json:"account_id"`
+	Amount    float64 `json:"amount"`
+}
+
 type ModifiedResult struct {
 	TransactionId int64           `json:"transaction_id"`
 	AppliedRules  []int64         `json:"applied_rules"`
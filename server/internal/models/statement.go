@@ -12,6 +12,7 @@ const (
 	StatementStatusProcessing StatementStatus = "processing"
 	StatementStatusDone       StatementStatus = "done"
 	StatementStatusError      StatementStatus = "error"
+	StatementStatusReverted   StatementStatus = "reverted"
 )
 
 type CreateStatementInput struct {
@@ -19,8 +20,13 @@ type CreateStatementInput struct {
 	CreatedBy        int64           `json:"created_by" binding:"required"`
 	OriginalFilename string          `json:"original_filename" binding:"required"`
 	FileType         string          `json:"file_type" binding:"required"`
-	Status           StatementStatus `json:"status" binding:"required,oneof=pending processing done error"`
+	Status           StatementStatus `json:"status" binding:"required,oneof=pending processing done error reverted"`
 	Message          *string         `json:"message,omitempty"`
+	ContentHash      *string         `json:"content_hash,omitempty"`
+	FileKey          *string         `json:"file_key,omitempty"`
+	BankType         *string         `json:"bank_type,omitempty"`
+	Metadata         *string         `json:"metadata,omitempty"`
+	DuplicateOf      *int64          `json:"duplicate_of,omitempty"`
 }
 
 type ParseStatementInput struct {
@@ -31,11 +37,95 @@ type ParseStatementInput struct {
 	BankType         string `json:"bank_type,omitempty" binding:"optional"`
 	Metadata         string `json:"metadata,omitempty" binding:"optional"`
 	Password         string `json:"password,omitempty" binding:"optional"`
+	Force            bool   `json:"force,omitempty"`
+	// Reprocess replaces the transactions of an earlier import of the
+	// statement when the file is parsed.
+	Reprocess bool `json:"reprocess,omitempty"`
 }
 
 type UpdateStatementStatusInput struct {
-	Status  StatementStatus `json:"status" binding:"required,oneof=pending processing done error"`
-	Message *string         `json:"message,omitempty"`
+	Status         StatementStatus `json:"status" binding:"required,oneof=pending processing done error reverted"`
+	Message        *string         `json:"message,omitempty"`
+	OpeningBalance *float64        `json:"opening_balance,omitempty"`
+	ClosingBalance *float64        `json:"closing_balance,omitempty"`
+	DetectedFormat *string         `json:"detected_format,omitempty"`
+	InsertedCount  *int            `json:"inserted_count,omitempty"`
+	DuplicateCount *int            `json:"duplicate_count,omitempty"`
+	FailedCount    *int            `json:"failed_count,omitempty"`
+	BankType       *string         `json:"bank_type,omitempty"`
+	Metadata       *string         `json:"metadata,omitempty"`
+	// Billing details of credit card statements
+	StatementPeriodStart *time.Time `json:"statement_period_start,omitempty"`
+	StatementPeriodEnd   *time.Time `json:"statement_period_end,omitempty"`
+	TotalDue             *float64   `json:"total_due,omitempty"`
+	MinimumDue           *float64   `json:"minimum_due,omitempty"`
+	PaymentDueDate       *time.Time `json:"payment_due_date,omitempty"`
+}
+
+// ReprocessStatementInput overrides the parser options a statement was
+// uploaded with. Fields left empty keep their previous values; the password
+// is never stored, so protected files need it again.
+type ReprocessStatementInput struct {
+	BankType *string `json:"bank_type,omitempty"`
+	Metadata *string `json:"metadata,omitempty"`
+	Password string  `json:"password,omitempty"`
+}
+
+// StatementBalances holds the balances reported by the statement file itself.
+// Either value is nil when the file does not contain it.
+type StatementBalances struct {
+	OpeningBalance *float64 `json:"opening_balance,omitempty"`
+	ClosingBalance *float64 `json:"closing_balance,omitempty"`
+}
+
+// PreviousStatement is the closing balance of the statement imported before
+// another one for the same account, and the date of its last transaction.
+type PreviousStatement struct {
+	StatementId         int64     `json:"statement_id"`
+	ClosingBalance      float64   `json:"closing_balance"`
+	LastTransactionDate time.Time `json:"last_transaction_date"`
+}
+
+// CardStatementSummary holds the billing details printed on a credit card
+// statement. Fields are nil when the statement does not contain them.
+type CardStatementSummary struct {
+	StatementPeriodStart *time.Time `json:"statement_period_start,omitempty"`
+	StatementPeriodEnd   *time.Time `json:"statement_period_end,omitempty"`
+	TotalDue             *float64   `json:"total_due,omitempty"`
+	MinimumDue           *float64   `json:"minimum_due,omitempty"`
+	PaymentDueDate       *time.Time `json:"payment_due_date,omitempty"`
+}
+
+// CardDueResponse is the latest bill of a credit card account whose payment
+// is still due.
+type CardDueResponse struct {
+	StatementId          int64      `json:"statement_id"`
+	AccountId            int64      `json:"account_id"`
+	AccountName          string     `json:"account_name"`
+	StatementPeriodStart *time.Time `json:"statement_period_start,omitempty"`
+	StatementPeriodEnd   *time.Time `json:"statement_period_end,omitempty"`
+	TotalDue             *float64   `json:"total_due,omitempty"`
+	MinimumDue           *float64   `json:"minimum_due,omitempty"`
+	PaymentDueDate       time.Time  `json:"payment_due_date"`
+}
+
+// StatementRowError describes a row of a statement file that could not be
+// imported. LineNumber is the 1-based position of the row in the file (or in
+// the extracted table for PDFs), or 0 for rows that parsed but could not be
+// inserted. RawRow holds the original content of the row.
+type StatementRowError struct {
+	LineNumber int    `json:"line_number"`
+	RawRow     string `json:"raw_row"`
+	Reason     string `json:"reason"`
+}
+
+type StatementRowErrorResponse struct {
+	Id          int64     `json:"id"`
+	StatementId int64     `json:"statement_id"`
+	LineNumber  int       `json:"line_number"`
+	RawRow      string    `json:"raw_row"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type StatementResponse struct {
@@ -46,7 +136,24 @@ type StatementResponse struct {
 	FileType         string          `json:"file_type"`
 	Status           StatementStatus `json:"status"`
 	Message          *string         `json:"message,omitempty"`
-	CreatedAt        time.Time       `json:"created_at"`
+	OpeningBalance   *float64        `json:"opening_balance,omitempty"`
+	ClosingBalance   *float64        `json:"closing_balance,omitempty"`
+	DetectedFormat   *string         `json:"detected_format,omitempty"`
+	InsertedCount    *int            `json:"inserted_count,omitempty"`
+	DuplicateCount   *int            `json:"duplicate_count,omitempty"`
+	FailedCount      *int            `json:"failed_count,omitempty"`
+	ContentHash      *string         `json:"content_hash,omitempty"`
+	FileKey          *string         `json:"-"`
+	BankType         *string         `json:"bank_type,omitempty"`
+	Metadata         *string         `json:"metadata,omitempty"`
+	DuplicateOf      *int64          `json:"duplicate_of,omitempty"`
+	// Billing details of credit card statements
+	StatementPeriodStart *time.Time `json:"statement_period_start,omitempty"`
+	StatementPeriodEnd   *time.Time `json:"statement_period_end,omitempty"`
+	TotalDue             *float64   `json:"total_due,omitempty"`
+	MinimumDue           *float64   `json:"minimum_due,omitempty"`
+	PaymentDueDate       *time.Time `json:"payment_due_date,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
 }
 
 type PaginatedStatementResponse struct {
@@ -59,6 +166,17 @@ type PaginatedStatementResponse struct {
 type StatementPreview struct {
 	Headers []string   `json:"headers"`
 	Rows    [][]string `json:"rows"`
+	// Sheets lists the worksheets of an Excel workbook and Sheet names the one
+	// that was read. Both are empty for text files.
+	Sheets []string `json:"sheets,omitempty"`
+	Sheet  string   `json:"sheet,omitempty"`
+}
+
+// SheetSelector picks the worksheet to read from an Excel workbook, by name
+// or by its 0-based position. The first sheet is read when neither is set.
+type SheetSelector struct {
+	SheetName  string `json:"sheet_name,omitempty" form:"sheet_name"`
+	SheetIndex *int   `json:"sheet_index,omitempty" form:"sheet_index"`
 }
 
 // Form parsing
@@ -67,6 +185,7 @@ type ParseStatementForm struct {
 	BankType  string                `form:"bank_type"`
 	Metadata  string                `form:"metadata"`
 	Password  string                `form:"password"`
+	Force     bool                  `form:"force"`
 	File      *multipart.FileHeader `form:"file" binding:"required"`
 }
 
@@ -75,6 +194,7 @@ type PreviewStatementForm struct {
 	RowSize  int                   `form:"row_size"`
 	Password string                `form:"password"`
 	File     *multipart.FileHeader `form:"file" binding:"required"`
+	SheetSelector
 }
 
 type StatementListQuery struct {
@@ -85,3 +205,31 @@ type StatementListQuery struct {
 	DateTo    *time.Time // filter by end date
 	Search    *string    // search in filename
 }
+
+// ParserInfo describes a registered statement parser so clients can tell the
+// user which formats can be uploaded and what each of them needs.
+type ParserInfo struct {
+	BankType BankType `json:"bank_type"`
+	Name     string   `json:"name"`
+	// Extensions lists the file extensions the parser reads, with the dot.
+	Extensions []string `json:"extensions"`
+	// NeedsPassword is set for formats that banks commonly password protect,
+	// so the upload form should ask for the password.
+	NeedsPassword bool `json:"needs_password"`
+	// NeedsMetadata is set for parsers that read the column mapping of a file
+	// from the metadata sent with it.
+	NeedsMetadata bool   `json:"needs_metadata"`
+	SampleHeader  string `json:"sample_header,omitempty"`
+	// Capabilities are filled in by the registry from the interfaces the
+	// parser implements.
+	Capabilities ParserCapabilities `json:"capabilities"`
+}
+
+// ParserCapabilities lists the optional features of a statement parser.
+type ParserCapabilities struct {
+	Detection   bool `json:"detection"`
+	RowErrors   bool `json:"row_errors"`
+	Balances    bool `json:"balances"`
+	CardSummary bool `json:"card_summary"`
+	Categories  bool `json:"categories"`
+}
@@ -0,0 +1,51 @@
+package models
+
+type StatementEventType string
+
+const (
+	// StatementEventStatus is sent whenever the status of a statement changes.
+	StatementEventStatus StatementEventType = "status"
+	// StatementEventProgress is sent once the file is parsed, before the
+	// transactions are inserted.
+	StatementEventProgress StatementEventType = "progress"
+	// StatementEventRules is sent when the rule run queued for the imported
+	// transactions has finished.
+	StatementEventRules StatementEventType = "rules"
+)
+
+type StatementEvent struct {
+	Type           StatementEventType `json:"type"`
+	StatementId    int64              `json:"statement_id"`
+	Status         StatementStatus    `json:"status,omitempty"`
+	Message        *string            `json:"message,omitempty"`
+	ParsedCount    *int               `json:"parsed_count,omitempty"`
+	InsertedCount  *int               `json:"inserted_count,omitempty"`
+	DuplicateCount *int               `json:"duplicate_count,omitempty"`
+	FailedCount    *int               `json:"failed_count,omitempty"`
+	ModifiedCount  *int               `json:"modified_count,omitempty"`
+}
+
+// IsFinal reports whether no more events follow for the statement: its import
+// failed or was reverted, or the rules for its transactions have run.
+func (e StatementEvent) IsFinal() bool {
+	switch e.Type {
+	case StatementEventRules:
+		return true
+	case StatementEventStatus:
+		return e.Status == StatementStatusError || e.Status == StatementStatusReverted
+	}
+	return false
+}
+
+// NewStatementStatusEvent describes the current state of a statement.
+func NewStatementStatusEvent(statement StatementResponse) StatementEvent {
+	return StatementEvent{
+		Type:           StatementEventStatus,
+		StatementId:    statement.Id,
+		Status:         statement.Status,
+		Message:        statement.Message,
+		InsertedCount:  statement.InsertedCount,
+		DuplicateCount: statement.DuplicateCount,
+		FailedCount:    statement.FailedCount,
+	}
+}
@@ -12,6 +12,10 @@ type CreateBaseTransactionInput struct {
 	Date        time.Time `json:"date" binding:"required"`
 	CreatedBy   int64     `json:"created_by" binding:"required"`
 	AccountId   int64     `json:"account_id" binding:"required"`
+	ExternalId  *string   `json:"external_id,omitempty" binding:"omitempty,max=255"`
+	// SourceTransactionId links transactions created by rules (such as the
+	// counterpart of a transfer) to the transaction they were created from.
+	SourceTransactionId *int64 `json:"-"`
 }
 
 // UpdateBaseTransactionInput is used for updating DB update (without mapping fields)
@@ -40,6 +44,22 @@ type CreateTransactionInput struct {
 	CategoryIds []int64 `json:"category_ids"`
 }
 
+// BulkCreateTransactionsResult reports the outcome of a bulk insert. Rows that
+// already exist are skipped and rows that cannot be inserted are reported
+// individually instead of failing the whole batch.
+type BulkCreateTransactionsResult struct {
+	Inserted   []TransactionResponse
+	Duplicates int
+	Failed     []BulkCreateTransactionFailure
+}
+
+// BulkCreateTransactionFailure is an input row of a bulk insert that could not
+// be inserted. Index is the position of the row in the input.
+type BulkCreateTransactionFailure struct {
+	Index int
+	Err   error
+}
+
 // UpdateTransactionInput is used for updating an existing transaction
 type UpdateTransactionInput struct {
 	UpdateBaseTransactionInput
@@ -52,6 +72,14 @@ type TransactionResponse struct {
 	CategoryIds []int64 `json:"category_ids"`
 }
 
+// TransactionExportQuery selects the transactions of an account to export.
+// Either date may be nil to leave the range open on that side.
+type TransactionExportQuery struct {
+	AccountId int64
+	DateFrom  *time.Time
+	DateTo    *time.Time
+}
+
 // PaginatedTransactionsResponse is the paginated response for transaction listing
 // Contains the data, total count, current page, and page size
 type PaginatedTransactionsResponse struct {
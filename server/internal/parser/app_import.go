@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/csv"
+	"expenses/internal/models"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AppImporter reads the export of another personal finance app. Unlike a bank
+// statement, an export covers every account of its user and names the
+// category of each transaction, so transactions are returned with the names of
+// their account and categories.
+type AppImporter interface {
+	Import(fileBytes []byte, fileName string) ([]models.AppImportTransaction, []models.StatementRowError, error)
+}
+
+var appImporterRegistry = make(map[models.AppImportSource]AppImporter)
+
+func RegisterAppImporter(source models.AppImportSource, importer AppImporter) {
+	appImporterRegistry[source] = importer
+}
+
+func GetAppImporter(source models.AppImportSource) (AppImporter, bool) {
+	importer, ok := appImporterRegistry[source]
+	return importer, ok
+}
+
+// readExportRows returns the rows of an app export saved as an Excel workbook,
+// tab separated text or CSV.
+func readExportRows(fileBytes []byte, fileName string) ([][]string, error) {
+	if isCompoundFile(fileBytes) || bytes.HasPrefix(fileBytes, zipHeader) {
+		return readFirstSheet(fileBytes, "")
+	}
+
+	content := bytes.TrimPrefix(fileBytes, []byte("\ufeff"))
+	r := csv.NewReader(bytes.NewReader(content))
+	firstLine, _, _ := strings.Cut(string(headOf(content, 1024)), "\n")
+	if hasExtension(fileName, ".tsv") || (strings.Contains(firstLine, "\t") && !strings.Contains(firstLine, ",")) {
+		r.Comma = '\t'
+	}
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	return rows, nil
+}
+
+// newAppImportTransaction builds an imported transaction, leaving out empty
+// category names.
+func newAppImportTransaction(accountName string, date time.Time, name string, description string, amount float64, categoryNames ...string) *models.AppImportTransaction {
+	categories := make([]string, 0, len(categoryNames))
+	for _, category := range categoryNames {
+		if category = strings.TrimSpace(category); category != "" {
+			categories = append(categories, category)
+		}
+	}
+	return &models.AppImportTransaction{
+		CreateTransactionInput: models.CreateTransactionInput{
+			CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+				Name:        truncateName(name),
+				Description: description,
+				Amount:      &amount,
+				Date:        date,
+			},
+			CategoryIds: []int64{},
+		},
+		AccountName:   strings.TrimSpace(accountName),
+		CategoryNames: categories,
+	}
+}
@@ -20,9 +20,14 @@ import (
 type AxisCreditParser struct{}
 
 func (p *AxisCreditParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *AxisCreditParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
 	f, err := excelize.OpenReader(bytes.NewReader(fileBytes))
 	if err != nil {
-		return nil, fmt.Errorf("failed to open xlsx: %w", err)
+		return nil, nil, fmt.Errorf("failed to open xlsx: %w", err)
 	}
 	defer func() {
 		_ = f.Close()
@@ -70,6 +75,7 @@ func (p *AxisCreditParser) Parse(fileBytes []byte, metadata string, fileName str
 		}
 
 		var transactions []models.CreateTransactionInput
+		var rowErrors []models.StatementRowError
 		for i := headerIndex + 1; i < len(rows); i++ {
 			row := rows[i]
 			// Skip empty rows
@@ -85,6 +91,7 @@ func (p *AxisCreditParser) Parse(fileBytes []byte, metadata string, fileName str
 			txn, err := p.parseTransactionRow(row, dateIdx, descIdx, amountIdx, signIdx)
 			if err != nil {
 				logger.Warnf("Failed to parse row %d in sheet %s: %v", i+1, sheet, err)
+				rowErrors = append(rowErrors, newRowError(i+1, row, err))
 				continue
 			}
 			if txn != nil {
@@ -93,11 +100,11 @@ func (p *AxisCreditParser) Parse(fileBytes []byte, metadata string, fileName str
 		}
 
 		if len(transactions) > 0 {
-			return transactions, nil
+			return transactions, rowErrors, nil
 		}
 	}
 
-	return nil, errors.New("transaction header row not found in Axis credit statement")
+	return nil, nil, errors.New("transaction header row not found in Axis credit statement")
 }
 
 func (p *AxisCreditParser) parseTransactionRow(row []string, dateIdx int, descIdx int, amountIdx int, signIdx int) (*models.CreateTransactionInput, error) {
@@ -256,6 +263,46 @@ func (p *AxisCreditParser) parseAmount(raw string) (float64, string, error) {
 	return v, inferred, nil
 }
 
+// ParseCardSummary reads the billing period, the amounts due and the payment
+// due date from the summary above the transactions.
+func (p *AxisCreditParser) ParseCardSummary(fileBytes []byte, password string) (models.CardStatementSummary, error) {
+	f, err := openWorkbook(fileBytes, password)
+	if err != nil {
+		return models.CardStatementSummary{}, fmt.Errorf("failed to open xlsx: %w", err)
+	}
+	defer f.Close()
+
+	var rows [][]string
+	for _, sheet := range f.GetSheetList() {
+		sheetRows, err := f.GetRows(sheet)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, sheetRows...)
+	}
+	return scanCardSummary(rows), nil
+}
+
+// Detect recognises Axis credit card workbooks by a header with a single amount
+// column and a Debit/Credit indicator column.
+func (p *AxisCreditParser) Detect(sniff *Sniff) float64 {
+	for _, row := range sniff.Rows {
+		joined := strings.ToLower(strings.Join(row, " "))
+		if containsAll(joined, "date", "amount", "debit/credit") {
+			return 0.85
+		}
+		if containsAll(joined, "date", "amount") && (strings.Contains(joined, "debit") || strings.Contains(joined, "credit")) {
+			return 0.6
+		}
+	}
+	return 0
+}
+
 func init() {
-	RegisterParser(models.BankTypeAxisCredit, &AxisCreditParser{})
+	RegisterParser(models.BankTypeAxisCredit, &AxisCreditParser{}, models.ParserInfo{
+		Name:          "Axis Bank Credit Card",
+		Extensions:    []string{".xlsx"},
+		NeedsPassword: true,
+		SampleHeader:  "Date,Transaction Details,,Amount (INR),Debit/Credit",
+	})
 }
@@ -179,6 +179,41 @@ var _ = Describe("AxisCreditParser", func() {
 		})
 	})
 
+	Describe("ParseCardSummary", func() {
+		It("reads the billing details above the transactions", func() {
+			data := [][]string{
+				{"Selected Statement Month", "Nov 2025", "", "", ""},
+				{"Total Payment Due", "Minimum Payment Due", "Statement Period", "Payment Due Date", ""},
+				{"₹ 18,933.38", "₹ 950.00", "15 Oct '25 - 14 Nov '25", "04 Dec '25", ""},
+				{"Date", "Transaction Details", "", "Amount (INR)", "Debit/Credit"},
+				{"14 Nov '25", "Restro,MUMBAI", "", "₹ 7,977.00", "Debit"},
+			}
+
+			summary, err := parser.ParseCardSummary(utils.CreateXLSXFile(data), "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*summary.TotalDue).To(Equal(18933.38))
+			Expect(*summary.MinimumDue).To(Equal(950.00))
+			Expect(*summary.StatementPeriodStart).To(Equal(time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)))
+			Expect(*summary.StatementPeriodEnd).To(Equal(time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC)))
+			Expect(*summary.PaymentDueDate).To(Equal(time.Date(2025, 12, 4, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("returns an empty summary when the statement has no billing details", func() {
+			data := [][]string{
+				{"Date", "Transaction Details", "", "Amount (INR)", "Debit/Credit"},
+				{"14 Nov '25", "Restro,MUMBAI", "", "₹ 7,977.00", "Debit"},
+			}
+			summary, err := parser.ParseCardSummary(utils.CreateXLSXFile(data), "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary).To(Equal(models.CardStatementSummary{}))
+		})
+
+		It("errors on files that are not workbooks", func() {
+			_, err := parser.ParseCardSummary([]byte("not a workbook"), "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("Parser Registry", func() {
 		It("should return axis credit parser for BankTypeAxisCredit", func() {
 			p, ok := GetParser(models.BankTypeAxisCredit)
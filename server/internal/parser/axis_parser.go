@@ -13,9 +13,22 @@ import (
 	"expenses/pkg/utils"
 )
 
-// AxisParser parses Axis bank account CSV statements
+// AxisParser parses Axis bank account CSV and PDF statements
 type AxisParser struct{}
 
+// axisPDFLayout maps the Axis PDF statement columns to the order of the CSV export.
+var axisPDFLayout = tableLayout{
+	headerKeywords: []string{"tran date", "particulars"},
+	columns: [][]string{
+		{"tran date", "date"},
+		{"chq"},
+		{"particulars"},
+		{"debit", "dr"},
+		{"credit", "cr"},
+		{"balance", "bal"},
+	},
+}
+
 // Precompiled regex patterns for Axis transaction description parsing.
 // Compiling once improves performance when parsing many rows.
 var axisPatterns = []struct {
@@ -31,13 +44,27 @@ var axisPatterns = []struct {
 }
 
 func (p *AxisParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *AxisParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	if IsPDF(fileBytes) {
+		rows, err := extractPDFTable(fileBytes, password, axisPDFLayout)
+		if err != nil {
+			return nil, nil, err
+		}
+		transactions, rowErrors := parsePDFRows(rows, p.parseTransactionRow)
+		return transactions, rowErrors, nil
+	}
+
 	r := csv.NewReader(bytes.NewReader(fileBytes))
 	r.FieldsPerRecord = -1
 	// Bank CSVs can contain malformed quoting; be permissive
 	r.LazyQuotes = true
 	recs, err := r.ReadAll()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read csv: %w", err)
+		return nil, nil, fmt.Errorf("failed to read csv: %w", err)
 	}
 
 	// find header row
@@ -54,10 +81,11 @@ func (p *AxisParser) Parse(fileBytes []byte, metadata string, fileName string, p
 	}
 
 	if headerIdx == -1 {
-		return nil, errors.New("transaction header row not found")
+		return nil, nil, errors.New("transaction header row not found")
 	}
 
 	var transactions []models.CreateTransactionInput
+	var rowErrors []models.StatementRowError
 	for i := headerIdx + 1; i < len(recs); i++ {
 		row := recs[i]
 		// trim fields
@@ -73,6 +101,7 @@ func (p *AxisParser) Parse(fileBytes []byte, metadata string, fileName string, p
 		txn, err := p.parseTransactionRow(row)
 		if err != nil {
 			logger.Warnf("Failed to parse row %d: %v", i+1, err)
+			rowErrors = append(rowErrors, newRowError(i+1, row, err))
 			continue
 		}
 		if txn != nil {
@@ -80,7 +109,7 @@ func (p *AxisParser) Parse(fileBytes []byte, metadata string, fileName string, p
 		}
 	}
 
-	return transactions, nil
+	return transactions, rowErrors, nil
 }
 
 func (p *AxisParser) parseTransactionRow(fields []string) (*models.CreateTransactionInput, error) {
@@ -172,6 +201,25 @@ func (p *AxisParser) generateTransactionName(description string, isCredit bool)
 	return n
 }
 
+// Detect recognises Axis account CSV exports by their Tran Date/PARTICULARS header.
+func (p *AxisParser) Detect(sniff *Sniff) float64 {
+	content := sniff.Text
+	switch {
+	case containsAll(content, "tran date", "particulars", "dr", "cr"):
+		return 0.9
+	case strings.Contains(content, "tran date") && strings.Contains(content, "particulars"):
+		return 0.8
+	case strings.Contains(content, "tran date") && strings.Contains(content, "chqno"):
+		return 0.7
+	}
+	return 0
+}
+
 func init() {
-	RegisterParser(models.BankTypeAxis, &AxisParser{})
+	RegisterParser(models.BankTypeAxis, &AxisParser{}, models.ParserInfo{
+		Name:          "Axis Bank",
+		Extensions:    []string{".csv", ".pdf"},
+		NeedsPassword: true,
+		SampleHeader:  "Tran Date,CHQNO,PARTICULARS,DR,CR,BAL,SOL",
+	})
 }
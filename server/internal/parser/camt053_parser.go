@@ -0,0 +1,298 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"expenses/internal/models"
+	"expenses/pkg/logger"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CAMT053Parser parses ISO 20022 camt.053 (bank to customer statement) XML files.
+type CAMT053Parser struct{}
+
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	Balances []camtBalance `xml:"Bal"`
+	Entries  []camtEntry   `xml:"Ntry"`
+}
+
+type camtBalance struct {
+	Code      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtEntry struct {
+	Amount         camtAmount           `xml:"Amt"`
+	CdtDbtInd      string               `xml:"CdtDbtInd"`
+	Reversal       bool                 `xml:"RvslInd"`
+	BookingDate    camtDate             `xml:"BookgDt"`
+	ValueDate      camtDate             `xml:"ValDt"`
+	EntryRef       string               `xml:"NtryRef"`
+	ServicerRef    string               `xml:"AcctSvcrRef"`
+	AdditionalInfo string               `xml:"AddtlNtryInf"`
+	Details        []camtTransactionDtl `xml:"NtryDtls>TxDtls"`
+}
+
+type camtTransactionDtl struct {
+	Unstructured []string `xml:"RmtInf>Ustrd"`
+	CreditorRef  []string `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	Creditor     string   `xml:"RltdPties>Cdtr>Nm"`
+	CreditorPty  string   `xml:"RltdPties>Cdtr>Pty>Nm"`
+	Debtor       string   `xml:"RltdPties>Dbtr>Nm"`
+	DebtorPty    string   `xml:"RltdPties>Dbtr>Pty>Nm"`
+	EndToEndId   string   `xml:"Refs>EndToEndId"`
+	AdditionalTx string   `xml:"AddtlTxInf"`
+}
+
+func (p *CAMT053Parser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *CAMT053Parser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	doc, err := decodeCAMTDocument(fileBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var transactions []models.CreateTransactionInput
+	var rowErrors []models.StatementRowError
+	entryNumber := 0
+	for s, stmt := range doc.Statements {
+		for i, entry := range stmt.Entries {
+			entryNumber++
+			transaction, err := p.parseEntry(entry)
+			if err != nil {
+				logger.Warnf("Failed to parse entry %d of statement %d: %v", i+1, s+1, err)
+				// The XML decoder does not track lines, so entries are
+				// numbered across all statements in the file instead.
+				rowErrors = append(rowErrors, models.StatementRowError{
+					LineNumber: entryNumber,
+					RawRow:     entry.summary(),
+					Reason:     err.Error(),
+				})
+				continue
+			}
+			transactions = append(transactions, *transaction)
+		}
+	}
+
+	return transactions, rowErrors, nil
+}
+
+// ParseBalances returns the opening balance of the first statement and the
+// closing balance of the last statement in the file.
+func (p *CAMT053Parser) ParseBalances(fileBytes []byte) (models.StatementBalances, error) {
+	var balances models.StatementBalances
+	doc, err := decodeCAMTDocument(fileBytes)
+	if err != nil {
+		return balances, err
+	}
+
+	for _, stmt := range doc.Statements {
+		for _, bal := range stmt.Balances {
+			amount, err := signedCAMTAmount(bal.Amount.Value, bal.CdtDbtInd)
+			if err != nil {
+				return balances, fmt.Errorf("invalid %s balance: %w", bal.Code, err)
+			}
+			// Balances follow the account convention where a credit balance
+			// (money in the account) is positive.
+			amount = -amount
+			switch strings.ToUpper(bal.Code) {
+			case "OPBD", "PRCD":
+				if balances.OpeningBalance == nil {
+					balances.OpeningBalance = &amount
+				}
+			case "CLBD":
+				balances.ClosingBalance = &amount
+			}
+		}
+	}
+
+	return balances, nil
+}
+
+func decodeCAMTDocument(fileBytes []byte) (*camtDocument, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(bytes.NewReader(fileBytes)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to read camt.053 XML: %w", err)
+	}
+	if len(doc.Statements) == 0 {
+		return nil, errors.New("no statements found in camt.053 file")
+	}
+	return &doc, nil
+}
+
+func (p *CAMT053Parser) parseEntry(entry camtEntry) (*models.CreateTransactionInput, error) {
+	txnDate, err := entry.BookingDate.parse()
+	if err != nil {
+		txnDate, err = entry.ValueDate.parse()
+		if err != nil {
+			return nil, errors.New("missing booking date")
+		}
+	}
+
+	amount, err := signedCAMTAmount(entry.Amount.Value, entry.CdtDbtInd)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Reversal {
+		amount = -amount
+	}
+
+	var remittance []string
+	var counterparty string
+	for _, detail := range entry.Details {
+		remittance = append(remittance, detail.Unstructured...)
+		remittance = append(remittance, detail.CreditorRef...)
+		if counterparty == "" {
+			counterparty = detail.counterparty(amount < 0)
+		}
+	}
+	description := strings.Join(trimAll(remittance), " ")
+	if description == "" {
+		description = strings.TrimSpace(entry.AdditionalInfo)
+	}
+
+	name := counterparty
+	if name == "" {
+		name = strings.TrimSpace(entry.AdditionalInfo)
+	}
+	if name == "" {
+		name = description
+	}
+	if name == "" {
+		return nil, errors.New("entry has no counterparty or remittance information")
+	}
+
+	transaction := &models.CreateTransactionInput{
+		CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+			Name:        truncateName(name),
+			Description: description,
+			Amount:      &amount,
+			Date:        txnDate,
+		},
+		CategoryIds: []int64{},
+	}
+	if ref := firstNonEmpty(entry.ServicerRef, entry.EntryRef); ref != "" {
+		transaction.ExternalId = &ref
+	}
+
+	return transaction, nil
+}
+
+// summary describes an entry that could not be parsed by its reference, amount
+// and booking date.
+func (e camtEntry) summary() string {
+	return strings.Join(trimAll([]string{
+		firstNonEmpty(e.ServicerRef, e.EntryRef),
+		e.CdtDbtInd,
+		e.Amount.Value,
+		e.Amount.Currency,
+		firstNonEmpty(e.BookingDate.Date, e.BookingDate.DateTime),
+	}), " ")
+}
+
+// counterparty returns the other side of the transaction: the debtor for
+// incoming money and the creditor for outgoing money.
+func (d camtTransactionDtl) counterparty(isCredit bool) string {
+	if isCredit {
+		return firstNonEmpty(d.Debtor, d.DebtorPty)
+	}
+	return firstNonEmpty(d.Creditor, d.CreditorPty)
+}
+
+func (d camtDate) parse() (time.Time, error) {
+	if date := strings.TrimSpace(d.Date); date != "" {
+		return time.Parse("2006-01-02", date)
+	}
+	if dateTime := strings.TrimSpace(d.DateTime); len(dateTime) >= 10 {
+		return time.Parse("2006-01-02", dateTime[:10])
+	}
+	return time.Time{}, errors.New("empty date")
+}
+
+// signedCAMTAmount converts an unsigned camt amount to the transaction sign
+// convention: debits are positive and credits are negative.
+func signedCAMTAmount(value string, indicator string) (float64, error) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse amount '%s': %w", value, err)
+	}
+	switch strings.ToUpper(strings.TrimSpace(indicator)) {
+	case "DBIT":
+		return amount, nil
+	case "CRDT":
+		return -amount, nil
+	default:
+		return 0, fmt.Errorf("unknown credit/debit indicator '%s'", indicator)
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+func trimAll(values []string) []string {
+	trimmed := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
+
+// truncateName keeps transaction names short enough for list views, cutting on
+// rune boundaries.
+func truncateName(name string) string {
+	runes := []rune(strings.TrimSpace(name))
+	if len(runes) > 40 {
+		return strings.TrimSpace(string(runes[:37])) + "..."
+	}
+	return string(runes)
+}
+
+// Detect recognises camt.053 documents by their namespace or statement root element.
+func (p *CAMT053Parser) Detect(sniff *Sniff) float64 {
+	content := sniff.Text
+	switch {
+	case strings.Contains(content, "camt.053"):
+		return 0.95
+	case strings.Contains(content, "<bktocstmrstmt"):
+		return 0.9
+	}
+	return 0
+}
+
+func init() {
+	RegisterParser(models.BankTypeCAMT053, &CAMT053Parser{}, models.ParserInfo{
+		Name:         "ISO 20022 camt.053",
+		Extensions:   []string{".xml"},
+		SampleHeader: `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">`,
+	})
+}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CAMT053Parser", func() {
+	var parser *CAMT053Parser
+
+	const camtContent = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <GrpHdr><MsgId>MSG-1</MsgId></GrpHdr>
+    <Stmt>
+      <Id>STMT-1</Id>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="EUR">1000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Dt><Dt>2024-03-01</Dt></Dt>
+      </Bal>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="EUR">1374.50</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Dt><Dt>2024-03-31</Dt></Dt>
+      </Bal>
+      <Ntry>
+        <Amt Ccy="EUR">125.50</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2024-03-05</Dt></BookgDt>
+        <ValDt><Dt>2024-03-06</Dt></ValDt>
+        <AcctSvcrRef>REF-001</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <RltdPties><Cdtr><Nm>Electricity Co</Nm></Cdtr></RltdPties>
+            <RmtInf><Ustrd>Invoice 42</Ustrd><Ustrd>March</Ustrd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">500.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><DtTm>2024-03-10T09:30:00</DtTm></BookgDt>
+        <AcctSvcrRef>REF-002</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <RltdPties><Dbtr><Nm>Employer GmbH</Nm></Dbtr></RltdPties>
+            <RmtInf><Ustrd>Salary</Ustrd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+	BeforeEach(func() {
+		parser = &CAMT053Parser{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse entries with booking date, sign and remittance info", func() {
+			txns, err := parser.Parse([]byte(camtContent), "", "statement.xml", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(2))
+
+			Expect(txns[0].Name).To(Equal("Electricity Co"))
+			Expect(txns[0].Description).To(Equal("Invoice 42 March"))
+			Expect(*txns[0].Amount).To(BeNumerically("==", 125.50))
+			Expect(txns[0].Date).To(Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)))
+			Expect(*txns[0].ExternalId).To(Equal("REF-001"))
+
+			Expect(txns[1].Name).To(Equal("Employer GmbH"))
+			Expect(txns[1].Description).To(Equal("Salary"))
+			Expect(*txns[1].Amount).To(BeNumerically("==", -500.00))
+			Expect(txns[1].Date).To(Equal(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should skip entries with an unknown credit/debit indicator", func() {
+			content := `<Document><BkToCstmrStmt><Stmt>
+<Ntry><Amt Ccy="EUR">1.00</Amt><CdtDbtInd>XXXX</CdtDbtInd><BookgDt><Dt>2024-01-01</Dt></BookgDt><AddtlNtryInf>Bad</AddtlNtryInf></Ntry>
+<Ntry><Amt Ccy="EUR">2.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><BookgDt><Dt>2024-01-02</Dt></BookgDt><AddtlNtryInf>Card payment</AddtlNtryInf></Ntry>
+</Stmt></BkToCstmrStmt></Document>`
+			txns, err := parser.Parse([]byte(content), "", "statement.xml", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(1))
+			Expect(txns[0].Name).To(Equal("Card payment"))
+			Expect(txns[0].ExternalId).To(BeNil())
+		})
+
+		It("should error on invalid XML", func() {
+			_, err := parser.Parse([]byte("not xml"), "", "statement.xml", "")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should error when the document has no statements", func() {
+			_, err := parser.Parse([]byte("<Document><BkToCstmrStmt></BkToCstmrStmt></Document>"), "", "statement.xml", "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ParseBalances", func() {
+		It("should return opening and closing balances", func() {
+			balances, err := parser.ParseBalances([]byte(camtContent))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*balances.OpeningBalance).To(BeNumerically("==", 1000.00))
+			Expect(*balances.ClosingBalance).To(BeNumerically("==", 1374.50))
+		})
+
+		It("should report debit balances as negative", func() {
+			content := `<Document><BkToCstmrStmt><Stmt>
+<Bal><Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp><Amt Ccy="EUR">50.00</Amt><CdtDbtInd>DBIT</CdtDbtInd></Bal>
+</Stmt></BkToCstmrStmt></Document>`
+			balances, err := parser.ParseBalances([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(balances.OpeningBalance).To(BeNil())
+			Expect(*balances.ClosingBalance).To(BeNumerically("==", -50.00))
+		})
+	})
+})
@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"errors"
+	"expenses/internal/models"
+	"expenses/pkg/utils"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// cardSummaryLabels lists the labels credit card statements print next to
+// their billing details, in the order they are matched. Longer labels come
+// first so "payment due date" is not read as "due date".
+var cardSummaryLabels = []struct {
+	field  string
+	labels []string
+}{
+	{"period", []string{"statement period", "billing period", "billing cycle", "statement cycle"}},
+	{"minimum", []string{"minimum amount due", "minimum payment due", "minimum due", "min. amount due", "min amount due"}},
+	{"total", []string{"total amount due", "total payment due", "total dues", "total due"}},
+	{"due_date", []string{"payment due date", "due date"}},
+}
+
+var (
+	// cardPeriodSeparator splits a statement period such as
+	// "15 Oct 2025 - 14 Nov 2025" or "15/10/2025 To 14/11/2025".
+	cardPeriodSeparator = regexp.MustCompile(`(?i)\s+(?:to|-|–)\s+`)
+	// cardShortYear matches two digit years written as '25.
+	cardShortYear = regexp.MustCompile(`'(\d{2})\b`)
+)
+
+// scanCardSummary looks for the billing details of a credit card statement in
+// its rows. A value is read from the rest of the labelled cell ("Due Date:
+// 05/12/2025"), the next cell in the row, or the cell below the label when
+// the details are laid out as a table. Values that cannot be read are left nil.
+func scanCardSummary(rows [][]string) models.CardStatementSummary {
+	var summary models.CardStatementSummary
+	for r, row := range rows {
+		for c, cell := range row {
+			label := strings.ToLower(strings.Join(strings.Fields(cell), " "))
+			for _, field := range cardSummaryLabels {
+				rest, ok := cutLabel(label, cell, field.labels)
+				if !ok {
+					continue
+				}
+				for _, value := range cardSummaryCandidates(rows, r, c, rest) {
+					if setCardSummaryField(&summary, field.field, value) {
+						break
+					}
+				}
+				break
+			}
+		}
+	}
+	return summary
+}
+
+// cutLabel returns what follows the label at the start of a cell, with the
+// separating colon removed.
+func cutLabel(label string, cell string, labels []string) (string, bool) {
+	for _, l := range labels {
+		if !strings.HasPrefix(label, l) {
+			continue
+		}
+		rest := strings.Join(strings.Fields(cell), " ")[len(l):]
+		return strings.TrimSpace(strings.TrimLeft(rest, " :-")), true
+	}
+	return "", false
+}
+
+func cardSummaryCandidates(rows [][]string, r int, c int, rest string) []string {
+	var candidates []string
+	if rest != "" {
+		candidates = append(candidates, rest)
+	}
+	for _, cell := range rows[r][c+1:] {
+		if cell = strings.TrimSpace(cell); cell != "" {
+			candidates = append(candidates, cell)
+			break
+		}
+	}
+	if r+1 < len(rows) && c < len(rows[r+1]) {
+		if cell := strings.TrimSpace(rows[r+1][c]); cell != "" {
+			candidates = append(candidates, cell)
+		}
+	}
+	return candidates
+}
+
+// setCardSummaryField stores a value in the summary if it parses and the
+// field is not set yet. It reports whether the value was used.
+func setCardSummaryField(summary *models.CardStatementSummary, field string, value string) bool {
+	switch field {
+	case "period":
+		if summary.StatementPeriodStart != nil {
+			return true
+		}
+		parts := cardPeriodSeparator.Split(value, 2)
+		if len(parts) != 2 {
+			return false
+		}
+		start, err := parseCardDate(parts[0])
+		if err != nil {
+			return false
+		}
+		end, err := parseCardDate(parts[1])
+		if err != nil {
+			return false
+		}
+		summary.StatementPeriodStart, summary.StatementPeriodEnd = &start, &end
+	case "due_date":
+		if summary.PaymentDueDate != nil {
+			return true
+		}
+		date, err := parseCardDate(value)
+		if err != nil {
+			return false
+		}
+		summary.PaymentDueDate = &date
+	case "total", "minimum":
+		target := &summary.TotalDue
+		if field == "minimum" {
+			target = &summary.MinimumDue
+		}
+		if *target != nil {
+			return true
+		}
+		amount, err := parseCardAmount(value)
+		if err != nil {
+			return false
+		}
+		*target = &amount
+	}
+	return true
+}
+
+func parseCardDate(value string) (time.Time, error) {
+	value = cardShortYear.ReplaceAllString(strings.TrimSpace(value), "20$1")
+	return parseDateWithLayouts(value,
+		"02 Jan 2006", "2 Jan 2006", "02-Jan-2006", "02 Jan, 2006", "Jan 2, 2006", "January 2, 2006",
+		"02/01/2006", "02-01-2006", "02/01/06")
+}
+
+// parseCardAmount reads an amount due. A trailing Cr marks a credit balance,
+// which is returned as a negative amount.
+func parseCardAmount(value string) (float64, error) {
+	s := strings.TrimSpace(stripCurrency(value))
+	lower := strings.ToLower(s)
+	negative := false
+	if trimmed, ok := strings.CutSuffix(lower, "cr"); ok {
+		s, negative = s[:len(trimmed)], true
+	} else if trimmed, ok := strings.CutSuffix(lower, "dr"); ok {
+		s = s[:len(trimmed)]
+	}
+	s = strings.TrimSpace(stripCurrency(s))
+	if s == "" {
+		return 0, errors.New("empty amount")
+	}
+	amount, err := utils.ParseFloat(s)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("scanCardSummary", func() {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	It("reads values written next to their labels", func() {
+		summary := scanCardSummary([][]string{
+			{"Statement Period", "15 Oct 2025 - 14 Nov 2025"},
+			{"Payment Due Date", "04 Dec 2025"},
+			{"Total Amount Due", "₹ 18,933.38"},
+			{"Minimum Amount Due", "₹ 950.00"},
+		})
+		Expect(*summary.StatementPeriodStart).To(Equal(date(2025, 10, 15)))
+		Expect(*summary.StatementPeriodEnd).To(Equal(date(2025, 11, 14)))
+		Expect(*summary.PaymentDueDate).To(Equal(date(2025, 12, 4)))
+		Expect(*summary.TotalDue).To(Equal(18933.38))
+		Expect(*summary.MinimumDue).To(Equal(950.00))
+	})
+
+	It("reads values in the same cell as their labels", func() {
+		summary := scanCardSummary([][]string{
+			{"Statement period : 15/10/2025 To 14/11/2025"},
+			{"Due Date: 04/12/2025"},
+			{"Total Due: Rs. 1,200.50 Dr"},
+		})
+		Expect(*summary.StatementPeriodStart).To(Equal(date(2025, 10, 15)))
+		Expect(*summary.StatementPeriodEnd).To(Equal(date(2025, 11, 14)))
+		Expect(*summary.PaymentDueDate).To(Equal(date(2025, 12, 4)))
+		Expect(*summary.TotalDue).To(Equal(1200.50))
+		Expect(summary.MinimumDue).To(BeNil())
+	})
+
+	It("reads values laid out below their labels", func() {
+		summary := scanCardSummary([][]string{
+			{"Total Payment Due", "Minimum Payment Due", "Statement Period", "Payment Due Date"},
+			{"18,933.38", "950.00", "15 Oct '25 - 14 Nov '25", "04 Dec '25"},
+		})
+		Expect(*summary.TotalDue).To(Equal(18933.38))
+		Expect(*summary.MinimumDue).To(Equal(950.00))
+		Expect(*summary.StatementPeriodStart).To(Equal(date(2025, 10, 15)))
+		Expect(*summary.PaymentDueDate).To(Equal(date(2025, 12, 4)))
+	})
+
+	It("returns credit balances as negative amounts", func() {
+		summary := scanCardSummary([][]string{{"Total Amount Due", "250.00 Cr"}})
+		Expect(*summary.TotalDue).To(Equal(-250.00))
+	})
+
+	It("leaves values that cannot be read empty", func() {
+		summary := scanCardSummary([][]string{
+			{"Payment Due Date", "Immediate"},
+			{"Date", "Transaction Details", "Amount"},
+			{"01/11/2025", "SHOP", "100.00"},
+		})
+		Expect(summary.PaymentDueDate).To(BeNil())
+		Expect(summary.StatementPeriodStart).To(BeNil())
+		Expect(summary.TotalDue).To(BeNil())
+	})
+})
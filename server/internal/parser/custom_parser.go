@@ -7,7 +7,6 @@ import (
 	"errors"
 	"expenses/internal/models"
 	"expenses/pkg/logger"
-	"expenses/pkg/utils"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -18,13 +17,32 @@ import (
 type CustomParser struct{}
 
 // StatementMetadata defines the structure for custom parser configuration.
+// Only the column mapping is required; the remaining options describe how
+// dates and amounts are written in the file.
 type StatementMetadata struct {
 	SkipRows      int               `json:"skip_rows"`
 	ColumnMapping map[string]string `json:"column_mapping"`
+	// SheetSelector picks the worksheet of Excel statements.
+	models.SheetSelector
+	// DateFormats are tried in order, e.g. "DD/MM/YYYY" or "DD-MMM-YY".
+	DateFormats []string   `json:"date_formats,omitempty"`
+	AmountSign  AmountSign `json:"amount_sign,omitempty"`
+	// Indicator overrides the default Dr/Cr values of the indicator column.
+	Indicator         *IndicatorMapping `json:"indicator,omitempty"`
+	ThousandSeparator string            `json:"thousand_separator,omitempty"`
+	DecimalSeparator  string            `json:"decimal_separator,omitempty"`
+	// DescriptionColumns are joined with DescriptionSeparator (a space by
+	// default) to build the description, skipping empty cells.
+	DescriptionColumns   []string `json:"description_columns,omitempty"`
+	DescriptionSeparator string   `json:"description_separator,omitempty"`
 }
 
 var ErrWorkbookPasswordRequired = errors.New("workbook password required")
 
+// ErrInvalidSheetSelection is returned when the selected sheet is not in the
+// workbook or the selection itself is malformed.
+var ErrInvalidSheetSelection = errors.New("invalid sheet selection")
+
 func openWorkbook(fileBytes []byte, password string) (*excelize.File, error) {
 	f, err := excelize.OpenReader(bytes.NewReader(fileBytes), excelize.Options{Password: password})
 	if err != nil {
@@ -61,6 +79,39 @@ func ValidateWorkbookPassword(fileBytes []byte, password string) error {
 	return nil
 }
 
+func validateSheetSelector(sheet models.SheetSelector) error {
+	if sheet.SheetName != "" && sheet.SheetIndex != nil {
+		return fmt.Errorf("%w: select the sheet either by sheet_name or by sheet_index, not both", ErrInvalidSheetSelection)
+	}
+	if sheet.SheetIndex != nil && *sheet.SheetIndex < 0 {
+		return fmt.Errorf("%w: sheet_index cannot be negative", ErrInvalidSheetSelection)
+	}
+	return nil
+}
+
+// selectSheet returns the position of the selected sheet in a workbook. Names
+// are matched ignoring case.
+func selectSheet(sheetNames []string, sheet models.SheetSelector) (int, error) {
+	if err := validateSheetSelector(sheet); err != nil {
+		return 0, err
+	}
+	if sheet.SheetName != "" {
+		for i, name := range sheetNames {
+			if strings.EqualFold(name, sheet.SheetName) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("%w: no sheet named '%s', the workbook has %s", ErrInvalidSheetSelection, sheet.SheetName, strings.Join(sheetNames, ", "))
+	}
+	if sheet.SheetIndex != nil {
+		if *sheet.SheetIndex >= len(sheetNames) {
+			return 0, fmt.Errorf("%w: sheet_index %d is out of range, the workbook has %d sheets", ErrInvalidSheetSelection, *sheet.SheetIndex, len(sheetNames))
+		}
+		return *sheet.SheetIndex, nil
+	}
+	return 0, nil
+}
+
 // trimRecords iterates through a 2D string slice and trims whitespace from each element.
 func trimRecords(records [][]string) [][]string {
 	logger.Debugf("CustomParser.trimRecords: Trimming %d records", len(records))
@@ -82,7 +133,7 @@ func trimRecords(records [][]string) [][]string {
 	return trimmed
 }
 
-func (p *CustomParser) Preview(fileBytes []byte, fileName string, skipRows int, rowSize int, password string) (*models.StatementPreview, error) {
+func (p *CustomParser) Preview(fileBytes []byte, fileName string, skipRows int, rowSize int, password string, sheet models.SheetSelector) (*models.StatementPreview, error) {
 	logger.Debugf("CustomParser.Preview: Starting preview for file '%s', skipRows=%d, rowSize=%d", fileName, skipRows, rowSize)
 	logger.Debugf("CustomParser.Preview: File size: %d bytes", len(fileBytes))
 
@@ -91,6 +142,8 @@ func (p *CustomParser) Preview(fileBytes []byte, fileName string, skipRows int,
 	logger.Debugf("CustomParser.Preview: Detected file extension: %s", extension)
 
 	var records [][]string
+	var sheetNames []string
+	var sheetName string
 	var err error
 
 	switch extension {
@@ -105,6 +158,32 @@ func (p *CustomParser) Preview(fileBytes []byte, fileName string, skipRows int,
 		}
 		logger.Debugf("CustomParser.Preview: Successfully read %d CSV records", len(records))
 	case ".xls":
+		if isCompoundFile(fileBytes) {
+			logger.Debugf("CustomParser.Preview: Processing as Excel 97-2003 workbook")
+			sheets, err := readXLSSheets(fileBytes)
+			if err != nil {
+				logger.Debugf("CustomParser.Preview: Failed to read XLS workbook: %v", err)
+				return nil, err
+			}
+			if len(sheets) == 0 {
+				return nil, errors.New("no sheets found in XLS file")
+			}
+			for _, s := range sheets {
+				sheetNames = append(sheetNames, s.Name)
+			}
+			idx, err := selectSheet(sheetNames, sheet)
+			if err != nil {
+				return nil, err
+			}
+			sheetName = sheetNames[idx]
+			records = sheets[idx].Rows
+			logger.Debugf("CustomParser.Preview: Successfully read %d XLS records", len(records))
+			break
+		}
+		// Many banks export tab separated text with an .xls extension.
+		if bytes.IndexByte(headOf(fileBytes, 512), 0) != -1 {
+			return nil, errors.New("xls file is neither an Excel 97-2003 workbook nor tab separated text")
+		}
 		logger.Debugf("CustomParser.Preview: Processing as XLS file (tab-separated)")
 		r := csv.NewReader(bytes.NewReader(fileBytes))
 		r.Comma = '\t'
@@ -123,11 +202,16 @@ func (p *CustomParser) Preview(fileBytes []byte, fileName string, skipRows int,
 			return nil, fmt.Errorf("failed to open xlsx file: %w", err)
 		}
 		defer f.Close()
-		sheets := f.GetSheetList()
-		if len(sheets) == 0 {
+		sheetNames = f.GetSheetList()
+		if len(sheetNames) == 0 {
 			return nil, errors.New("no sheets found in XLSX file")
 		}
-		records, err = f.GetRows(sheets[0])
+		idx, err := selectSheet(sheetNames, sheet)
+		if err != nil {
+			return nil, err
+		}
+		sheetName = sheetNames[idx]
+		records, err = f.GetRows(sheetName)
 		if err != nil {
 			logger.Debugf("CustomParser.Preview: Failed to read rows from sheet: %v", err)
 			return nil, fmt.Errorf("failed to read rows from sheet: %w", err)
@@ -144,7 +228,7 @@ func (p *CustomParser) Preview(fileBytes []byte, fileName string, skipRows int,
 
 	if len(records) <= skipRows {
 		logger.Debugf("CustomParser.Preview: Not enough records (%d) to skip %d rows, returning empty preview", len(records), skipRows)
-		return &models.StatementPreview{Headers: []string{}, Rows: [][]string{}}, nil
+		return &models.StatementPreview{Headers: []string{}, Rows: [][]string{}, Sheets: sheetNames, Sheet: sheetName}, nil
 	}
 
 	// Skip rows to drop the metadata or header rows
@@ -153,7 +237,7 @@ func (p *CustomParser) Preview(fileBytes []byte, fileName string, skipRows int,
 
 	if len(records) == 0 {
 		logger.Debugf("CustomParser.Preview: No records remaining after skipping rows")
-		return &models.StatementPreview{Headers: []string{}, Rows: [][]string{}}, nil
+		return &models.StatementPreview{Headers: []string{}, Rows: [][]string{}, Sheets: sheetNames, Sheet: sheetName}, nil
 	}
 
 	headers := records[0]
@@ -169,6 +253,8 @@ func (p *CustomParser) Preview(fileBytes []byte, fileName string, skipRows int,
 	preview := &models.StatementPreview{
 		Headers: headers,
 		Rows:    dataRows,
+		Sheets:  sheetNames,
+		Sheet:   sheetName,
 	}
 
 	logger.Debugf("CustomParser.Preview: Preview generated successfully with %d headers and %d rows", len(preview.Headers), len(preview.Rows))
@@ -177,26 +263,35 @@ func (p *CustomParser) Preview(fileBytes []byte, fileName string, skipRows int,
 
 // Parse processes a file using metadata to map columns and create transactions.
 func (p *CustomParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *CustomParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
 	logger.Debugf("CustomParser.Parse: Starting parse for file '%s'", fileName)
 	logger.Debugf("CustomParser.Parse: File size: %d bytes", len(fileBytes))
 	logger.Debugf("CustomParser.Parse: Metadata: %s", metadata)
 
 	if metadata == "" {
 		logger.Debugf("CustomParser.Parse: No metadata provided")
-		return nil, errors.New("metadata is required for custom parser")
+		return nil, nil, errors.New("metadata is required for custom parser")
 	}
 
 	var meta StatementMetadata
 	if err := json.Unmarshal([]byte(metadata), &meta); err != nil {
 		logger.Debugf("CustomParser.Parse: Failed to unmarshal metadata: %v", err)
-		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		return nil, nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
 	}
 	logger.Debugf("CustomParser.Parse: Parsed metadata - SkipRows: %d, ColumnMapping: %v", meta.SkipRows, meta.ColumnMapping)
+	if err := meta.validateOptions(); err != nil {
+		logger.Debugf("CustomParser.Parse: Invalid metadata options: %v", err)
+		return nil, nil, err
+	}
 
-	preview, err := p.Preview(fileBytes, fileName, meta.SkipRows, -1, password)
+	preview, err := p.Preview(fileBytes, fileName, meta.SkipRows, -1, password, meta.SheetSelector)
 	if err != nil {
 		logger.Debugf("CustomParser.Parse: Failed to preview file: %v", err)
-		return nil, fmt.Errorf("failed to preview file for parsing: %w", err)
+		return nil, nil, fmt.Errorf("failed to preview file for parsing: %w", err)
 	}
 	logger.Debugf("CustomParser.Parse: Preview generated with %d headers and %d rows", len(preview.Headers), len(preview.Rows))
 
@@ -208,6 +303,7 @@ func (p *CustomParser) Parse(fileBytes []byte, metadata string, fileName string,
 	logger.Debugf("CustomParser.Parse: Created header index map: %v", headerIndex)
 
 	columnIndex := make(map[string]int)
+	var descriptionColumns []int
 	for field, columnName := range meta.ColumnMapping {
 		if idx, ok := headerIndex[columnName]; ok {
 			columnIndex[field] = idx
@@ -215,29 +311,40 @@ func (p *CustomParser) Parse(fileBytes []byte, metadata string, fileName string,
 		} else {
 			if field != "description" {
 				logger.Debugf("CustomParser.Parse: Required column '%s' not found in headers", columnName)
-				return nil, fmt.Errorf("mapped column '%s' not found in statement header", columnName)
+				return nil, nil, fmt.Errorf("mapped column '%s' not found in statement header", columnName)
 			}
 			logger.Debugf("CustomParser.Parse: Optional field '%s' column '%s' not found, skipping", field, columnName)
 		}
 	}
+	for _, columnName := range meta.DescriptionColumns {
+		idx, ok := headerIndex[columnName]
+		if !ok {
+			return nil, nil, fmt.Errorf("description column '%s' not found in statement header", columnName)
+		}
+		descriptionColumns = append(descriptionColumns, idx)
+	}
 	logger.Debugf("CustomParser.Parse: Final column index mapping: %v", columnIndex)
 
 	err = p.validateMappings(columnIndex)
 	if err != nil {
 		logger.Debugf("CustomParser.Parse: Validation failed: %v", err)
-		return nil, err
+		return nil, nil, err
 	}
 	logger.Debugf("CustomParser.Parse: Column mapping validation passed")
 
 	var transactions []models.CreateTransactionInput
+	var rowErrors []models.StatementRowError
 	logger.Debugf("CustomParser.Parse: Starting to parse %d data rows", len(preview.Rows))
 
 	for i, row := range preview.Rows {
 		logger.Debugf("CustomParser.Parse: Processing row %d: %v", i+1, row)
-		transaction, err := p.parseRow(row, columnIndex)
+		transaction, err := p.parseRow(row, columnIndex, descriptionColumns, &meta)
 		if err != nil {
-			logger.Debugf("CustomParser.Parse: Failed to parse row %d: %v", i+meta.SkipRows+2, err)
-			logger.Warnf("skipping row %d due to parsing error: %v", i+meta.SkipRows+2, err)
+			lineNumber := i + meta.SkipRows + 2
+			err = fmt.Errorf("row %d: %w", lineNumber, err)
+			logger.Debugf("CustomParser.Parse: Failed to parse row %d: %v", lineNumber, err)
+			logger.Warnf("skipping row %d due to parsing error: %v", lineNumber, err)
+			rowErrors = append(rowErrors, newRowError(lineNumber, row, err))
 			continue
 		}
 		logger.Debugf("CustomParser.Parse: Successfully parsed row %d into transaction: Name='%s', Amount=%v, Date=%v",
@@ -246,7 +353,32 @@ func (p *CustomParser) Parse(fileBytes []byte, metadata string, fileName string,
 	}
 
 	logger.Debugf("CustomParser.Parse: Parse completed successfully with %d transactions", len(transactions))
-	return transactions, nil
+	return transactions, rowErrors, nil
+}
+
+// ValidateStatementMetadata checks that custom parser metadata can be read and
+// maps every field a transaction needs, without looking at a file.
+func ValidateStatementMetadata(metadata string) error {
+	var meta StatementMetadata
+	if err := json.Unmarshal([]byte(metadata), &meta); err != nil {
+		return fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	if meta.SkipRows < 0 {
+		return errors.New("skip_rows cannot be negative")
+	}
+	if err := validateSheetSelector(meta.SheetSelector); err != nil {
+		return err
+	}
+	if err := meta.validateOptions(); err != nil {
+		return err
+	}
+	mapped := make(map[string]int, len(meta.ColumnMapping))
+	for field, columnName := range meta.ColumnMapping {
+		if strings.TrimSpace(columnName) != "" {
+			mapped[field] = 0
+		}
+	}
+	return (&CustomParser{}).validateMappings(mapped)
 }
 
 func (p *CustomParser) validateMappings(columnIndex map[string]int) error {
@@ -278,7 +410,8 @@ func (p *CustomParser) validateMappings(columnIndex map[string]int) error {
 }
 
 // parseRow parses a single row into a transaction based on the column index map.
-func (p *CustomParser) parseRow(row []string, columnIndex map[string]int) (*models.CreateTransactionInput, error) {
+// Errors name the column whose value could not be read.
+func (p *CustomParser) parseRow(row []string, columnIndex map[string]int, descriptionColumns []int, meta *StatementMetadata) (*models.CreateTransactionInput, error) {
 	logger.Debugf("CustomParser.parseRow: Parsing row with %d columns: %v", len(row), row)
 
 	dateStr, err := p.getRequiredField(row, columnIndex, "txn_date")
@@ -296,16 +429,19 @@ func (p *CustomParser) parseRow(row []string, columnIndex map[string]int) (*mode
 	logger.Debugf("CustomParser.parseRow: Extracted name: '%s'", name)
 
 	description := p.getOptionalField(row, columnIndex, "description")
+	if len(descriptionColumns) > 0 {
+		description = joinColumns(row, descriptionColumns, meta.DescriptionSeparator)
+	}
 	logger.Debugf("CustomParser.parseRow: Extracted description: '%s'", description)
 
-	date, err := utils.ParseDate(dateStr)
+	date, err := meta.parseDate(dateStr)
 	if err != nil {
 		logger.Debugf("CustomParser.parseRow: Failed to parse date '%s': %v", dateStr, err)
-		return nil, err
+		return nil, fmt.Errorf("column '%s': %w", meta.ColumnMapping["txn_date"], err)
 	}
 	logger.Debugf("CustomParser.parseRow: Parsed date: %v", date)
 
-	amount, err := p.getAmount(row, columnIndex)
+	amount, err := p.getAmount(row, columnIndex, meta)
 	if err != nil {
 		logger.Debugf("CustomParser.parseRow: Failed to get amount: %v", err)
 		return nil, err
@@ -326,38 +462,59 @@ func (p *CustomParser) parseRow(row []string, columnIndex map[string]int) (*mode
 	return tx, nil
 }
 
-func (p *CustomParser) getAmount(row []string, columnIndex map[string]int) (*float64, error) {
+// joinColumns concatenates the non-empty cells of the given columns.
+func joinColumns(row []string, columns []int, separator string) string {
+	if separator == "" {
+		separator = " "
+	}
+	parts := make([]string, 0, len(columns))
+	for _, idx := range columns {
+		if idx < len(row) && row[idx] != "" {
+			parts = append(parts, row[idx])
+		}
+	}
+	return strings.Join(parts, separator)
+}
+
+func (p *CustomParser) getAmount(row []string, columnIndex map[string]int, meta *StatementMetadata) (*float64, error) {
 	logger.Debugf("CustomParser.getAmount: Extracting amount from row")
 
 	var amount float64
 	amountStr, amountOk := p.getOptionalFieldOk(row, columnIndex, "amount")
 	creditStr, creditOk := p.getOptionalFieldOk(row, columnIndex, "credit")
 	debitStr, debitOk := p.getOptionalFieldOk(row, columnIndex, "debit")
+	indicatorStr, indicatorOk := p.getOptionalFieldOk(row, columnIndex, "indicator")
 
-	logger.Debugf("CustomParser.getAmount: Field values - amount: '%s' (ok=%v), credit: '%s' (ok=%v), debit: '%s' (ok=%v)",
-		amountStr, amountOk, creditStr, creditOk, debitStr, debitOk)
+	logger.Debugf("CustomParser.getAmount: Field values - amount: '%s' (ok=%v), credit: '%s' (ok=%v), debit: '%s' (ok=%v), indicator: '%s' (ok=%v)",
+		amountStr, amountOk, creditStr, creditOk, debitStr, debitOk, indicatorStr, indicatorOk)
 
 	if amountOk && amountStr != "" {
 		logger.Debugf("CustomParser.getAmount: Using amount field: '%s'", amountStr)
-		val, err := utils.ParseFloat(amountStr)
+		val, err := meta.parseAmount(amountStr)
 		if err != nil {
 			logger.Debugf("CustomParser.getAmount: Failed to parse amount '%s': %v", amountStr, err)
-			return nil, fmt.Errorf("failed to parse amount: %w", err)
+			return nil, fmt.Errorf("column '%s': failed to parse amount: %w", meta.ColumnMapping["amount"], err)
 		}
 		amount = val
+		if indicatorOk {
+			amount, err = meta.applyIndicator(amount, indicatorStr)
+			if err != nil {
+				return nil, fmt.Errorf("column '%s': %w", meta.ColumnMapping["indicator"], err)
+			}
+		}
 		logger.Debugf("CustomParser.getAmount: Parsed amount: %f", amount)
 	} else if creditOk && debitOk {
 		logger.Debugf("CustomParser.getAmount: Using credit/debit fields - credit: '%s', debit: '%s'", creditStr, debitStr)
 
-		credit, err := utils.ParseFloat(creditStr)
+		credit, err := meta.parseAmount(creditStr)
 		if err != nil && creditStr != "" {
 			logger.Debugf("CustomParser.getAmount: Failed to parse credit '%s': %v", creditStr, err)
-			return nil, fmt.Errorf("failed to parse credit: %w", err)
+			return nil, fmt.Errorf("column '%s': failed to parse credit: %w", meta.ColumnMapping["credit"], err)
 		}
-		debit, err := utils.ParseFloat(debitStr)
+		debit, err := meta.parseAmount(debitStr)
 		if err != nil && debitStr != "" {
 			logger.Debugf("CustomParser.getAmount: Failed to parse debit '%s': %v", debitStr, err)
-			return nil, fmt.Errorf("failed to parse debit: %w", err)
+			return nil, fmt.Errorf("column '%s': failed to parse debit: %w", meta.ColumnMapping["debit"], err)
 		}
 
 		logger.Debugf("CustomParser.getAmount: Parsed values - credit: %f, debit: %f", credit, debit)
@@ -374,6 +531,7 @@ func (p *CustomParser) getAmount(row []string, columnIndex map[string]int) (*flo
 		return nil, errors.New("insufficient amount information: map either 'amount' or both 'credit' and 'debit'")
 	}
 
+	amount = meta.applySign(amount)
 	logger.Debugf("CustomParser.getAmount: Final amount: %f", amount)
 	return &amount, nil
 }
@@ -427,5 +585,10 @@ func (p *CustomParser) getOptionalFieldOk(row []string, columnIndex map[string]i
 
 func init() {
 	logger.Debugf("CustomParser.init: Registering CustomParser for BankTypeOthers")
-	RegisterParser(models.BankTypeOthers, &CustomParser{})
+	RegisterParser(models.BankTypeOthers, &CustomParser{}, models.ParserInfo{
+		Name:          "Custom CSV or Excel",
+		Extensions:    []string{".csv", ".xls", ".xlsx"},
+		NeedsPassword: true,
+		NeedsMetadata: true,
+	})
 }
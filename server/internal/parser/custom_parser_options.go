@@ -0,0 +1,296 @@
+package parser
+
+import (
+	"errors"
+	"expenses/pkg/utils"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AmountSign decides which direction of money becomes a positive amount.
+type AmountSign string
+
+const (
+	// AmountSignCreditPositive keeps signed amounts as they are in the file and
+	// makes credits positive and debits negative. It is the default.
+	AmountSignCreditPositive AmountSign = "credit_positive"
+	// AmountSignDebitPositive flips the sign, for exports where money spent is
+	// written as a positive number.
+	AmountSignDebitPositive AmountSign = "debit_positive"
+)
+
+// IndicatorMapping lists the values of the column mapped as "indicator" that
+// mark a row as a debit or a credit. The amount column then only holds the
+// magnitude. Values are matched ignoring case and a trailing dot.
+type IndicatorMapping struct {
+	DebitValues  []string `json:"debit_values"`
+	CreditValues []string `json:"credit_values"`
+}
+
+var (
+	defaultDebitIndicators  = []string{"dr", "d", "debit"}
+	defaultCreditIndicators = []string{"cr", "c", "credit"}
+	// currencyCodes are stripped from amounts along with currency symbols.
+	currencyCodes = []string{"inr", "rs.", "rs"}
+)
+
+// dateFormatTokens maps the tokens accepted in date_formats to Go layouts.
+// Longer tokens come first so "MMM" is not read as "MM" followed by "M".
+var dateFormatTokens = []struct {
+	token  string
+	layout string
+}{
+	{"YYYY", "2006"},
+	{"YY", "06"},
+	{"MMMM", "January"},
+	{"MMM", "Jan"},
+	{"MM", "01"},
+	{"M", "1"},
+	{"DD", "02"},
+	{"D", "2"},
+	{"HH", "15"},
+	{"mm", "04"},
+	{"ss", "05"},
+}
+
+// toGoLayout converts a date format such as "DD/MM/YYYY" or "DD-MMM-YY" into
+// a layout for time.Parse.
+func toGoLayout(format string) (string, error) {
+	var layout strings.Builder
+	var hasYear, hasMonth, hasDay bool
+	for rest := format; rest != ""; {
+		matched := false
+		for _, t := range dateFormatTokens {
+			if strings.HasPrefix(rest, t.token) {
+				layout.WriteString(t.layout)
+				switch t.token[0] {
+				case 'Y':
+					hasYear = true
+				case 'M':
+					hasMonth = true
+				case 'D':
+					hasDay = true
+				}
+				rest = rest[len(t.token):]
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(rest)
+		if unicode.IsDigit(r) {
+			return "", fmt.Errorf("date format '%s' cannot contain digits", format)
+		}
+		layout.WriteRune(r)
+		rest = rest[size:]
+	}
+	if !hasYear || !hasMonth || !hasDay {
+		return "", fmt.Errorf("date format '%s' must contain a day (DD), month (MM or MMM) and year (YYYY or YY)", format)
+	}
+	return layout.String(), nil
+}
+
+// validateOptions checks the parsing options of the metadata. Column mappings
+// are checked separately since they depend on the statement header.
+func (m *StatementMetadata) validateOptions() error {
+	switch m.AmountSign {
+	case "", AmountSignCreditPositive, AmountSignDebitPositive:
+	default:
+		return fmt.Errorf("unknown amount_sign '%s': use '%s' or '%s'", m.AmountSign, AmountSignCreditPositive, AmountSignDebitPositive)
+	}
+
+	for _, format := range m.DateFormats {
+		if _, err := toGoLayout(format); err != nil {
+			return err
+		}
+	}
+
+	thousand, decimal := m.separators()
+	if utf8.RuneCountInString(thousand) != 1 {
+		return fmt.Errorf("thousand_separator '%s' must be a single character", thousand)
+	}
+	if utf8.RuneCountInString(decimal) != 1 {
+		return fmt.Errorf("decimal_separator '%s' must be a single character", decimal)
+	}
+	if thousand == decimal {
+		return errors.New("thousand_separator and decimal_separator must be different")
+	}
+	if strings.ContainsAny(thousand+decimal, "0123456789-") {
+		return errors.New("separators cannot be digits or '-'")
+	}
+
+	_, indicatorMapped := m.ColumnMapping["indicator"]
+	if m.Indicator != nil {
+		if !indicatorMapped {
+			return errors.New("indicator values are set but no 'indicator' column is mapped")
+		}
+		debit, credit := m.indicatorValues()
+		if len(debit) == 0 || len(credit) == 0 {
+			return errors.New("indicator needs at least one debit and one credit value")
+		}
+		for _, value := range debit {
+			for _, other := range credit {
+				if value == other {
+					return fmt.Errorf("indicator value '%s' is used for both debit and credit", value)
+				}
+			}
+		}
+	}
+	if indicatorMapped {
+		if _, ok := m.ColumnMapping["amount"]; !ok {
+			return errors.New("the 'indicator' column needs an 'amount' column with the transaction amount")
+		}
+	}
+
+	if len(m.DescriptionColumns) > 0 {
+		if _, ok := m.ColumnMapping["description"]; ok {
+			return errors.New("map the description either in column_mapping or in description_columns, not both")
+		}
+		for i, column := range m.DescriptionColumns {
+			if strings.TrimSpace(column) == "" {
+				return fmt.Errorf("description_columns entry %d is empty", i+1)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *StatementMetadata) separators() (string, string) {
+	thousand, decimal := m.ThousandSeparator, m.DecimalSeparator
+	if thousand == "" {
+		thousand = ","
+	}
+	if decimal == "" {
+		decimal = "."
+	}
+	return thousand, decimal
+}
+
+func (m *StatementMetadata) indicatorValues() ([]string, []string) {
+	if m.Indicator == nil {
+		return defaultDebitIndicators, defaultCreditIndicators
+	}
+	return normalizeIndicators(m.Indicator.DebitValues), normalizeIndicators(m.Indicator.CreditValues)
+}
+
+func normalizeIndicators(values []string) []string {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = normalizeIndicator(value); value != "" {
+			normalized = append(normalized, value)
+		}
+	}
+	return normalized
+}
+
+func normalizeIndicator(value string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), ".")
+}
+
+// parseDate reads a date with the configured formats, falling back to the
+// common layouts when none are configured.
+func (m *StatementMetadata) parseDate(value string) (time.Time, error) {
+	if len(m.DateFormats) == 0 {
+		return utils.ParseDate(value)
+	}
+	for _, format := range m.DateFormats {
+		layout, err := toGoLayout(format)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date '%s' does not match any of the formats %s", value, strings.Join(m.DateFormats, ", "))
+}
+
+// parseAmount reads an amount written with the configured separators. Currency
+// symbols and codes are ignored, thousand separators may appear anywhere so
+// lakh grouping (1,00,000.00) works, and amounts in parentheses are negative.
+func (m *StatementMetadata) parseAmount(value string) (float64, error) {
+	s := strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+	s = stripCurrency(s)
+
+	thousand, decimal := m.separators()
+	var cleaned strings.Builder
+	for _, r := range s {
+		switch {
+		case string(r) == thousand, unicode.IsSpace(r):
+		case string(r) == decimal:
+			cleaned.WriteRune('.')
+		default:
+			cleaned.WriteRune(r)
+		}
+	}
+	if cleaned.Len() == 0 {
+		return 0, errors.New("empty amount")
+	}
+
+	amount, err := strconv.ParseFloat(cleaned.String(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount '%s'", value)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func stripCurrency(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Sc, r) {
+			return -1
+		}
+		return r
+	}, value)
+	value = strings.TrimSpace(value)
+	for _, code := range currencyCodes {
+		lower := strings.ToLower(value)
+		if strings.HasPrefix(lower, code) {
+			value = strings.TrimSpace(value[len(code):])
+		} else if strings.HasSuffix(lower, code) {
+			value = strings.TrimSpace(value[:len(value)-len(code)])
+		}
+	}
+	return value
+}
+
+// applyIndicator signs the magnitude of an amount from the indicator value of
+// the row, making credits positive.
+func (m *StatementMetadata) applyIndicator(amount float64, indicator string) (float64, error) {
+	value := normalizeIndicator(indicator)
+	debit, credit := m.indicatorValues()
+	for _, v := range debit {
+		if v == value {
+			return -math.Abs(amount), nil
+		}
+	}
+	for _, v := range credit {
+		if v == value {
+			return math.Abs(amount), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown debit/credit indicator '%s'", indicator)
+}
+
+// applySign orients an amount computed with credits positive according to the
+// configured amount_sign.
+func (m *StatementMetadata) applySign(amount float64) float64 {
+	if m.AmountSign == AmountSignDebitPositive {
+		return -amount
+	}
+	return amount
+}
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"expenses/internal/models"
+	"expenses/pkg/utils"
+
+	"github.com/xuri/excelize/v2"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -14,7 +17,6 @@ var _ = Describe("CustomParser", func() {
 
 	BeforeEach(func() {
 		p = &CustomParser{}
-		RegisterParser(models.BankTypeOthers, &CustomParser{})
 	})
 
 	Describe("Preview", func() {
@@ -26,7 +28,7 @@ Row3Col1,Row3Col2,Row3Col3`
 			fileBytes := []byte(csvContent)
 
 			It("should parse correctly with no rows skipped and all rows returned", func() {
-				preview, err := p.Preview(fileBytes, "test.csv", 0, -1, "")
+				preview, err := p.Preview(fileBytes, "test.csv", 0, -1, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview.Headers).To(Equal([]string{"Header1", "Header2", "Header3"}))
 				Expect(preview.Rows).To(HaveLen(3))
@@ -38,14 +40,14 @@ Row3Col1,Row3Col2,Row3Col3`
 Generated on 2023-10-27
 Header1,Header2,Header3
 Row1Col1,Row1Col2,Row1Col3`
-				preview, err := p.Preview([]byte(csvWithMeta), "test.csv", 2, -1, "")
+				preview, err := p.Preview([]byte(csvWithMeta), "test.csv", 2, -1, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview.Headers).To(Equal([]string{"Header1", "Header2", "Header3"}))
 				Expect(preview.Rows).To(HaveLen(1))
 			})
 
 			It("should limit the number of data rows when rowSize is specified", func() {
-				preview, err := p.Preview(fileBytes, "test.csv", 0, 2, "")
+				preview, err := p.Preview(fileBytes, "test.csv", 0, 2, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview.Rows).To(HaveLen(2))
 			})
@@ -53,7 +55,7 @@ Row1Col1,Row1Col2,Row1Col3`
 			It("should trim leading and trailing spaces from headers and fields", func() {
 				csvWithSpaces := ` Header1,  Header2  ,Header3
   Row1Col1  ,Row1Col2  , Row1Col3 `
-				preview, err := p.Preview([]byte(csvWithSpaces), "test.csv", 0, -1, "")
+				preview, err := p.Preview([]byte(csvWithSpaces), "test.csv", 0, -1, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview.Headers).To(Equal([]string{"Header1", "Header2", "Header3"}))
 				Expect(preview.Rows[0]).To(Equal([]string{"Row1Col1", "Row1Col2", "Row1Col3"}))
@@ -64,7 +66,7 @@ Row1Col1,Row1Col2,Row1Col3`
 			It("should parse a simple file correctly", func() {
 				xlsContent := "Header1\tHeader2\tHeader3\nRow1Col1\tRow1Col2\tRow1Col3"
 				fileBytes := []byte(xlsContent)
-				preview, err := p.Preview(fileBytes, "test.xls", 0, -1, "")
+				preview, err := p.Preview(fileBytes, "test.xls", 0, -1, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview.Headers).To(Equal([]string{"Header1", "Header2", "Header3"}))
 				Expect(preview.Rows).To(HaveLen(1))
@@ -79,7 +81,7 @@ Row2Col1	Row2Col2	Row2Col3
 Row3Col1	Row3Col2	Row3Col3`
 				fileBytes := []byte(xlsContent)
 
-				preview, err := p.Preview(fileBytes, "test.xls", 2, 2, "")
+				preview, err := p.Preview(fileBytes, "test.xls", 2, 2, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview.Headers).To(Equal([]string{"Header1", "Header2", "Header3"}))
 				Expect(preview.Rows).To(HaveLen(2))
@@ -88,36 +90,125 @@ Row3Col1	Row3Col2	Row3Col3`
 			})
 		})
 
+		Context("with a binary Excel 97-2003 XLS file", func() {
+			It("should read the first sheet, respecting skipRows", func() {
+				fileBytes := utils.CreateXLSFile([][]string{
+					{"Bank Statement"},
+					{"Date", "Payee", "Amount"},
+					{"2024-01-15", "Supermarket", "150.75"},
+				})
+				preview, err := p.Preview(fileBytes, "test.xls", 1, -1, "", models.SheetSelector{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(preview.Headers).To(Equal([]string{"Date", "Payee", "Amount"}))
+				Expect(preview.Rows).To(Equal([][]string{{"2024-01-15", "Supermarket", "150.75"}}))
+			})
+
+			It("should parse transactions from it", func() {
+				fileBytes := utils.CreateXLSFile([][]string{
+					{"Date", "Payee", "Amount"},
+					{"2024-01-15", "Supermarket", "150.75"},
+				})
+				metadata := `{"column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount" }}`
+				transactions, err := p.Parse(fileBytes, metadata, "statement.XLS", "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(transactions).To(HaveLen(1))
+				Expect(transactions[0].Date).To(Equal(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)))
+				Expect(*transactions[0].Amount).To(Equal(150.75))
+			})
+
+			It("should reject binary files that are not workbooks", func() {
+				_, err := p.Preview([]byte{0x01, 0x00, 0x02, 0x00}, "test.xls", 0, -1, "", models.SheetSelector{})
+				Expect(err).To(MatchError("xls file is neither an Excel 97-2003 workbook nor tab separated text"))
+			})
+		})
+
+		Context("with a workbook of several sheets", func() {
+			var fileBytes []byte
+
+			BeforeEach(func() {
+				f := excelize.NewFile()
+				defer f.Close()
+				f.SetSheetName("Sheet1", "Summary")
+				f.SetCellValue("Summary", "A1", "Account summary")
+				_, err := f.NewSheet("Ledger")
+				Expect(err).NotTo(HaveOccurred())
+				f.SetSheetRow("Ledger", "A1", &[]string{"Date", "Payee", "Amount"})
+				f.SetSheetRow("Ledger", "A2", &[]string{"2024-01-15", "Supermarket", "150.75"})
+				buf, err := f.WriteToBuffer()
+				Expect(err).NotTo(HaveOccurred())
+				fileBytes = buf.Bytes()
+			})
+
+			It("should read the first sheet and list every sheet by default", func() {
+				preview, err := p.Preview(fileBytes, "test.xlsx", 0, -1, "", models.SheetSelector{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(preview.Sheets).To(Equal([]string{"Summary", "Ledger"}))
+				Expect(preview.Sheet).To(Equal("Summary"))
+				Expect(preview.Headers).To(Equal([]string{"Account summary"}))
+			})
+
+			It("should read the sheet selected by name or index", func() {
+				preview, err := p.Preview(fileBytes, "test.xlsx", 0, -1, "", models.SheetSelector{SheetName: "ledger"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(preview.Sheet).To(Equal("Ledger"))
+				Expect(preview.Headers).To(Equal([]string{"Date", "Payee", "Amount"}))
+
+				index := 1
+				preview, err = p.Preview(fileBytes, "test.xlsx", 0, -1, "", models.SheetSelector{SheetIndex: &index})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(preview.Sheet).To(Equal("Ledger"))
+				Expect(preview.Rows).To(Equal([][]string{{"2024-01-15", "Supermarket", "150.75"}}))
+			})
+
+			It("should return an error for a sheet that is not in the workbook", func() {
+				_, err := p.Preview(fileBytes, "test.xlsx", 0, -1, "", models.SheetSelector{SheetName: "Trades"})
+				Expect(err).To(MatchError(ErrInvalidSheetSelection))
+				Expect(err.Error()).To(ContainSubstring("no sheet named 'Trades', the workbook has Summary, Ledger"))
+
+				index := 2
+				_, err = p.Preview(fileBytes, "test.xlsx", 0, -1, "", models.SheetSelector{SheetIndex: &index})
+				Expect(err).To(MatchError(ContainSubstring("sheet_index 2 is out of range, the workbook has 2 sheets")))
+			})
+
+			It("should parse the sheet named in the metadata", func() {
+				metadata := `{"sheet_name": "Ledger", "column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount" }}`
+				transactions, err := p.Parse(fileBytes, metadata, "test.xlsx", "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(transactions).To(HaveLen(1))
+				Expect(transactions[0].Name).To(Equal("Supermarket"))
+			})
+		})
+
 		Context("with edge cases", func() {
 			It("should return an error for an unsupported file type", func() {
-				_, err := p.Preview([]byte("content"), "statement.pdf", 0, -1, "")
+				_, err := p.Preview([]byte("content"), "statement.pdf", 0, -1, "", models.SheetSelector{})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("unsupported file type for preview"))
 			})
 
 			It("should handle an empty file", func() {
-				preview, err := p.Preview([]byte(""), "test.csv", 0, -1, "")
+				preview, err := p.Preview([]byte(""), "test.csv", 0, -1, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview.Headers).To(BeEmpty())
 				Expect(preview.Rows).To(BeEmpty())
 			})
 
 			It("should handle a file with only a header row", func() {
-				preview, err := p.Preview([]byte("Header1,Header2"), "test.csv", 0, -1, "")
+				preview, err := p.Preview([]byte("Header1,Header2"), "test.csv", 0, -1, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview.Headers).To(Equal([]string{"Header1", "Header2"}))
 				Expect(preview.Rows).To(BeEmpty())
 			})
 
 			It("should handle when skipRows is greater than the number of available rows", func() {
-				preview, err := p.Preview([]byte("row1\nrow2"), "test.csv", 5, -1, "")
+				preview, err := p.Preview([]byte("row1\nrow2"), "test.csv", 5, -1, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview.Headers).To(BeEmpty())
 				Expect(preview.Rows).To(BeEmpty())
 			})
 
 			It("should handle rowSize being zero, returning headers but no rows", func() {
-				preview, err := p.Preview([]byte("H1,H2\nR1,R2"), "test.csv", 0, 0, "")
+				preview, err := p.Preview([]byte("H1,H2\nR1,R2"), "test.csv", 0, 0, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview.Headers).To(Equal([]string{"H1", "H2"}))
 				Expect(preview.Rows).To(BeEmpty())
@@ -280,6 +371,177 @@ not-a-date,Supermarket,150.75
 				Expect(transactions[0].Name).To(Equal("Restaurant"))
 				Expect(*transactions[0].Amount).To(Equal(120.00))
 			})
+
+			It("should report skipped rows with their line number in the file", func() {
+				csvContent := `Statement export
+Date,Payee,Amount
+not-a-date,Supermarket,150.75
+2024-01-17,Restaurant,120.00`
+				metadata := `{
+					"skip_rows": 1,
+					"column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount" }
+				}`
+				transactions, rowErrors, err := p.ParseWithRowErrors([]byte(csvContent), metadata, "test.csv", "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(transactions).To(HaveLen(1))
+				Expect(rowErrors).To(HaveLen(1))
+				Expect(rowErrors[0].LineNumber).To(Equal(3))
+				Expect(rowErrors[0].RawRow).To(Equal("not-a-date,Supermarket,150.75"))
+				Expect(rowErrors[0].Reason).To(ContainSubstring("not-a-date"))
+			})
+		})
+	})
+
+	Describe("Parse with formatting options", func() {
+		It("should read dates with the configured formats in order", func() {
+			csvContent := `Date,Payee,Amount
+05-Mar-24,Cafe,10
+2024/03/06,Bakery,20`
+			metadata := `{
+				"column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount" },
+				"date_formats": ["DD-MMM-YY", "YYYY/MM/DD"]
+			}`
+			transactions, err := p.Parse([]byte(csvContent), metadata, "test.csv", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transactions).To(HaveLen(2))
+			Expect(transactions[0].Date).To(Equal(time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)))
+			Expect(transactions[1].Date).To(Equal(time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should read amounts with currency symbols, lakh grouping and parentheses", func() {
+			csvContent := `Date,Payee,Amount
+2024-01-15,Rent,"₹1,25,000.50"
+2024-01-16,Refund,Rs. 99
+2024-01-17,Fee,(12.00)`
+			metadata := `{"column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount" }}`
+			transactions, err := p.Parse([]byte(csvContent), metadata, "test.csv", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transactions).To(HaveLen(3))
+			Expect(*transactions[0].Amount).To(Equal(125000.50))
+			Expect(*transactions[1].Amount).To(Equal(99.0))
+			Expect(*transactions[2].Amount).To(Equal(-12.0))
+		})
+
+		It("should read amounts with custom thousand and decimal separators", func() {
+			csvContent := `Date,Payee,Amount
+2024-01-15,Supermarkt,"1.234,56 €"`
+			metadata := `{
+				"column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount" },
+				"thousand_separator": ".",
+				"decimal_separator": ","
+			}`
+			transactions, err := p.Parse([]byte(csvContent), metadata, "test.csv", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transactions).To(HaveLen(1))
+			Expect(*transactions[0].Amount).To(Equal(1234.56))
+		})
+
+		It("should sign amounts from a Dr/Cr indicator column", func() {
+			csvContent := `Date,Payee,Amount,Type
+2024-01-15,Salary,"5,000.00",CR
+2024-01-16,Groceries,75.20,Dr.
+2024-01-17,Unknown,10.00,X`
+			metadata := `{"column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount", "indicator": "Type" }}`
+			transactions, rowErrors, err := p.ParseWithRowErrors([]byte(csvContent), metadata, "test.csv", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transactions).To(HaveLen(2))
+			Expect(*transactions[0].Amount).To(Equal(5000.0))
+			Expect(*transactions[1].Amount).To(Equal(-75.20))
+			Expect(rowErrors).To(HaveLen(1))
+			Expect(rowErrors[0].LineNumber).To(Equal(4))
+			Expect(rowErrors[0].Reason).To(Equal("row 4: column 'Type': unknown debit/credit indicator 'X'"))
+		})
+
+		It("should use custom indicator values and make debits positive when asked", func() {
+			csvContent := `Date,Payee,Amount,Direction
+2024-01-15,Salary,5000,IN
+2024-01-16,Groceries,75,OUT`
+			metadata := `{
+				"column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount", "indicator": "Direction" },
+				"indicator": { "debit_values": ["out"], "credit_values": ["in"] },
+				"amount_sign": "debit_positive"
+			}`
+			transactions, err := p.Parse([]byte(csvContent), metadata, "test.csv", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transactions).To(HaveLen(2))
+			Expect(*transactions[0].Amount).To(Equal(-5000.0))
+			Expect(*transactions[1].Amount).To(Equal(75.0))
+		})
+
+		It("should join several columns into the description", func() {
+			csvContent := `Date,Payee,Ref,Narration,Amount
+2024-01-15,Cafe,UPI-123,Coffee,10
+2024-01-16,Bakery,,Bread,20`
+			metadata := `{
+				"column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount" },
+				"description_columns": ["Ref", "Narration"],
+				"description_separator": " / "
+			}`
+			transactions, err := p.Parse([]byte(csvContent), metadata, "test.csv", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transactions).To(HaveLen(2))
+			Expect(transactions[0].Description).To(Equal("UPI-123 / Coffee"))
+			Expect(transactions[1].Description).To(Equal("Bread"))
+		})
+
+		It("should name the row and column of a value that cannot be read", func() {
+			csvContent := `Date,Payee,Amount
+15/01/2024,Cafe,10
+2024-01-16,Bakery,1.2.3`
+			metadata := `{
+				"column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount" },
+				"date_formats": ["DD/MM/YYYY"]
+			}`
+			_, rowErrors, err := p.ParseWithRowErrors([]byte(csvContent), metadata, "test.csv", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rowErrors).To(HaveLen(1))
+			Expect(rowErrors[0].Reason).To(Equal("row 3: column 'Date': date '2024-01-16' does not match any of the formats DD/MM/YYYY"))
+		})
+
+		It("should return an error when a description column is missing from the header", func() {
+			csvContent := `Date,Payee,Amount
+2024-01-15,Cafe,10`
+			metadata := `{
+				"column_mapping": { "txn_date": "Date", "name": "Payee", "amount": "Amount" },
+				"description_columns": ["Memo"]
+			}`
+			_, err := p.Parse([]byte(csvContent), metadata, "test.csv", "")
+			Expect(err).To(MatchError("description column 'Memo' not found in statement header"))
+		})
+	})
+
+	Describe("ValidateStatementMetadata", func() {
+		It("should accept metadata that maps the required fields", func() {
+			Expect(ValidateStatementMetadata(`{"skip_rows": 1, "column_mapping": {"txn_date": "Date", "name": "Payee", "amount": "Amount"}}`)).To(Succeed())
+			Expect(ValidateStatementMetadata(`{"column_mapping": {"txn_date": "Date", "name": "Payee", "credit": "Cr", "debit": "Dr"}}`)).To(Succeed())
+		})
+
+		It("should reject metadata that cannot be read", func() {
+			Expect(ValidateStatementMetadata(`not json`)).To(MatchError(ContainSubstring("failed to unmarshal metadata")))
+		})
+
+		It("should reject metadata without a date or amount column", func() {
+			Expect(ValidateStatementMetadata(`{"column_mapping": {"name": "Payee", "amount": "Amount"}}`)).To(MatchError("required field 'txn_date' is not mapped in metadata"))
+			Expect(ValidateStatementMetadata(`{"column_mapping": {"txn_date": "Date", "name": "Payee", "credit": "Cr"}}`)).To(MatchError(ContainSubstring("insufficient amount information")))
+		})
+
+		It("should reject invalid formatting options", func() {
+			base := `"column_mapping": {"txn_date": "Date", "name": "Payee", "amount": "Amount"}`
+			Expect(ValidateStatementMetadata(`{` + base + `, "date_formats": ["MM/YYYY"]}`)).To(MatchError(ContainSubstring("date format 'MM/YYYY' must contain a day")))
+			Expect(ValidateStatementMetadata(`{` + base + `, "amount_sign": "negative"}`)).To(MatchError(ContainSubstring("unknown amount_sign 'negative'")))
+			Expect(ValidateStatementMetadata(`{` + base + `, "thousand_separator": "."}`)).To(MatchError("thousand_separator and decimal_separator must be different"))
+			Expect(ValidateStatementMetadata(`{` + base + `, "indicator": {"debit_values": ["dr"], "credit_values": ["cr"]}}`)).To(MatchError("indicator values are set but no 'indicator' column is mapped"))
+			Expect(ValidateStatementMetadata(`{"column_mapping": {"txn_date": "Date", "name": "Payee", "amount": "Amount", "indicator": "Type"}, "indicator": {"debit_values": ["d"], "credit_values": ["D"]}}`)).To(MatchError("indicator value 'd' is used for both debit and credit"))
+			Expect(ValidateStatementMetadata(`{"column_mapping": {"txn_date": "Date", "name": "Payee", "description": "Memo", "amount": "Amount"}, "description_columns": ["Ref"]}`)).To(MatchError(ContainSubstring("not both")))
+		})
+
+		It("should reject a malformed sheet selection", func() {
+			Expect(ValidateStatementMetadata(`{"sheet_name": "Ledger", "sheet_index": 1, "column_mapping": {"txn_date": "Date", "name": "Payee", "amount": "Amount"}}`)).To(MatchError(ContainSubstring("not both")))
+			Expect(ValidateStatementMetadata(`{"sheet_index": -1, "column_mapping": {"txn_date": "Date", "name": "Payee", "amount": "Amount"}}`)).To(MatchError(ContainSubstring("sheet_index cannot be negative")))
+		})
+
+		It("should reject a negative skip_rows", func() {
+			Expect(ValidateStatementMetadata(`{"skip_rows": -1, "column_mapping": {"txn_date": "Date", "name": "Payee", "amount": "Amount"}}`)).To(MatchError("skip_rows cannot be negative"))
 		})
 	})
 })
@@ -0,0 +1,174 @@
+package parser
+
+import (
+	"bytes"
+	"expenses/internal/models"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Detector is implemented by parsers that can recognise their own statement
+// format. Detect returns a confidence score between 0 (not this format) and 1
+// (certainly this format), based on the file name and contents.
+type Detector interface {
+	Detect(sniff *Sniff) float64
+}
+
+// MinDetectionConfidence is the score a parser has to reach before its format
+// is picked automatically.
+const MinDetectionConfidence = 0.5
+
+// sniffSize limits how much of a text file is inspected during detection.
+const sniffSize = 16 * 1024
+
+// sniffRows limits how many rows of each workbook sheet are inspected during
+// detection.
+const sniffRows = 50
+
+// Sniff is the part of a statement file that detectors look at. The file is
+// opened once to build it, and the same Sniff is passed to every Detector.
+type Sniff struct {
+	FileName string
+	// PDF is set for PDF files, whose Text is the text of their first pages.
+	PDF bool
+	// Text is the lower-cased beginning of a text file or PDF. It is empty
+	// for workbooks and other binary files.
+	Text string
+	// Rows holds the first rows of every sheet of a workbook, and is nil for
+	// files that are not readable workbooks.
+	Rows [][]string
+}
+
+// NewSniff reads what detectors need from a statement file.
+func NewSniff(fileBytes []byte, fileName string, password string) *Sniff {
+	pdf := IsPDF(fileBytes)
+	sniff := &Sniff{
+		FileName: fileName,
+		PDF:      pdf,
+		Text:     sniffText(fileBytes, password),
+	}
+	if !pdf {
+		sniff.Rows = sniffWorkbookRows(fileBytes, password, sniffRows)
+	}
+	return sniff
+}
+
+// Detection is the outcome of format detection for a statement file.
+type Detection struct {
+	BankType   models.BankType
+	Parser     Parser
+	Confidence float64
+}
+
+// DetectParser asks every registered Detector to score the file and returns the
+// best match. ok is false when no parser is confident enough.
+func DetectParser(fileBytes []byte, fileName string, password string) (Detection, bool) {
+	bankTypes := make([]string, 0, len(parserRegistry))
+	for bankType := range parserRegistry {
+		bankTypes = append(bankTypes, string(bankType))
+	}
+	// Sort so that ties are resolved the same way on every run.
+	sort.Strings(bankTypes)
+
+	sniff := NewSniff(fileBytes, fileName, password)
+	var best Detection
+	for _, bankType := range bankTypes {
+		p := parserRegistry[models.BankType(bankType)]
+		detector, ok := p.(Detector)
+		if !ok {
+			continue
+		}
+		confidence := detector.Detect(sniff)
+		if confidence > best.Confidence {
+			best = Detection{BankType: models.BankType(bankType), Parser: p, Confidence: confidence}
+		}
+	}
+
+	if best.Confidence < MinDetectionConfidence {
+		return Detection{}, false
+	}
+	return best, true
+}
+
+// sniffText returns the lower-cased beginning of a text file, or an empty
+// string for zip based (xlsx) and other binary files. For PDFs the extracted
+// text of the first pages is returned.
+func sniffText(fileBytes []byte, password string) string {
+	if IsPDF(fileBytes) {
+		return sniffPDFText(fileBytes, password)
+	}
+	if bytes.HasPrefix(fileBytes, zipHeader) || bytes.IndexByte(headOf(fileBytes, 512), 0) != -1 {
+		return ""
+	}
+	return strings.ToLower(string(headOf(fileBytes, sniffSize)))
+}
+
+// sniffWorkbookRows returns the first rows of every sheet in an Excel workbook,
+// or nil when the file is not a readable workbook.
+func sniffWorkbookRows(fileBytes []byte, password string, maxRows int) [][]string {
+	if isCompoundFile(fileBytes) && password == "" {
+		sheets, err := readXLSSheets(fileBytes)
+		if err != nil {
+			return nil
+		}
+		var rows [][]string
+		for _, sheet := range sheets {
+			rows = append(rows, headRows(sheet.Rows, maxRows)...)
+		}
+		return rows
+	}
+	if !bytes.HasPrefix(fileBytes, zipHeader) && password == "" {
+		return nil
+	}
+	f, err := openWorkbook(fileBytes, password)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rows [][]string
+	for _, sheet := range f.GetSheetList() {
+		sheetRows, err := f.GetRows(sheet)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, headRows(sheetRows, maxRows)...)
+	}
+	return rows
+}
+
+func headRows(rows [][]string, maxRows int) [][]string {
+	if len(rows) > maxRows {
+		return rows[:maxRows]
+	}
+	return rows
+}
+
+func headOf(fileBytes []byte, size int) []byte {
+	if len(fileBytes) > size {
+		return fileBytes[:size]
+	}
+	return fileBytes
+}
+
+// hasExtension reports whether fileName ends with one of the given extensions.
+func hasExtension(fileName string, extensions ...string) bool {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSpace(fileName)))
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAll reports whether s contains every one of the given substrings.
+func containsAll(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"expenses/internal/models"
+	"expenses/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DetectParser", func() {
+	DescribeTable("should detect the statement format from the file",
+		func(fileBytes []byte, fileName string, expected models.BankType) {
+			detection, ok := DetectParser(fileBytes, fileName, "")
+			Expect(ok).To(BeTrue())
+			Expect(detection.BankType).To(Equal(expected))
+			Expect(detection.Confidence).To(BeNumerically(">=", MinDetectionConfidence))
+			Expect(detection.Parser).NotTo(BeNil())
+		},
+		Entry("HDFC text export",
+			[]byte("Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n01/08/22,UPI-TEST,01/08/22,100.00,0.00,123,900.00"),
+			"statement.txt", models.BankTypeHDFC),
+		Entry("Axis account CSV",
+			[]byte("Tran Date,CHQNO,PARTICULARS,DR,CR,BAL,SOL\n31-03-2025,-,UPI/P2M/1/SHOP/UPI/,1.00, ,99.00,4806"),
+			"statement.csv", models.BankTypeAxis),
+		Entry("ICICI credit card CSV",
+			[]byte("Accountno:,XXXX\n\"Date\",\"Sr.No.\",\"Transaction Details\",\"Reward Point Header\",\"Intl.Amount\",\"Amount(in Rs)\",\"BillingAmountSign\"\n"),
+			"statement.csv", models.BankTypeICICICredit),
+		Entry("SBI workbook",
+			utils.CreateXLSXFile([][]string{
+				{"Date", "Details", "Ref No/Cheque No", "Debit", "Credit", "Balance"},
+				{"01/08/2022", "WDL TFR UPI", "123", "100.00", "", "1000.00"},
+			}),
+			"statement.xlsx", models.BankTypeSBI),
+		Entry("Axis credit card workbook",
+			utils.CreateXLSXFile([][]string{
+				{"Date", "Transaction Details", "", "Amount (INR)", "Debit/Credit"},
+				{"01 Aug '22", "SHOP", "", "100.00", "Debit"},
+			}),
+			"statement.xlsx", models.BankTypeAxisCredit),
+		Entry("ICICI savings workbook",
+			utils.CreateXLSFile([][]string{
+				{"", "S No.", "Value Date", "Transaction Date", "Cheque Number", "Transaction Remarks", "Withdrawal Amount (INR )", "Deposit Amount (INR )", "Balance (INR )"},
+				{"", "1", "01/04/2024", "01/04/2024", "-", "UPI/SHOP/shop@ybl/UPI", "100.00", "0.00", "900.00"},
+			}),
+			"statement.xls", models.BankTypeICICI),
+		Entry("Kotak CSV",
+			[]byte("Sl. No.,Transaction Date,Value Date,Description,Chq / Ref No.,Amount,Dr / Cr,Balance,Dr / Cr\n1,01-04-2024,01-04-2024,UPI/SHOP/1/UPI,UPI-1,100.00,DR,900.00,CR"),
+			"statement.csv", models.BankTypeKotak),
+		Entry("IDFC First workbook",
+			utils.CreateXLSXFile([][]string{
+				{"Transaction Date", "Value Date", "Particulars", "Cheque No.", "Debit", "Credit", "Balance"},
+				{"01-Apr-2024", "01-Apr-2024", "UPI/MOB/1/SHOP", "", "100.00", "", "900.00"},
+			}),
+			"statement.xlsx", models.BankTypeIDFC),
+		Entry("OFX file",
+			[]byte("OFXHEADER:100\nDATA:OFXSGML\n<OFX><STMTTRN><DTPOSTED>20240101<TRNAMT>-1</STMTTRN></OFX>"),
+			"statement.qfx", models.BankTypeOFX),
+		Entry("camt.053 file",
+			[]byte(`<?xml version="1.0"?><Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"><BkToCstmrStmt/></Document>`),
+			"statement.xml", models.BankTypeCAMT053),
+		Entry("MT940 file",
+			[]byte(":20:STMT\n:25:123\n:60F:C240101EUR0,00\n:61:240102D1,00NMSCNONREF\n:62F:D240102EUR1,00"),
+			"statement.sta", models.BankTypeMT940),
+		Entry("QIF file",
+			[]byte("!Type:Bank\nD01/02/2024\nT-10.00\nPShop\n^\n"),
+			"statement.qif", models.BankTypeQIF),
+	)
+
+	It("should not detect a format for unknown files", func() {
+		_, ok := DetectParser([]byte("Txn Date\tValue Date\tDescription\n1 Aug 2022\t1 Aug 2022\tDesc"), "statement.csv", "")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not detect a format for unreadable binary files", func() {
+		_, ok := DetectParser([]byte{0xD0, 0xCF, 0x11, 0xE0, 0x00, 0x01}, "statement.xlsx", "")
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("NewSniff", func() {
+		It("should read the text of text files", func() {
+			sniff := NewSniff([]byte("Date,Narration\n01/08/22,Shop"), "statement.csv", "")
+			Expect(sniff.Text).To(Equal("date,narration\n01/08/22,shop"))
+			Expect(sniff.Rows).To(BeNil())
+			Expect(sniff.PDF).To(BeFalse())
+		})
+
+		It("should read the first rows of workbooks", func() {
+			rows := [][]string{{"Date", "Details"}}
+			for i := 0; i < 60; i++ {
+				rows = append(rows, []string{"01/08/22", "Shop"})
+			}
+			sniff := NewSniff(utils.CreateXLSXFile(rows), "statement.xlsx", "")
+			Expect(sniff.Text).To(BeEmpty())
+			Expect(sniff.Rows).To(HaveLen(sniffRows))
+			Expect(sniff.Rows[0]).To(Equal([]string{"Date", "Details"}))
+		})
+	})
+})
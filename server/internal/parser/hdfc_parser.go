@@ -12,10 +12,38 @@ import (
 	"strings"
 )
 
-// HDFCParser parses HDFC bank statements exported as CSV-like text
+// HDFCParser parses HDFC bank statements exported as CSV-like text or PDF
 type HDFCParser struct{}
 
+// hdfcPDFLayout maps the HDFC PDF statement columns to the order of the text export.
+var hdfcPDFLayout = tableLayout{
+	headerKeywords: []string{"date", "narration"},
+	columns: [][]string{
+		{"date"},
+		{"narration"},
+		{"value dt", "value date"},
+		{"withdrawal", "debit"},
+		{"deposit", "credit"},
+		{"chq", "ref"},
+		{"closing balance", "balance"},
+	},
+}
+
 func (p *HDFCParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *HDFCParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	if IsPDF(fileBytes) {
+		rows, err := extractPDFTable(fileBytes, password, hdfcPDFLayout)
+		if err != nil {
+			return nil, nil, err
+		}
+		transactions, rowErrors := parsePDFRows(rows, p.parseTransactionRow)
+		return transactions, rowErrors, nil
+	}
+
 	scanner := bufio.NewScanner(bytes.NewReader(fileBytes))
 
 	var lines []string
@@ -24,7 +52,7 @@ func (p *HDFCParser) Parse(fileBytes []byte, metadata string, fileName string, p
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	headerRowIndex := -1
@@ -37,10 +65,11 @@ func (p *HDFCParser) Parse(fileBytes []byte, metadata string, fileName string, p
 	}
 
 	if headerRowIndex == -1 {
-		return nil, errors.New("transaction header row not found")
+		return nil, nil, errors.New("transaction header row not found")
 	}
 
 	var transactions []models.CreateTransactionInput
+	var rowErrors []models.StatementRowError
 	for i := headerRowIndex + 1; i < len(lines); i++ {
 		raw := strings.TrimSpace(lines[i])
 		if raw == "" {
@@ -51,7 +80,7 @@ func (p *HDFCParser) Parse(fileBytes []byte, metadata string, fileName string, p
 		fields := strings.Split(raw, ",")
 		// Ensure at least the required columns exist
 		if len(fields) < 6 {
-			logger.Debugf("Skipping line %d: expected at least 6 columns, got %d", i+1, len(fields))
+			rowErrors = append(rowErrors, newRowError(i+1, fields, fmt.Errorf("expected at least 6 columns, got %d", len(fields))))
 			continue
 		}
 
@@ -63,6 +92,7 @@ func (p *HDFCParser) Parse(fileBytes []byte, metadata string, fileName string, p
 		transaction, err := p.parseTransactionRow(fields)
 		if err != nil {
 			logger.Warnf("Failed to parse line %d: %v\n", i+1, err)
+			rowErrors = append(rowErrors, models.StatementRowError{LineNumber: i + 1, RawRow: raw, Reason: err.Error()})
 			continue
 		}
 
@@ -71,7 +101,7 @@ func (p *HDFCParser) Parse(fileBytes []byte, metadata string, fileName string, p
 		}
 	}
 
-	return transactions, nil
+	return transactions, rowErrors, nil
 }
 
 func (p *HDFCParser) parseTransactionRow(fields []string) (*models.CreateTransactionInput, error) {
@@ -184,6 +214,25 @@ func (p *HDFCParser) generateTransactionName(narration string, isCredit bool) st
 	return desc
 }
 
+// Detect recognises HDFC text and PDF exports by their Narration header row.
+func (p *HDFCParser) Detect(sniff *Sniff) float64 {
+	content := sniff.Text
+	switch {
+	case containsAll(content, "narration", "chq/ref number", "closing balance"):
+		return 0.9
+	case containsAll(content, "narration", "withdrawal amt", "deposit amt"):
+		return 0.85
+	case containsAll(content, "date", "narration", "debit amount", "credit amount"):
+		return 0.75
+	}
+	return 0
+}
+
 func init() {
-	RegisterParser(models.BankTypeHDFC, &HDFCParser{})
+	RegisterParser(models.BankTypeHDFC, &HDFCParser{}, models.ParserInfo{
+		Name:          "HDFC Bank",
+		Extensions:    []string{".csv", ".txt", ".pdf"},
+		NeedsPassword: true,
+		SampleHeader:  "Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance",
+	})
 }
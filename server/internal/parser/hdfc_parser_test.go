@@ -215,6 +215,31 @@ var _ = Describe("HDFCParser", func() {
 			Expect(txns).To(HaveLen(1))
 			Expect(txns[0].Name).To(Equal("Debit: This is a very..."))
 		})
+
+		It("reports rows that fail to parse with their line number", func() {
+			input := "Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n" +
+				"01/10/24,POS SOME MERCHANT,30/09/24,abc,0.00,0,20185.00\n" +
+				"01/10/24,POS SOME MERCHANT,30/09/24,2.00,0.00,0,20185.00\n"
+			txns, rowErrors, err := parser.ParseWithRowErrors([]byte(input), "", "", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(1))
+			Expect(rowErrors).To(HaveLen(1))
+			Expect(rowErrors[0].LineNumber).To(Equal(2))
+			Expect(rowErrors[0].RawRow).To(Equal("01/10/24,POS SOME MERCHANT,30/09/24,abc,0.00,0,20185.00"))
+			Expect(rowErrors[0].Reason).NotTo(BeEmpty())
+		})
+		It("reports rows with insufficient columns", func() {
+			input := "Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n" +
+				"01/10/24,Short\n" +
+				"01/10/24,POS SOME MERCHANT,30/09/24,2.00,0.00,0,20185.00\n"
+			txns, rowErrors, err := parser.ParseWithRowErrors([]byte(input), "", "", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(1))
+			Expect(rowErrors).To(HaveLen(1))
+			Expect(rowErrors[0].LineNumber).To(Equal(2))
+			Expect(rowErrors[0].RawRow).To(Equal("01/10/24,Short"))
+			Expect(rowErrors[0].Reason).To(ContainSubstring("expected at least 6 columns"))
+		})
 	})
 
 	Describe("generateTransactionName", func() {
@@ -230,5 +255,15 @@ var _ = Describe("HDFCParser", func() {
 			Expect(ok).To(BeTrue())
 			Expect(p).NotTo(BeNil())
 		})
+
+		It("returns the row errors of a parser through ParseWithRowErrors", func() {
+			input := "Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n" +
+				"xx/10/24,POS SOME MERCHANT,30/09/24,2.00,0.00,0,20185.00\n"
+			txns, rowErrors, err := ParseWithRowErrors(parser, []byte(input), "", "", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(BeEmpty())
+			Expect(rowErrors).To(HaveLen(1))
+			Expect(rowErrors[0].LineNumber).To(Equal(2))
+		})
 	})
 })
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/csv"
 	"errors"
 	"expenses/internal/models"
@@ -14,14 +15,53 @@ import (
 // ICICICreditParser is a parser for ICICI credit card statements in CSV format.
 type ICICICreditParser struct{}
 
+// iciciCreditPDFLayout maps the ICICI credit card PDF statement columns to the
+// order of the CSV export. The PDF has no separate sign column; it is filled
+// from the amount suffix instead.
+var iciciCreditPDFLayout = tableLayout{
+	headerKeywords: []string{"date", "transaction details"},
+	columns: [][]string{
+		{"date"},
+		{"serno", "sr.no", "sr no"},
+		{"transaction details"},
+		{"reward"},
+		{"intl"},
+		{"amount"},
+		{"billingamountsign"},
+	},
+}
+
 // Parse extracts transactions from an ICICI credit card statement.
 func (p *ICICICreditParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *ICICICreditParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	if IsPDF(fileBytes) {
+		rows, err := extractPDFTable(fileBytes, password, iciciCreditPDFLayout)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, row := range rows {
+			amount := strings.ToUpper(row[5])
+			for _, sign := range []string{"CR", "DR"} {
+				if trimmed, ok := strings.CutSuffix(amount, sign); ok {
+					row[5], row[6] = strings.TrimSpace(trimmed), sign
+					break
+				}
+			}
+		}
+		transactions, rowErrors := parsePDFRows(rows, p.parseTransactionRow)
+		return transactions, rowErrors, nil
+	}
+
 	// The CSV is not standard; it has metadata at the top. We need to find the header row first.
 	// A simple way is to convert to string and find the start of the actual CSV data.
 	content := string(fileBytes)
 	headerIndex := strings.Index(content, `"Date","Sr.No.","Transaction Details"`)
 	if headerIndex == -1 {
-		return nil, errors.New("transaction header row not found in ICICI credit statement")
+		return nil, nil, errors.New("transaction header row not found in ICICI credit statement")
 	}
 
 	// Read from the header onwards
@@ -32,10 +72,11 @@ func (p *ICICICreditParser) Parse(fileBytes []byte, metadata string, fileName st
 	// The first line is now the header
 	_, err := reader.Read()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read header row: %w", err)
+		return nil, nil, fmt.Errorf("failed to read header row: %w", err)
 	}
 
 	var transactions []models.CreateTransactionInput
+	var rowErrors []models.StatementRowError
 	lineNum := 1 // Start counting from after the header
 	for {
 		record, err := reader.Read()
@@ -45,12 +86,14 @@ func (p *ICICICreditParser) Parse(fileBytes []byte, metadata string, fileName st
 		}
 		if err != nil {
 			logger.Warnf("Skipping malformed CSV record at line %d: %v", lineNum, err)
+			rowErrors = append(rowErrors, newRowError(lineNum, record, err))
 			continue
 		}
 
 		transaction, err := p.parseTransactionRow(record)
 		if err != nil {
 			logger.Warnf("Failed to parse transaction row at line %d: %v", lineNum, err)
+			rowErrors = append(rowErrors, newRowError(lineNum, record, err))
 			continue
 		}
 
@@ -59,7 +102,7 @@ func (p *ICICICreditParser) Parse(fileBytes []byte, metadata string, fileName st
 		}
 	}
 
-	return transactions, nil
+	return transactions, rowErrors, nil
 }
 
 // parseTransactionRow parses a single row from the CSV into a transaction.
@@ -119,6 +162,57 @@ func (p *ICICICreditParser) generateTransactionName(description string) string {
 	return name
 }
 
+// ParseCardSummary reads the billing period, the amounts due and the payment
+// due date printed above the transactions of the CSV export or on the first
+// pages of the PDF statement.
+func (p *ICICICreditParser) ParseCardSummary(fileBytes []byte, password string) (models.CardStatementSummary, error) {
+	if IsPDF(fileBytes) {
+		pages, err := readPDFLines(fileBytes, password, 2)
+		if err != nil {
+			return models.CardStatementSummary{}, err
+		}
+		var rows [][]string
+		for _, lines := range pages {
+			for _, line := range lines {
+				cells := make([]string, len(line))
+				for i, fragment := range line {
+					cells[i] = fragment.Text
+				}
+				rows = append(rows, cells)
+			}
+		}
+		return scanCardSummary(rows), nil
+	}
+
+	reader := csv.NewReader(bytes.NewReader(fileBytes))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return models.CardStatementSummary{}, fmt.Errorf("failed to read csv: %w", err)
+	}
+	return scanCardSummary(rows), nil
+}
+
+// Detect recognises ICICI credit card CSV exports by their quoted header row.
+func (p *ICICICreditParser) Detect(sniff *Sniff) float64 {
+	content := sniff.Text
+	switch {
+	case strings.Contains(content, `"date","sr.no.","transaction details"`):
+		return 0.95
+	case containsAll(content, "sr.no.", "transaction details", "billingamountsign"):
+		return 0.8
+	case containsAll(content, "transaction details", "reward points", "intl"):
+		return 0.7
+	}
+	return 0
+}
+
 func init() {
-	RegisterParser(models.BankTypeICICICredit, &ICICICreditParser{})
+	RegisterParser(models.BankTypeICICICredit, &ICICICreditParser{}, models.ParserInfo{
+		Name:          "ICICI Bank Credit Card",
+		Extensions:    []string{".csv", ".pdf"},
+		NeedsPassword: true,
+		SampleHeader:  "Date,Sr.No.,Transaction Details,Reward Point Header,Intl.Amount,Amount(in Rs),BillingAmountSign",
+	})
 }
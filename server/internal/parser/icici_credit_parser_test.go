@@ -216,6 +216,33 @@ var _ = Describe("ICICICreditParser", func() {
 		})
 	})
 
+	Describe("ParseCardSummary", func() {
+		It("reads the billing details above the transactions of the CSV export", func() {
+			input := "Accountno:,XXXX\n" +
+				"Statement Period:,15/10/2025 To 14/11/2025\n" +
+				"Payment Due Date:,04/12/2025\n" +
+				"Total Amount Due:,\"18,933.38\"\n" +
+				"Minimum Amount Due:,950.00\n" +
+				"\"Date\",\"Sr.No.\",\"Transaction Details\",\"Reward Point Header\",\"Intl.Amount\",\"Amount(in Rs)\",\"BillingAmountSign\"\n" +
+				"\"12/11/2025\",\"1\",\"SHOP\",\"0\",\"0\",\"100.00\",\"\"\n"
+
+			summary, err := parser.ParseCardSummary([]byte(input), "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*summary.StatementPeriodStart).To(Equal(time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)))
+			Expect(*summary.StatementPeriodEnd).To(Equal(time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC)))
+			Expect(*summary.PaymentDueDate).To(Equal(time.Date(2025, 12, 4, 0, 0, 0, 0, time.UTC)))
+			Expect(*summary.TotalDue).To(Equal(18933.38))
+			Expect(*summary.MinimumDue).To(Equal(950.00))
+		})
+
+		It("returns an empty summary when the export has no billing details", func() {
+			input := "\"Date\",\"Sr.No.\",\"Transaction Details\",\"Reward Point Header\",\"Intl.Amount\",\"Amount(in Rs)\",\"BillingAmountSign\"\n"
+			summary, err := parser.ParseCardSummary([]byte(input), "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary).To(Equal(models.CardStatementSummary{}))
+		})
+	})
+
 	Describe("Parser Registry", func() {
 		It("should return ICICI credit parser for BankTypeICICICredit", func() {
 			p, ok := GetParser(models.BankTypeICICICredit)
@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"errors"
+	"expenses/internal/models"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ICICIParser parses ICICI savings account statements exported as XLS or XLSX
+type ICICIParser struct{}
+
+// iciciLayout maps the columns of the ICICI export, which starts with an empty
+// column and lists the value date before the transaction date.
+var iciciLayout = tableLayout{
+	headerKeywords: []string{"transaction remarks", "withdrawal", "deposit"},
+	columns: [][]string{
+		{"transaction date", "value date"},
+		{"transaction remarks", "remarks"},
+		{"cheque"},
+		{"withdrawal"},
+		{"deposit"},
+		{"balance"},
+	},
+}
+
+var iciciPatterns = []struct {
+	regex      *regexp.Regexp
+	creditName string
+	debitName  string
+}{
+	{regexp.MustCompile(`(?i)^UPI/([^/]+)/`), "UPI from $1", "UPI to $1"},
+	{regexp.MustCompile(`(?i)IMPS/P2A/\d+/([^/]+)`), "IMPS from $1", "IMPS to $1"},
+	{regexp.MustCompile(`(?i)^MMT/IMPS/\d+/([^/]+)`), "IMPS from $1", "IMPS to $1"},
+	{regexp.MustCompile(`(?i)^NEFT-[^-]+-([^-]+)`), "NEFT from $1", "NEFT to $1"},
+	{regexp.MustCompile(`(?i)^RTGS-[^-]+-([^-]+)`), "RTGS from $1", "RTGS to $1"},
+	{regexp.MustCompile(`(?i)^(?:ATM|NFS)/`), "ATM Deposit", "ATM Withdrawal"},
+	{regexp.MustCompile(`(?i)Int\.?Pd`), "Interest", "Interest"},
+}
+
+func (p *ICICIParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *ICICIParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	rows, err := readFirstSheet(fileBytes, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseTableRows(rows, iciciLayout, p.parseTransactionRow)
+}
+
+func (p *ICICIParser) parseTransactionRow(fields []string) (*models.CreateTransactionInput, error) {
+	// Columns as aligned by iciciLayout:
+	// 0: Transaction Date, 1: Remarks, 2: Cheque Number, 3: Withdrawal, 4: Deposit, 5: Balance
+	if len(fields) < 5 {
+		return nil, errors.New("insufficient columns in row")
+	}
+
+	dateStr := fields[0]
+	remarks := fields[1]
+	chequeNo := fields[2]
+
+	txnDate, err := parseDateWithLayouts(dateStr, "02/01/2006", "02-01-2006")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transaction date '%s': %w", dateStr, err)
+	}
+
+	withdrawal, hasWithdrawal, err := parseOptionalAmount(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse withdrawal amount '%s': %w", fields[3], err)
+	}
+	deposit, hasDeposit, err := parseOptionalAmount(fields[4])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse deposit amount '%s': %w", fields[4], err)
+	}
+
+	var amount float64
+	var isCredit bool
+	switch {
+	case hasWithdrawal:
+		amount = withdrawal
+	case hasDeposit:
+		amount = -deposit
+		isCredit = true
+	default:
+		return nil, errors.New("both withdrawal and deposit amounts are empty or zero")
+	}
+
+	description := remarks
+	if chequeNo != "" && chequeNo != "-" && chequeNo != "0" {
+		description = fmt.Sprintf("%s (Cheque: %s)", remarks, chequeNo)
+	}
+
+	transaction := &models.CreateTransactionInput{
+		CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+			Name:        p.generateTransactionName(remarks, isCredit),
+			Description: description,
+			Amount:      &amount,
+			Date:        txnDate,
+		},
+		CategoryIds: []int64{},
+	}
+
+	return transaction, nil
+}
+
+func (p *ICICIParser) generateTransactionName(remarks string, isCredit bool) string {
+	desc := strings.TrimSpace(remarks)
+
+	for _, pattern := range iciciPatterns {
+		matches := pattern.regex.FindStringSubmatch(desc)
+		if matches == nil {
+			continue
+		}
+		name := pattern.debitName
+		if isCredit {
+			name = pattern.creditName
+		}
+		if len(matches) > 1 {
+			return strings.Replace(name, "$1", strings.Join(strings.Fields(matches[1]), " "), 1)
+		}
+		return name
+	}
+
+	if isCredit {
+		desc = "Credit: " + desc
+	} else {
+		desc = "Debit: " + desc
+	}
+
+	if len(desc) > 25 {
+		return strings.TrimSpace(desc[:22]) + "..."
+	}
+	return desc
+}
+
+// Detect recognises ICICI savings exports by their Transaction Remarks header
+// with separate withdrawal and deposit columns.
+func (p *ICICIParser) Detect(sniff *Sniff) float64 {
+	for _, row := range sniff.Rows {
+		joined := strings.ToLower(strings.Join(row, " "))
+		switch {
+		case containsAll(joined, "transaction remarks", "withdrawal amount", "deposit amount"):
+			return 0.9
+		case containsAll(joined, "transaction remarks", "withdrawal", "deposit"):
+			return 0.7
+		}
+	}
+	return 0
+}
+
+func init() {
+	RegisterParser(models.BankTypeICICI, &ICICIParser{}, models.ParserInfo{
+		Name:          "ICICI Bank",
+		Extensions:    []string{".xls", ".xlsx"},
+		NeedsPassword: true,
+		SampleHeader:  "S No.,Value Date,Transaction Date,Cheque Number,Transaction Remarks,Withdrawal Amount (INR ),Deposit Amount (INR ),Balance (INR )",
+	})
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"expenses/internal/models"
+	"expenses/pkg/utils"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ICICIParser", func() {
+	var parser *ICICIParser
+
+	header := []string{"", "S No.", "Value Date", "Transaction Date", "Cheque Number", "Transaction Remarks", "Withdrawal Amount (INR )", "Deposit Amount (INR )", "Balance (INR )"}
+	statement := [][]string{
+		{"", "", "DETAILED STATEMENT"},
+		{},
+		{"", "Transactions List - JOHN DOE - INR - 000101234567"},
+		header,
+		{"", "1", "01/04/2024", "01/04/2024", "-", "UPI/JANE ROE/jane@okaxis/Dinner/HDFC BANK/409112345678", "250.00", "0.00", "9750.00"},
+		{"", "2", "02/04/2024", "02/04/2024", "-", "NEFT-HDFCN52024040212345-ACME CORP-SALARY", "0.00", "50,000.00", "59750.00"},
+		{"", "3", "05/04/2024", "05/04/2024", "123456", "CHQ PAID", "1000.00", "0.00", "58750.00"},
+		{},
+		{"", "Legends Used in Account Statement"},
+	}
+
+	BeforeEach(func() {
+		parser = &ICICIParser{}
+	})
+
+	Describe("Parse", func() {
+		It("parses an Excel 97-2003 export", func() {
+			txns, err := parser.Parse(utils.CreateXLSFile(statement), "", "statement.xls", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(3))
+
+			Expect(txns[0].Name).To(Equal("UPI to JANE ROE"))
+			Expect(*txns[0].Amount).To(Equal(250.00))
+			Expect(txns[0].Date).To(Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)))
+
+			Expect(txns[1].Name).To(Equal("NEFT from ACME CORP"))
+			Expect(*txns[1].Amount).To(Equal(-50000.00))
+
+			Expect(txns[2].Name).To(Equal("Debit: CHQ PAID"))
+			Expect(txns[2].Description).To(Equal("CHQ PAID (Cheque: 123456)"))
+			Expect(txns[2].CategoryIds).To(BeEmpty())
+		})
+
+		It("parses an XLSX export", func() {
+			txns, err := parser.Parse(utils.CreateXLSXFile(statement), "", "statement.xlsx", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(3))
+			Expect(*txns[1].Amount).To(Equal(-50000.00))
+		})
+
+		It("reads dates stored as Excel dates", func() {
+			data := [][]string{
+				header,
+				{"", "1", "2024-04-06", "2024-04-06", "", "ATM/CASH WDL/MUMBAI", "2000", "", "1000"},
+			}
+			txns, err := parser.Parse(utils.CreateXLSFile(data), "", "statement.xls", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(1))
+			Expect(txns[0].Name).To(Equal("ATM Withdrawal"))
+			Expect(txns[0].Date).To(Equal(time.Date(2024, 4, 6, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("reports rows that fail to parse", func() {
+			data := [][]string{
+				header,
+				{"", "1", "bad-date", "bad-date", "", "Something", "10.00", "", "1000"},
+				{"", "2", "01/04/2024", "01/04/2024", "", "Something", "0.00", "0.00", "1000"},
+				{"", "3", "01/04/2024", "01/04/2024", "", "Something", "10.00", "", "990"},
+			}
+			txns, rowErrors, err := parser.ParseWithRowErrors(utils.CreateXLSFile(data), "", "statement.xls", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(1))
+			Expect(rowErrors).To(HaveLen(2))
+			Expect(rowErrors[0].LineNumber).To(Equal(2))
+			Expect(rowErrors[0].Reason).To(ContainSubstring("bad-date"))
+			Expect(rowErrors[1].LineNumber).To(Equal(3))
+			Expect(rowErrors[1].Reason).To(ContainSubstring("empty or zero"))
+		})
+
+		It("returns error when header row is missing", func() {
+			_, err := parser.Parse(utils.CreateXLSFile([][]string{{"No header here"}}), "", "statement.xls", "")
+			Expect(err).To(MatchError("transaction header row not found"))
+		})
+
+		It("returns error for files that are not workbooks", func() {
+			_, err := parser.Parse([]byte("Transaction Remarks,Withdrawal,Deposit"), "", "statement.csv", "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Detect", func() {
+		It("recognises ICICI exports", func() {
+			Expect(parser.Detect(NewSniff(utils.CreateXLSFile(statement), "statement.xls", ""))).To(BeNumerically(">=", 0.9))
+		})
+
+		It("ignores other workbooks", func() {
+			Expect(parser.Detect(NewSniff(utils.CreateXLSXFile([][]string{{"Date", "Details", "Debit", "Credit"}}), "statement.xlsx", ""))).To(BeZero())
+		})
+	})
+
+	Describe("Parser Registry", func() {
+		It("should return ICICI parser for BankTypeICICI", func() {
+			p, ok := GetParser(models.BankTypeICICI)
+			Expect(ok).To(BeTrue())
+			_, isCorrectType := p.(*ICICIParser)
+			Expect(isCorrectType).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"errors"
+	"expenses/internal/models"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IDFCParser parses IDFC First Bank account statements exported as XLSX
+type IDFCParser struct{}
+
+// idfcLayout maps the columns of the IDFC First export.
+var idfcLayout = tableLayout{
+	headerKeywords: []string{"particulars", "debit", "credit", "balance"},
+	columns: [][]string{
+		{"transaction date", "txn date", "date"},
+		{"particulars", "narration"},
+		{"cheque", "chq", "ref"},
+		{"debit", "withdrawal"},
+		{"credit", "deposit"},
+		{"balance"},
+	},
+}
+
+var idfcPatterns = []struct {
+	regex      *regexp.Regexp
+	creditName string
+	debitName  string
+}{
+	{regexp.MustCompile(`(?i)^UPI/[^/]+/\d+/([^/]+)`), "UPI from $1", "UPI to $1"},
+	{regexp.MustCompile(`(?i)^IMPS/[^/]+/\d+/([^/]+)`), "IMPS from $1", "IMPS to $1"},
+	{regexp.MustCompile(`(?i)^NEFT/[^/]+/([^/]+)`), "NEFT from $1", "NEFT to $1"},
+	{regexp.MustCompile(`(?i)^RTGS/[^/]+/([^/]+)`), "RTGS from $1", "RTGS to $1"},
+	{regexp.MustCompile(`(?i)^(?:ATM|ATW)[-/ ]`), "ATM Deposit", "ATM Withdrawal"},
+	{regexp.MustCompile(`(?i)interest`), "Interest", "Interest"},
+}
+
+func (p *IDFCParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *IDFCParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	rows, err := readFirstSheet(fileBytes, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseTableRows(rows, idfcLayout, p.parseTransactionRow)
+}
+
+func (p *IDFCParser) parseTransactionRow(fields []string) (*models.CreateTransactionInput, error) {
+	// Columns as aligned by idfcLayout:
+	// 0: Transaction Date, 1: Particulars, 2: Cheque No., 3: Debit, 4: Credit, 5: Balance
+	if len(fields) < 5 {
+		return nil, errors.New("insufficient columns in row")
+	}
+
+	dateStr := fields[0]
+	particulars := fields[1]
+	chequeNo := fields[2]
+
+	txnDate, err := parseDateWithLayouts(dateStr, "02-Jan-2006", "02-Jan-06", "02/01/2006", "02-01-2006")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transaction date '%s': %w", dateStr, err)
+	}
+
+	debit, hasDebit, err := parseOptionalAmount(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse debit amount '%s': %w", fields[3], err)
+	}
+	credit, hasCredit, err := parseOptionalAmount(fields[4])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse credit amount '%s': %w", fields[4], err)
+	}
+
+	var amount float64
+	var isCredit bool
+	switch {
+	case hasDebit:
+		amount = debit
+	case hasCredit:
+		amount = -credit
+		isCredit = true
+	default:
+		return nil, errors.New("both debit and credit amounts are empty or zero")
+	}
+
+	description := particulars
+	if chequeNo != "" && chequeNo != "-" {
+		description = fmt.Sprintf("%s (Ref: %s)", particulars, chequeNo)
+	}
+
+	transaction := &models.CreateTransactionInput{
+		CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+			Name:        p.generateTransactionName(particulars, isCredit),
+			Description: description,
+			Amount:      &amount,
+			Date:        txnDate,
+		},
+		CategoryIds: []int64{},
+	}
+
+	return transaction, nil
+}
+
+func (p *IDFCParser) generateTransactionName(particulars string, isCredit bool) string {
+	desc := strings.TrimSpace(particulars)
+
+	for _, pattern := range idfcPatterns {
+		matches := pattern.regex.FindStringSubmatch(desc)
+		if matches == nil {
+			continue
+		}
+		name := pattern.debitName
+		if isCredit {
+			name = pattern.creditName
+		}
+		if len(matches) > 1 {
+			return strings.Replace(name, "$1", strings.Join(strings.Fields(matches[1]), " "), 1)
+		}
+		return name
+	}
+
+	if isCredit {
+		desc = "Credit: " + desc
+	} else {
+		desc = "Debit: " + desc
+	}
+
+	if len(desc) > 25 {
+		return strings.TrimSpace(desc[:22]) + "..."
+	}
+	return desc
+}
+
+// Detect recognises IDFC First workbooks by their Particulars header with
+// separate debit and credit columns next to a value date.
+func (p *IDFCParser) Detect(sniff *Sniff) float64 {
+	for _, row := range sniff.Rows {
+		joined := strings.ToLower(strings.Join(row, " "))
+		switch {
+		case containsAll(joined, "transaction date", "value date", "particulars", "cheque no", "debit", "credit"):
+			return 0.9
+		case containsAll(joined, "particulars", "debit", "credit", "balance") && !strings.Contains(joined, "tran date"):
+			return 0.6
+		}
+	}
+	return 0
+}
+
+func init() {
+	RegisterParser(models.BankTypeIDFC, &IDFCParser{}, models.ParserInfo{
+		Name:          "IDFC First Bank",
+		Extensions:    []string{".xls", ".xlsx"},
+		NeedsPassword: true,
+		SampleHeader:  "Transaction Date,Value Date,Particulars,Cheque No.,Debit,Credit,Balance",
+	})
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"expenses/internal/models"
+	"expenses/pkg/utils"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IDFCParser", func() {
+	var parser *IDFCParser
+
+	header := []string{"Transaction Date", "Value Date", "Particulars", "Cheque No.", "Debit", "Credit", "Balance"}
+	statement := [][]string{
+		{"IDFC FIRST Bank"},
+		{"Account Number", "10012345678"},
+		{"Statement Period", "01-Apr-2024 to 30-Apr-2024"},
+		{},
+		header,
+		{"01-Apr-2024", "01-Apr-2024", "UPI/MOB/409112345678/JANE ROE", "", "250.00", "", "9750.00"},
+		{"02-Apr-2024", "02-Apr-2024", "NEFT/ACME0001234/ACME CORP/SALARY", "", "", "50,000.00", "59750.00"},
+		{"30-Apr-2024", "30-Apr-2024", "Monthly Savings Interest Credit", "", "", "12.50", "59762.50"},
+		{},
+		{"Total", "", "", "", "250.00", "50012.50"},
+	}
+
+	BeforeEach(func() {
+		parser = &IDFCParser{}
+	})
+
+	Describe("Parse", func() {
+		It("parses an XLSX export", func() {
+			txns, rowErrors, err := parser.ParseWithRowErrors(utils.CreateXLSXFile(statement), "", "statement.xlsx", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(3))
+
+			Expect(txns[0].Name).To(Equal("UPI to JANE ROE"))
+			Expect(*txns[0].Amount).To(Equal(250.00))
+			Expect(txns[0].Date).To(Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)))
+
+			Expect(txns[1].Name).To(Equal("NEFT from ACME CORP"))
+			Expect(*txns[1].Amount).To(Equal(-50000.00))
+
+			Expect(txns[2].Name).To(Equal("Interest"))
+			Expect(*txns[2].Amount).To(Equal(-12.50))
+			Expect(txns[2].Date).To(Equal(time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)))
+
+			// The totals row below the table cannot be parsed as a transaction
+			Expect(rowErrors).To(HaveLen(1))
+			Expect(rowErrors[0].LineNumber).To(Equal(10))
+		})
+
+		It("keeps the cheque number in the description", func() {
+			data := [][]string{
+				header,
+				{"03-Apr-2024", "03-Apr-2024", "CHQ PAID", "000123", "1,000.00", "", "8750.00"},
+			}
+			txns, err := parser.Parse(utils.CreateXLSXFile(data), "", "statement.xlsx", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(1))
+			Expect(txns[0].Name).To(Equal("Debit: CHQ PAID"))
+			Expect(txns[0].Description).To(Equal("CHQ PAID (Ref: 000123)"))
+		})
+
+		It("returns error when header row is missing", func() {
+			_, err := parser.Parse(utils.CreateXLSXFile([][]string{{"No header here"}}), "", "statement.xlsx", "")
+			Expect(err).To(MatchError("transaction header row not found"))
+		})
+	})
+
+	Describe("Detect", func() {
+		It("recognises IDFC First exports", func() {
+			Expect(parser.Detect(NewSniff(utils.CreateXLSXFile(statement), "statement.xlsx", ""))).To(BeNumerically(">=", 0.9))
+		})
+
+		It("ignores text files", func() {
+			Expect(parser.Detect(NewSniff([]byte("Transaction Date,Value Date,Particulars,Cheque No.,Debit,Credit,Balance"), "statement.csv", ""))).To(BeZero())
+		})
+	})
+
+	Describe("Parser Registry", func() {
+		It("should return IDFC parser for BankTypeIDFC", func() {
+			p, ok := GetParser(models.BankTypeIDFC)
+			Expect(ok).To(BeTrue())
+			_, isCorrectType := p.(*IDFCParser)
+			Expect(isCorrectType).To(BeTrue())
+		})
+	})
+})
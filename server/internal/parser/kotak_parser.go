@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"expenses/internal/models"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// KotakParser parses Kotak Mahindra Bank account statements exported as CSV
+type KotakParser struct{}
+
+// kotakLayout maps the columns of both Kotak CSV exports: the older one with a
+// single amount and a Dr / Cr column, and the newer one with separate
+// withdrawal and deposit columns.
+var kotakLayout = tableLayout{
+	headerKeywords: []string{"description", "chq", "balance"},
+	columns: [][]string{
+		{"transaction date", "date"},
+		{"description", "narration"},
+		{"chq", "ref"},
+		{"withdrawal", "debit"},
+		{"deposit", "credit"},
+		{"amount"},
+		{"dr / cr", "dr/cr"},
+	},
+}
+
+var kotakPatterns = []struct {
+	regex      *regexp.Regexp
+	creditName string
+	debitName  string
+}{
+	{regexp.MustCompile(`(?i)^UPI/([^/]+)/`), "UPI from $1", "UPI to $1"},
+	{regexp.MustCompile(`(?i)^IMPS[-/ ]\d+[-/ ]([^-/]+)`), "IMPS from $1", "IMPS to $1"},
+	{regexp.MustCompile(`(?i)^NEFT[-/ ][^-/ ]+[-/ ]([^-/]+)`), "NEFT from $1", "NEFT to $1"},
+	{regexp.MustCompile(`(?i)^RTGS[-/ ][^-/ ]+[-/ ]([^-/]+)`), "RTGS from $1", "RTGS to $1"},
+	{regexp.MustCompile(`(?i)^(?:PCD|POS)/\d+/([^/]+)`), "Card refund from $1", "Card POS at $1"},
+	{regexp.MustCompile(`(?i)^Int\.?Pd`), "Interest", "Interest"},
+}
+
+func (p *KotakParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *KotakParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	r := csv.NewReader(bytes.NewReader(fileBytes))
+	r.FieldsPerRecord = -1
+	// The account details above the table are not quoted consistently
+	r.LazyQuotes = true
+	recs, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+	return parseTableRows(recs, kotakLayout, p.parseTransactionRow)
+}
+
+func (p *KotakParser) parseTransactionRow(fields []string) (*models.CreateTransactionInput, error) {
+	// Columns as aligned by kotakLayout:
+	// 0: Date, 1: Description, 2: Chq / Ref No., 3: Withdrawal, 4: Deposit, 5: Amount, 6: Dr / Cr
+	if len(fields) < 7 {
+		return nil, errors.New("insufficient columns in row")
+	}
+
+	dateStr := fields[0]
+	description := fields[1]
+	refNo := fields[2]
+
+	// Kotak writes dates day first, which utils.ParseDate would read as
+	// month first when they are separated by dashes.
+	txnDate, err := parseDateWithLayouts(dateStr, "02-01-2006", "02/01/2006", "02-01-06", "02 Jan 2006")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transaction date '%s': %w", dateStr, err)
+	}
+
+	amount, isCredit, err := p.parseAmount(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	fullDescription := description
+	if refNo != "" {
+		fullDescription = fmt.Sprintf("%s (Ref: %s)", description, refNo)
+	}
+
+	transaction := &models.CreateTransactionInput{
+		CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+			Name:        p.generateTransactionName(description, isCredit),
+			Description: fullDescription,
+			Amount:      &amount,
+			Date:        txnDate,
+		},
+		CategoryIds: []int64{},
+	}
+
+	return transaction, nil
+}
+
+// parseAmount returns the amount of a row with debits positive, reading either
+// the withdrawal and deposit columns or the amount and its Dr / Cr indicator.
+func (p *KotakParser) parseAmount(fields []string) (float64, bool, error) {
+	withdrawal, hasWithdrawal, err := parseOptionalAmount(fields[3])
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse withdrawal amount '%s': %w", fields[3], err)
+	}
+	if hasWithdrawal {
+		return withdrawal, false, nil
+	}
+	deposit, hasDeposit, err := parseOptionalAmount(fields[4])
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse deposit amount '%s': %w", fields[4], err)
+	}
+	if hasDeposit {
+		return -deposit, true, nil
+	}
+
+	amount, hasAmount, err := parseOptionalAmount(fields[5])
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse amount '%s': %w", fields[5], err)
+	}
+	if !hasAmount {
+		return 0, false, errors.New("both debit and credit amounts are empty or zero")
+	}
+	switch strings.ToUpper(strings.TrimSuffix(fields[6], ".")) {
+	case "DR", "D", "DEBIT":
+		return amount, false, nil
+	case "CR", "C", "CREDIT":
+		return -amount, true, nil
+	}
+	return 0, false, fmt.Errorf("unknown Dr / Cr indicator '%s'", fields[6])
+}
+
+func (p *KotakParser) generateTransactionName(description string, isCredit bool) string {
+	desc := strings.TrimSpace(description)
+
+	for _, pattern := range kotakPatterns {
+		matches := pattern.regex.FindStringSubmatch(desc)
+		if matches == nil {
+			continue
+		}
+		name := pattern.debitName
+		if isCredit {
+			name = pattern.creditName
+		}
+		if len(matches) > 1 {
+			return strings.Replace(name, "$1", strings.Join(strings.Fields(matches[1]), " "), 1)
+		}
+		return name
+	}
+
+	if isCredit {
+		desc = "Credit: " + desc
+	} else {
+		desc = "Debit: " + desc
+	}
+
+	if len(desc) > 25 {
+		return strings.TrimSpace(desc[:22]) + "..."
+	}
+	return desc
+}
+
+// Detect recognises Kotak CSV exports by their Chq / Ref header together with
+// either the Dr / Cr or the Withdrawal (Dr) / Deposit (Cr) columns.
+func (p *KotakParser) Detect(sniff *Sniff) float64 {
+	content := sniff.Text
+	switch {
+	case containsAll(content, "description", "chq / ref", "dr / cr"):
+		return 0.9
+	case containsAll(content, "description", "chq / ref", "withdrawal (dr)", "deposit (cr)"):
+		return 0.85
+	}
+	return 0
+}
+
+func init() {
+	RegisterParser(models.BankTypeKotak, &KotakParser{}, models.ParserInfo{
+		Name:         "Kotak Mahindra Bank",
+		Extensions:   []string{".csv"},
+		SampleHeader: "Sl. No.,Transaction Date,Value Date,Description,Chq / Ref No.,Amount,Dr / Cr,Balance,Dr / Cr",
+	})
+}
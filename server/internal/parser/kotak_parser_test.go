@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"expenses/internal/models"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KotakParser", func() {
+	var parser *KotakParser
+
+	BeforeEach(func() {
+		parser = &KotakParser{}
+	})
+
+	Describe("parseTransactionRow", func() {
+		It("reads day first dates", func() {
+			fields := []string{"03-04-2024", "UPI/JANE ROE/409112345678/Lunch", "UPI-409112345678", "", "", "120.00", "DR"}
+			result, err := parser.parseTransactionRow(fields)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Date).To(Equal(time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC)))
+			Expect(*result.Amount).To(Equal(120.00))
+			Expect(result.Name).To(Equal("UPI to JANE ROE"))
+			Expect(result.Description).To(Equal("UPI/JANE ROE/409112345678/Lunch (Ref: UPI-409112345678)"))
+		})
+
+		It("errors on an unknown Dr / Cr indicator", func() {
+			fields := []string{"03-04-2024", "Something", "", "", "", "120.00", "XX"}
+			_, err := parser.parseTransactionRow(fields)
+			Expect(err).To(MatchError(ContainSubstring("unknown Dr / Cr indicator")))
+		})
+
+		It("errors when there is no amount", func() {
+			fields := []string{"03-04-2024", "Something", "", "", "", "", ""}
+			_, err := parser.parseTransactionRow(fields)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Parse", func() {
+		It("parses the export with an amount and Dr / Cr column", func() {
+			input := "Account No.,1234567890\n" +
+				"Period,01-04-2024 to 30-04-2024\n" +
+				"\n" +
+				"Sl. No.,Transaction Date,Value Date,Description,Chq / Ref No.,Amount,Dr / Cr,Balance,Dr / Cr\n" +
+				"1,01-04-2024,01-04-2024,UPI/JANE ROE/409112345678/Lunch,UPI-409112345678,250.00,DR,9750.00,CR\n" +
+				"2,12-04-2024,12-04-2024,NEFT ACME0001 ACME CORP,NEFTINW-0012,\"50,000.00\",CR,59750.00,CR\n" +
+				"3,30-04-2024,30-04-2024,Int.Pd:1234567890:01-01-2024 to 31-03-2024,,12.00,CR,59762.00,CR\n" +
+				"\n" +
+				"Opening balance,10000.00\n"
+			txns, rowErrors, err := parser.ParseWithRowErrors([]byte(input), "", "statement.csv", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(3))
+
+			Expect(*txns[0].Amount).To(Equal(250.00))
+			Expect(txns[0].Name).To(Equal("UPI to JANE ROE"))
+			Expect(txns[0].Date).To(Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)))
+
+			Expect(*txns[1].Amount).To(Equal(-50000.00))
+			Expect(txns[1].Name).To(Equal("NEFT from ACME CORP"))
+			Expect(txns[1].Date).To(Equal(time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC)))
+
+			Expect(*txns[2].Amount).To(Equal(-12.00))
+			Expect(txns[2].Name).To(Equal("Interest"))
+
+			Expect(rowErrors).To(HaveLen(1))
+			Expect(rowErrors[0].LineNumber).To(Equal(7))
+		})
+
+		It("parses the export with withdrawal and deposit columns", func() {
+			input := "Sl. No.,Date,Description,Chq / Ref number,Withdrawal (Dr),Deposit (Cr),Balance\n" +
+				"1,05-04-2024,PCD/1234/AMAZON PAY/BANGALORE,,499.00,,9501.00\n" +
+				"2,06-04-2024,IMPS-409612345678-JANE ROE-Rent,,,\"15,000.00\",24501.00\n"
+			txns, err := parser.Parse([]byte(input), "", "statement.csv", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(2))
+			Expect(*txns[0].Amount).To(Equal(499.00))
+			Expect(txns[0].Name).To(Equal("Card POS at AMAZON PAY"))
+			Expect(*txns[1].Amount).To(Equal(-15000.00))
+			Expect(txns[1].Name).To(Equal("IMPS from JANE ROE"))
+			Expect(txns[1].Date).To(Equal(time.Date(2024, 4, 6, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("returns error when header row is missing", func() {
+			_, err := parser.Parse([]byte("No header here\nJust text"), "", "statement.csv", "")
+			Expect(err).To(MatchError("transaction header row not found"))
+		})
+	})
+
+	Describe("Detect", func() {
+		It("recognises both Kotak exports", func() {
+			Expect(parser.Detect(NewSniff([]byte("Sl. No.,Transaction Date,Value Date,Description,Chq / Ref No.,Amount,Dr / Cr,Balance,Dr / Cr\n"), "statement.csv", ""))).To(BeNumerically(">=", 0.9))
+			Expect(parser.Detect(NewSniff([]byte("Sl. No.,Date,Description,Chq / Ref number,Withdrawal (Dr),Deposit (Cr),Balance\n"), "statement.csv", ""))).To(BeNumerically(">=", 0.85))
+		})
+
+		It("ignores other CSV files", func() {
+			Expect(parser.Detect(NewSniff([]byte("Tran Date,CHQNO,PARTICULARS,DR,CR,BAL,SOL\n"), "statement.csv", ""))).To(BeZero())
+		})
+	})
+
+	Describe("Parser Registry", func() {
+		It("should return Kotak parser for BankTypeKotak", func() {
+			p, ok := GetParser(models.BankTypeKotak)
+			Expect(ok).To(BeTrue())
+			_, isCorrectType := p.(*KotakParser)
+			Expect(isCorrectType).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"errors"
+	"expenses/internal/models"
+	"expenses/pkg/utils"
+	"fmt"
+	"strings"
+)
+
+// MoneyManagerImporter reads the Excel or CSV backup exported by Realbyte's
+// Money Manager. Every row names its account, category and subcategory, and
+// whether it is an income, an expense or one side of a transfer.
+type MoneyManagerImporter struct{}
+
+var moneyManagerLayout = tableLayout{
+	headerKeywords: []string{"category", "note", "income/expense"},
+	columns: [][]string{
+		{"period", "date"},
+		{"account"},
+		// Subcategory is matched first so that it is not taken for the
+		// category.
+		{"subcategory"},
+		{"category"},
+		{"note"},
+		{"description"},
+		{"income/expense"},
+		{"amount"},
+	},
+}
+
+func init() {
+	RegisterAppImporter(models.AppImportSourceMoneyManager, &MoneyManagerImporter{})
+}
+
+func (i *MoneyManagerImporter) Import(fileBytes []byte, fileName string) ([]models.AppImportTransaction, []models.StatementRowError, error) {
+	rows, err := readExportRows(fileBytes, fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseTableRows(rows, moneyManagerLayout, i.parseRow)
+}
+
+func (i *MoneyManagerImporter) parseRow(fields []string) (*models.AppImportTransaction, error) {
+	// Columns as aligned by moneyManagerLayout:
+	// 0: Period, 1: Accounts, 2: Subcategory, 3: Category, 4: Note,
+	// 5: Description, 6: Income/Expense, 7: Amount
+	// The period carries the time of the transaction after the date.
+	dateStr, _, _ := strings.Cut(fields[0], " ")
+	date, err := parseDateWithLayouts(dateStr, "02/01/2006", "2006-01-02", "02-01-2006", "02.01.2006")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse date '%s': %w", fields[0], err)
+	}
+	if fields[7] == "" {
+		return nil, errors.New("missing amount")
+	}
+	amount, err := utils.ParseFloat(stripCurrency(fields[7]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse amount '%s': %w", fields[7], err)
+	}
+
+	category, subcategory := fields[3], fields[2]
+	kind := strings.ToLower(fields[6])
+	switch {
+	case strings.HasPrefix(kind, "income"), kind == "transfer-in":
+		amount = -amount
+	case strings.HasPrefix(kind, "exp"), kind == "transfer-out":
+	default:
+		return nil, fmt.Errorf("unknown transaction type '%s'", fields[6])
+	}
+	// The category of a transfer is the account on its other side.
+	if strings.HasPrefix(kind, "transfer") {
+		category, subcategory = "", ""
+	}
+
+	note := fields[4]
+	name := note
+	if name == "" {
+		name = category
+	}
+	if name == "" {
+		name = fields[6]
+	}
+	account := fields[1]
+	if account == "" {
+		account = "Money Manager"
+	}
+	return newAppImportTransaction(account, date, name, fields[5], amount, category, subcategory), nil
+}
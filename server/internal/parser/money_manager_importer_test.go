@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MoneyManagerImporter", func() {
+	var importer *MoneyManagerImporter
+
+	BeforeEach(func() {
+		importer = &MoneyManagerImporter{}
+	})
+
+	It("imports incomes, expenses and transfers", func() {
+		input := "Period\tAccounts\tCategory\tSubcategory\tNote\tINR\tIncome/Expense\tDescription\tAmount\tCurrency\tAccounts\n" +
+			"17/03/2024 21:12:05\tCash\tFood\tLunch\tBiryani\t250\tExp.\tWith team\t250\tINR\t250\n" +
+			"01/03/2024 09:00:00\tHDFC\tSalary\t\tMarch salary\t85000\tIncome\t\t85000\tINR\t85000\n" +
+			"05/03/2024 10:30:00\tHDFC\tCash\t\t\t2000\tTransfer-Out\t\t2000\tINR\t2000\n" +
+			"05/03/2024 10:30:00\tCash\tHDFC\t\t\t2000\tTransfer-In\t\t2000\tINR\t2000\n" +
+			"06/03/2024 10:30:00\tCash\tFood\t\t\t20\tRefund\t\t20\tINR\t20\n"
+		txns, rowErrors, err := importer.Import([]byte(input), "backup.tsv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(txns).To(HaveLen(4))
+
+		Expect(txns[0].AccountName).To(Equal("Cash"))
+		Expect(txns[0].Name).To(Equal("Biryani"))
+		Expect(txns[0].Description).To(Equal("With team"))
+		Expect(txns[0].Date).To(Equal(time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)))
+		Expect(*txns[0].Amount).To(Equal(250.00))
+		Expect(txns[0].CategoryNames).To(Equal([]string{"Food", "Lunch"}))
+
+		Expect(txns[1].AccountName).To(Equal("HDFC"))
+		Expect(*txns[1].Amount).To(Equal(-85000.00))
+		Expect(txns[1].CategoryNames).To(Equal([]string{"Salary"}))
+
+		Expect(*txns[2].Amount).To(Equal(2000.00))
+		Expect(txns[2].Name).To(Equal("Transfer-Out"))
+		Expect(txns[2].CategoryNames).To(BeEmpty())
+		Expect(*txns[3].Amount).To(Equal(-2000.00))
+
+		Expect(rowErrors).To(HaveLen(1))
+		Expect(rowErrors[0].LineNumber).To(Equal(6))
+		Expect(rowErrors[0].Reason).To(ContainSubstring("unknown transaction type"))
+	})
+})
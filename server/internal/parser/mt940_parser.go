@@ -0,0 +1,259 @@
+package parser
+
+import (
+	"errors"
+	"expenses/internal/models"
+	"expenses/pkg/logger"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MT940Parser parses SWIFT MT940 customer statement files.
+type MT940Parser struct{}
+
+type mt940Field struct {
+	tag   string
+	value string
+	// line is the line of the file the field starts on.
+	line int
+}
+
+var (
+	mt940Tag = regexp.MustCompile(`^:(\d{2}[A-Z]?):(.*)$`)
+	// :61: YYMMDD[MMDD] (R)C/D [funds code] amount transaction type reference [//bank reference]
+	mt940StatementLine = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d*)([A-Z][A-Z0-9]{3})([^/\r\n]*)(?://([^\r\n]*))?`)
+	mt940Balance       = regexp.MustCompile(`^([CD])(\d{6})([A-Z]{3})(\d+,\d*)`)
+	// Structured :86: information uses ?NN sub-fields (e.g. ?20 remittance, ?32 name).
+	mt940SubField = regexp.MustCompile(`\?(\d{2})([^?]*)`)
+)
+
+func (p *MT940Parser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *MT940Parser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	fields := splitMT940Fields(string(fileBytes))
+	if !hasMT940Tag(fields, "61") && !hasMT940Tag(fields, "60F") {
+		return nil, nil, errors.New("no MT940 statement found in file")
+	}
+
+	var transactions []models.CreateTransactionInput
+	var rowErrors []models.StatementRowError
+	for i := 0; i < len(fields); i++ {
+		if fields[i].tag != "61" {
+			continue
+		}
+		info := ""
+		if i+1 < len(fields) && fields[i+1].tag == "86" {
+			info = fields[i+1].value
+		}
+		transaction, err := p.parseStatementLine(fields[i].value, info)
+		if err != nil {
+			logger.Warnf("Failed to parse :61: line '%s': %v", fields[i].value, err)
+			rowErrors = append(rowErrors, models.StatementRowError{
+				LineNumber: fields[i].line,
+				RawRow:     ":61:" + fields[i].value,
+				Reason:     err.Error(),
+			})
+			continue
+		}
+		transactions = append(transactions, *transaction)
+	}
+
+	return transactions, rowErrors, nil
+}
+
+// ParseBalances returns the first opening balance (:60F:) and the last closing
+// balance (:62F:) in the file, so multi-page statements cover the whole period.
+func (p *MT940Parser) ParseBalances(fileBytes []byte) (models.StatementBalances, error) {
+	var balances models.StatementBalances
+	for _, field := range splitMT940Fields(string(fileBytes)) {
+		switch field.tag {
+		case "60F", "60M":
+			if balances.OpeningBalance != nil {
+				continue
+			}
+			amount, err := parseMT940Balance(field.value)
+			if err != nil {
+				return balances, fmt.Errorf("invalid opening balance: %w", err)
+			}
+			balances.OpeningBalance = &amount
+		case "62F", "62M":
+			amount, err := parseMT940Balance(field.value)
+			if err != nil {
+				return balances, fmt.Errorf("invalid closing balance: %w", err)
+			}
+			balances.ClosingBalance = &amount
+		}
+	}
+	return balances, nil
+}
+
+// splitMT940Fields splits the file into tagged fields, joining continuation
+// lines to the field they belong to.
+func splitMT940Fields(content string) []mt940Field {
+	var fields []mt940Field
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \r")
+		if line == "" || line == "-" || strings.HasPrefix(line, "{") {
+			continue
+		}
+		if match := mt940Tag.FindStringSubmatch(line); match != nil {
+			fields = append(fields, mt940Field{tag: match[1], value: match[2], line: i + 1})
+			continue
+		}
+		if len(fields) > 0 {
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+	return fields
+}
+
+func hasMT940Tag(fields []mt940Field, tag string) bool {
+	for _, field := range fields {
+		if field.tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *MT940Parser) parseStatementLine(line string, info string) (*models.CreateTransactionInput, error) {
+	match := mt940StatementLine.FindStringSubmatch(line)
+	if match == nil {
+		return nil, errors.New("unrecognised statement line format")
+	}
+
+	txnDate, err := time.Parse("060102", match[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse value date '%s': %w", match[1], err)
+	}
+	// The optional entry (booking) date only carries month and day; it can fall
+	// in the neighbouring year around new year.
+	if match[2] != "" {
+		if bookingDate, err := time.Parse("0102", match[2]); err == nil {
+			entryDate := time.Date(txnDate.Year(), bookingDate.Month(), bookingDate.Day(), 0, 0, 0, 0, time.UTC)
+			if entryDate.Sub(txnDate) > 180*24*time.Hour {
+				entryDate = entryDate.AddDate(-1, 0, 0)
+			} else if txnDate.Sub(entryDate) > 180*24*time.Hour {
+				entryDate = entryDate.AddDate(1, 0, 0)
+			}
+			txnDate = entryDate
+		}
+	}
+
+	amount, err := parseMT940Amount(match[5])
+	if err != nil {
+		return nil, err
+	}
+	// Debits are stored as positive amounts and credits as negative amounts. A
+	// reversal of a credit (RC) takes money out again, and vice versa.
+	switch match[3] {
+	case "C", "RD":
+		amount = -amount
+	}
+
+	customerRef := strings.TrimSpace(match[7])
+	bankRef := strings.TrimSpace(match[8])
+
+	name, description := parseMT940Information(info)
+	if name == "" {
+		name = description
+	}
+	if name == "" {
+		name = customerRef
+	}
+	if name == "" {
+		return nil, errors.New("statement line has no information to name the transaction")
+	}
+
+	transaction := &models.CreateTransactionInput{
+		CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+			Name:        truncateName(name),
+			Description: description,
+			Amount:      &amount,
+			Date:        txnDate,
+		},
+		CategoryIds: []int64{},
+	}
+	for _, ref := range []string{bankRef, customerRef} {
+		if ref != "" && !strings.EqualFold(ref, "NONREF") {
+			transaction.ExternalId = &ref
+			break
+		}
+	}
+
+	return transaction, nil
+}
+
+// parseMT940Information extracts the counterparty name and remittance text
+// from a :86: field. Unstructured fields are returned as the description.
+func parseMT940Information(info string) (string, string) {
+	info = strings.TrimSpace(info)
+	if !strings.Contains(info, "?") {
+		return "", strings.Join(strings.Fields(info), " ")
+	}
+
+	var remittance, name []string
+	for _, match := range mt940SubField.FindAllStringSubmatch(strings.ReplaceAll(info, "\n", ""), -1) {
+		code, _ := strconv.Atoi(match[1])
+		value := strings.TrimSpace(match[2])
+		switch {
+		case code >= 20 && code <= 29, code >= 60 && code <= 63:
+			remittance = append(remittance, value)
+		case code == 32 || code == 33:
+			name = append(name, value)
+		}
+	}
+	return strings.Join(trimAll(name), " "), strings.Join(trimAll(remittance), " ")
+}
+
+func parseMT940Balance(value string) (float64, error) {
+	match := mt940Balance.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("unrecognised balance '%s'", value)
+	}
+	amount, err := parseMT940Amount(match[4])
+	if err != nil {
+		return 0, err
+	}
+	if match[1] == "D" {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// parseMT940Amount parses SWIFT amounts, which use a comma as the decimal separator.
+func parseMT940Amount(value string) (float64, error) {
+	amount, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse amount '%s': %w", value, err)
+	}
+	return amount, nil
+}
+
+// Detect recognises MT940 files by their mandatory balance and statement line tags.
+func (p *MT940Parser) Detect(sniff *Sniff) float64 {
+	content := sniff.Text
+	switch {
+	case containsAll(content, ":20:", ":60f:", ":61:"):
+		return 0.95
+	case containsAll(content, ":60f:", ":62f:"):
+		return 0.85
+	case hasExtension(sniff.FileName, ".sta", ".mt940", ".940") && strings.Contains(content, ":61:"):
+		return 0.7
+	}
+	return 0
+}
+
+func init() {
+	RegisterParser(models.BankTypeMT940, &MT940Parser{}, models.ParserInfo{
+		Name:         "SWIFT MT940",
+		Extensions:   []string{".sta", ".mt940", ".940"},
+		SampleHeader: ":20:",
+	})
+}
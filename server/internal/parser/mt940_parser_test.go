@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MT940Parser", func() {
+	var parser *MT940Parser
+
+	const mt940Content = `{1:F01BANKDEFFXXXX0000000000}{2:I940BANKDEFFXXXXN}{4:
+:20:STARTUMSE
+:25:10020030/1234567
+:28C:00001/001
+:60F:C240301EUR1000,00
+:61:2403050305D125,50NTRFNONREF//BANKREF1
+:86:Electricity Co invoice 42
+ March
+:61:2403100310C500,00NTRFSALARY
+:86:166?00GUTSCHRIFT?20Salary March?32Employer GmbH
+:62F:C240331EUR1374,50
+-}`
+
+	BeforeEach(func() {
+		parser = &MT940Parser{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse statement lines with sign, date and information", func() {
+			txns, err := parser.Parse([]byte(mt940Content), "", "statement.sta", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(2))
+
+			Expect(txns[0].Name).To(Equal("Electricity Co invoice 42 March"))
+			Expect(txns[0].Description).To(Equal("Electricity Co invoice 42 March"))
+			Expect(*txns[0].Amount).To(BeNumerically("==", 125.50))
+			Expect(txns[0].Date).To(Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)))
+			Expect(*txns[0].ExternalId).To(Equal("BANKREF1"))
+
+			Expect(txns[1].Name).To(Equal("Employer GmbH"))
+			Expect(txns[1].Description).To(Equal("Salary March"))
+			Expect(*txns[1].Amount).To(BeNumerically("==", -500.00))
+			Expect(*txns[1].ExternalId).To(Equal("SALARY"))
+		})
+
+		It("should treat reversals with the opposite sign", func() {
+			content := ":60F:C240101EUR0,00\n:61:240102RC10,00NTRFNONREF\n:86:Reversed refund\n:62F:D240102EUR10,00"
+			txns, err := parser.Parse([]byte(content), "", "statement.sta", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(1))
+			Expect(*txns[0].Amount).To(BeNumerically("==", 10.00))
+			Expect(txns[0].ExternalId).To(BeNil())
+		})
+
+		It("should move the entry date into the next year around new year", func() {
+			content := ":60F:C231231EUR0,00\n:61:2312310102D1,00NTRFNONREF\n:86:Late booking"
+			txns, err := parser.Parse([]byte(content), "", "statement.sta", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns[0].Date).To(Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should skip malformed statement lines", func() {
+			content := ":60F:C240101EUR0,00\n:61:garbage\n:86:Bad\n:61:240102D1,00NMSCNONREF\n:86:Fee"
+			txns, err := parser.Parse([]byte(content), "", "statement.sta", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(1))
+			Expect(txns[0].Name).To(Equal("Fee"))
+		})
+
+		It("should report malformed statement lines with their line number", func() {
+			content := ":60F:C240101EUR0,00\n:61:garbage\n:86:Bad\n:61:240102D1,00NMSCNONREF\n:86:Fee"
+			_, rowErrors, err := parser.ParseWithRowErrors([]byte(content), "", "statement.sta", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rowErrors).To(HaveLen(1))
+			Expect(rowErrors[0].LineNumber).To(Equal(2))
+			Expect(rowErrors[0].RawRow).To(Equal(":61:garbage"))
+			Expect(rowErrors[0].Reason).To(Equal("unrecognised statement line format"))
+		})
+
+		It("should error when the file is not MT940", func() {
+			_, err := parser.Parse([]byte("Date,Amount\n2024-01-01,10"), "", "statement.sta", "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ParseBalances", func() {
+		It("should return opening and closing balances", func() {
+			balances, err := parser.ParseBalances([]byte(mt940Content))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*balances.OpeningBalance).To(BeNumerically("==", 1000.00))
+			Expect(*balances.ClosingBalance).To(BeNumerically("==", 1374.50))
+		})
+
+		It("should report debit balances as negative", func() {
+			balances, err := parser.ParseBalances([]byte(":60F:D240101EUR25,00\n:62F:D240131EUR30,00"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*balances.OpeningBalance).To(BeNumerically("==", -25.00))
+			Expect(*balances.ClosingBalance).To(BeNumerically("==", -30.00))
+		})
+
+		It("should error on a malformed balance", func() {
+			_, err := parser.ParseBalances([]byte(":60F:X240101EUR25,00"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,164 @@
+package parser
+
+import (
+	"errors"
+	"expenses/internal/models"
+	"expenses/pkg/logger"
+	"expenses/pkg/utils"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// OFXParser parses OFX/QFX statements. Both the SGML based OFX 1.x files (where
+// leaf elements are not closed) and the XML based OFX 2.x files are supported.
+type OFXParser struct{}
+
+var (
+	ofxTransactionBlock = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	ofxElement          = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+)
+
+func (p *OFXParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *OFXParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	content := string(fileBytes)
+	if !strings.Contains(strings.ToUpper(content), "<OFX>") {
+		return nil, nil, errors.New("OFX root element not found")
+	}
+
+	blocks := ofxTransactionBlock.FindAllStringSubmatchIndex(content, -1)
+	if len(blocks) == 0 {
+		return nil, nil, errors.New("no STMTTRN entries found in OFX file")
+	}
+
+	var transactions []models.CreateTransactionInput
+	var rowErrors []models.StatementRowError
+	for i, block := range blocks {
+		transaction, err := p.parseTransactionBlock(ofxElements(content[block[2]:block[3]]))
+		if err != nil {
+			logger.Warnf("Failed to parse STMTTRN entry %d: %v", i+1, err)
+			rowErrors = append(rowErrors, models.StatementRowError{
+				LineNumber: strings.Count(content[:block[0]], "\n") + 1,
+				RawRow:     content[block[0]:block[1]],
+				Reason:     err.Error(),
+			})
+			continue
+		}
+		transactions = append(transactions, *transaction)
+	}
+
+	return transactions, rowErrors, nil
+}
+
+// ofxElements collects the leaf elements of an OFX aggregate into a map keyed by
+// upper-cased tag name. Only the first occurrence of a tag is kept.
+func ofxElements(block string) map[string]string {
+	elements := make(map[string]string)
+	for _, match := range ofxElement.FindAllStringSubmatch(block, -1) {
+		tag := strings.ToUpper(match[1])
+		value := strings.TrimSpace(html.UnescapeString(match[2]))
+		if value == "" {
+			continue
+		}
+		if _, exists := elements[tag]; !exists {
+			elements[tag] = value
+		}
+	}
+	return elements
+}
+
+func (p *OFXParser) parseTransactionBlock(elements map[string]string) (*models.CreateTransactionInput, error) {
+	dateStr, ok := elements["DTPOSTED"]
+	if !ok {
+		return nil, errors.New("missing DTPOSTED")
+	}
+	txnDate, err := parseOFXDate(dateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	amountStr, ok := elements["TRNAMT"]
+	if !ok {
+		return nil, errors.New("missing TRNAMT")
+	}
+	trnAmount, err := utils.ParseFloat(amountStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse TRNAMT '%s': %w", amountStr, err)
+	}
+	// OFX amounts are signed from the account holder's point of view (debits are
+	// negative) while transactions store debits as positive amounts.
+	amount := -trnAmount
+
+	name := elements["NAME"]
+	memo := elements["MEMO"]
+	if name == "" {
+		name = memo
+	}
+	if name == "" {
+		name = elements["TRNTYPE"]
+	}
+	if name == "" {
+		return nil, errors.New("missing NAME and MEMO")
+	}
+
+	description := memo
+	if description == name {
+		description = ""
+	}
+
+	transaction := &models.CreateTransactionInput{
+		CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+			Name:        name,
+			Description: description,
+			Amount:      &amount,
+			Date:        txnDate,
+		},
+		CategoryIds: []int64{},
+	}
+	if fitId, ok := elements["FITID"]; ok {
+		transaction.ExternalId = &fitId
+	}
+
+	return transaction, nil
+}
+
+// parseOFXDate parses OFX datetime values such as 20240131, 20240131120000 or
+// 20240131120000.000[-5:EST]. Only the date part is relevant for transactions.
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid OFX date '%s'", value)
+	}
+	txnDate, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid OFX date '%s': %w", value, err)
+	}
+	return txnDate, nil
+}
+
+// Detect recognises OFX/QFX files by their header or root element.
+func (p *OFXParser) Detect(sniff *Sniff) float64 {
+	content := sniff.Text
+	switch {
+	case strings.Contains(content, "ofxheader"):
+		return 0.95
+	case strings.Contains(content, "<ofx>"):
+		return 0.9
+	case hasExtension(sniff.FileName, ".ofx", ".qfx") && content != "":
+		return 0.5
+	}
+	return 0
+}
+
+func init() {
+	RegisterParser(models.BankTypeOFX, &OFXParser{}, models.ParserInfo{
+		Name:         "OFX / QFX",
+		Extensions:   []string{".ofx", ".qfx"},
+		SampleHeader: "OFXHEADER:100",
+	})
+}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OFXParser", func() {
+	var parser *OFXParser
+
+	BeforeEach(func() {
+		parser = &OFXParser{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse an SGML OFX 1.x statement with unclosed elements", func() {
+			content := `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+
+<OFX>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<STMTRS>
+<CURDEF>INR
+<BANKTRANLIST>
+<DTSTART>20240101
+<DTEND>20240131
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240105120000.000[+5:30:IST]
+<TRNAMT>-250.50
+<FITID>TXN0001
+<NAME>GROCERY STORE
+<MEMO>POS PURCHASE
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240110
+<TRNAMT>5000.00
+<FITID>TXN0002
+<NAME>SALARY &amp; BONUS
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>`
+
+			txns, err := parser.Parse([]byte(content), "", "statement.ofx", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(2))
+
+			Expect(txns[0].Name).To(Equal("GROCERY STORE"))
+			Expect(txns[0].Description).To(Equal("POS PURCHASE"))
+			Expect(*txns[0].Amount).To(BeNumerically("==", 250.50))
+			Expect(txns[0].Date).To(Equal(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)))
+			Expect(txns[0].ExternalId).NotTo(BeNil())
+			Expect(*txns[0].ExternalId).To(Equal("TXN0001"))
+
+			Expect(txns[1].Name).To(Equal("SALARY & BONUS"))
+			Expect(*txns[1].Amount).To(BeNumerically("==", -5000.00))
+			Expect(*txns[1].ExternalId).To(Equal("TXN0002"))
+		})
+
+		It("should parse an XML OFX 2.x statement", func() {
+			content := `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE"?>
+<OFX>
+  <CREDITCARDMSGSRSV1>
+    <CCSTMTTRNRS>
+      <CCSTMTRS>
+        <BANKTRANLIST>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20240215</DTPOSTED>
+            <TRNAMT>-99.99</TRNAMT>
+            <FITID>CC-1</FITID>
+            <MEMO>Online subscription</MEMO>
+          </STMTTRN>
+        </BANKTRANLIST>
+      </CCSTMTRS>
+    </CCSTMTTRNRS>
+  </CREDITCARDMSGSRSV1>
+</OFX>`
+
+			txns, err := parser.Parse([]byte(content), "", "statement.qfx", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(1))
+			Expect(txns[0].Name).To(Equal("Online subscription"))
+			Expect(txns[0].Description).To(BeEmpty())
+			Expect(*txns[0].Amount).To(BeNumerically("==", 99.99))
+			Expect(txns[0].Date).To(Equal(time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)))
+			Expect(*txns[0].ExternalId).To(Equal("CC-1"))
+		})
+
+		It("should skip entries with invalid dates or amounts", func() {
+			content := `<OFX>
+<STMTTRN><DTPOSTED>bad<TRNAMT>-1.00<NAME>A</STMTTRN>
+<STMTTRN><DTPOSTED>20240101<TRNAMT>abc<NAME>B</STMTTRN>
+<STMTTRN><DTPOSTED>20240101<TRNAMT>-3.00<NAME>C</STMTTRN>
+</OFX>`
+
+			txns, err := parser.Parse([]byte(content), "", "statement.ofx", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(1))
+			Expect(txns[0].Name).To(Equal("C"))
+			Expect(txns[0].ExternalId).To(BeNil())
+		})
+
+		It("should error when the OFX root element is missing", func() {
+			_, err := parser.Parse([]byte("Date,Amount\n2024-01-01,10"), "", "statement.ofx", "")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should error when there are no transactions", func() {
+			_, err := parser.Parse([]byte("<OFX><BANKTRANLIST></BANKTRANLIST></OFX>"), "", "statement.ofx", "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,309 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"expenses/internal/models"
+	"expenses/pkg/logger"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+var ErrPDFPasswordRequired = errors.New("pdf password required")
+
+var pdfHeader = []byte("%PDF-")
+
+// IsPDF reports whether the file is a PDF document.
+func IsPDF(fileBytes []byte) bool {
+	return bytes.HasPrefix(fileBytes, pdfHeader)
+}
+
+// IsPDFPasswordProtected reports whether the PDF cannot be opened without a
+// user password.
+func IsPDFPasswordProtected(fileBytes []byte) bool {
+	_, err := pdf.NewReader(bytes.NewReader(fileBytes), int64(len(fileBytes)))
+	return errors.Is(err, pdf.ErrInvalidPassword)
+}
+
+// ValidatePDFPassword checks that the PDF can be opened with the given password.
+func ValidatePDFPassword(fileBytes []byte, password string) error {
+	_, err := openPDF(fileBytes, password)
+	return err
+}
+
+func openPDF(fileBytes []byte, password string) (*pdf.Reader, error) {
+	tried := false
+	reader, err := pdf.NewReaderEncrypted(bytes.NewReader(fileBytes), int64(len(fileBytes)), func() string {
+		if tried {
+			return ""
+		}
+		tried = true
+		return password
+	})
+	if err != nil {
+		if errors.Is(err, pdf.ErrInvalidPassword) {
+			return nil, ErrPDFPasswordRequired
+		}
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	return reader, nil
+}
+
+// pdfFragment is a run of text on a line, separated from its neighbours by a
+// gap wide enough to be a column break.
+type pdfFragment struct {
+	X    float64
+	End  float64
+	Text string
+}
+
+// extractPDFTable reads the text of every page and returns the transaction
+// rows below the table header, with cells aligned to layout.columns by their
+// position under the header. Lines that only continue the previous row (such
+// as wrapped narrations) are merged into it.
+func extractPDFTable(fileBytes []byte, password string, layout tableLayout) ([][]string, error) {
+	pages, err := readPDFLines(fileBytes, password, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var headerCells []pdfFragment
+	var columnIndex []int
+	var rows [][]string
+	for _, lines := range pages {
+		var previous []string
+		for _, line := range lines {
+			if isPDFHeaderLine(line, layout.headerKeywords) {
+				headerCells = line
+				columnIndex = matchPDFColumns(line, layout.columns)
+				previous = nil
+				continue
+			}
+			if headerCells == nil {
+				continue
+			}
+
+			cells := make([]string, len(headerCells))
+			for _, fragment := range line {
+				col := nearestPDFColumn(headerCells, fragment)
+				cells[col] = strings.TrimSpace(cells[col] + " " + fragment.Text)
+			}
+
+			if previous != nil && cells[0] == "" && countNonEmpty(cells) <= len(cells)/2 {
+				for i, cell := range cells {
+					if cell != "" {
+						previous[i] = strings.TrimSpace(previous[i] + " " + cell)
+					}
+				}
+				continue
+			}
+			previous = cells
+			rows = append(rows, cells)
+		}
+	}
+
+	if headerCells == nil {
+		return nil, errors.New("transaction header row not found in PDF")
+	}
+
+	aligned := make([][]string, 0, len(rows))
+	for _, cells := range rows {
+		aligned = append(aligned, alignRow(cells, columnIndex))
+	}
+	return aligned, nil
+}
+
+// parsePDFRows runs a bank specific row parser over the rows of a PDF table,
+// skipping rows that fail to parse. Skipped rows are numbered by their position
+// in the table.
+func parsePDFRows(rows [][]string, parseRow func([]string) (*models.CreateTransactionInput, error)) ([]models.CreateTransactionInput, []models.StatementRowError) {
+	var transactions []models.CreateTransactionInput
+	var rowErrors []models.StatementRowError
+	for i, row := range rows {
+		transaction, err := parseRow(row)
+		if err != nil {
+			logger.Warnf("Failed to parse PDF row %d: %v", i+1, err)
+			rowErrors = append(rowErrors, newRowError(i+1, row, err))
+			continue
+		}
+		if transaction != nil {
+			transactions = append(transactions, *transaction)
+		}
+	}
+	return transactions, rowErrors
+}
+
+// sniffPDFText returns the lower-cased text of the first pages of a PDF, one
+// line per text line, for format detection.
+func sniffPDFText(fileBytes []byte, password string) string {
+	pages, err := readPDFLines(fileBytes, password, 2)
+	if err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, lines := range pages {
+		for _, line := range lines {
+			for i, fragment := range line {
+				if i > 0 {
+					sb.WriteString(" ")
+				}
+				sb.WriteString(fragment.Text)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return strings.ToLower(sb.String())
+}
+
+// readPDFLines returns the text lines of each page, top to bottom, split into
+// fragments. maxPages limits how many pages are read; 0 reads all of them.
+func readPDFLines(fileBytes []byte, password string, maxPages int) (pages [][][]pdfFragment, err error) {
+	reader, err := openPDF(fileBytes, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// The PDF library panics on malformed content streams.
+	defer func() {
+		if r := recover(); r != nil {
+			pages = nil
+			err = fmt.Errorf("failed to read PDF content: %v", r)
+		}
+	}()
+
+	numPages := reader.NumPage()
+	if maxPages > 0 && numPages > maxPages {
+		numPages = maxPages
+	}
+	for i := 1; i <= numPages; i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		pages = append(pages, groupPDFText(page.Content().Text))
+	}
+	if len(pages) == 0 {
+		return nil, errors.New("no text found in PDF; scanned statements are not supported")
+	}
+	return pages, nil
+}
+
+// groupPDFText groups positioned glyphs into lines and splits every line into
+// fragments wherever the horizontal gap between glyphs is larger than a space.
+func groupPDFText(texts []pdf.Text) [][]pdfFragment {
+	const lineTolerance = 2.0
+
+	sorted := make([]pdf.Text, 0, len(texts))
+	for _, t := range texts {
+		if t.S != "" {
+			sorted = append(sorted, t)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if math.Abs(sorted[i].Y-sorted[j].Y) > lineTolerance {
+			return sorted[i].Y > sorted[j].Y
+		}
+		return sorted[i].X < sorted[j].X
+	})
+
+	var lines [][]pdfFragment
+	var current []pdf.Text
+	flush := func() {
+		if line := splitPDFLine(current); len(line) > 0 {
+			lines = append(lines, line)
+		}
+		current = nil
+	}
+	for _, t := range sorted {
+		if len(current) > 0 && math.Abs(current[0].Y-t.Y) > lineTolerance {
+			flush()
+		}
+		current = append(current, t)
+	}
+	flush()
+	return lines
+}
+
+func splitPDFLine(glyphs []pdf.Text) []pdfFragment {
+	var fragments []pdfFragment
+	var sb strings.Builder
+	var start, end float64
+	for i, g := range glyphs {
+		// Glyphs of fonts without a width table do not advance the text
+		// position, so the whole string shares one X.
+		advancing := i == 0 || g.X != glyphs[i-1].X
+		if i > 0 && advancing && g.X-end > math.Max(g.FontSize, 3) {
+			if text := strings.TrimSpace(sb.String()); text != "" {
+				fragments = append(fragments, pdfFragment{X: start, End: end, Text: text})
+			}
+			sb.Reset()
+		}
+		if sb.Len() == 0 {
+			start = g.X
+		}
+		sb.WriteString(g.S)
+
+		width := g.W
+		if width <= 0 {
+			// Assume an average glyph width when the font does not say.
+			width = g.FontSize * 0.5
+		}
+		if advancing {
+			end = g.X + width
+		} else {
+			end += width
+		}
+	}
+	if text := strings.TrimSpace(sb.String()); text != "" {
+		fragments = append(fragments, pdfFragment{X: start, End: end, Text: text})
+	}
+	return fragments
+}
+
+func isPDFHeaderLine(line []pdfFragment, keywords []string) bool {
+	texts := make([]string, len(line))
+	for i, fragment := range line {
+		texts[i] = fragment.Text
+	}
+	return containsAll(strings.ToLower(strings.Join(texts, " ")), keywords...)
+}
+
+// matchPDFColumns maps every expected column to the index of the header cell
+// that names it, or -1 when the statement has no such column.
+func matchPDFColumns(header []pdfFragment, columns [][]string) []int {
+	texts := make([]string, len(header))
+	for i, fragment := range header {
+		texts[i] = fragment.Text
+	}
+	return matchHeaderColumns(texts, columns)
+}
+
+// nearestPDFColumn picks the header cell a fragment belongs to: the one it
+// overlaps most (relative to the header width), falling back to the closest
+// one. This works for both left aligned text and right aligned amounts.
+func nearestPDFColumn(header []pdfFragment, fragment pdfFragment) int {
+	best, bestOverlap, bestDistance := 0, 0.0, math.MaxFloat64
+	for i, cell := range header {
+		width := math.Max(cell.End-cell.X, 1)
+		overlap := (math.Min(cell.End, fragment.End) - math.Max(cell.X, fragment.X)) / width
+		distance := math.Min(math.Abs(fragment.X-cell.X), math.Abs(fragment.End-cell.End))
+		if overlap > bestOverlap || (bestOverlap <= 0 && overlap <= 0 && distance < bestDistance) {
+			best, bestOverlap, bestDistance = i, math.Max(overlap, 0), distance
+		}
+	}
+	return best
+}
+
+func countNonEmpty(cells []string) int {
+	count := 0
+	for _, cell := range cells {
+		if cell != "" {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"expenses/pkg/utils"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PDF statements", func() {
+	hdfcRows := [][]string{
+		{"HDFC BANK Ltd.", "Statement of account"},
+		{"Date", "Narration", "Chq./Ref.No.", "Value Dt", "Withdrawal Amt.", "Deposit Amt.", "Closing Balance"},
+		{"01/08/22", "UPI-SHOP-123", "0000123", "01/08/22", "100.00", "", "900.00"},
+		{"", "GROCERIES"},
+		{"02/08/22", "NEFT CR-HDFC0001-X", "0000124", "02/08/22", "", "500.00", "1400.00"},
+		{"Page 1 of 1"},
+	}
+
+	Describe("IsPDF and passwords", func() {
+		It("should recognise PDF files", func() {
+			Expect(IsPDF(utils.CreatePDFFile(hdfcRows, ""))).To(BeTrue())
+			Expect(IsPDF([]byte("Date,Narration"))).To(BeFalse())
+		})
+
+		It("should report password protection", func() {
+			Expect(IsPDFPasswordProtected(utils.CreatePDFFile(hdfcRows, ""))).To(BeFalse())
+			Expect(IsPDFPasswordProtected(utils.CreatePDFFile(hdfcRows, "secret"))).To(BeTrue())
+		})
+
+		It("should validate the password", func() {
+			fileBytes := utils.CreatePDFFile(hdfcRows, "secret")
+			Expect(ValidatePDFPassword(fileBytes, "secret")).To(Succeed())
+			Expect(ValidatePDFPassword(fileBytes, "wrong")).To(MatchError(ErrPDFPasswordRequired))
+			Expect(ValidatePDFPassword(fileBytes, "")).To(MatchError(ErrPDFPasswordRequired))
+		})
+
+		It("should error on corrupt PDF files", func() {
+			Expect(ValidatePDFPassword([]byte("%PDF-1.4\ngarbage"), "")).NotTo(Succeed())
+		})
+	})
+
+	Describe("extractPDFTable", func() {
+		It("should align cells to the layout columns and merge wrapped lines", func() {
+			rows, err := extractPDFTable(utils.CreatePDFFile(hdfcRows, ""), "", hdfcPDFLayout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rows).To(HaveLen(3))
+			Expect(rows[0]).To(Equal([]string{"01/08/22", "UPI-SHOP-123 GROCERIES", "01/08/22", "100.00", "", "0000123", "900.00"}))
+			Expect(rows[1]).To(Equal([]string{"02/08/22", "NEFT CR-HDFC0001-X", "02/08/22", "", "500.00", "0000124", "1400.00"}))
+			Expect(rows[2][0]).To(Equal("Page 1 of 1"))
+		})
+
+		It("should error when the header row is missing", func() {
+			_, err := extractPDFTable(utils.CreatePDFFile([][]string{{"Nothing", "here"}}, ""), "", hdfcPDFLayout)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("bank parsers", func() {
+		It("should parse an encrypted HDFC PDF statement", func() {
+			p := &HDFCParser{}
+			txns, err := p.Parse(utils.CreatePDFFile(hdfcRows, "secret"), "", "statement.pdf", "secret")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(2))
+			Expect(*txns[0].Amount).To(BeNumerically("==", 100.00))
+			Expect(txns[0].Name).To(Equal("UPI to SHOP"))
+			Expect(txns[0].Description).To(Equal("UPI-SHOP-123 GROCERIES (Ref: 0000123)"))
+			Expect(txns[0].Date).To(Equal(time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(*txns[1].Amount).To(BeNumerically("==", -500.00))
+		})
+
+		It("should require the password for an encrypted PDF", func() {
+			p := &HDFCParser{}
+			_, err := p.Parse(utils.CreatePDFFile(hdfcRows, "secret"), "", "statement.pdf", "")
+			Expect(err).To(MatchError(ErrPDFPasswordRequired))
+		})
+
+		It("should parse an SBI PDF statement", func() {
+			fileBytes := utils.CreatePDFFile([][]string{
+				{"Txn Date", "Value Date", "Description", "Ref No./Cheque No.", "Debit", "Credit", "Balance"},
+				{"1 Aug 2022", "1 Aug 2022", "WDL TFR UPI/DR/1/RITIK S/SBIN/", "123456", "100.00", "", "1,000.00"},
+				{"2 Aug 2022", "2 Aug 2022", "DEP TFR NEFT*HDFC01*N1*X", "654321", "", "200.00", "1,200.00"},
+			}, "")
+			p := &SBIParser{}
+			txns, err := p.Parse(fileBytes, "", "statement.pdf", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(2))
+			Expect(txns[0].Name).To(Equal("UPI to RITIK S"))
+			Expect(*txns[0].Amount).To(BeNumerically("==", 100.00))
+			Expect(txns[1].Name).To(Equal("NEFT from HDFC01"))
+			Expect(*txns[1].Amount).To(BeNumerically("==", -200.00))
+		})
+
+		It("should parse an Axis PDF statement", func() {
+			fileBytes := utils.CreatePDFFile([][]string{
+				{"Tran Date", "Chq No", "Particulars", "Debit", "Credit", "Balance", "Init. Br"},
+				{"31-03-2025", "", "UPI/P2M/1/SHOP/UPI/", "1.00", "", "131999.00", "4806"},
+				{"31-03-2025", "", "IMPS/P2A/2/EMPLOYER/X", "", "500.00", "132499.00", "4806"},
+			}, "")
+			p := &AxisParser{}
+			txns, err := p.Parse(fileBytes, "", "statement.pdf", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(2))
+			Expect(txns[0].Name).To(Equal("UPI to SHOP"))
+			Expect(*txns[0].Amount).To(BeNumerically("==", 1.00))
+			Expect(txns[1].Name).To(Equal("IMPS from EMPLOYER"))
+			Expect(*txns[1].Amount).To(BeNumerically("==", -500.00))
+		})
+
+		It("should parse an ICICI credit card PDF statement with CR suffixes", func() {
+			fileBytes := utils.CreatePDFFile([][]string{
+				{"Date", "SerNo.", "Transaction Details", "Reward Points", "Intl.# amount", "Amount (in Rs)"},
+				{"01/08/2022", "1001", "AMAZON PAY", "10", "", "1,234.00"},
+				{"02/08/2022", "1002", "PAYMENT RECEIVED", "0", "", "500.00 CR"},
+			}, "")
+			p := &ICICICreditParser{}
+			txns, err := p.Parse(fileBytes, "", "statement.pdf", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(2))
+			Expect(*txns[0].Amount).To(BeNumerically("==", 1234.00))
+			Expect(*txns[1].Amount).To(BeNumerically("==", -500.00))
+		})
+	})
+
+	Describe("detection", func() {
+		It("should detect the bank of a PDF statement", func() {
+			detection, ok := DetectParser(utils.CreatePDFFile(hdfcRows, "secret"), "statement.pdf", "secret")
+			Expect(ok).To(BeTrue())
+			Expect(detection.BankType).To(BeEquivalentTo("hdfc"))
+		})
+
+		It("should not detect an encrypted PDF without the password", func() {
+			_, ok := DetectParser(utils.CreatePDFFile(hdfcRows, "secret"), "statement.pdf", "")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
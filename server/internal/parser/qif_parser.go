@@ -0,0 +1,387 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"expenses/internal/models"
+	"expenses/pkg/logger"
+	"expenses/pkg/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QIFParser parses Quicken Interchange Format files. Transactions are read
+// from the bank, cash, credit card, asset, liability and investment sections;
+// lists such as categories, classes and memorized payees are skipped. Files
+// exported with several accounts are imported into the account the file is
+// uploaded to.
+type QIFParser struct{}
+
+// qifField is a line of a QIF record: a one letter code followed by its value.
+type qifField struct {
+	code  byte
+	value string
+}
+
+// qifRecord is a record of a QIF section, ended by a line holding ^.
+type qifRecord struct {
+	section string
+	line    int
+	lines   []string
+	fields  []qifField
+}
+
+// qifTransaction is a parsed QIF transaction with the categories it names.
+type qifTransaction struct {
+	transaction models.CreateTransactionInput
+	categories  []string
+}
+
+func (p *QIFParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *QIFParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	transactions, _, rowErrors, err := p.ParseWithCategories(fileBytes)
+	return transactions, rowErrors, err
+}
+
+// ParseWithCategories returns the transactions of the file together with the
+// categories each of them names.
+func (p *QIFParser) ParseWithCategories(fileBytes []byte) ([]models.CreateTransactionInput, [][]string, []models.StatementRowError, error) {
+	parsed, rowErrors, err := p.parse(fileBytes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	transactions := make([]models.CreateTransactionInput, len(parsed))
+	categories := make([][]string, len(parsed))
+	for i, txn := range parsed {
+		transactions[i] = txn.transaction
+		categories[i] = txn.categories
+	}
+	return transactions, categories, rowErrors, nil
+}
+
+func (p *QIFParser) parse(fileBytes []byte) ([]qifTransaction, []models.StatementRowError, error) {
+	records, err := readQIFRecords(fileBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var transactions []qifTransaction
+	var rowErrors []models.StatementRowError
+	for _, record := range records {
+		var txn *qifTransaction
+		switch record.section {
+		case "bank", "cash", "ccard", "oth a", "oth l":
+			txn, err = p.parseBankRecord(record)
+		case "invst":
+			txn, err = p.parseInvestmentRecord(record)
+		default:
+			continue
+		}
+		if err != nil {
+			logger.Warnf("Failed to parse QIF record at line %d: %v", record.line, err)
+			rowErrors = append(rowErrors, models.StatementRowError{
+				LineNumber: record.line,
+				RawRow:     strings.Join(record.lines, "\n"),
+				Reason:     err.Error(),
+			})
+			continue
+		}
+		if txn != nil {
+			transactions = append(transactions, *txn)
+		}
+	}
+	return transactions, rowErrors, nil
+}
+
+// readQIFRecords splits a QIF file into records, each tagged with the
+// lower-cased type of the section it belongs to.
+func readQIFRecords(fileBytes []byte) ([]qifRecord, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(fileBytes, []byte("\ufeff"))))
+	var records []qifRecord
+	var current qifRecord
+	section := ""
+	foundType := false
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" {
+			continue
+		}
+		if line[0] == '!' {
+			header := strings.ToLower(line)
+			switch {
+			case strings.HasPrefix(header, "!type:"):
+				section = strings.TrimSpace(strings.TrimPrefix(header, "!type:"))
+				foundType = true
+			case header == "!account":
+				section = "account"
+			}
+			// Options such as !Option:AutoSwitch do not start a section.
+			continue
+		}
+		if len(current.lines) == 0 {
+			current = qifRecord{section: section, line: lineNumber}
+		}
+		current.lines = append(current.lines, line)
+		if line[0] == '^' {
+			records = append(records, current)
+			current = qifRecord{}
+			continue
+		}
+		current.fields = append(current.fields, qifField{code: line[0], value: strings.TrimSpace(line[1:])})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read QIF file: %w", err)
+	}
+	if !foundType {
+		return nil, errors.New("QIF !Type header not found")
+	}
+	// A missing end marker after the last record is tolerated.
+	if len(current.fields) > 0 {
+		records = append(records, current)
+	}
+	return records, nil
+}
+
+// first returns the value of the first field with the given code.
+func (r qifRecord) first(code byte) string {
+	for _, field := range r.fields {
+		if field.code == code {
+			return field.value
+		}
+	}
+	return ""
+}
+
+func (p *QIFParser) parseBankRecord(record qifRecord) (*qifTransaction, error) {
+	date, err := parseQIFDate(record.first('D'))
+	if err != nil {
+		return nil, err
+	}
+	amountStr := record.first('T')
+	if amountStr == "" {
+		amountStr = record.first('U')
+	}
+	if amountStr == "" {
+		return nil, errors.New("missing amount")
+	}
+	amount, err := utils.ParseFloat(amountStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse amount '%s': %w", amountStr, err)
+	}
+
+	payee := record.first('P')
+	memo := record.first('M')
+	categories := []string{}
+	categories = appendQIFCategory(categories, record.first('L'))
+
+	// Split lines give the category, memo and amount of every part of the
+	// transaction. The transaction keeps its total and takes the categories
+	// of all of its parts.
+	var splits []string
+	splitCategory := ""
+	for _, field := range record.fields {
+		switch field.code {
+		case 'S':
+			splitCategory = field.value
+			categories = appendQIFCategory(categories, field.value)
+		case '$':
+			splits = append(splits, strings.TrimSpace(fmt.Sprintf("%s %s", qifCategoryName(splitCategory), field.value)))
+		}
+	}
+
+	description := memo
+	if len(splits) > 0 {
+		description = strings.TrimSpace(fmt.Sprintf("%s (split: %s)", memo, strings.Join(splits, ", ")))
+	}
+	name := payee
+	if name == "" {
+		name = memo
+	}
+	if name == "" {
+		name = "QIF transaction"
+	}
+
+	// QIF writes money leaving the account as negative amounts, while
+	// transactions keep debits positive.
+	amount = -amount
+	return &qifTransaction{
+		transaction: models.CreateTransactionInput{
+			CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+				Name:        truncateName(name),
+				Description: description,
+				Amount:      &amount,
+				Date:        date,
+			},
+			CategoryIds: []int64{},
+		},
+		categories: categories,
+	}, nil
+}
+
+// qifInvestmentActions gives the sign of the cash movement of investment
+// actions, with debits positive. Actions that move shares but no cash, such as
+// reinvested dividends and splits, are left out and not imported.
+var qifInvestmentActions = map[string]float64{
+	"buy":      1,
+	"buyx":     1,
+	"miscexp":  1,
+	"margint":  1,
+	"xout":     1,
+	"sell":     -1,
+	"sellx":    -1,
+	"div":      -1,
+	"divx":     -1,
+	"intinc":   -1,
+	"intincx":  -1,
+	"cglong":   -1,
+	"cglongx":  -1,
+	"cgmid":    -1,
+	"cgmidx":   -1,
+	"cgshort":  -1,
+	"cgshortx": -1,
+	"miscinc":  -1,
+	"rtrncap":  -1,
+	"xin":      -1,
+}
+
+func (p *QIFParser) parseInvestmentRecord(record qifRecord) (*qifTransaction, error) {
+	action := record.first('N')
+	sign, ok := qifInvestmentActions[strings.ToLower(action)]
+	if !ok {
+		logger.Debugf("Skipping QIF investment action %q at line %d", action, record.line)
+		return nil, nil
+	}
+
+	date, err := parseQIFDate(record.first('D'))
+	if err != nil {
+		return nil, err
+	}
+	amount, err := p.investmentAmount(record)
+	if err != nil {
+		return nil, err
+	}
+	amount *= sign
+
+	security := record.first('Y')
+	name := action
+	if security != "" {
+		name = fmt.Sprintf("%s %s", action, security)
+	}
+	description := record.first('M')
+	if quantity, price := record.first('Q'), record.first('I'); quantity != "" && price != "" {
+		description = strings.TrimSpace(fmt.Sprintf("%s %s shares at %s", description, quantity, price))
+	}
+
+	return &qifTransaction{
+		transaction: models.CreateTransactionInput{
+			CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+				Name:        truncateName(name),
+				Description: description,
+				Amount:      &amount,
+				Date:        date,
+			},
+			CategoryIds: []int64{},
+		},
+		categories: appendQIFCategory([]string{}, record.first('L')),
+	}, nil
+}
+
+// investmentAmount returns the cash amount of an investment record, working it
+// out from the quantity, price and commission when the record has none.
+func (p *QIFParser) investmentAmount(record qifRecord) (float64, error) {
+	amountStr := record.first('T')
+	if amountStr == "" {
+		amountStr = record.first('U')
+	}
+	if amountStr != "" {
+		amount, err := utils.ParseFloat(amountStr)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse amount '%s': %w", amountStr, err)
+		}
+		if amount < 0 {
+			amount = -amount
+		}
+		return amount, nil
+	}
+
+	quantity, err := utils.ParseFloat(record.first('Q'))
+	if err != nil {
+		return 0, fmt.Errorf("missing amount: %w", err)
+	}
+	price, err := utils.ParseFloat(record.first('I'))
+	if err != nil {
+		return 0, fmt.Errorf("missing amount: %w", err)
+	}
+	commission, _, err := parseOptionalAmount(record.first('O'))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse commission '%s': %w", record.first('O'), err)
+	}
+	return quantity*price + commission, nil
+}
+
+// parseQIFDate reads the dates written by Quicken and similar software, which
+// are month first and write years after 2000 with an apostrophe, as in
+// 1/ 5'24.
+func parseQIFDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("missing date")
+	}
+	normalized := strings.ReplaceAll(strings.ReplaceAll(value, " ", ""), "'", "/")
+	date, err := parseDateWithLayouts(normalized, "1/2/2006", "1/2/06", "1-2-2006", "1-2-06", "2006-01-02")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse date '%s': %w", value, err)
+	}
+	return date, nil
+}
+
+// appendQIFCategory adds the category of an L or S line to categories, unless
+// it is a transfer to another account or already present.
+func appendQIFCategory(categories []string, value string) []string {
+	name := qifCategoryName(value)
+	if name == "" {
+		return categories
+	}
+	for _, category := range categories {
+		if strings.EqualFold(category, name) {
+			return categories
+		}
+	}
+	return append(categories, name)
+}
+
+// qifCategoryName returns the category of an L or S line without its class,
+// or an empty string for transfers, which name an account in brackets.
+func qifCategoryName(value string) string {
+	name, _, _ := strings.Cut(value, "/")
+	name = strings.TrimSpace(name)
+	if strings.HasPrefix(name, "[") {
+		return ""
+	}
+	return name
+}
+
+// Detect recognises QIF files by their !Type header.
+func (p *QIFParser) Detect(sniff *Sniff) float64 {
+	content := strings.TrimPrefix(strings.TrimSpace(sniff.Text), "\ufeff")
+	switch {
+	case strings.HasPrefix(content, "!type:"), strings.HasPrefix(content, "!option:"), strings.HasPrefix(content, "!account"):
+		return 0.95
+	case hasExtension(sniff.FileName, ".qif") && content != "":
+		return 0.5
+	}
+	return 0
+}
+
+func init() {
+	RegisterParser(models.BankTypeQIF, &QIFParser{}, models.ParserInfo{
+		Name:         "Quicken Interchange Format (QIF)",
+		Extensions:   []string{".qif"},
+		SampleHeader: "!Type:Bank",
+	})
+}
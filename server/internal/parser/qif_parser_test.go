@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"expenses/internal/models"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QIFParser", func() {
+	var parser *QIFParser
+
+	BeforeEach(func() {
+		parser = &QIFParser{}
+	})
+
+	Describe("bank and credit card sections", func() {
+		input := "!Option:AutoSwitch\n" +
+			"!Account\n" +
+			"NChecking\n" +
+			"TBank\n" +
+			"^\n" +
+			"!Clear:AutoSwitch\n" +
+			"!Type:Bank\n" +
+			"D1/ 5'24\n" +
+			"T-1,250.00\n" +
+			"PLandlord\n" +
+			"MJanuary rent\n" +
+			"LHousing:Rent/Home\n" +
+			"^\n" +
+			"D01/15/2024\n" +
+			"T3500.00\n" +
+			"PAcme Corp\n" +
+			"LSalary\n" +
+			"^\n" +
+			"D01/20/2024\n" +
+			"T-60.00\n" +
+			"PSupermarket\n" +
+			"SGroceries\n" +
+			"EFood\n" +
+			"$-40.00\n" +
+			"SHousehold\n" +
+			"$-20.00\n" +
+			"^\n" +
+			"D01/21/2024\n" +
+			"T-500.00\n" +
+			"PTransfer\n" +
+			"L[Savings]\n" +
+			"^\n" +
+			"!Type:CCard\n" +
+			"D13/45/2024\n" +
+			"T-5.00\n" +
+			"^\n" +
+			"D02/01/2024\n" +
+			"U-99.50\n" +
+			"MStreaming\n" +
+			"LSubscriptions\n" +
+			"^\n"
+
+		It("reads transactions with debits positive", func() {
+			txns, rowErrors, err := parser.ParseWithRowErrors([]byte(input), "", "export.qif", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns).To(HaveLen(5))
+
+			Expect(txns[0].Name).To(Equal("Landlord"))
+			Expect(txns[0].Description).To(Equal("January rent"))
+			Expect(txns[0].Date).To(Equal(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)))
+			Expect(*txns[0].Amount).To(Equal(1250.00))
+
+			Expect(*txns[1].Amount).To(Equal(-3500.00))
+
+			Expect(*txns[2].Amount).To(Equal(60.00))
+			Expect(txns[2].Description).To(Equal("(split: Groceries -40.00, Household -20.00)"))
+
+			Expect(txns[4].Name).To(Equal("Streaming"))
+			Expect(*txns[4].Amount).To(Equal(99.50))
+
+			Expect(rowErrors).To(HaveLen(1))
+			Expect(rowErrors[0].LineNumber).To(Equal(34))
+			Expect(rowErrors[0].Reason).To(ContainSubstring("failed to parse date"))
+		})
+
+		It("returns the categories of every transaction", func() {
+			txns, categories, _, err := parser.ParseWithCategories([]byte(input))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(categories).To(HaveLen(len(txns)))
+			Expect(categories).To(Equal([][]string{
+				{"Housing:Rent"},
+				{"Salary"},
+				{"Groceries", "Household"},
+				{},
+				{"Subscriptions"},
+			}))
+		})
+	})
+
+	It("reads the cash movements of investment sections", func() {
+		input := "!Type:Invst\n" +
+			"D03/01/2024\n" +
+			"NBuy\n" +
+			"YINFY\n" +
+			"I1500.00\n" +
+			"Q10\n" +
+			"O20.00\n" +
+			"^\n" +
+			"D03/15/2024\n" +
+			"NDiv\n" +
+			"YINFY\n" +
+			"T180.00\n" +
+			"^\n" +
+			"D03/20/2024\n" +
+			"NReinvDiv\n" +
+			"YINFY\n" +
+			"T50.00\n" +
+			"^\n"
+		txns, rowErrors, err := parser.ParseWithRowErrors([]byte(input), "", "export.qif", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rowErrors).To(BeEmpty())
+		Expect(txns).To(HaveLen(2))
+
+		Expect(txns[0].Name).To(Equal("Buy INFY"))
+		Expect(txns[0].Description).To(Equal("10 shares at 1500.00"))
+		Expect(*txns[0].Amount).To(Equal(15020.00))
+
+		Expect(txns[1].Name).To(Equal("Div INFY"))
+		Expect(*txns[1].Amount).To(Equal(-180.00))
+	})
+
+	It("errors on files without a type header", func() {
+		_, _, err := parser.ParseWithRowErrors([]byte("D01/02/2024\nT-1\n^\n"), "", "export.qif", "")
+		Expect(err).To(MatchError(ContainSubstring("!Type header not found")))
+	})
+
+	Describe("WriteQIF", func() {
+		It("writes transactions that are read back unchanged", func() {
+			description := "weekly\nshop"
+			transactions := []models.TransactionResponse{
+				{
+					TransactionBaseResponse: models.TransactionBaseResponse{
+						Name:        "Supermarket",
+						Description: &description,
+						Amount:      84.20,
+						Date:        time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+					},
+					CategoryIds: []int64{7, 3},
+				},
+				{
+					TransactionBaseResponse: models.TransactionBaseResponse{
+						Name:   "Refund",
+						Amount: -20.00,
+						Date:   time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC),
+					},
+					CategoryIds: []int64{},
+				},
+			}
+			qif := WriteQIF(models.BankTypeAxisCredit, transactions, map[int64]string{3: "Food", 7: "Groceries"})
+			Expect(string(qif)).To(Equal("!Type:CCard\n" +
+				"D03/15/2024\nT-84.20\nPSupermarket\nMweekly shop\nLGroceries\n^\n" +
+				"D03/16/2024\nT20.00\nPRefund\n^\n"))
+
+			txns, rowErrors, err := parser.ParseWithRowErrors(qif, "", "export.qif", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rowErrors).To(BeEmpty())
+			Expect(txns).To(HaveLen(2))
+			Expect(*txns[0].Amount).To(Equal(84.20))
+			Expect(txns[0].Date).To(Equal(transactions[0].Date))
+			Expect(*txns[1].Amount).To(Equal(-20.00))
+
+			_, categories, _, err := parser.ParseWithCategories(qif)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(categories[0]).To(Equal([]string{"Groceries"}))
+		})
+	})
+})
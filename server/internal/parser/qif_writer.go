@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"bytes"
+	"expenses/internal/models"
+	"fmt"
+	"strings"
+)
+
+// qifAccountType returns the QIF section type for transactions of an account.
+func qifAccountType(bankType models.BankType) string {
+	switch bankType {
+	case models.BankTypeAxisCredit, models.BankTypeICICICredit:
+		return "CCard"
+	case models.BankTypeInvestment:
+		return "Oth A"
+	default:
+		return "Bank"
+	}
+}
+
+// WriteQIF writes the transactions of an account as a QIF file that QIFParser
+// reads back. categoryNames maps category ids to names; QIF has a single
+// category per transaction, so only the first category of a transaction is
+// written.
+func WriteQIF(bankType models.BankType, transactions []models.TransactionResponse, categoryNames map[int64]string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "!Type:%s\n", qifAccountType(bankType))
+	for _, txn := range transactions {
+		fmt.Fprintf(&buf, "D%s\n", txn.Date.Format("01/02/2006"))
+		// Transactions keep debits positive, QIF writes them as negative.
+		fmt.Fprintf(&buf, "T%.2f\n", -txn.Amount)
+		fmt.Fprintf(&buf, "P%s\n", qifValue(txn.Name))
+		if txn.Description != nil && strings.TrimSpace(*txn.Description) != "" {
+			fmt.Fprintf(&buf, "M%s\n", qifValue(*txn.Description))
+		}
+		for _, categoryId := range txn.CategoryIds {
+			if name, ok := categoryNames[categoryId]; ok {
+				fmt.Fprintf(&buf, "L%s\n", qifValue(name))
+				break
+			}
+		}
+		buf.WriteString("^\n")
+	}
+	return buf.Bytes()
+}
+
+// qifValue keeps a value on a single line, as every QIF field is one line.
+func qifValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
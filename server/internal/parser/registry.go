@@ -1,19 +1,120 @@
 package parser
 
-import "expenses/internal/models"
+import (
+	"expenses/internal/models"
+	"sort"
+	"strings"
+)
 
 // Parser defines the interface for different bank statement parsers.
 type Parser interface {
 	Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error)
 }
 
-var parserRegistry = make(map[models.BankType]Parser)
+// BalanceParser is implemented by parsers whose statement format carries the
+// opening and closing balances of the statement period.
+type BalanceParser interface {
+	ParseBalances(fileBytes []byte) (models.StatementBalances, error)
+}
+
+// CardSummaryParser is implemented by credit card parsers that read the
+// billing details of the statement: its period, the amounts due and the date
+// the payment is due.
+type CardSummaryParser interface {
+	ParseCardSummary(fileBytes []byte, password string) (models.CardStatementSummary, error)
+}
+
+// CategoryParser is implemented by parsers of formats that name the categories
+// of their transactions. The names of each transaction are returned at its
+// index and are matched against the user's categories by name.
+type CategoryParser interface {
+	ParseWithCategories(fileBytes []byte) ([]models.CreateTransactionInput, [][]string, []models.StatementRowError, error)
+}
 
-func RegisterParser(bankType models.BankType, parser Parser) {
+var (
+	parserRegistry     = make(map[models.BankType]Parser)
+	parserInfoRegistry = make(map[models.BankType]models.ParserInfo)
+)
+
+// RegisterParser registers the parser of a statement format together with the
+// description shown to users. The bank type and capabilities of info are set
+// here from the parser itself.
+func RegisterParser(bankType models.BankType, parser Parser, info models.ParserInfo) {
+	info.BankType = bankType
+	info.Capabilities = parserCapabilities(parser)
+	if info.Extensions == nil {
+		info.Extensions = []string{}
+	}
 	parserRegistry[bankType] = parser
+	parserInfoRegistry[bankType] = info
 }
 
 func GetParser(bankType models.BankType) (Parser, bool) {
 	parser, ok := parserRegistry[bankType]
 	return parser, ok
 }
+
+// ListParsers returns the description of every registered parser, ordered by
+// bank type.
+func ListParsers() []models.ParserInfo {
+	infos := make([]models.ParserInfo, 0, len(parserInfoRegistry))
+	for _, info := range parserInfoRegistry {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].BankType < infos[j].BankType
+	})
+	return infos
+}
+
+func parserCapabilities(p Parser) models.ParserCapabilities {
+	_, detection := p.(Detector)
+	_, rowErrors := p.(RowErrorParser)
+	_, balances := p.(BalanceParser)
+	_, cardSummary := p.(CardSummaryParser)
+	_, categories := p.(CategoryParser)
+	return models.ParserCapabilities{
+		Detection:   detection,
+		RowErrors:   rowErrors,
+		Balances:    balances,
+		CardSummary: cardSummary,
+		Categories:  categories,
+	}
+}
+
+// RowErrorParser is implemented by parsers that report the rows they skipped
+// while parsing a statement, so they can be shown to the user.
+type RowErrorParser interface {
+	ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error)
+}
+
+// ParseWithRowErrors parses a statement with the given parser and returns the
+// rows it could not import. Parsers that do not report row errors return none.
+func ParseWithRowErrors(p Parser, fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	if rowErrorParser, ok := p.(RowErrorParser); ok {
+		return rowErrorParser.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	}
+	transactions, err := p.Parse(fileBytes, metadata, fileName, password)
+	return transactions, nil, err
+}
+
+// ParseWithCategories parses the file like ParseWithRowErrors, and also returns
+// the categories named by each transaction when p implements CategoryParser.
+// The categories are nil for other parsers.
+func ParseWithCategories(p Parser, fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, [][]string, []models.StatementRowError, error) {
+	if categoryParser, ok := p.(CategoryParser); ok {
+		return categoryParser.ParseWithCategories(fileBytes)
+	}
+	transactions, rowErrors, err := ParseWithRowErrors(p, fileBytes, metadata, fileName, password)
+	return transactions, nil, rowErrors, err
+}
+
+// newRowError builds the row error for a row that failed to parse. Cells are
+// joined with commas to show the row as it appeared in the file.
+func newRowError(lineNumber int, cells []string, err error) models.StatementRowError {
+	return models.StatementRowError{
+		LineNumber: lineNumber,
+		RawRow:     strings.Join(cells, ","),
+		Reason:     err.Error(),
+	}
+}
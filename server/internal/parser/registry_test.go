@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"expenses/internal/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registry", func() {
+	Describe("ListParsers", func() {
+		It("should describe every registered parser in bank type order", func() {
+			parsers := ListParsers()
+			Expect(parsers).To(HaveLen(len(parserRegistry)))
+			for i := 1; i < len(parsers); i++ {
+				Expect(parsers[i-1].BankType < parsers[i].BankType).To(BeTrue())
+			}
+			for _, info := range parsers {
+				Expect(info.Name).NotTo(BeEmpty(), string(info.BankType))
+				Expect(info.Extensions).NotTo(BeEmpty(), string(info.BankType))
+			}
+		})
+
+		It("should fill in the capabilities from the parser", func() {
+			infos := make(map[models.BankType]models.ParserInfo)
+			for _, info := range ListParsers() {
+				infos[info.BankType] = info
+			}
+
+			Expect(infos[models.BankTypeMT940].Capabilities).To(Equal(models.ParserCapabilities{
+				Detection: true,
+				RowErrors: true,
+				Balances:  true,
+			}))
+			Expect(infos[models.BankTypeAxisCredit].Capabilities.CardSummary).To(BeTrue())
+			Expect(infos[models.BankTypeQIF].Capabilities.Categories).To(BeTrue())
+			Expect(infos[models.BankTypeOthers].NeedsMetadata).To(BeTrue())
+			Expect(infos[models.BankTypeOthers].Capabilities.Detection).To(BeFalse())
+		})
+	})
+})
@@ -10,23 +10,51 @@ import (
 	"strings"
 )
 
+// SBIParser parses SBI account statements exported as XLSX or PDF
 type SBIParser struct{}
 
+// sbiPDFLayout maps the SBI PDF statement columns to the order of the XLSX export.
+var sbiPDFLayout = tableLayout{
+	headerKeywords: []string{"date", "debit", "credit", "balance"},
+	columns: [][]string{
+		{"txn date", "transaction date", "date"},
+		{"description", "details", "narration"},
+		{"ref no", "cheque"},
+		{"debit", "withdrawal"},
+		{"credit", "deposit"},
+		{"balance"},
+	},
+}
+
 func (p *SBIParser) Parse(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, error) {
+	transactions, _, err := p.ParseWithRowErrors(fileBytes, metadata, fileName, password)
+	return transactions, err
+}
+
+func (p *SBIParser) ParseWithRowErrors(fileBytes []byte, metadata string, fileName string, password string) ([]models.CreateTransactionInput, []models.StatementRowError, error) {
+	if IsPDF(fileBytes) {
+		rows, err := extractPDFTable(fileBytes, password, sbiPDFLayout)
+		if err != nil {
+			return nil, nil, err
+		}
+		transactions, rowErrors := parsePDFRows(rows, p.parseTransactionRow)
+		return transactions, rowErrors, nil
+	}
+
 	f, err := openWorkbook(fileBytes, password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open XLSX file: %w", err)
+		return nil, nil, fmt.Errorf("failed to open XLSX file: %w", err)
 	}
 	defer f.Close()
 
 	sheets := f.GetSheetList()
 	if len(sheets) == 0 {
-		return nil, errors.New("no sheets found in XLSX file")
+		return nil, nil, errors.New("no sheets found in XLSX file")
 	}
 
 	rows, err := f.GetRows(sheets[0])
 	if err != nil {
-		return nil, fmt.Errorf("failed to read rows from sheet: %w", err)
+		return nil, nil, fmt.Errorf("failed to read rows from sheet: %w", err)
 	}
 
 	headerRowIndex := -1
@@ -42,10 +70,11 @@ func (p *SBIParser) Parse(fileBytes []byte, metadata string, fileName string, pa
 	}
 
 	if headerRowIndex == -1 {
-		return nil, errors.New("transaction header row not found")
+		return nil, nil, errors.New("transaction header row not found")
 	}
 
 	var transactions []models.CreateTransactionInput
+	var rowErrors []models.StatementRowError
 	for i := headerRowIndex + 1; i < len(rows); i++ {
 		row := rows[i]
 
@@ -57,6 +86,7 @@ func (p *SBIParser) Parse(fileBytes []byte, metadata string, fileName string, pa
 		transaction, err := p.parseTransactionRow(row)
 		if err != nil {
 			logger.Warnf("Failed to parse row %d: %v\n", i+1, err)
+			rowErrors = append(rowErrors, newRowError(i+1, row, err))
 			continue
 		}
 
@@ -65,7 +95,7 @@ func (p *SBIParser) Parse(fileBytes []byte, metadata string, fileName string, pa
 		}
 	}
 
-	return transactions, nil
+	return transactions, rowErrors, nil
 }
 
 func (p *SBIParser) parseTransactionRow(fields []string) (*models.CreateTransactionInput, error) {
@@ -177,6 +207,37 @@ func (p *SBIParser) generateTransactionName(description string, isCredit bool) s
 	return desc
 }
 
+// Detect recognises SBI workbooks by a Date/Details header with separate debit
+// and credit columns.
+func (p *SBIParser) Detect(sniff *Sniff) float64 {
+	if sniff.PDF {
+		if containsAll(sniff.Text, "txn date", "description", "ref no", "debit", "credit") {
+			return 0.8
+		}
+		return 0
+	}
+	for _, row := range sniff.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		first := strings.ToLower(row[0]) + " " + strings.ToLower(row[1])
+		joined := strings.ToLower(strings.Join(row, " "))
+		if !strings.Contains(first, "date") || !strings.Contains(first, "details") || strings.Contains(joined, "amount") {
+			continue
+		}
+		if containsAll(joined, "ref no", "debit", "credit") {
+			return 0.85
+		}
+		return 0.6
+	}
+	return 0
+}
+
 func init() {
-	RegisterParser(models.BankTypeSBI, &SBIParser{})
+	RegisterParser(models.BankTypeSBI, &SBIParser{}, models.ParserInfo{
+		Name:          "State Bank of India",
+		Extensions:    []string{".xlsx", ".pdf"},
+		NeedsPassword: true,
+		SampleHeader:  "Date,Details,Ref No/Cheque No,Debit,Credit,Balance",
+	})
 }
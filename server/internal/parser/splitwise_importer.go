@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"errors"
+	"expenses/internal/models"
+	"expenses/pkg/utils"
+	"fmt"
+	"strings"
+)
+
+// SplitwiseImporter reads the CSV export of a Splitwise group or friend.
+// Splitwise has no accounts, so every expense is imported into one account
+// named after the app. The export lists the balance of each member but not
+// which member exported it, so the full cost of the expense is imported.
+type SplitwiseImporter struct{}
+
+// splitwiseAccountName is the account Splitwise expenses are imported into.
+const splitwiseAccountName = "Splitwise"
+
+var splitwiseLayout = tableLayout{
+	headerKeywords: []string{"date", "description", "category", "cost", "currency"},
+	columns: [][]string{
+		{"date"},
+		{"description"},
+		{"category"},
+		{"cost"},
+	},
+}
+
+func init() {
+	RegisterAppImporter(models.AppImportSourceSplitwise, &SplitwiseImporter{})
+}
+
+func (i *SplitwiseImporter) Import(fileBytes []byte, fileName string) ([]models.AppImportTransaction, []models.StatementRowError, error) {
+	rows, err := readExportRows(fileBytes, fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseTableRows(rows, splitwiseLayout, i.parseRow)
+}
+
+func (i *SplitwiseImporter) parseRow(fields []string) (*models.AppImportTransaction, error) {
+	// Columns as aligned by splitwiseLayout:
+	// 0: Date, 1: Description, 2: Category, 3: Cost
+	description := fields[1]
+	category := fields[2]
+	// The export ends with the total balance of every member, and settle ups
+	// are recorded as payments; neither is an expense.
+	if strings.EqualFold(description, "Total balance") || strings.EqualFold(category, "Payment") {
+		return nil, nil
+	}
+
+	date, err := parseDateWithLayouts(fields[0], "2006-01-02")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse date '%s': %w", fields[0], err)
+	}
+	if fields[3] == "" {
+		return nil, errors.New("missing cost")
+	}
+	cost, err := utils.ParseFloat(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cost '%s': %w", fields[3], err)
+	}
+	// General is what Splitwise calls an uncategorised expense.
+	if strings.EqualFold(category, "General") {
+		category = ""
+	}
+	return newAppImportTransaction(splitwiseAccountName, date, description, description, cost, category), nil
+}
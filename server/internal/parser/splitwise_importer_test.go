@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SplitwiseImporter", func() {
+	var importer *SplitwiseImporter
+
+	BeforeEach(func() {
+		importer = &SplitwiseImporter{}
+	})
+
+	It("imports expenses into the Splitwise account", func() {
+		input := "Date,Description,Category,Cost,Currency,Jane Roe,John Doe\n" +
+			"\n" +
+			"2024-03-02,Dinner at Toit,Dining out,2400.00,INR,1200.00,-1200.00\n" +
+			"2024-03-05,Cab to airport,Taxi,\"1,150.50\",INR,-575.25,575.25\n" +
+			"2024-03-09,Movie tickets,General,600.00,INR,300.00,-300.00\n" +
+			"2024-03-10,John Doe paid Jane Roe,Payment,1200.00,INR,-1200.00,1200.00\n" +
+			"\n" +
+			"2024-03-31,Total balance, , ,INR,300.00,-300.00\n"
+		txns, rowErrors, err := importer.Import([]byte(input), "splitwise.csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rowErrors).To(BeEmpty())
+		Expect(txns).To(HaveLen(3))
+
+		Expect(txns[0].AccountName).To(Equal("Splitwise"))
+		Expect(txns[0].Name).To(Equal("Dinner at Toit"))
+		Expect(txns[0].Date).To(Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)))
+		Expect(*txns[0].Amount).To(Equal(2400.00))
+		Expect(txns[0].CategoryNames).To(Equal([]string{"Dining out"}))
+
+		Expect(*txns[1].Amount).To(Equal(1150.50))
+		Expect(txns[1].CategoryNames).To(Equal([]string{"Taxi"}))
+
+		Expect(txns[2].Name).To(Equal("Movie tickets"))
+		Expect(txns[2].CategoryNames).To(BeEmpty())
+	})
+
+	It("reports rows that cannot be read", func() {
+		input := "Date,Description,Category,Cost,Currency,Jane Roe\n" +
+			"2024-03-02,Dinner,Dining out,abc,INR,0\n"
+		txns, rowErrors, err := importer.Import([]byte(input), "splitwise.csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(txns).To(BeEmpty())
+		Expect(rowErrors).To(HaveLen(1))
+		Expect(rowErrors[0].LineNumber).To(Equal(2))
+		Expect(rowErrors[0].Reason).To(ContainSubstring("failed to parse cost"))
+	})
+
+	It("errors when the file is not a Splitwise export", func() {
+		_, _, err := importer.Import([]byte("foo,bar\n1,2\n"), "splitwise.csv")
+		Expect(err).To(MatchError(ContainSubstring("header row not found")))
+	})
+})
@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"errors"
+	"expenses/internal/models"
+	"expenses/pkg/logger"
+	"expenses/pkg/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// tableLayout describes how to find a transaction table in a statement whose
+// column order varies between exports, such as PDFs and spreadsheets.
+type tableLayout struct {
+	// headerKeywords must all appear (lower-cased) in the header line.
+	headerKeywords []string
+	// columns lists, for every column the row parser expects, the header
+	// keywords that identify it in order of preference. Columns that are not
+	// found are left empty.
+	columns [][]string
+}
+
+// matchHeaderColumns maps every expected column to the index of the header
+// cell that names it, or -1 when the statement has no such column.
+func matchHeaderColumns(header []string, columns [][]string) []int {
+	used := make([]bool, len(header))
+	index := make([]int, len(columns))
+	for i, keywords := range columns {
+		index[i] = -1
+	keywordLoop:
+		for _, keyword := range keywords {
+			for j, cell := range header {
+				if !used[j] && strings.Contains(strings.ToLower(cell), keyword) {
+					index[i] = j
+					used[j] = true
+					break keywordLoop
+				}
+			}
+		}
+	}
+	return index
+}
+
+// alignRow reorders the cells of a row to the columns of a layout, using the
+// index from matchHeaderColumns. Missing cells are left empty.
+func alignRow(cells []string, columnIndex []int) []string {
+	row := make([]string, len(columnIndex))
+	for i, idx := range columnIndex {
+		if idx >= 0 && idx < len(cells) {
+			row[i] = strings.TrimSpace(cells[idx])
+		}
+	}
+	return row
+}
+
+// findHeaderRow returns the index of the first row containing every header
+// keyword of the layout, or -1 when there is none.
+func findHeaderRow(rows [][]string, layout tableLayout) int {
+	for i, row := range rows {
+		if containsAll(strings.ToLower(strings.Join(row, " ")), layout.headerKeywords...) {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseTableRows finds the header of a spreadsheet or CSV table and runs a bank
+// or app specific row parser over the rows below it, with cells aligned to
+// layout.columns. Rows without a value in the first column (blank lines,
+// footers and legends) are skipped. Skipped rows are numbered by their
+// position in rows, counting from 1.
+func parseTableRows[T any](rows [][]string, layout tableLayout, parseRow func([]string) (*T, error)) ([]T, []models.StatementRowError, error) {
+	headerIndex := findHeaderRow(rows, layout)
+	if headerIndex == -1 {
+		return nil, nil, errors.New("transaction header row not found")
+	}
+	columnIndex := matchHeaderColumns(rows[headerIndex], layout.columns)
+
+	var transactions []T
+	var rowErrors []models.StatementRowError
+	for i := headerIndex + 1; i < len(rows); i++ {
+		row := alignRow(rows[i], columnIndex)
+		if row[0] == "" {
+			logger.Debugf("Skipping row %d: no transaction date", i+1)
+			continue
+		}
+
+		transaction, err := parseRow(row)
+		if err != nil {
+			logger.Warnf("Failed to parse row %d: %v", i+1, err)
+			rowErrors = append(rowErrors, newRowError(i+1, rows[i], err))
+			continue
+		}
+		if transaction != nil {
+			transactions = append(transactions, *transaction)
+		}
+	}
+	return transactions, rowErrors, nil
+}
+
+// readFirstSheet returns the rows of the first sheet of an Excel workbook,
+// either a legacy .xls file or an xlsx file.
+func readFirstSheet(fileBytes []byte, password string) ([][]string, error) {
+	if isCompoundFile(fileBytes) && password == "" {
+		sheets, err := readXLSSheets(fileBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open XLS file: %w", err)
+		}
+		if len(sheets) == 0 {
+			return nil, errors.New("no sheets found in XLS file")
+		}
+		return sheets[0].Rows, nil
+	}
+	f, err := openWorkbook(fileBytes, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX file: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("no sheets found in XLSX file")
+	}
+	rows, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rows from sheet: %w", err)
+	}
+	return rows, nil
+}
+
+// parseDateWithLayouts tries the bank specific layouts before the common ones
+// of utils.ParseDate, for banks whose dates would otherwise be ambiguous.
+func parseDateWithLayouts(value string, layouts ...string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return utils.ParseDate(value)
+}
+
+// parseOptionalAmount reads a debit or credit cell, treating empty cells and
+// zero placeholders as no amount.
+func parseOptionalAmount(value string) (float64, bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "-" {
+		return 0, false, nil
+	}
+	amount, err := utils.ParseFloat(value)
+	if err != nil {
+		return 0, false, err
+	}
+	return amount, amount != 0, nil
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"errors"
+	"expenses/internal/models"
+	"expenses/pkg/utils"
+	"fmt"
+	"strings"
+)
+
+// WalnutImporter reads the CSV report exported by Walnut. Walnut writes dates
+// day first and marks every amount as DR or CR.
+type WalnutImporter struct{}
+
+var walnutLayout = tableLayout{
+	headerKeywords: []string{"date", "place", "amount", "dr/cr", "account", "category"},
+	columns: [][]string{
+		{"date"},
+		{"place"},
+		{"amount"},
+		{"dr/cr"},
+		{"account"},
+		{"category"},
+		{"note"},
+	},
+}
+
+func init() {
+	RegisterAppImporter(models.AppImportSourceWalnut, &WalnutImporter{})
+}
+
+func (i *WalnutImporter) Import(fileBytes []byte, fileName string) ([]models.AppImportTransaction, []models.StatementRowError, error) {
+	rows, err := readExportRows(fileBytes, fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseTableRows(rows, walnutLayout, i.parseRow)
+}
+
+func (i *WalnutImporter) parseRow(fields []string) (*models.AppImportTransaction, error) {
+	// Columns as aligned by walnutLayout:
+	// 0: Date, 1: Place, 2: Amount, 3: DR/CR, 4: Account, 5: Category, 6: Note
+	date, err := parseDateWithLayouts(fields[0], "02-01-06", "02-01-2006", "02/01/06", "02/01/2006", "02 Jan 2006")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse date '%s': %w", fields[0], err)
+	}
+	if fields[2] == "" {
+		return nil, errors.New("missing amount")
+	}
+	amount, err := utils.ParseFloat(stripCurrency(fields[2]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse amount '%s': %w", fields[2], err)
+	}
+	switch strings.ToUpper(fields[3]) {
+	case "CR":
+		amount = -amount
+	case "DR":
+	default:
+		return nil, fmt.Errorf("unknown DR/CR indicator '%s'", fields[3])
+	}
+
+	place := fields[1]
+	note := fields[6]
+	name := place
+	if name == "" {
+		name = note
+	}
+	if name == "" {
+		name = "Walnut transaction"
+	}
+	account := fields[4]
+	if account == "" {
+		account = "Walnut"
+	}
+	return newAppImportTransaction(account, date, name, note, amount, fields[5]), nil
+}
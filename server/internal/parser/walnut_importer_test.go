@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WalnutImporter", func() {
+	var importer *WalnutImporter
+
+	BeforeEach(func() {
+		importer = &WalnutImporter{}
+	})
+
+	It("imports the transactions of every account", func() {
+		input := "Walnut report from 01-04-24 to 30-04-24\n" +
+			"\n" +
+			"DATE,TIME,PLACE,AMOUNT,DR/CR,ACCOUNT,EXPENSE,INCOME,CATEGORY,TAGS,NOTE\n" +
+			"03-04-24,01:15 PM,Swiggy,\"1,250.00\",DR,HDFC 1234,Yes,No,FOOD & DRINKS,,team lunch\n" +
+			"10-04-24,10:00 AM,,\"50,000.00\",CR,ICICI 5678,No,Yes,INCOME,,salary\n" +
+			"12-04-24,06:40 PM,Uber,320.00,DR,,Yes,No,TRAVEL,,\n"
+		txns, rowErrors, err := importer.Import([]byte(input), "walnut.csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rowErrors).To(BeEmpty())
+		Expect(txns).To(HaveLen(3))
+
+		Expect(txns[0].AccountName).To(Equal("HDFC 1234"))
+		Expect(txns[0].Name).To(Equal("Swiggy"))
+		Expect(txns[0].Description).To(Equal("team lunch"))
+		Expect(txns[0].Date).To(Equal(time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC)))
+		Expect(*txns[0].Amount).To(Equal(1250.00))
+		Expect(txns[0].CategoryNames).To(Equal([]string{"FOOD & DRINKS"}))
+
+		Expect(txns[1].AccountName).To(Equal("ICICI 5678"))
+		Expect(txns[1].Name).To(Equal("salary"))
+		Expect(*txns[1].Amount).To(Equal(-50000.00))
+
+		Expect(txns[2].AccountName).To(Equal("Walnut"))
+	})
+
+	It("reports an unknown DR/CR indicator", func() {
+		input := "DATE,TIME,PLACE,AMOUNT,DR/CR,ACCOUNT,EXPENSE,INCOME,CATEGORY,TAGS,NOTE\n" +
+			"03-04-24,01:15 PM,Swiggy,100.00,XX,HDFC 1234,Yes,No,FOOD & DRINKS,,\n"
+		_, rowErrors, err := importer.Import([]byte(input), "walnut.csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rowErrors).To(HaveLen(1))
+		Expect(rowErrors[0].Reason).To(ContainSubstring("unknown DR/CR indicator"))
+	})
+})
@@ -0,0 +1,516 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// BIFF8 record types read from legacy Excel workbooks.
+const (
+	xlsRecordFormula    = 0x0006
+	xlsRecordEOF        = 0x000A
+	xlsRecordDateMode   = 0x0022
+	xlsRecordFilePass   = 0x002F
+	xlsRecordContinue   = 0x003C
+	xlsRecordBoundSheet = 0x0085
+	xlsRecordMulRK      = 0x00BD
+	xlsRecordRString    = 0x00D6
+	xlsRecordXF         = 0x00E0
+	xlsRecordSST        = 0x00FC
+	xlsRecordLabelSST   = 0x00FD
+	xlsRecordNumber     = 0x0203
+	xlsRecordLabel      = 0x0204
+	xlsRecordBoolErr    = 0x0205
+	xlsRecordString     = 0x0207
+	xlsRecordRK         = 0x027E
+	xlsRecordFormat     = 0x041E
+	xlsRecordBOF        = 0x0809
+)
+
+var compoundFileHeader = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+var errInvalidXLS = errors.New("invalid xls file")
+
+// isCompoundFile reports whether data is an OLE compound file, the container
+// of genuine Excel 97-2003 workbooks. Many banks export HTML or tab separated
+// text with an .xls extension instead.
+func isCompoundFile(data []byte) bool {
+	return bytes.HasPrefix(data, compoundFileHeader)
+}
+
+// xlsSheet is a worksheet of a legacy workbook with its cells as text.
+type xlsSheet struct {
+	Name string
+	Rows [][]string
+}
+
+type xlsRecord struct {
+	id   uint16
+	data []byte
+}
+
+type xlsWorkbook struct {
+	stream   []byte
+	date1904 bool
+	sst      []string
+	// xfFormats holds the number format of each cell format (XF) record.
+	xfFormats     []uint16
+	customFormats map[uint16]string
+}
+
+// readXLSSheets reads the worksheets of a BIFF8 workbook. Numbers are written
+// without formatting and dates as YYYY-MM-DD.
+func readXLSSheets(fileBytes []byte) ([]xlsSheet, error) {
+	doc, err := mscfb.New(bytes.NewReader(fileBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open xls file: %w", err)
+	}
+	var stream []byte
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name == "Workbook" || entry.Name == "Book" {
+			if stream, err = io.ReadAll(entry); err != nil {
+				return nil, fmt.Errorf("failed to read xls workbook stream: %w", err)
+			}
+			break
+		}
+	}
+	if stream == nil {
+		return nil, errors.New("no workbook found in xls file")
+	}
+
+	wb := &xlsWorkbook{stream: stream, customFormats: make(map[uint16]string)}
+	type sheetRef struct {
+		name   string
+		offset uint32
+	}
+	var refs []sheetRef
+	offset := 0
+	for {
+		record, next, err := wb.readRecord(offset)
+		if err != nil {
+			return nil, err
+		}
+		switch record.id {
+		case xlsRecordFilePass:
+			return nil, errors.New("password protected xls files are not supported")
+		case xlsRecordBOF:
+			if len(record.data) < 2 || binary.LittleEndian.Uint16(record.data) != 0x0600 {
+				return nil, errors.New("only Excel 97-2003 (BIFF8) xls files are supported")
+			}
+		case xlsRecordDateMode:
+			wb.date1904 = len(record.data) >= 2 && binary.LittleEndian.Uint16(record.data) == 1
+		case xlsRecordXF:
+			if len(record.data) < 4 {
+				return nil, errInvalidXLS
+			}
+			wb.xfFormats = append(wb.xfFormats, binary.LittleEndian.Uint16(record.data[2:]))
+		case xlsRecordFormat:
+			if len(record.data) < 2 {
+				return nil, errInvalidXLS
+			}
+			r := &xlsReader{segments: [][]byte{record.data[2:]}}
+			format, err := r.readString(2)
+			if err != nil {
+				return nil, err
+			}
+			wb.customFormats[binary.LittleEndian.Uint16(record.data)] = format
+		case xlsRecordBoundSheet:
+			// Only worksheets hold cells; charts and macro sheets are skipped.
+			if len(record.data) < 8 || record.data[5] != 0 {
+				break
+			}
+			r := &xlsReader{segments: [][]byte{record.data[6:]}}
+			name, err := r.readString(1)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, sheetRef{name: name, offset: binary.LittleEndian.Uint32(record.data)})
+		case xlsRecordSST:
+			if wb.sst, next, err = wb.readSST(record, next); err != nil {
+				return nil, err
+			}
+		}
+		offset = next
+		if record.id == xlsRecordEOF {
+			break
+		}
+	}
+
+	sheets := make([]xlsSheet, 0, len(refs))
+	for _, ref := range refs {
+		rows, err := wb.readSheet(int(ref.offset))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sheet '%s': %w", ref.name, err)
+		}
+		sheets = append(sheets, xlsSheet{Name: ref.name, Rows: rows})
+	}
+	return sheets, nil
+}
+
+func (wb *xlsWorkbook) readRecord(offset int) (xlsRecord, int, error) {
+	if offset+4 > len(wb.stream) {
+		return xlsRecord{}, 0, errInvalidXLS
+	}
+	id := binary.LittleEndian.Uint16(wb.stream[offset:])
+	size := int(binary.LittleEndian.Uint16(wb.stream[offset+2:]))
+	end := offset + 4 + size
+	if end > len(wb.stream) {
+		return xlsRecord{}, 0, errInvalidXLS
+	}
+	return xlsRecord{id: id, data: wb.stream[offset+4 : end]}, end, nil
+}
+
+// readSST reads the shared string table along with the CONTINUE records it
+// spills into.
+func (wb *xlsWorkbook) readSST(record xlsRecord, next int) ([]string, int, error) {
+	segments := [][]byte{record.data}
+	for {
+		continuation, after, err := wb.readRecord(next)
+		if err != nil || continuation.id != xlsRecordContinue {
+			break
+		}
+		segments = append(segments, continuation.data)
+		next = after
+	}
+
+	r := &xlsReader{segments: segments}
+	if _, err := r.readUint32(); err != nil {
+		return nil, 0, err
+	}
+	count, err := r.readUint32()
+	if err != nil {
+		return nil, 0, err
+	}
+	strs := make([]string, 0, min(int(count), len(record.data)))
+	for i := uint32(0); i < count; i++ {
+		s, err := r.readString(2)
+		if err != nil {
+			return nil, 0, err
+		}
+		strs = append(strs, s)
+	}
+	return strs, next, nil
+}
+
+// readSheet reads the cells of the worksheet whose BOF record is at offset.
+func (wb *xlsWorkbook) readSheet(offset int) ([][]string, error) {
+	var rows [][]string
+	set := func(row, col uint16, value string) {
+		for len(rows) <= int(row) {
+			rows = append(rows, nil)
+		}
+		for len(rows[row]) <= int(col) {
+			rows[row] = append(rows[row], "")
+		}
+		rows[row][col] = value
+	}
+	setCell := func(data []byte, value string) {
+		row, col := cellRef(data)
+		set(row, col, value)
+	}
+
+	// A formula returning text keeps its value in the STRING record after it.
+	var pendingRow, pendingCol uint16
+	pendingString := false
+	for {
+		record, next, err := wb.readRecord(offset)
+		if err != nil {
+			return nil, err
+		}
+		offset = next
+		if record.id == xlsRecordEOF {
+			break
+		}
+		data := record.data
+		if record.id != xlsRecordString && record.id != xlsRecordContinue {
+			pendingString = false
+		}
+		if len(data) < 6 && record.id != xlsRecordString && record.id != xlsRecordContinue {
+			continue
+		}
+
+		switch record.id {
+		case xlsRecordLabelSST:
+			if len(data) < 10 {
+				return nil, errInvalidXLS
+			}
+			idx := binary.LittleEndian.Uint32(data[6:])
+			if int(idx) >= len(wb.sst) {
+				return nil, errInvalidXLS
+			}
+			setCell(data, wb.sst[idx])
+		case xlsRecordLabel, xlsRecordRString:
+			r := &xlsReader{segments: [][]byte{data[6:]}}
+			s, err := r.readString(2)
+			if err != nil {
+				return nil, err
+			}
+			setCell(data, s)
+		case xlsRecordNumber:
+			if len(data) < 14 {
+				return nil, errInvalidXLS
+			}
+			value := math.Float64frombits(binary.LittleEndian.Uint64(data[6:]))
+			setCell(data, wb.formatNumber(value, binary.LittleEndian.Uint16(data[4:])))
+		case xlsRecordRK:
+			if len(data) < 10 {
+				return nil, errInvalidXLS
+			}
+			value := decodeRK(binary.LittleEndian.Uint32(data[6:]))
+			setCell(data, wb.formatNumber(value, binary.LittleEndian.Uint16(data[4:])))
+		case xlsRecordMulRK:
+			row := binary.LittleEndian.Uint16(data)
+			col := binary.LittleEndian.Uint16(data[2:])
+			for pos := 4; pos+6 <= len(data)-2; pos += 6 {
+				xf := binary.LittleEndian.Uint16(data[pos:])
+				value := decodeRK(binary.LittleEndian.Uint32(data[pos+2:]))
+				set(row, col, wb.formatNumber(value, xf))
+				col++
+			}
+		case xlsRecordBoolErr:
+			if len(data) < 8 {
+				return nil, errInvalidXLS
+			}
+			if data[7] == 0 {
+				setCell(data, formatBool(data[6] != 0))
+			}
+		case xlsRecordFormula:
+			if len(data) < 14 {
+				return nil, errInvalidXLS
+			}
+			row, col := cellRef(data)
+			result := data[6:14]
+			if result[6] != 0xFF || result[7] != 0xFF {
+				value := math.Float64frombits(binary.LittleEndian.Uint64(result))
+				set(row, col, wb.formatNumber(value, binary.LittleEndian.Uint16(data[4:])))
+				break
+			}
+			switch result[0] {
+			case 0:
+				pendingRow, pendingCol, pendingString = row, col, true
+			case 1:
+				set(row, col, formatBool(result[2] != 0))
+			}
+		case xlsRecordString:
+			if !pendingString {
+				break
+			}
+			r := &xlsReader{segments: [][]byte{data}}
+			s, err := r.readString(2)
+			if err != nil {
+				return nil, err
+			}
+			set(pendingRow, pendingCol, s)
+			pendingString = false
+		}
+	}
+
+	// Drop trailing empty cells and rows, as excelize does for xlsx files.
+	for i, row := range rows {
+		end := len(row)
+		for end > 0 && row[end-1] == "" {
+			end--
+		}
+		rows[i] = row[:end]
+	}
+	for len(rows) > 0 && len(rows[len(rows)-1]) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	for i := range rows {
+		if rows[i] == nil {
+			rows[i] = []string{}
+		}
+	}
+	return rows, nil
+}
+
+func cellRef(data []byte) (uint16, uint16) {
+	return binary.LittleEndian.Uint16(data), binary.LittleEndian.Uint16(data[2:])
+}
+
+func formatBool(value bool) string {
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// decodeRK decodes the compressed number format Excel uses for cells that fit
+// in 30 bits.
+func decodeRK(rk uint32) float64 {
+	var value float64
+	if rk&0x02 != 0 {
+		value = float64(int32(rk) >> 2)
+	} else {
+		value = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		value /= 100
+	}
+	return value
+}
+
+// formatNumber writes a cell value as text, turning the serial numbers of
+// cells with a date format into dates.
+func (wb *xlsWorkbook) formatNumber(value float64, xf uint16) string {
+	if int(xf) < len(wb.xfFormats) && wb.isDateFormat(wb.xfFormats[xf]) {
+		base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+		if wb.date1904 {
+			base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+		}
+		t := base.Add(time.Duration(math.Round(value*86400)) * time.Second)
+		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+			return t.Format("2006-01-02")
+		}
+		return t.Format("2006-01-02 15:04:05")
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func (wb *xlsWorkbook) isDateFormat(format uint16) bool {
+	switch {
+	case format >= 14 && format <= 22, format >= 45 && format <= 47:
+		return true
+	case format < 164:
+		return false
+	}
+	code, ok := wb.customFormats[format]
+	if !ok {
+		return false
+	}
+	// Ignore quoted text, escaped characters and [colour] or [$-locale]
+	// sections before looking for day or year placeholders.
+	var plain strings.Builder
+	inQuote, inBracket, escaped := false, false, false
+	for _, c := range code {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '[':
+			inBracket = true
+		case c == ']':
+			inBracket = false
+		case inBracket:
+		default:
+			plain.WriteRune(c)
+		}
+	}
+	lower := strings.ToLower(plain.String())
+	return strings.ContainsAny(lower, "dy")
+}
+
+// xlsReader reads values that may be split over a record and its CONTINUE
+// records.
+type xlsReader struct {
+	segments [][]byte
+	segment  int
+	pos      int
+}
+
+func (r *xlsReader) next() bool {
+	for r.segment < len(r.segments) && r.pos >= len(r.segments[r.segment]) {
+		r.segment++
+		r.pos = 0
+	}
+	return r.segment < len(r.segments)
+}
+
+func (r *xlsReader) read(n int) ([]byte, error) {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		if !r.next() {
+			return nil, errInvalidXLS
+		}
+		segment := r.segments[r.segment]
+		take := min(n-len(out), len(segment)-r.pos)
+		out = append(out, segment[r.pos:r.pos+take]...)
+		r.pos += take
+	}
+	return out, nil
+}
+
+func (r *xlsReader) readUint32() (uint32, error) {
+	b, err := r.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// readString reads an XLUnicodeRichExtendedString whose character count takes
+// countSize bytes. Characters are one byte each unless the high byte flag is
+// set, and a string split over records restates that flag at the start of the
+// next record.
+func (r *xlsReader) readString(countSize int) (string, error) {
+	b, err := r.read(countSize + 1)
+	if err != nil {
+		return "", err
+	}
+	count := int(b[0])
+	if countSize == 2 {
+		count = int(binary.LittleEndian.Uint16(b))
+	}
+	flags := b[countSize]
+
+	var runs, extSize int
+	if flags&0x08 != 0 {
+		b, err := r.read(2)
+		if err != nil {
+			return "", err
+		}
+		runs = int(binary.LittleEndian.Uint16(b))
+	}
+	if flags&0x04 != 0 {
+		size, err := r.readUint32()
+		if err != nil {
+			return "", err
+		}
+		extSize = int(size)
+	}
+
+	chars := make([]uint16, 0, count)
+	highByte := flags&0x01 != 0
+	for len(chars) < count {
+		if r.segment < len(r.segments) && r.pos >= len(r.segments[r.segment]) {
+			if !r.next() {
+				return "", errInvalidXLS
+			}
+			highByte = r.segments[r.segment][0]&0x01 != 0
+			r.pos++
+		}
+		if r.segment >= len(r.segments) {
+			return "", errInvalidXLS
+		}
+		segment := r.segments[r.segment]
+		if highByte {
+			if r.pos+2 > len(segment) {
+				return "", errInvalidXLS
+			}
+			chars = append(chars, binary.LittleEndian.Uint16(segment[r.pos:]))
+			r.pos += 2
+		} else {
+			chars = append(chars, uint16(segment[r.pos]))
+			r.pos++
+		}
+	}
+
+	if _, err := r.read(4*runs + extSize); err != nil {
+		return "", err
+	}
+	return string(utf16.Decode(chars)), nil
+}
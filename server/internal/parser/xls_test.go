@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+
+	"expenses/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("XLS reader", func() {
+	It("should read text, numbers and dates from a BIFF8 workbook", func() {
+		fileBytes := utils.CreateXLSFile([][]string{
+			{"Date", "Payee", "Amount"},
+			{"2024-01-15", "Café ₹", "150.75"},
+			{},
+			{"2024-02-29", "", "-42"},
+		})
+		Expect(isCompoundFile(fileBytes)).To(BeTrue())
+
+		sheets, err := readXLSSheets(fileBytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sheets).To(HaveLen(1))
+		Expect(sheets[0].Name).To(Equal("Sheet1"))
+		Expect(sheets[0].Rows).To(Equal([][]string{
+			{"Date", "Payee", "Amount"},
+			{"2024-01-15", "Café ₹", "150.75"},
+			{},
+			{"2024-02-29", "", "-42"},
+		}))
+	})
+
+	It("should read a shared string table spread over CONTINUE records", func() {
+		data := [][]string{{"Name"}}
+		for i := 0; i < 400; i++ {
+			data = append(data, []string{fmt.Sprintf("Transaction description number %03d", i)})
+		}
+		sheets, err := readXLSSheets(utils.CreateXLSFile(data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sheets[0].Rows).To(HaveLen(401))
+		Expect(sheets[0].Rows[400]).To(Equal([]string{"Transaction description number 399"}))
+	})
+
+	It("should read a string whose characters continue in the next record", func() {
+		// "Hello" stored as one byte characters, split after "He"; the next
+		// record restates the flags and switches to two byte characters.
+		first := []byte{5, 0, 0, 'H', 'e'}
+		second := []byte{1}
+		for _, c := range utf16.Encode([]rune("llo")) {
+			second = binary.LittleEndian.AppendUint16(second, c)
+		}
+		r := &xlsReader{segments: [][]byte{first, second}}
+		s, err := r.readString(2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(Equal("Hello"))
+	})
+
+	It("should decode RK numbers", func() {
+		Expect(decodeRK(uint32(1234)<<2 | 0x02)).To(Equal(1234.0))
+		Expect(decodeRK(uint32(15075)<<2 | 0x03)).To(Equal(150.75))
+	})
+
+	It("should treat custom formats with day or year placeholders as dates", func() {
+		wb := &xlsWorkbook{customFormats: map[uint16]string{
+			164: "dd/mm/yyyy",
+			165: `#,##0.00 "days"`,
+			166: "[$-409]d-mmm-yy;@",
+		}}
+		Expect(wb.isDateFormat(14)).To(BeTrue())
+		Expect(wb.isDateFormat(4)).To(BeFalse())
+		Expect(wb.isDateFormat(164)).To(BeTrue())
+		Expect(wb.isDateFormat(165)).To(BeFalse())
+		Expect(wb.isDateFormat(166)).To(BeTrue())
+	})
+
+	It("should reject a file that is not a workbook", func() {
+		_, err := readXLSSheets([]byte("not a compound file"))
+		Expect(err).To(HaveOccurred())
+	})
+})
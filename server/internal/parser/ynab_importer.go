@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"expenses/internal/models"
+	"fmt"
+	"strings"
+)
+
+// YNABImporter reads the register CSV exported by YNAB, both the current app
+// (Category Group/Category, Category Group, Category) and YNAB 4 (Master
+// Category, Sub Category). Dates are read month first, as YNAB writes them by
+// default.
+type YNABImporter struct{}
+
+var ynabLayout = tableLayout{
+	headerKeywords: []string{"account", "date", "payee", "outflow", "inflow"},
+	columns: [][]string{
+		{"date"},
+		{"account"},
+		{"payee"},
+		{"memo"},
+		{"outflow"},
+		{"inflow"},
+		// The combined column is matched first so that the plain category
+		// columns are left for the group and the category.
+		{"category group/category"},
+		{"category group", "master category"},
+		{"sub category", "category"},
+	},
+}
+
+func init() {
+	RegisterAppImporter(models.AppImportSourceYNAB, &YNABImporter{})
+}
+
+func (i *YNABImporter) Import(fileBytes []byte, fileName string) ([]models.AppImportTransaction, []models.StatementRowError, error) {
+	rows, err := readExportRows(fileBytes, fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseTableRows(rows, ynabLayout, i.parseRow)
+}
+
+func (i *YNABImporter) parseRow(fields []string) (*models.AppImportTransaction, error) {
+	// Columns as aligned by ynabLayout:
+	// 0: Date, 1: Account, 2: Payee, 3: Memo, 4: Outflow, 5: Inflow,
+	// 6: Category Group/Category, 7: Category Group, 8: Category
+	date, err := parseDateWithLayouts(fields[0], "01/02/2006", "2006-01-02")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse date '%s': %w", fields[0], err)
+	}
+	outflow, _, err := parseOptionalAmount(stripCurrency(fields[4]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse outflow '%s': %w", fields[4], err)
+	}
+	inflow, _, err := parseOptionalAmount(stripCurrency(fields[5]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse inflow '%s': %w", fields[5], err)
+	}
+
+	payee := fields[2]
+	memo := fields[3]
+	name := payee
+	if name == "" {
+		name = memo
+	}
+	if name == "" {
+		name = "YNAB transaction"
+	}
+	account := fields[1]
+	if account == "" {
+		account = "YNAB"
+	}
+	return newAppImportTransaction(account, date, name, memo, outflow-inflow, i.category(fields[7], fields[8])), nil
+}
+
+// category returns the category of a row, leaving out the income YNAB assigns
+// to the budget rather than to a category.
+func (i *YNABImporter) category(group string, category string) string {
+	lowerCategory := strings.ToLower(category)
+	if strings.EqualFold(group, "Inflow") || strings.Contains(lowerCategory, "ready to assign") || strings.Contains(lowerCategory, "to be budgeted") {
+		return ""
+	}
+	return category
+}
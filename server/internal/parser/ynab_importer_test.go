@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("YNABImporter", func() {
+	var importer *YNABImporter
+
+	BeforeEach(func() {
+		importer = &YNABImporter{}
+	})
+
+	It("imports the register of the current app", func() {
+		input := "\ufeff\"Account\",\"Flag\",\"Date\",\"Payee\",\"Category Group/Category\",\"Category Group\",\"Category\",\"Memo\",\"Outflow\",\"Inflow\",\"Cleared\"\n" +
+			"\"Checking\",\"\",\"03/15/2024\",\"Whole Foods\",\"Everyday: Groceries\",\"Everyday\",\"Groceries\",\"weekly shop\",$84.20,$0.00,\"Cleared\"\n" +
+			"\"Checking\",\"\",\"03/01/2024\",\"Acme Corp\",\"Inflow: Ready to Assign\",\"Inflow\",\"Ready to Assign\",\"\",$0.00,\"$2,500.00\",\"Cleared\"\n" +
+			"\"Credit Card\",\"Red\",\"03/20/2024\",\"Shell\",\"Auto: Fuel\",\"Auto\",\"Fuel\",\"\",$40.00,$0.00,\"Uncleared\"\n"
+		txns, rowErrors, err := importer.Import([]byte(input), "register.csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rowErrors).To(BeEmpty())
+		Expect(txns).To(HaveLen(3))
+
+		Expect(txns[0].AccountName).To(Equal("Checking"))
+		Expect(txns[0].Name).To(Equal("Whole Foods"))
+		Expect(txns[0].Description).To(Equal("weekly shop"))
+		Expect(txns[0].Date).To(Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)))
+		Expect(*txns[0].Amount).To(Equal(84.20))
+		Expect(txns[0].CategoryNames).To(Equal([]string{"Groceries"}))
+
+		Expect(*txns[1].Amount).To(Equal(-2500.00))
+		Expect(txns[1].CategoryNames).To(BeEmpty())
+
+		Expect(txns[2].AccountName).To(Equal("Credit Card"))
+		Expect(txns[2].CategoryNames).To(Equal([]string{"Fuel"}))
+	})
+
+	It("imports the register of YNAB 4", func() {
+		input := "Account,Flag,Check Number,Date,Payee,Category,Master Category,Sub Category,Memo,Outflow,Inflow,Cleared,Running Balance\n" +
+			"Savings,,,01/05/2019,Landlord,Housing: Rent,Housing,Rent,January,$900.00,$0.00,R,$100.00\n"
+		txns, rowErrors, err := importer.Import([]byte(input), "register.csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rowErrors).To(BeEmpty())
+		Expect(txns).To(HaveLen(1))
+		Expect(txns[0].AccountName).To(Equal("Savings"))
+		Expect(txns[0].Date).To(Equal(time.Date(2019, 1, 5, 0, 0, 0, 0, time.UTC)))
+		Expect(*txns[0].Amount).To(Equal(900.00))
+		Expect(txns[0].CategoryNames).To(Equal([]string{"Rent"}))
+	})
+})
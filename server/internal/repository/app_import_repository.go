@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"expenses/internal/config"
+	"expenses/internal/database/helper"
+	customErrors "expenses/internal/errors"
+	"expenses/internal/models"
+	database "expenses/pkg/database/manager"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type AppImportRepositoryInterface interface {
+	CreateAppImport(ctx context.Context, input models.CreateAppImportInput) (models.AppImportResponse, error)
+	GetAppImportById(ctx context.Context, importId int64, userId int64) (models.AppImportResponse, error)
+	ListAppImports(ctx context.Context, userId int64) ([]models.AppImportResponse, error)
+	UpdateAppImport(ctx context.Context, importId int64, input models.UpdateAppImportInput) (models.AppImportResponse, error)
+}
+
+type AppImportRepository struct {
+	db        database.DatabaseManager
+	schema    string
+	tableName string
+}
+
+func NewAppImportRepository(db database.DatabaseManager, cfg *config.Config) AppImportRepositoryInterface {
+	return &AppImportRepository{
+		db:        db,
+		schema:    cfg.DBSchema,
+		tableName: "app_import",
+	}
+}
+
+func (r *AppImportRepository) CreateAppImport(ctx context.Context, input models.CreateAppImportInput) (models.AppImportResponse, error) {
+	var appImport models.AppImportResponse
+	query, values, ptrs, err := helper.CreateInsertQuery(&input, &appImport, r.tableName, r.schema)
+	if err != nil {
+		return appImport, err
+	}
+
+	err = r.db.FetchOne(ctx, query, values...).Scan(ptrs...)
+	if err != nil {
+		return appImport, err
+	}
+	return appImport, nil
+}
+
+func (r *AppImportRepository) GetAppImportById(ctx context.Context, importId int64, userId int64) (models.AppImportResponse, error) {
+	var appImport models.AppImportResponse
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&appImport)
+	if err != nil {
+		return appImport, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE id = $1 AND created_by = $2`, strings.Join(dbFields, ", "), r.schema, r.tableName)
+	err = r.db.FetchOne(ctx, query, importId, userId).Scan(ptrs...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appImport, customErrors.NewAppImportNotFoundError(err)
+		}
+		return appImport, err
+	}
+	return appImport, nil
+}
+
+func (r *AppImportRepository) ListAppImports(ctx context.Context, userId int64) ([]models.AppImportResponse, error) {
+	appImports := make([]models.AppImportResponse, 0)
+	var appImport models.AppImportResponse
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&appImport)
+	if err != nil {
+		return appImports, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE created_by = $1 ORDER BY created_at DESC, id DESC;`, strings.Join(dbFields, ", "), r.schema, r.tableName)
+	rows, err := r.db.FetchAll(ctx, query, userId)
+	if err != nil {
+		return appImports, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return appImports, err
+		}
+		appImports = append(appImports, appImport)
+	}
+	return appImports, nil
+}
+
+func (r *AppImportRepository) UpdateAppImport(ctx context.Context, importId int64, input models.UpdateAppImportInput) (models.AppImportResponse, error) {
+	var appImport models.AppImportResponse
+	fieldsClause, argValues, argIndex, err := helper.CreateUpdateParams(&input)
+	if err != nil {
+		return appImport, err
+	}
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&appImport)
+	if err != nil {
+		return appImport, err
+	}
+
+	query := fmt.Sprintf(`UPDATE %s.%s SET %s WHERE id = $%d RETURNING %s;`, r.schema, r.tableName, fieldsClause, argIndex, strings.Join(dbFields, ", "))
+	argValues = append(argValues, importId)
+	err = r.db.FetchOne(ctx, query, argValues...).Scan(ptrs...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appImport, customErrors.NewAppImportNotFoundError(err)
+		}
+		return appImport, err
+	}
+	return appImport, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"expenses/internal/config"
+	"expenses/internal/database/helper"
+	jobErrors "expenses/internal/errors"
+	"expenses/internal/models"
+	database "expenses/pkg/database/manager"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type JobRepositoryInterface interface {
+	CreateJob(ctx context.Context, input models.CreateJobInput) (models.Job, error)
+	ClaimJob(ctx context.Context, jobTypes []models.JobType, visibilityTimeout time.Duration) (*models.Job, error)
+	ExtendJobLock(ctx context.Context, jobId int64, visibilityTimeout time.Duration) error
+	CompleteJob(ctx context.Context, jobId int64) error
+	RetryJob(ctx context.Context, jobId int64, runAt time.Time, lastError string) error
+	FailJob(ctx context.Context, jobId int64, lastError string) error
+}
+
+type JobRepository struct {
+	db        database.DatabaseManager
+	schema    string
+	tableName string
+}
+
+func NewJobRepository(db database.DatabaseManager, cfg *config.Config) JobRepositoryInterface {
+	return &JobRepository{
+		db:        db,
+		schema:    cfg.DBSchema,
+		tableName: "job",
+	}
+}
+
+func (r *JobRepository) CreateJob(ctx context.Context, input models.CreateJobInput) (models.Job, error) {
+	var job models.Job
+	query, values, ptrs, err := helper.CreateInsertQuery(&input, &job, r.tableName, r.schema)
+	if err != nil {
+		return job, jobErrors.NewJobCreateError(err)
+	}
+	if err := r.db.FetchOne(ctx, query, values...).Scan(ptrs...); err != nil {
+		return job, jobErrors.NewJobCreateError(err)
+	}
+	return job, nil
+}
+
+// ClaimJob locks the oldest job of one of the given types that is due, or
+// whose previous worker let its lock expire, and counts the attempt. Jobs
+// locked by other workers are skipped. It returns nil when nothing is ready.
+func (r *JobRepository) ClaimJob(ctx context.Context, jobTypes []models.JobType, visibilityTimeout time.Duration) (*models.Job, error) {
+	var job models.Job
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&job)
+	if err != nil {
+		return nil, jobErrors.NewJobGetError(err)
+	}
+
+	types := make([]string, len(jobTypes))
+	for i, jobType := range jobTypes {
+		types[i] = string(jobType)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %[1]s.%[2]s
+		SET status = 'running', attempts = attempts + 1,
+			locked_until = NOW() + $2 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM %[1]s.%[2]s
+			WHERE job_type = ANY($1)
+			AND ((status = 'pending' AND run_at <= NOW()) OR (status = 'running' AND locked_until < NOW()))
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %[3]s;`,
+		r.schema, r.tableName, strings.Join(dbFields, ", "))
+	err = r.db.FetchOne(ctx, query, types, visibilityTimeout.Milliseconds()).Scan(ptrs...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, jobErrors.NewJobGetError(err)
+	}
+	return &job, nil
+}
+
+// ExtendJobLock keeps a long running job from being claimed by another worker.
+func (r *JobRepository) ExtendJobLock(ctx context.Context, jobId int64, visibilityTimeout time.Duration) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.%s SET locked_until = NOW() + $2 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id = $1 AND status = 'running'`, r.schema, r.tableName)
+	if _, err := r.db.ExecuteQuery(ctx, query, jobId, visibilityTimeout.Milliseconds()); err != nil {
+		return jobErrors.NewJobUpdateError(err)
+	}
+	return nil
+}
+
+// CompleteJob removes a finished job. Nothing reads a job once its work is
+// done, and deleting it keeps the table the workers poll small.
+func (r *JobRepository) CompleteJob(ctx context.Context, jobId int64) error {
+	query := fmt.Sprintf(`DELETE FROM %s.%s WHERE id = $1`, r.schema, r.tableName)
+	if _, err := r.db.ExecuteQuery(ctx, query, jobId); err != nil {
+		return jobErrors.NewJobUpdateError(err)
+	}
+	return nil
+}
+
+func (r *JobRepository) RetryJob(ctx context.Context, jobId int64, runAt time.Time, lastError string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.%s SET status = 'pending', run_at = $2, locked_until = NULL, last_error = $3, updated_at = NOW()
+		WHERE id = $1`, r.schema, r.tableName)
+	if _, err := r.db.ExecuteQuery(ctx, query, jobId, runAt, lastError); err != nil {
+		return jobErrors.NewJobUpdateError(err)
+	}
+	return nil
+}
+
+// FailJob gives up on a job. Failed jobs are kept for inspection.
+func (r *JobRepository) FailJob(ctx context.Context, jobId int64, lastError string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.%s SET status = 'failed', locked_until = NULL, last_error = $2, updated_at = NOW()
+		WHERE id = $1`, r.schema, r.tableName)
+	if _, err := r.db.ExecuteQuery(ctx, query, jobId, lastError); err != nil {
+		return jobErrors.NewJobUpdateError(err)
+	}
+	return nil
+}
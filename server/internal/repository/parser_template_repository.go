@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"expenses/internal/config"
+	"expenses/internal/database/helper"
+	customErrors "expenses/internal/errors"
+	"expenses/internal/models"
+	database "expenses/pkg/database/manager"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ParserTemplateRepositoryInterface interface {
+	CreateParserTemplate(ctx context.Context, input models.CreateParserTemplateInput) (models.ParserTemplateResponse, error)
+	GetParserTemplateById(ctx context.Context, templateId int64, userId int64) (models.ParserTemplateResponse, error)
+	GetParserTemplateByAccountId(ctx context.Context, accountId int64, userId int64) (*models.ParserTemplateResponse, error)
+	ListParserTemplates(ctx context.Context, userId int64) ([]models.ParserTemplateResponse, error)
+	UpdateParserTemplate(ctx context.Context, templateId int64, userId int64, input models.UpdateParserTemplateInput) (models.ParserTemplateResponse, error)
+	DeleteParserTemplate(ctx context.Context, templateId int64, userId int64) error
+}
+
+type ParserTemplateRepository struct {
+	db        database.DatabaseManager
+	schema    string
+	tableName string
+}
+
+func NewParserTemplateRepository(db database.DatabaseManager, cfg *config.Config) ParserTemplateRepositoryInterface {
+	return &ParserTemplateRepository{
+		db:        db,
+		schema:    cfg.DBSchema,
+		tableName: "parser_template",
+	}
+}
+
+func (r *ParserTemplateRepository) CreateParserTemplate(ctx context.Context, input models.CreateParserTemplateInput) (models.ParserTemplateResponse, error) {
+	var template models.ParserTemplateResponse
+	query, values, ptrs, err := helper.CreateInsertQuery(&input, &template, r.tableName, r.schema)
+	if err != nil {
+		return template, err
+	}
+
+	err = r.db.FetchOne(ctx, query, values...).Scan(ptrs...)
+	if err != nil {
+		return template, r.mapError(err)
+	}
+	return template, nil
+}
+
+func (r *ParserTemplateRepository) GetParserTemplateById(ctx context.Context, templateId int64, userId int64) (models.ParserTemplateResponse, error) {
+	var template models.ParserTemplateResponse
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&template)
+	if err != nil {
+		return template, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE id = $1 AND created_by = $2`, strings.Join(dbFields, ", "), r.schema, r.tableName)
+	err = r.db.FetchOne(ctx, query, templateId, userId).Scan(ptrs...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return template, customErrors.NewParserTemplateNotFoundError(err)
+		}
+		return template, err
+	}
+	return template, nil
+}
+
+// GetParserTemplateByAccountId returns the template bound to an account, or
+// nil when the account has none.
+func (r *ParserTemplateRepository) GetParserTemplateByAccountId(ctx context.Context, accountId int64, userId int64) (*models.ParserTemplateResponse, error) {
+	var template models.ParserTemplateResponse
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&template)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE account_id = $1 AND created_by = $2`, strings.Join(dbFields, ", "), r.schema, r.tableName)
+	err = r.db.FetchOne(ctx, query, accountId, userId).Scan(ptrs...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *ParserTemplateRepository) ListParserTemplates(ctx context.Context, userId int64) ([]models.ParserTemplateResponse, error) {
+	templates := make([]models.ParserTemplateResponse, 0)
+	var template models.ParserTemplateResponse
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&template)
+	if err != nil {
+		return templates, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE created_by = $1 ORDER BY name;`, strings.Join(dbFields, ", "), r.schema, r.tableName)
+	rows, err := r.db.FetchAll(ctx, query, userId)
+	if err != nil {
+		return templates, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return templates, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+func (r *ParserTemplateRepository) UpdateParserTemplate(ctx context.Context, templateId int64, userId int64, input models.UpdateParserTemplateInput) (models.ParserTemplateResponse, error) {
+	var template models.ParserTemplateResponse
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&template)
+	if err != nil {
+		return template, err
+	}
+
+	// account_id 0 clears the binding, which the update helper cannot express
+	// since it skips nil values.
+	query := fmt.Sprintf(`
+		UPDATE %s.%s SET
+			name = COALESCE($3, name),
+			metadata = COALESCE($4, metadata),
+			account_id = CASE WHEN $5::INTEGER IS NULL THEN account_id ELSE NULLIF($5::INTEGER, 0) END
+		WHERE id = $1 AND created_by = $2
+		RETURNING %s;`,
+		r.schema, r.tableName, strings.Join(dbFields, ", "))
+	err = r.db.FetchOne(ctx, query, templateId, userId, input.Name, input.Metadata, input.AccountId).Scan(ptrs...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return template, customErrors.NewParserTemplateNotFoundError(err)
+		}
+		return template, r.mapError(err)
+	}
+	return template, nil
+}
+
+func (r *ParserTemplateRepository) DeleteParserTemplate(ctx context.Context, templateId int64, userId int64) error {
+	query := fmt.Sprintf(`DELETE FROM %s.%s WHERE id = $1 AND created_by = $2;`, r.schema, r.tableName)
+	rowsAffected, err := r.db.ExecuteQuery(ctx, query, templateId, userId)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return customErrors.NewParserTemplateNotFoundError(fmt.Errorf("parser template with id %d not found", templateId))
+	}
+	return nil
+}
+
+func (r *ParserTemplateRepository) mapError(err error) error {
+	if customErrors.CheckForeignKey(err, "unique_parser_template_name_created_by") {
+		return customErrors.NewParserTemplateAlreadyExistsError(err)
+	}
+	if customErrors.CheckForeignKey(err, "unique_parser_template_account_id") {
+		return customErrors.NewParserTemplateAccountTakenError(err)
+	}
+	if customErrors.CheckForeignKey(err, "parser_template_account_id_fkey") {
+		return customErrors.NewAccountNotFoundError(err)
+	}
+	return err
+}
@@ -18,16 +18,20 @@ type RuleRepositoryInterface interface {
 	CreateRule(ctx context.Context, rule models.CreateBaseRuleRequest) (models.RuleResponse, error)
 	CreateRuleActions(ctx context.Context, actions []models.CreateRuleActionRequest) ([]models.RuleActionResponse, error)
 	CreateRuleConditions(ctx context.Context, conditions []models.CreateRuleConditionRequest) ([]models.RuleConditionResponse, error)
+	CreateRuleConditionGroups(ctx context.Context, ruleId int64, groups []models.CreateRuleConditionGroupRequest) ([]models.RuleConditionGroupResponse, error)
 	CreateRuleTransactionMapping(ctx context.Context, ruleId int64, transactionId int64) error
 	GetRule(ctx context.Context, id int64, userId int64) (models.RuleResponse, error)
 	ListRules(ctx context.Context, userId int64, query models.RuleListQuery) (models.PaginatedRulesResponse, error)
+	GetNextRulePriority(ctx context.Context, userId int64) (int, error)
+	ReorderRules(ctx context.Context, userId int64, ruleIds []int64) error
 	ListRuleActionsByRuleId(ctx context.Context, ruleId int64) ([]models.RuleActionResponse, error)
 	ListRuleConditionsByRuleId(ctx context.Context, ruleId int64) ([]models.RuleConditionResponse, error)
+	ListRuleConditionGroupsByRuleId(ctx context.Context, ruleId int64) ([]models.RuleConditionGroupResponse, error)
 	UpdateRule(ctx context.Context, id int64, userId int64, rule models.UpdateRuleRequest) (models.RuleResponse, error)
 	UpdateRuleAction(ctx context.Context, id int64, ruleId int64, action models.UpdateRuleActionRequest) (models.RuleActionResponse, error)
 	UpdateRuleCondition(ctx context.Context, id int64, ruleId int64, condition models.UpdateRuleConditionRequest) (models.RuleConditionResponse, error)
 	PutRuleActions(ctx context.Context, ruleId int64, actions []models.CreateRuleActionRequest) ([]models.RuleActionResponse, error)
-	PutRuleConditions(ctx context.Context, ruleId int64, conditions []models.CreateRuleConditionRequest) ([]models.RuleConditionResponse, error)
+	PutRuleConditions(ctx context.Context, ruleId int64, conditions []models.CreateRuleConditionRequest, groups []models.CreateRuleConditionGroupRequest) ([]models.RuleConditionResponse, []models.RuleConditionGroupResponse, error)
 	DeleteRuleActionsByRuleId(ctx context.Context, ruleId int64) error
 	DeleteRuleConditionsByRuleId(ctx context.Context, ruleId int64) error
 	DeleteRule(ctx context.Context, id int64, userId int64) error
@@ -39,6 +43,7 @@ type RuleRepository struct {
 	ruleTable                   string
 	ruleActionTable             string
 	ruleConditionTable          string
+	ruleConditionGroupTable     string
 	ruleTransactionMappingTable string
 }
 
@@ -49,6 +54,7 @@ func NewRuleRepository(db database.DatabaseManager, cfg *config.Config) RuleRepo
 		ruleTable:                   "rule",
 		ruleActionTable:             "rule_action",
 		ruleConditionTable:          "rule_condition",
+		ruleConditionGroupTable:     "rule_condition_group",
 		ruleTransactionMappingTable: "rule_transaction_mapping",
 	}
 }
@@ -118,6 +124,52 @@ func (r *RuleRepository) CreateRuleConditions(ctx context.Context, conditions []
 	return ruleConditions, nil
 }
 
+// createRuleConditionGroup inserts a condition group with its conditions and
+// nested groups. The children of a group are returned nested in the group.
+func (r *RuleRepository) createRuleConditionGroup(ctx context.Context, ruleId int64, parentId *int64, req models.CreateRuleConditionGroupRequest) (models.RuleConditionGroupResponse, error) {
+	var group models.RuleConditionGroupResponse
+	query := fmt.Sprintf(`
+		INSERT INTO %s.%s (rule_id, parent_id, logic) VALUES ($1, $2, $3)
+		RETURNING id, rule_id, parent_id, logic;`, r.schema, r.ruleConditionGroupTable)
+	err := r.db.FetchOne(ctx, query, ruleId, parentId, req.Logic).Scan(&group.Id, &group.RuleId, &group.ParentId, &group.Logic)
+	if err != nil {
+		return group, errorsPkg.NewRuleRepositoryError("failed to create rule condition group", err)
+	}
+
+	group.Conditions = make([]models.RuleConditionResponse, 0, len(req.Conditions))
+	for _, condition := range req.Conditions {
+		condition.RuleId = ruleId
+		condition.GroupId = &group.Id
+		ruleCondition, err := r.createRuleCondition(ctx, &condition)
+		if err != nil {
+			return group, err
+		}
+		group.Conditions = append(group.Conditions, ruleCondition)
+	}
+
+	group.Groups = make([]models.RuleConditionGroupResponse, 0, len(req.Groups))
+	for _, child := range req.Groups {
+		childGroup, err := r.createRuleConditionGroup(ctx, ruleId, &group.Id, child)
+		if err != nil {
+			return group, err
+		}
+		group.Groups = append(group.Groups, childGroup)
+	}
+	return group, nil
+}
+
+func (r *RuleRepository) CreateRuleConditionGroups(ctx context.Context, ruleId int64, groups []models.CreateRuleConditionGroupRequest) ([]models.RuleConditionGroupResponse, error) {
+	ruleGroups := make([]models.RuleConditionGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		ruleGroup, err := r.createRuleConditionGroup(ctx, ruleId, nil, group)
+		if err != nil {
+			return ruleGroups, err
+		}
+		ruleGroups = append(ruleGroups, ruleGroup)
+	}
+	return ruleGroups, nil
+}
+
 func (r *RuleRepository) GetRule(ctx context.Context, id int64, userId int64) (models.RuleResponse, error) {
 	var rule models.RuleResponse
 	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&rule)
@@ -178,7 +230,7 @@ func (r *RuleRepository) ListRules(ctx context.Context, userId int64, query mode
 			SELECT %s 
 			FROM %s.%s 
 			%s 
-			ORDER BY id DESC 
+			ORDER BY priority, id 
 			LIMIT $%d OFFSET $%d`,
 			strings.Join(dbFields, ", "), r.schema, r.ruleTable, whereClause, argIndex, argIndex+1)
 
@@ -189,7 +241,7 @@ func (r *RuleRepository) ListRules(ctx context.Context, userId int64, query mode
 			SELECT %s 
 			FROM %s.%s 
 			%s 
-			ORDER BY id DESC`,
+			ORDER BY priority, id`,
 			strings.Join(dbFields, ", "), r.schema, r.ruleTable, whereClause)
 	}
 
@@ -210,6 +262,41 @@ func (r *RuleRepository) ListRules(ctx context.Context, userId int64, query mode
 	return response, nil
 }
 
+// GetNextRulePriority returns the priority that makes a new rule run after
+// all the existing rules of the user. Callers hold the user's rule priority
+// lock until the rule is created, so concurrent rules get distinct values.
+func (r *RuleRepository) GetNextRulePriority(ctx context.Context, userId int64) (int, error) {
+	var priority int
+	query := fmt.Sprintf(`SELECT COALESCE(MAX(priority), 0) + 1 FROM %s.%s WHERE created_by = $1`, r.schema, r.ruleTable)
+	err := r.db.FetchOne(ctx, query, userId).Scan(&priority)
+	if err != nil {
+		return 0, errorsPkg.NewRuleRepositoryError("failed to get next rule priority", err)
+	}
+	return priority, nil
+}
+
+// ReorderRules renumbers the priorities of the rules of a user from 1, with
+// ruleIds first in the given order and the other rules after them in their
+// current order.
+func (r *RuleRepository) ReorderRules(ctx context.Context, userId int64, ruleIds []int64) error {
+	query := fmt.Sprintf(`
+		UPDATE %[1]s.%[2]s r
+		SET priority = ordered.position
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (
+				ORDER BY COALESCE(array_position($2::bigint[], id::bigint), 2147483647), priority, id
+			) AS position
+			FROM %[1]s.%[2]s
+			WHERE created_by = $1
+		) ordered
+		WHERE r.id = ordered.id AND r.priority <> ordered.position`, r.schema, r.ruleTable)
+	_, err := r.db.ExecuteQuery(ctx, query, userId, ruleIds)
+	if err != nil {
+		return errorsPkg.NewRuleRepositoryError("failed to reorder rules", err)
+	}
+	return nil
+}
+
 func (r *RuleRepository) ListRuleActionsByRuleId(ctx context.Context, ruleId int64) ([]models.RuleActionResponse, error) {
 	var actions []models.RuleActionResponse
 	var action models.RuleActionResponse
@@ -256,6 +343,27 @@ func (r *RuleRepository) ListRuleConditionsByRuleId(ctx context.Context, ruleId
 	return conditions, nil
 }
 
+// ListRuleConditionGroupsByRuleId lists the condition groups of a rule without
+// their children, parents before their nested groups.
+func (r *RuleRepository) ListRuleConditionGroupsByRuleId(ctx context.Context, ruleId int64) ([]models.RuleConditionGroupResponse, error) {
+	groups := make([]models.RuleConditionGroupResponse, 0)
+	query := fmt.Sprintf(`SELECT id, rule_id, parent_id, logic FROM %s.%s WHERE rule_id = $1 ORDER BY id`, r.schema, r.ruleConditionGroupTable)
+	rows, err := r.db.FetchAll(ctx, query, ruleId)
+	if err != nil {
+		return groups, errorsPkg.NewRuleRepositoryError("failed to list rule condition groups", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var group models.RuleConditionGroupResponse
+		err := rows.Scan(&group.Id, &group.RuleId, &group.ParentId, &group.Logic)
+		if err != nil {
+			return groups, errorsPkg.NewRuleRepositoryError("failed to scan rule condition group row", err)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
 func (r *RuleRepository) UpdateRule(ctx context.Context, id int64, userId int64, rule models.UpdateRuleRequest) (models.RuleResponse, error) {
 	var ruleResponse models.RuleResponse
 	fieldsClause, argValues, argIndex, err := helper.CreateUpdateParams(&rule)
@@ -389,30 +497,39 @@ func (r *RuleRepository) PutRuleActions(ctx context.Context, ruleId int64, actio
 	return result, nil
 }
 
-func (r *RuleRepository) PutRuleConditions(ctx context.Context, ruleId int64, conditions []models.CreateRuleConditionRequest) ([]models.RuleConditionResponse, error) {
+func (r *RuleRepository) PutRuleConditions(ctx context.Context, ruleId int64, conditions []models.CreateRuleConditionRequest, groups []models.CreateRuleConditionGroupRequest) ([]models.RuleConditionResponse, []models.RuleConditionGroupResponse, error) {
 	var result []models.RuleConditionResponse
+	var groupResult []models.RuleConditionGroupResponse
 	err := r.db.WithLock(ctx, ruleId, func(ctx context.Context) error {
 		deleteQuery := fmt.Sprintf(`DELETE FROM %s.%s WHERE rule_id = $1`, r.schema, r.ruleConditionTable)
 		_, err := r.db.ExecuteQuery(ctx, deleteQuery, ruleId)
 		if err != nil {
 			return errorsPkg.NewRuleRepositoryError("failed to delete existing rule conditions", err)
 		}
+		deleteGroupsQuery := fmt.Sprintf(`DELETE FROM %s.%s WHERE rule_id = $1`, r.schema, r.ruleConditionGroupTable)
+		_, err = r.db.ExecuteQuery(ctx, deleteGroupsQuery, ruleId)
+		if err != nil {
+			return errorsPkg.NewRuleRepositoryError("failed to delete existing rule condition groups", err)
+		}
 
 		result = make([]models.RuleConditionResponse, 0, len(conditions))
 		for _, condition := range conditions {
 			condition.RuleId = ruleId
+			condition.GroupId = nil
 			ruleCondition, err := r.createRuleCondition(ctx, &condition)
 			if err != nil {
 				return err
 			}
 			result = append(result, ruleCondition)
 		}
-		return nil
+
+		groupResult, err = r.CreateRuleConditionGroups(ctx, ruleId, groups)
+		return err
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return result, nil
+	return result, groupResult, nil
 }
 
 func (r *RuleRepository) CreateRuleTransactionMapping(ctx context.Context, ruleId int64, transactionId int64) error {
@@ -11,6 +11,7 @@ import (
 	"expenses/pkg/logger"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -21,23 +22,37 @@ type StatementRepositoryInterface interface {
 	CreateStatementTxns(ctx context.Context, statementId int64, transactionIds []int64) error
 	UpdateStatementStatus(ctx context.Context, statementId int64, input models.UpdateStatementStatusInput) (models.StatementResponse, error)
 	GetStatementByID(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, error)
+	GetStatementByContentHash(ctx context.Context, userId int64, accountId int64, contentHash string) (*models.StatementResponse, error)
 	ListStatementByUserId(ctx context.Context, userId int64, limit, offset int, query models.StatementListQuery) ([]models.StatementResponse, error)
 	CountStatementsByUserId(ctx context.Context, userId int64, query models.StatementListQuery) (int, error)
+	ListStatementTransactionIds(ctx context.Context, statementId int64) ([]int64, error)
+	ListUnlinkedTransactions(ctx context.Context, accountId int64, userId int64, since time.Time) ([]models.TransactionBaseResponse, error)
+	GetPreviousStatement(ctx context.Context, statementId int64, accountId int64, userId int64, before time.Time) (*models.PreviousStatement, error)
+	CreateStatementRowErrors(ctx context.Context, statementId int64, rowErrors []models.StatementRowError) error
+	DeleteStatementRowErrors(ctx context.Context, statementId int64) error
+	ListStatementRowErrors(ctx context.Context, statementId int64) ([]models.StatementRowErrorResponse, error)
+	FailStaleStatements(ctx context.Context, message string) (int64, error)
+	ResetStatementImport(ctx context.Context, statementId int64) error
+	ListUpcomingCardStatements(ctx context.Context, userId int64, dueFrom time.Time) ([]models.StatementResponse, error)
 }
 
 type StatementRepository struct {
-	db               database.DatabaseManager
-	schema           string
-	tableName        string
-	mappingTableName string
+	db                   database.DatabaseManager
+	schema               string
+	tableName            string
+	mappingTableName     string
+	rowErrorTableName    string
+	transactionTableName string
 }
 
 func NewStatementRepository(db database.DatabaseManager, cfg *config.Config) StatementRepositoryInterface {
 	return &StatementRepository{
-		db:               db,
-		schema:           cfg.DBSchema,
-		tableName:        "statement",
-		mappingTableName: "statement_transaction_mapping",
+		db:                   db,
+		schema:               cfg.DBSchema,
+		tableName:            "statement",
+		mappingTableName:     "statement_transaction_mapping",
+		rowErrorTableName:    "statement_row_error",
+		transactionTableName: "transaction",
 	}
 }
 
@@ -52,6 +67,9 @@ func (r *StatementRepository) CreateStatement(ctx context.Context, input models.
 		if statementErrors.CheckForeignKey(err, "statement_account_id_fkey") {
 			return statement, statementErrors.NewAccountNotFoundError(errors.New("account not found"))
 		}
+		if statementErrors.CheckForeignKey(err, "idx_statement_unique_content_hash") {
+			return statement, statementErrors.NewStatementAlreadyUploadedError(err, nil)
+		}
 		return statement, statementErrors.NewStatementCreateError(err)
 	}
 	return statement, nil
@@ -105,6 +123,80 @@ func (r *StatementRepository) CreateStatementTxns(ctx context.Context, statement
 	return nil
 }
 
+func (r *StatementRepository) ListStatementTransactionIds(ctx context.Context, statementId int64) ([]int64, error) {
+	transactionIds := make([]int64, 0)
+	query := fmt.Sprintf(`SELECT transaction_id FROM %s.%s WHERE statement_id = $1 ORDER BY transaction_id`, r.schema, r.mappingTableName)
+	rows, err := r.db.FetchAll(ctx, query, statementId)
+	if err != nil {
+		return transactionIds, statementErrors.NewStatementGetError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var transactionId int64
+		if err := rows.Scan(&transactionId); err != nil {
+			return transactionIds, statementErrors.NewStatementGetError(err)
+		}
+		transactionIds = append(transactionIds, transactionId)
+	}
+	return transactionIds, nil
+}
+
+// ListUnlinkedTransactions returns the transactions the user added to the
+// account since the given time that are not linked to any statement. A retried
+// import uses it to find the transactions an earlier attempt inserted but
+// failed to link.
+func (r *StatementRepository) ListUnlinkedTransactions(ctx context.Context, accountId int64, userId int64, since time.Time) ([]models.TransactionBaseResponse, error) {
+	transactions := make([]models.TransactionBaseResponse, 0)
+	query := fmt.Sprintf(`
+		SELECT t.id, t.name, t.description, t.amount, t.date, t.created_by, t.account_id
+		FROM %[1]s.%[2]s t
+		WHERE t.account_id = $1 AND t.created_by = $2 AND t.created_at >= $3 AND t.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM %[1]s.%[3]s m WHERE m.transaction_id = t.id)
+		ORDER BY t.id`,
+		r.schema, r.transactionTableName, r.mappingTableName)
+	rows, err := r.db.FetchAll(ctx, query, accountId, userId, since)
+	if err != nil {
+		return transactions, statementErrors.NewStatementGetError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var txn models.TransactionBaseResponse
+		if err := rows.Scan(&txn.Id, &txn.Name, &txn.Description, &txn.Amount, &txn.Date, &txn.CreatedBy, &txn.AccountId); err != nil {
+			return transactions, statementErrors.NewStatementGetError(err)
+		}
+		transactions = append(transactions, txn)
+	}
+	return transactions, nil
+}
+
+// GetPreviousStatement returns the imported statement of the account whose
+// transactions end latest on or before the given date, among those that
+// report a closing balance. It returns nil when there is none.
+func (r *StatementRepository) GetPreviousStatement(ctx context.Context, statementId int64, accountId int64, userId int64, before time.Time) (*models.PreviousStatement, error) {
+	var previous models.PreviousStatement
+	query := fmt.Sprintf(`
+		SELECT s.id, s.closing_balance, MAX(t.date) AS last_date
+		FROM %[1]s.%[2]s s
+		JOIN %[1]s.%[3]s m ON m.statement_id = s.id
+		JOIN %[1]s.%[4]s t ON t.id = m.transaction_id AND t.deleted_at IS NULL
+		WHERE s.account_id = $1 AND s.created_by = $2 AND s.id <> $3 AND s.status = $4
+			AND s.closing_balance IS NOT NULL AND s.deleted_at IS NULL
+		GROUP BY s.id, s.closing_balance
+		HAVING MAX(t.date) <= $5
+		ORDER BY last_date DESC, s.id DESC
+		LIMIT 1`,
+		r.schema, r.tableName, r.mappingTableName, r.transactionTableName)
+	err := r.db.FetchOne(ctx, query, accountId, userId, statementId, models.StatementStatusDone, before).
+		Scan(&previous.StatementId, &previous.ClosingBalance, &previous.LastTransactionDate)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, statementErrors.NewStatementGetError(err)
+	}
+	return &previous, nil
+}
+
 func (r *StatementRepository) UpdateStatementStatus(ctx context.Context, statementId int64, input models.UpdateStatementStatusInput) (models.StatementResponse, error) {
 	logger.Debugf("Updating statement %d with status %s", statementId, input.Status)
 	fieldsClause, argValues, argIndex, err := helper.CreateUpdateParams(&input)
@@ -128,6 +220,45 @@ func (r *StatementRepository) UpdateStatementStatus(ctx context.Context, stateme
 	return statement, nil
 }
 
+// ResetStatementImport forgets the transactions and row errors of a previous
+// import of the statement, so it can be imported again.
+func (r *StatementRepository) ResetStatementImport(ctx context.Context, statementId int64) error {
+	return r.db.WithTxn(ctx, func(ctx context.Context) error {
+		query := fmt.Sprintf(`DELETE FROM %s.%s WHERE statement_id = $1`, r.schema, r.mappingTableName)
+		if _, err := r.db.ExecuteQuery(ctx, query, statementId); err != nil {
+			return statementErrors.NewStatementUpdateError(err)
+		}
+		query = fmt.Sprintf(`DELETE FROM %s.%s WHERE statement_id = $1`, r.schema, r.rowErrorTableName)
+		if _, err := r.db.ExecuteQuery(ctx, query, statementId); err != nil {
+			return statementErrors.NewStatementUpdateError(err)
+		}
+		return nil
+	})
+}
+
+// FailStaleStatements marks statements that are still pending or processing
+// but no longer have a queued or running parse job as failed. This happens when
+// the server stopped while the statement was being processed by an earlier
+// version, or when its job was given up on.
+func (r *StatementRepository) FailStaleStatements(ctx context.Context, message string) (int64, error) {
+	query := fmt.Sprintf(`
+		UPDATE %[1]s.%[2]s s
+		SET status = $1, message = $2, updated_at = NOW()
+		WHERE s.status IN ($3, $4) AND s.deleted_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM %[1]s.job j
+			WHERE j.job_type = $5 AND j.reference_id = s.id AND j.status IN ('pending', 'running')
+		)`, r.schema, r.tableName)
+	rowsAffected, err := r.db.ExecuteQuery(ctx, query,
+		models.StatementStatusError, message,
+		models.StatementStatusPending, models.StatementStatusProcessing,
+		models.JobTypeParseStatement)
+	if err != nil {
+		return 0, statementErrors.NewStatementUpdateError(err)
+	}
+	return rowsAffected, nil
+}
+
 func (r *StatementRepository) GetStatementByID(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, error) {
 	var statement models.StatementResponse
 	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&statement)
@@ -150,6 +281,69 @@ func (r *StatementRepository) GetStatementByID(ctx context.Context, statementId
 	return statement, nil
 }
 
+// GetStatementByContentHash returns the statement a file was first uploaded
+// with to the account, ignoring uploads that were forced as duplicates and
+// imports that failed or were reverted. It returns nil when the file was not
+// uploaded before.
+func (r *StatementRepository) GetStatementByContentHash(ctx context.Context, userId int64, accountId int64, contentHash string) (*models.StatementResponse, error) {
+	var statement models.StatementResponse
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&statement)
+	if err != nil {
+		return nil, statementErrors.NewStatementGetError(err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.%s
+		WHERE created_by = $1 AND account_id = $2 AND content_hash = $3
+		AND duplicate_of IS NULL AND deleted_at IS NULL AND status NOT IN ($4, $5)`,
+		strings.Join(dbFields, ", "), r.schema, r.tableName)
+	err = r.db.FetchOne(ctx, query, userId, accountId, contentHash, models.StatementStatusError, models.StatementStatusReverted).Scan(ptrs...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, statementErrors.NewStatementGetError(err)
+	}
+	return &statement, nil
+}
+
+// ListUpcomingCardStatements returns, for every account, the imported
+// statement with the latest payment due date on or after dueFrom. Statements
+// of accounts without billing details are not returned.
+func (r *StatementRepository) ListUpcomingCardStatements(ctx context.Context, userId int64, dueFrom time.Time) ([]models.StatementResponse, error) {
+	statements := make([]models.StatementResponse, 0)
+	var statement models.StatementResponse
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&statement)
+	if err != nil {
+		return statements, statementErrors.NewStatementGetError(err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %[1]s FROM (
+			SELECT DISTINCT ON (account_id) %[1]s
+			FROM %[2]s.%[3]s
+			WHERE created_by = $1 AND status = $2 AND deleted_at IS NULL
+			AND payment_due_date >= $3
+			ORDER BY account_id, payment_due_date DESC, id DESC
+		) latest
+		ORDER BY payment_due_date, account_id`,
+		strings.Join(dbFields, ", "), r.schema, r.tableName)
+	rows, err := r.db.FetchAll(ctx, query, userId, models.StatementStatusDone, dueFrom)
+	if err != nil {
+		return statements, statementErrors.NewStatementGetError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return statements, statementErrors.NewStatementGetError(err)
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
 func (r *StatementRepository) ListStatementByUserId(ctx context.Context, userId int64, limit, offset int, query models.StatementListQuery) ([]models.StatementResponse, error) {
 	statements := make([]models.StatementResponse, 0)
 	var statement models.StatementResponse
@@ -251,3 +445,76 @@ func (r *StatementRepository) CountStatementsByUserId(ctx context.Context, userI
 	}
 	return count, nil
 }
+
+func (r *StatementRepository) CreateStatementRowErrors(ctx context.Context, statementId int64, rowErrors []models.StatementRowError) error {
+	if len(rowErrors) == 0 {
+		return nil
+	}
+
+	const batchSize = 1000
+
+	for batchStart := 0; batchStart < len(rowErrors); batchStart += batchSize {
+		batchEnd := batchStart + batchSize
+		if batchEnd > len(rowErrors) {
+			batchEnd = len(rowErrors)
+		}
+
+		placeholders := make([]string, 0, batchEnd-batchStart)
+		args := make([]interface{}, 0, (batchEnd-batchStart)*4)
+		argIndex := 1
+
+		for _, rowError := range rowErrors[batchStart:batchEnd] {
+			placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d)", argIndex, argIndex+1, argIndex+2, argIndex+3))
+			args = append(args, statementId, rowError.LineNumber, rowError.RawRow, rowError.Reason)
+			argIndex += 4
+		}
+
+		query := fmt.Sprintf(`INSERT INTO %s.%s (statement_id, line_number, raw_row, reason) VALUES %s`,
+			r.schema, r.rowErrorTableName, strings.Join(placeholders, ", "))
+
+		_, err := r.db.ExecuteQuery(ctx, query, args...)
+		if err != nil {
+			return statementErrors.NewStatementCreateError(err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteStatementRowErrors removes the row errors recorded for the statement,
+// so a retried import does not report them twice.
+func (r *StatementRepository) DeleteStatementRowErrors(ctx context.Context, statementId int64) error {
+	query := fmt.Sprintf(`DELETE FROM %s.%s WHERE statement_id = $1`, r.schema, r.rowErrorTableName)
+	if _, err := r.db.ExecuteQuery(ctx, query, statementId); err != nil {
+		return statementErrors.NewStatementUpdateError(err)
+	}
+	return nil
+}
+
+func (r *StatementRepository) ListStatementRowErrors(ctx context.Context, statementId int64) ([]models.StatementRowErrorResponse, error) {
+	rowErrors := make([]models.StatementRowErrorResponse, 0)
+	var rowError models.StatementRowErrorResponse
+	ptrs, dbFields, err := helper.GetDbFieldsFromObject(&rowError)
+	if err != nil {
+		return rowErrors, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.%s
+		WHERE statement_id = $1
+		ORDER BY line_number, id`,
+		strings.Join(dbFields, ", "), r.schema, r.rowErrorTableName)
+	rows, err := r.db.FetchAll(ctx, query, statementId)
+	if err != nil {
+		return rowErrors, statementErrors.NewStatementGetError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return rowErrors, statementErrors.NewStatementGetError(err)
+		}
+		rowErrors = append(rowErrors, rowError)
+	}
+	return rowErrors, nil
+}
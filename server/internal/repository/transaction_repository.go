@@ -8,6 +8,7 @@ import (
 	customErrors "expenses/internal/errors"
 	"expenses/internal/models"
 	database "expenses/pkg/database/manager"
+	"expenses/pkg/logger"
 	"fmt"
 	"strconv"
 	"strings"
@@ -17,11 +18,12 @@ import (
 
 type TransactionRepositoryInterface interface {
 	CreateTransaction(ctx context.Context, transaction models.CreateBaseTransactionInput, categoryIds []int64) (models.TransactionResponse, error)
-	CreateTransactions(ctx context.Context, transactions []models.CreateBaseTransactionInput, categoryIds [][]int64) ([]models.TransactionResponse, error)
+	CreateTransactions(ctx context.Context, transactions []models.CreateBaseTransactionInput, categoryIds [][]int64) (models.BulkCreateTransactionsResult, error)
 	GetTransactionById(ctx context.Context, transactionId int64, userId int64) (models.TransactionResponse, error)
 	GetTransactionsByIds(ctx context.Context, transactionIds []int64, userId int64) ([]models.TransactionResponse, error)
 	UpdateTransaction(ctx context.Context, transactionId int64, userId int64, input models.UpdateBaseTransactionInput) error
 	DeleteTransaction(ctx context.Context, transactionId int64, userId int64) error
+	DeleteTransactions(ctx context.Context, transactionIds []int64, userId int64) (int64, error)
 	ListTransactions(ctx context.Context, userId int64, query models.TransactionListQuery) (models.PaginatedTransactionsResponse, error)
 	UpdateCategoryMapping(ctx context.Context, transactionId int64, userId int64, categoryIds []int64) error
 }
@@ -60,7 +62,7 @@ func (r *TransactionRepository) CreateTransaction(ctx context.Context, transacti
 		}
 		err = r.db.FetchOne(txCtx, query, values...).Scan(ptrs...)
 		if err != nil {
-			if customErrors.CheckForeignKey(err, "idx_transaction_unique_composite") {
+			if customErrors.CheckForeignKey(err, "idx_transaction_unique_composite") || customErrors.CheckForeignKey(err, "idx_transaction_unique_external_id") {
 				return customErrors.NewTransactionAlreadyExistsError(err)
 			}
 			return err
@@ -120,114 +122,146 @@ func transactionKeyFromResponse(tx models.TransactionBaseResponse) transactionIn
 	}
 }
 
-func (r *TransactionRepository) CreateTransactions(ctx context.Context, transactions []models.CreateBaseTransactionInput, categoryIds [][]int64) ([]models.TransactionResponse, error) {
+// CreateTransactions inserts transactions in batches. Rows that already exist
+// are skipped and counted as duplicates. When a batch fails, its rows are
+// retried one at a time so that a single bad row only fails itself.
+func (r *TransactionRepository) CreateTransactions(ctx context.Context, transactions []models.CreateBaseTransactionInput, categoryIds [][]int64) (models.BulkCreateTransactionsResult, error) {
+	result := models.BulkCreateTransactionsResult{Inserted: []models.TransactionResponse{}}
 	if len(transactions) == 0 {
-		return []models.TransactionResponse{}, nil
+		return result, nil
 	}
 
 	if len(transactions) != len(categoryIds) {
-		return nil, fmt.Errorf("transactions and categoryIds must have the same length")
+		return result, fmt.Errorf("transactions and categoryIds must have the same length")
 	}
 
 	const batchSize = 10000
+
+	// Process transactions in batches of 10k
+	for batchStart := 0; batchStart < len(transactions); batchStart += batchSize {
+		batchEnd := batchStart + batchSize
+		if batchEnd > len(transactions) {
+			batchEnd = len(transactions)
+		}
+
+		inserted, err := r.insertTransactionBatch(ctx, transactions[batchStart:batchEnd], categoryIds[batchStart:batchEnd])
+		if err == nil {
+			result.Inserted = append(result.Inserted, inserted...)
+			result.Duplicates += batchEnd - batchStart - len(inserted)
+			continue
+		}
+
+		logger.Warnf("Bulk insert of transactions %d-%d failed, retrying row by row: %v", batchStart, batchEnd-1, err)
+		for i := batchStart; i < batchEnd; i++ {
+			inserted, err := r.insertTransactionBatch(ctx, transactions[i:i+1], categoryIds[i:i+1])
+			switch {
+			case err != nil:
+				result.Failed = append(result.Failed, models.BulkCreateTransactionFailure{Index: i, Err: err})
+			case len(inserted) == 0:
+				result.Duplicates++
+			default:
+				result.Inserted = append(result.Inserted, inserted...)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// insertTransactionBatch inserts a batch of transactions and their category
+// mappings in one database transaction, skipping rows that already exist. It
+// returns the inserted transactions.
+func (r *TransactionRepository) insertTransactionBatch(ctx context.Context, batchTxs []models.CreateBaseTransactionInput, batchCatIds [][]int64) ([]models.TransactionResponse, error) {
 	var results []models.TransactionResponse
 
 	err := r.db.WithTxn(ctx, func(txCtx context.Context) error {
-		results = make([]models.TransactionResponse, 0, len(transactions))
+		results = make([]models.TransactionResponse, 0, len(batchTxs))
+		keyToIndexes := make(map[transactionInsertKey][]int, len(batchTxs))
 
-		// Process transactions in batches of 10k
-		for batchStart := 0; batchStart < len(transactions); batchStart += batchSize {
-			batchEnd := batchStart + batchSize
-			if batchEnd > len(transactions) {
-				batchEnd = len(transactions)
-			}
+		for i, tx := range batchTxs {
+			key := transactionKeyFromInput(tx)
+			keyToIndexes[key] = append(keyToIndexes[key], i)
+		}
 
-			batchTxs := transactions[batchStart:batchEnd]
-			batchCatIds := categoryIds[batchStart:batchEnd]
-			keyToIndexes := make(map[transactionInsertKey][]int, len(batchTxs))
+		placeholders := make([]string, 0, len(batchTxs))
+		args := make([]interface{}, 0, len(batchTxs)*7)
+		argIndex := 1
 
-			for i, tx := range batchTxs {
-				key := transactionKeyFromInput(tx)
-				keyToIndexes[key] = append(keyToIndexes[key], i)
-			}
+		for _, tx := range batchTxs {
+			placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+				argIndex, argIndex+1, argIndex+2, argIndex+3, argIndex+4, argIndex+5, argIndex+6))
+			args = append(args, tx.Name, tx.Description, tx.Amount, tx.Date, tx.CreatedBy, tx.AccountId, tx.ExternalId)
+			argIndex += 7
+		}
 
-			placeholders := make([]string, 0, len(batchTxs))
-			args := make([]interface{}, 0, len(batchTxs)*6)
-			argIndex := 1
+		query := fmt.Sprintf(`
+			INSERT INTO %s.%s (name, description, amount, date, created_by, account_id, external_id)
+			VALUES %s
+			ON CONFLICT DO NOTHING
+			RETURNING id, name, description, amount, date, created_by, account_id;
+		`, r.schema, r.tableName, strings.Join(placeholders, ", "))
 
-			for _, tx := range batchTxs {
-				placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)",
-					argIndex, argIndex+1, argIndex+2, argIndex+3, argIndex+4, argIndex+5))
-				args = append(args, tx.Name, tx.Description, tx.Amount, tx.Date, tx.CreatedBy, tx.AccountId)
-				argIndex += 6
-			}
+		rows, err := r.db.FetchAll(txCtx, query, args...)
+		if err != nil {
+			return err
+		}
 
-			query := fmt.Sprintf(`
-				INSERT INTO %s.%s (name, description, amount, date, created_by, account_id)
-				VALUES %s
-				ON CONFLICT DO NOTHING
-				RETURNING id, name, description, amount, date, created_by, account_id;
-			`, r.schema, r.tableName, strings.Join(placeholders, ", "))
+		inserted := make([]struct {
+			id     int64
+			rowIdx int
+		}, 0, len(batchTxs))
 
-			rows, err := r.db.FetchAll(txCtx, query, args...)
+		for rows.Next() {
+			var txResp models.TransactionBaseResponse
+			err = rows.Scan(&txResp.Id, &txResp.Name, &txResp.Description, &txResp.Amount, &txResp.Date, &txResp.CreatedBy, &txResp.AccountId)
 			if err != nil {
+				rows.Close()
 				return err
 			}
 
-			inserted := make([]struct {
+			key := transactionKeyFromResponse(txResp)
+			indexes := keyToIndexes[key]
+			if len(indexes) == 0 {
+				rows.Close()
+				return fmt.Errorf("unable to match inserted transaction to input batch")
+			}
+			rowIdx := indexes[0]
+			keyToIndexes[key] = indexes[1:]
+
+			inserted = append(inserted, struct {
 				id     int64
 				rowIdx int
-			}, 0, len(batchTxs))
-
-			for rows.Next() {
-				var txResp models.TransactionBaseResponse
-				err = rows.Scan(&txResp.Id, &txResp.Name, &txResp.Description, &txResp.Amount, &txResp.Date, &txResp.CreatedBy, &txResp.AccountId)
-				if err != nil {
-					rows.Close()
-					return err
-				}
+			}{
+				id:     txResp.Id,
+				rowIdx: rowIdx,
+			})
+			results = append(results, models.TransactionResponse{
+				TransactionBaseResponse: txResp,
+				CategoryIds:             batchCatIds[rowIdx],
+			})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
 
-				key := transactionKeyFromResponse(txResp)
-				indexes := keyToIndexes[key]
-				if len(indexes) == 0 {
-					rows.Close()
-					return fmt.Errorf("unable to match inserted transaction to input batch")
+		if len(inserted) > 0 {
+			categoryBatch := &pgx.Batch{}
+			for _, insertedTx := range inserted {
+				for _, catID := range batchCatIds[insertedTx.rowIdx] {
+					query := fmt.Sprintf(`INSERT INTO %s.%s (category_id, transaction_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;`,
+						r.schema, r.transactionCategoryMappingTable)
+					categoryBatch.Queue(query, catID, insertedTx.id)
 				}
-				rowIdx := indexes[0]
-				keyToIndexes[key] = indexes[1:]
-
-				inserted = append(inserted, struct {
-					id     int64
-					rowIdx int
-				}{
-					id:     txResp.Id,
-					rowIdx: rowIdx,
-				})
-				results = append(results, models.TransactionResponse{
-					TransactionBaseResponse: txResp,
-					CategoryIds:             batchCatIds[rowIdx],
-				})
 			}
-			rows.Close()
-
-			if len(inserted) > 0 {
-				categoryBatch := &pgx.Batch{}
-				for _, insertedTx := range inserted {
-					for _, catID := range batchCatIds[insertedTx.rowIdx] {
-						query := fmt.Sprintf(`INSERT INTO %s.%s (category_id, transaction_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;`,
-							r.schema, r.transactionCategoryMappingTable)
-						categoryBatch.Queue(query, catID, insertedTx.id)
-					}
-				}
 
-				if categoryBatch.Len() > 0 {
-					err := r.db.ExecuteBatch(txCtx, categoryBatch)
-					if err != nil {
-						if customErrors.CheckForeignKey(err, "fk_category") {
-							return customErrors.NewCategoryNotFoundError(err)
-						}
-						return err
+			if categoryBatch.Len() > 0 {
+				err := r.db.ExecuteBatch(txCtx, categoryBatch)
+				if err != nil {
+					if customErrors.CheckForeignKey(err, "fk_category") {
+						return customErrors.NewCategoryNotFoundError(err)
 					}
+					return err
 				}
 			}
 		}
@@ -358,6 +392,19 @@ func (r *TransactionRepository) DeleteTransaction(ctx context.Context, transacti
 	return nil
 }
 
+// DeleteTransactions soft-deletes the given transactions together with the
+// transactions rules created from them, and returns how many were deleted.
+func (r *TransactionRepository) DeleteTransactions(ctx context.Context, transactionIds []int64, userId int64) (int64, error) {
+	if len(transactionIds) == 0 {
+		return 0, nil
+	}
+	query := fmt.Sprintf(`
+		UPDATE %s.%s SET deleted_at = NOW()
+		WHERE created_by = $1 AND deleted_at IS NULL
+			AND (id = ANY($2) OR source_transaction_id = ANY($2));`, r.schema, r.tableName)
+	return r.db.ExecuteQuery(ctx, query, userId, transactionIds)
+}
+
 func (r *TransactionRepository) updateMapping(ctx context.Context, mappingTable, transactionColumn, idColumn string, transactionId int64, ids []int64) error {
 	// Clear existing mappings
 	_, err := r.db.ExecuteQuery(ctx, fmt.Sprintf(`DELETE FROM %s.%s WHERE %s = $1;`, r.schema, mappingTable, transactionColumn), transactionId)
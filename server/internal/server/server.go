@@ -35,6 +35,9 @@ func Start() {
 			logger.Errorf("Failed to close the provider: %v", err)
 		}
 	}(provider)
+	if err := provider.StartWorkers(context.Background()); err != nil {
+		logger.Fatalf("Failed to start job workers: %v", err)
+	}
 
 	httpServer = &http.Server{
 		Addr:              ":" + strconv.Itoa(port),
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	customErrors "expenses/internal/errors"
+	"expenses/internal/models"
+	"expenses/internal/parser"
+	"expenses/internal/repository"
+	"expenses/pkg/logger"
+	"expenses/pkg/storage"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type AppImportServiceInterface interface {
+	ImportApp(ctx context.Context, input models.AppImportInput, userId int64) (models.AppImportResponse, error)
+	GetAppImport(ctx context.Context, importId int64, userId int64) (models.AppImportResponse, error)
+	ListAppImports(ctx context.Context, userId int64) ([]models.AppImportResponse, error)
+}
+
+type AppImportService struct {
+	repo            repository.AppImportRepositoryInterface
+	accountService  AccountServiceInterface
+	categoryService CategoryServiceInterface
+	txService       TransactionServiceInterface
+	jobQueue        JobQueueInterface
+	storage         storage.BlobStorage
+}
+
+func NewAppImportService(
+	repo repository.AppImportRepositoryInterface,
+	accountService AccountServiceInterface,
+	categoryService CategoryServiceInterface,
+	txService TransactionServiceInterface,
+	jobQueue JobQueueInterface,
+	storage storage.BlobStorage,
+) AppImportServiceInterface {
+	s := &AppImportService{
+		repo:            repo,
+		accountService:  accountService,
+		categoryService: categoryService,
+		txService:       txService,
+		jobQueue:        jobQueue,
+		storage:         storage,
+	}
+	jobQueue.RegisterHandler(models.JobTypeAppImport, s.handleAppImportJob)
+	return s
+}
+
+// ImportApp stores the export of another app and queues it to be imported.
+func (s *AppImportService) ImportApp(ctx context.Context, input models.AppImportInput, userId int64) (models.AppImportResponse, error) {
+	if err := validateAppImportUpload(input); err != nil {
+		return models.AppImportResponse{}, err
+	}
+	if input.Currency == "" {
+		input.Currency = "inr"
+	}
+
+	hash := sha256.Sum256(input.FileBytes)
+	fileKey := fmt.Sprintf("imports/%d/%s", userId, hex.EncodeToString(hash[:]))
+	if err := s.storage.Put(ctx, fileKey, input.FileBytes); err != nil {
+		return models.AppImportResponse{}, customErrors.NewAppImportFileStoreError(err)
+	}
+
+	appImport, err := s.repo.CreateAppImport(ctx, models.CreateAppImportInput{
+		Source:           input.Source,
+		OriginalFilename: input.OriginalFilename,
+		Currency:         input.Currency,
+		Status:           models.AppImportStatusPending,
+		FileKey:          fileKey,
+		CreatedBy:        userId,
+	})
+	if err != nil {
+		return models.AppImportResponse{}, err
+	}
+
+	_, err = s.jobQueue.Enqueue(ctx, models.JobTypeAppImport, &appImport.Id, models.AppImportJobPayload{
+		ImportId:         appImport.Id,
+		UserId:           userId,
+		FileKey:          fileKey,
+		Source:           input.Source,
+		Currency:         input.Currency,
+		OriginalFilename: input.OriginalFilename,
+	})
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to queue import: %v", err)
+		_, _ = s.repo.UpdateAppImport(ctx, appImport.Id, models.UpdateAppImportInput{
+			Status:  models.AppImportStatusError,
+			Message: &errMsg,
+		})
+		return models.AppImportResponse{}, err
+	}
+	return appImport, nil
+}
+
+func validateAppImportUpload(input models.AppImportInput) error {
+	if _, ok := parser.GetAppImporter(input.Source); !ok {
+		return customErrors.NewAppImportBadRequestError(fmt.Errorf("unsupported source: %s", input.Source))
+	}
+	if len(input.FileBytes) == 0 {
+		return customErrors.NewAppImportBadRequestError(errors.New("file is required"))
+	}
+	if len(input.FileBytes) > 5*1024*1024 {
+		return customErrors.NewAppImportBadRequestError(errors.New("file size must be less than 5MB"))
+	}
+	switch strings.ToLower(filepath.Ext(strings.TrimSpace(input.OriginalFilename))) {
+	case ".csv", ".tsv", ".txt", ".xls", ".xlsx":
+		return nil
+	default:
+		return customErrors.NewAppImportBadRequestError(errors.New("file must be CSV or Excel format (.csv, .tsv, .txt, .xls, .xlsx)"))
+	}
+}
+
+func (s *AppImportService) GetAppImport(ctx context.Context, importId int64, userId int64) (models.AppImportResponse, error) {
+	return s.repo.GetAppImportById(ctx, importId, userId)
+}
+
+func (s *AppImportService) ListAppImports(ctx context.Context, userId int64) ([]models.AppImportResponse, error) {
+	return s.repo.ListAppImports(ctx, userId)
+}
+
+func (s *AppImportService) handleAppImportJob(ctx context.Context, job models.Job) error {
+	var payload models.AppImportJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		// A payload that cannot be read will not get better on retry.
+		logger.Errorf("Dropping app import job %d with invalid payload: %v", job.Id, err)
+		return nil
+	}
+	fileBytes, err := s.storage.Get(ctx, payload.FileKey)
+	if err != nil {
+		return s.retryOrFail(ctx, job, payload.ImportId, fmt.Sprintf("Failed to read import file: %v", err))
+	}
+	return s.processAppImport(ctx, job, payload, fileBytes)
+}
+
+// retryOrFail returns an error so the job is retried, and fails the import
+// once the job is out of attempts.
+func (s *AppImportService) retryOrFail(ctx context.Context, job models.Job, importId int64, errMsg string) error {
+	if job.IsLastAttempt() {
+		_, _ = s.repo.UpdateAppImport(ctx, importId, models.UpdateAppImportInput{
+			Status:  models.AppImportStatusError,
+			Message: &errMsg,
+		})
+	}
+	return errors.New(errMsg)
+}
+
+// processAppImport reads the export and imports it: accounts and categories
+// named by the export are matched by name against the user's own, the missing
+// ones are created, and the transactions are inserted with their categories.
+// Accounts and categories created by an attempt that failed are matched on
+// retry, and transactions already inserted are skipped as duplicates.
+func (s *AppImportService) processAppImport(ctx context.Context, job models.Job, payload models.AppImportJobPayload, fileBytes []byte) error {
+	logger.Debugf("Processing %s import ID %d by user ID %d", payload.Source, payload.ImportId, payload.UserId)
+	_, _ = s.repo.UpdateAppImport(ctx, payload.ImportId, models.UpdateAppImportInput{
+		Status: models.AppImportStatusProcessing,
+	})
+
+	importer, ok := parser.GetAppImporter(payload.Source)
+	if !ok {
+		errMsg := fmt.Sprintf("No importer available for source: %s", payload.Source)
+		_, _ = s.repo.UpdateAppImport(ctx, payload.ImportId, models.UpdateAppImportInput{
+			Status:  models.AppImportStatusError,
+			Message: &errMsg,
+		})
+		return nil
+	}
+
+	txns, rowErrors, err := importer.Import(fileBytes, payload.OriginalFilename)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to read export: %v", err)
+		_, _ = s.repo.UpdateAppImport(ctx, payload.ImportId, models.UpdateAppImportInput{
+			Status:  models.AppImportStatusError,
+			Message: &errMsg,
+		})
+		return nil
+	}
+	logger.Debugf("Read %d transactions from import ID %d, %d rows failed", len(txns), payload.ImportId, len(rowErrors))
+
+	accountIds, accountsCreated, err := s.resolveAccounts(ctx, payload.UserId, payload.Currency, txns)
+	if err != nil {
+		return s.retryOrFail(ctx, job, payload.ImportId, fmt.Sprintf("Failed to create accounts: %v", err))
+	}
+	categoryIds, categoriesCreated, err := s.resolveCategories(ctx, payload.UserId, txns)
+	if err != nil {
+		return s.retryOrFail(ctx, job, payload.ImportId, fmt.Sprintf("Failed to create categories: %v", err))
+	}
+
+	inputs := make([]models.CreateTransactionInput, len(txns))
+	for i, txn := range txns {
+		input := txn.CreateTransactionInput
+		input.AccountId = accountIds[strings.ToLower(txn.AccountName)]
+		input.CreatedBy = payload.UserId
+		input.CategoryIds = []int64{}
+		seen := make(map[int64]bool)
+		for _, name := range txn.CategoryNames {
+			categoryId := categoryIds[strings.ToLower(name)]
+			if !seen[categoryId] {
+				seen[categoryId] = true
+				input.CategoryIds = append(input.CategoryIds, categoryId)
+			}
+		}
+		inputs[i] = input
+	}
+
+	created, err := s.txService.CreateTransactions(ctx, inputs)
+	if err != nil {
+		return s.retryOrFail(ctx, job, payload.ImportId, fmt.Sprintf("Failed to create transactions: %v", err))
+	}
+
+	insertedCount := len(created.Inserted)
+	duplicateCount := created.Duplicates
+	failedCount := len(rowErrors) + len(created.Failed)
+	msg := fmt.Sprintf("Processed %d transactions: %d inserted, %d duplicates, %d failed; created %d accounts and %d categories",
+		insertedCount+duplicateCount+failedCount, insertedCount, duplicateCount, failedCount, accountsCreated, categoriesCreated)
+	_, err = s.repo.UpdateAppImport(ctx, payload.ImportId, models.UpdateAppImportInput{
+		Status:            models.AppImportStatusDone,
+		Message:           &msg,
+		AccountsCreated:   &accountsCreated,
+		CategoriesCreated: &categoriesCreated,
+		InsertedCount:     &insertedCount,
+		DuplicateCount:    &duplicateCount,
+		FailedCount:       &failedCount,
+	})
+	if err != nil {
+		logger.Errorf("Failed to update app import status for ID %d: %v", payload.ImportId, err)
+	}
+	return nil
+}
+
+// resolveAccounts maps the lower-cased account names of the transactions to
+// the ids of the user's accounts, creating the accounts that do not exist.
+func (s *AppImportService) resolveAccounts(ctx context.Context, userId int64, currency string, txns []models.AppImportTransaction) (map[string]int64, int, error) {
+	accounts, err := s.accountService.ListAccounts(ctx, userId)
+	if err != nil {
+		return nil, 0, err
+	}
+	accountIds := make(map[string]int64, len(accounts))
+	for _, account := range accounts {
+		accountIds[strings.ToLower(strings.TrimSpace(account.Name))] = account.Id
+	}
+
+	created := 0
+	for _, txn := range txns {
+		key := strings.ToLower(txn.AccountName)
+		if _, ok := accountIds[key]; ok {
+			continue
+		}
+		account, err := s.accountService.CreateAccount(ctx, models.CreateAccountInput{
+			Name:      txn.AccountName,
+			BankType:  models.BankTypeOthers,
+			Currency:  currency,
+			CreatedBy: userId,
+		})
+		if err != nil {
+			return nil, created, err
+		}
+		accountIds[key] = account.Id
+		created++
+	}
+	return accountIds, created, nil
+}
+
+// resolveCategories maps the lower-cased category names of the transactions
+// to the ids of the user's categories, creating the categories that do not
+// exist.
+func (s *AppImportService) resolveCategories(ctx context.Context, userId int64, txns []models.AppImportTransaction) (map[string]int64, int, error) {
+	categories, err := s.categoryService.ListCategories(ctx, userId)
+	if err != nil {
+		return nil, 0, err
+	}
+	categoryIds := make(map[string]int64, len(categories))
+	for _, category := range categories {
+		categoryIds[strings.ToLower(strings.TrimSpace(category.Name))] = category.Id
+	}
+
+	created := 0
+	for _, txn := range txns {
+		for _, name := range txn.CategoryNames {
+			key := strings.ToLower(name)
+			if _, ok := categoryIds[key]; ok {
+				continue
+			}
+			category, err := s.categoryService.CreateCategory(ctx, models.CreateCategoryInput{
+				Name:      name,
+				CreatedBy: userId,
+			})
+			if err != nil {
+				return nil, created, err
+			}
+			categoryIds[key] = category.Id
+			created++
+		}
+	}
+	return categoryIds, created, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	customErrors "expenses/internal/errors"
+	mockDatabase "expenses/internal/mock/database"
+	repository "expenses/internal/mock/repository"
+	"expenses/internal/models"
+	"expenses/pkg/storage"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppImportService", func() {
+	var (
+		service         AppImportServiceInterface
+		accountService  AccountServiceInterface
+		categoryService CategoryServiceInterface
+		txnService      TransactionServiceInterface
+		jobQueue        JobQueueInterface
+		userId          int64
+		ctx             context.Context
+	)
+
+	const walnutExport = "DATE,TIME,PLACE,AMOUNT,DR/CR,ACCOUNT,EXPENSE,INCOME,CATEGORY,TAGS,NOTE\n" +
+		"03-04-24,01:15 PM,Swiggy,250.00,DR,Imported Wallet,Yes,No,Food,,\n" +
+		"04-04-24,09:15 AM,Zomato,180.00,DR,Imported Wallet,Yes,No,FOOD,,\n" +
+		"05-04-24,06:40 PM,Uber,320.00,DR,Imported Card,Yes,No,Commute,,\n" +
+		"not a date,06:40 PM,Uber,320.00,DR,Imported Card,Yes,No,Commute,,\n"
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockTxnRepo := repository.NewMockTransactionRepository()
+		mockCategoryRepo := repository.NewMockCategoryRepository()
+		mockAccountRepo := repository.NewMockAccountRepository()
+		txnService = NewTransactionService(mockTxnRepo, mockCategoryRepo, mockAccountRepo, mockDatabase.NewMockDatabaseManager())
+		accountService = NewAccountService(mockAccountRepo)
+		categoryService = NewCategoryService(mockCategoryRepo)
+		fileStorage, err := storage.NewLocalStorage(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		jobQueue = NewJobQueue(repository.NewMockJobRepository(), testJobConfig())
+		service = NewAppImportService(repository.NewMockAppImportRepository(), accountService, categoryService, txnService, jobQueue, fileStorage)
+		jobQueue.Start()
+		userId = 42
+	})
+
+	AfterEach(func() {
+		jobQueue.Stop()
+	})
+
+	waitForImport := func(importId int64) models.AppImportResponse {
+		Eventually(func() models.AppImportStatus {
+			result, _ := service.GetAppImport(ctx, importId, userId)
+			return result.Status
+		}, "2s", "10ms").Should(BeElementOf(models.AppImportStatusDone, models.AppImportStatusError))
+		result, err := service.GetAppImport(ctx, importId, userId)
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	Describe("ImportApp", func() {
+		It("creates the accounts, categories and transactions of an export", func() {
+			existing, err := categoryService.CreateCategory(ctx, models.CreateCategoryInput{Name: "food", CreatedBy: userId})
+			Expect(err).NotTo(HaveOccurred())
+
+			appImport, err := service.ImportApp(ctx, models.AppImportInput{
+				Source:           models.AppImportSourceWalnut,
+				OriginalFilename: "walnut.csv",
+				FileBytes:        []byte(walnutExport),
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(appImport.Status).To(Equal(models.AppImportStatusPending))
+			Expect(appImport.Currency).To(Equal("inr"))
+
+			result := waitForImport(appImport.Id)
+			Expect(result.Status).To(Equal(models.AppImportStatusDone))
+			Expect(result.AccountsCreated).To(Equal(2))
+			Expect(result.CategoriesCreated).To(Equal(1))
+			Expect(result.InsertedCount).To(Equal(3))
+			Expect(result.FailedCount).To(Equal(1))
+
+			accounts, err := accountService.ListAccounts(ctx, userId)
+			Expect(err).NotTo(HaveOccurred())
+			accountIds := map[string]int64{}
+			for _, account := range accounts {
+				accountIds[account.Name] = account.Id
+			}
+			Expect(accountIds).To(HaveKey("Imported Wallet"))
+			Expect(accountIds).To(HaveKey("Imported Card"))
+
+			walletId := accountIds["Imported Wallet"]
+			txns, err := txnService.ListTransactions(ctx, userId, models.TransactionListQuery{Page: 1, PageSize: 10, AccountId: &walletId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns.Transactions).To(HaveLen(2))
+			for _, txn := range txns.Transactions {
+				Expect(txn.CategoryIds).To(Equal([]int64{existing.Id}))
+			}
+		})
+
+		It("skips transactions that were already imported", func() {
+			input := models.AppImportInput{
+				Source:           models.AppImportSourceWalnut,
+				OriginalFilename: "walnut.csv",
+				FileBytes:        []byte(walnutExport),
+			}
+			first, err := service.ImportApp(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waitForImport(first.Id).Status).To(Equal(models.AppImportStatusDone))
+
+			second, err := service.ImportApp(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+			result := waitForImport(second.Id)
+			Expect(result.Status).To(Equal(models.AppImportStatusDone))
+			Expect(result.AccountsCreated).To(Equal(0))
+			Expect(result.CategoriesCreated).To(Equal(0))
+			Expect(result.InsertedCount).To(Equal(0))
+			Expect(result.DuplicateCount).To(Equal(3))
+		})
+
+		It("fails the import when the file is not an export of the app", func() {
+			appImport, err := service.ImportApp(ctx, models.AppImportInput{
+				Source:           models.AppImportSourceYNAB,
+				OriginalFilename: "register.csv",
+				FileBytes:        []byte("foo,bar\n1,2\n"),
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+			result := waitForImport(appImport.Id)
+			Expect(result.Status).To(Equal(models.AppImportStatusError))
+			Expect(*result.Message).To(ContainSubstring("header row not found"))
+		})
+
+		It("rejects unsupported sources and files", func() {
+			_, err := service.ImportApp(ctx, models.AppImportInput{
+				Source:           "mint",
+				OriginalFilename: "mint.csv",
+				FileBytes:        []byte("a,b\n"),
+			}, userId)
+			var authErr *customErrors.AuthError
+			Expect(errors.As(err, &authErr)).To(BeTrue())
+			Expect(authErr.Status).To(Equal(http.StatusBadRequest))
+
+			_, err = service.ImportApp(ctx, models.AppImportInput{
+				Source:           models.AppImportSourceSplitwise,
+				OriginalFilename: "splitwise.pdf",
+				FileBytes:        []byte("%PDF-1.4"),
+			}, userId)
+			Expect(errors.As(err, &authErr)).To(BeTrue())
+			Expect(authErr.Status).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("GetAppImport", func() {
+		It("does not return the imports of other users", func() {
+			appImport, err := service.ImportApp(ctx, models.AppImportInput{
+				Source:           models.AppImportSourceWalnut,
+				OriginalFilename: "walnut.csv",
+				FileBytes:        []byte(walnutExport),
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+			waitForImport(appImport.Id)
+
+			_, err = service.GetAppImport(ctx, appImport.Id, userId+1)
+			var authErr *customErrors.AuthError
+			Expect(errors.As(err, &authErr)).To(BeTrue())
+			Expect(authErr.Status).To(Equal(http.StatusNotFound))
+
+			imports, err := service.ListAppImports(ctx, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imports).To(HaveLen(1))
+		})
+	})
+})
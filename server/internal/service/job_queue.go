@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"expenses/internal/config"
+	customErrors "expenses/internal/errors"
+	"expenses/internal/models"
+	"expenses/internal/repository"
+	"expenses/pkg/logger"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	jobRetryBaseDelay = 5 * time.Second
+	jobRetryMaxDelay  = 10 * time.Minute
+)
+
+// JobHandler does the work of a job. Returning an error schedules a retry
+// until the job runs out of attempts.
+type JobHandler func(ctx context.Context, job models.Job) error
+
+type JobQueueInterface interface {
+	RegisterHandler(jobType models.JobType, handler JobHandler)
+	Enqueue(ctx context.Context, jobType models.JobType, referenceId *int64, payload any) (models.Job, error)
+	Start()
+	Stop()
+}
+
+// JobQueue runs background work stored in the job table, so queued work
+// survives restarts. Any number of servers can run workers against the same
+// table; a job that is not finished within the visibility timeout is picked up
+// again by another worker.
+type JobQueue struct {
+	repo              repository.JobRepositoryInterface
+	workers           int
+	maxAttempts       int
+	pollInterval      time.Duration
+	visibilityTimeout time.Duration
+
+	mu       sync.RWMutex
+	handlers map[models.JobType]JobHandler
+	wake     chan struct{}
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewJobQueue(repo repository.JobRepositoryInterface, cfg *config.Config) JobQueueInterface {
+	return &JobQueue{
+		repo:              repo,
+		workers:           cfg.JobWorkers,
+		maxAttempts:       cfg.JobMaxAttempts,
+		pollInterval:      cfg.JobPollInterval,
+		visibilityTimeout: cfg.JobVisibilityTimeout,
+		handlers:          make(map[models.JobType]JobHandler),
+		wake:              make(chan struct{}, 1),
+	}
+}
+
+func (q *JobQueue) RegisterHandler(jobType models.JobType, handler JobHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// Enqueue stores a job with a JSON encoded payload and wakes an idle worker.
+func (q *JobQueue) Enqueue(ctx context.Context, jobType models.JobType, referenceId *int64, payload any) (models.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return models.Job{}, customErrors.NewJobCreateError(err)
+	}
+	job, err := q.repo.CreateJob(ctx, models.CreateJobInput{
+		JobType:     jobType,
+		ReferenceId: referenceId,
+		Payload:     data,
+		MaxAttempts: q.maxAttempts,
+	})
+	if err != nil {
+		return job, err
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// Start launches the workers. Handlers have to be registered before.
+func (q *JobQueue) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+	logger.Infof("Started %d job workers", q.workers)
+}
+
+// Stop stops claiming new jobs and waits for running jobs to finish.
+func (q *JobQueue) Stop() {
+	if q.cancel == nil {
+		return
+	}
+	q.cancel()
+	q.wg.Wait()
+	q.cancel = nil
+}
+
+func (q *JobQueue) work(ctx context.Context) {
+	defer q.wg.Done()
+	for {
+		job, err := q.repo.ClaimJob(ctx, q.jobTypes(), q.visibilityTimeout)
+		if err != nil && ctx.Err() == nil {
+			logger.Errorf("Failed to claim job: %v", err)
+		}
+		if job != nil {
+			q.run(*job)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-time.After(q.pollInterval):
+		}
+	}
+}
+
+func (q *JobQueue) jobTypes() []models.JobType {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	jobTypes := make([]models.JobType, 0, len(q.handlers))
+	for jobType := range q.handlers {
+		jobTypes = append(jobTypes, jobType)
+	}
+	return jobTypes
+}
+
+// run executes a claimed job and records the outcome. Jobs run with their own
+// context so that stopping the queue lets them finish.
+func (q *JobQueue) run(job models.Job) {
+	ctx := context.Background()
+	q.mu.RLock()
+	handler := q.handlers[job.JobType]
+	q.mu.RUnlock()
+
+	logger.Debugf("Running job %d (%s), attempt %d of %d", job.Id, job.JobType, job.Attempts, job.MaxAttempts)
+	stopHeartbeat := q.keepLocked(job.Id)
+	err := safeRunJob(ctx, handler, job)
+	stopHeartbeat()
+
+	if err == nil {
+		if err := q.repo.CompleteJob(ctx, job.Id); err != nil {
+			logger.Errorf("Failed to complete job %d: %v", job.Id, err)
+		}
+		return
+	}
+
+	if job.IsLastAttempt() {
+		logger.Errorf("Job %d (%s) failed after %d attempts: %v", job.Id, job.JobType, job.Attempts, err)
+		if err := q.repo.FailJob(ctx, job.Id, err.Error()); err != nil {
+			logger.Errorf("Failed to mark job %d as failed: %v", job.Id, err)
+		}
+		return
+	}
+
+	delay := retryDelay(job.Attempts)
+	logger.Warnf("Job %d (%s) failed on attempt %d, retrying in %v: %v", job.Id, job.JobType, job.Attempts, delay, err)
+	if err := q.repo.RetryJob(ctx, job.Id, time.Now().Add(delay), err.Error()); err != nil {
+		logger.Errorf("Failed to reschedule job %d: %v", job.Id, err)
+	}
+}
+
+// keepLocked extends the lock of a running job at half the visibility timeout
+// until the returned function is called.
+func (q *JobQueue) keepLocked(jobId int64) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(q.visibilityTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := q.repo.ExtendJobLock(context.Background(), jobId, q.visibilityTimeout); err != nil {
+					logger.Warnf("Failed to extend lock of job %d: %v", jobId, err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func safeRunJob(ctx context.Context, handler JobHandler, job models.Job) (err error) {
+	if handler == nil {
+		return fmt.Errorf("no handler registered for job type %s", job.JobType)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// retryDelay doubles the wait after every failed attempt.
+func retryDelay(attempts int) time.Duration {
+	delay := jobRetryBaseDelay
+	for i := 1; i < attempts && delay < jobRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > jobRetryMaxDelay {
+		delay = jobRetryMaxDelay
+	}
+	return delay
+}
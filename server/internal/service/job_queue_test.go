@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	repository "expenses/internal/mock/repository"
+	"expenses/internal/models"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JobQueue", func() {
+	var (
+		mockJobRepo *repository.MockJobRepository
+		queue       JobQueueInterface
+		ctx         context.Context
+		jobType     models.JobType
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		jobType = models.JobType("test_job")
+		mockJobRepo = repository.NewMockJobRepository()
+		queue = NewJobQueue(mockJobRepo, testJobConfig())
+	})
+
+	AfterEach(func() {
+		queue.Stop()
+	})
+
+	It("should run a queued job with its payload and remove it", func() {
+		received := make(chan string, 1)
+		queue.RegisterHandler(jobType, func(ctx context.Context, job models.Job) error {
+			received <- string(job.Payload)
+			return nil
+		})
+		queue.Start()
+
+		job, err := queue.Enqueue(ctx, jobType, nil, map[string]int{"user_id": 7})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(received).Should(Receive(Equal(`{"user_id":7}`)))
+		Eventually(func() bool {
+			_, ok := mockJobRepo.GetJob(job.Id)
+			return ok
+		}).Should(BeFalse())
+	})
+
+	It("should run jobs queued before the workers started", func() {
+		var runs atomic.Int32
+		queue.RegisterHandler(jobType, func(ctx context.Context, job models.Job) error {
+			runs.Add(1)
+			return nil
+		})
+		_, err := queue.Enqueue(ctx, jobType, nil, struct{}{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = queue.Enqueue(ctx, jobType, nil, struct{}{})
+		Expect(err).NotTo(HaveOccurred())
+
+		queue.Start()
+		Eventually(runs.Load).Should(Equal(int32(2)))
+	})
+
+	It("should schedule a retry with backoff when the handler fails", func() {
+		queue.RegisterHandler(jobType, func(ctx context.Context, job models.Job) error {
+			return errors.New("database unavailable")
+		})
+		queue.Start()
+
+		start := time.Now()
+		job, err := queue.Enqueue(ctx, jobType, nil, struct{}{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() *string {
+			stored, _ := mockJobRepo.GetJob(job.Id)
+			return stored.LastError
+		}).ShouldNot(BeNil())
+		stored, _ := mockJobRepo.GetJob(job.Id)
+		Expect(stored.Status).To(Equal(models.JobStatusPending))
+		Expect(stored.Attempts).To(Equal(1))
+		Expect(*stored.LastError).To(Equal("database unavailable"))
+		Expect(stored.RunAt).To(BeTemporally(">=", start.Add(jobRetryBaseDelay)))
+	})
+
+	It("should fail the job once it runs out of attempts", func() {
+		queue.RegisterHandler(jobType, func(ctx context.Context, job models.Job) error {
+			panic("unexpected")
+		})
+		job, err := queue.Enqueue(ctx, jobType, nil, struct{}{})
+		Expect(err).NotTo(HaveOccurred())
+		// Use up all but the last attempt
+		for i := 1; i < job.MaxAttempts; i++ {
+			claimed, err := mockJobRepo.ClaimJob(ctx, []models.JobType{jobType}, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mockJobRepo.RetryJob(ctx, claimed.Id, time.Now(), "failed")).To(Succeed())
+		}
+		queue.Start()
+
+		Eventually(func() models.JobStatus {
+			stored, _ := mockJobRepo.GetJob(job.Id)
+			return stored.Status
+		}).Should(Equal(models.JobStatusFailed))
+		stored, _ := mockJobRepo.GetJob(job.Id)
+		Expect(stored.Attempts).To(Equal(job.MaxAttempts))
+		Expect(*stored.LastError).To(Equal("job panicked: unexpected"))
+	})
+
+	It("should double the retry delay up to the maximum", func() {
+		Expect(retryDelay(1)).To(Equal(jobRetryBaseDelay))
+		Expect(retryDelay(2)).To(Equal(2 * jobRetryBaseDelay))
+		Expect(retryDelay(3)).To(Equal(4 * jobRetryBaseDelay))
+		Expect(retryDelay(50)).To(Equal(jobRetryMaxDelay))
+	})
+})
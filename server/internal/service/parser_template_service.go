@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	customErrors "expenses/internal/errors"
+	"expenses/internal/models"
+	"expenses/internal/parser"
+	"expenses/internal/repository"
+)
+
+type ParserTemplateServiceInterface interface {
+	CreateParserTemplate(ctx context.Context, input models.CreateParserTemplateInput) (models.ParserTemplateResponse, error)
+	GetParserTemplateById(ctx context.Context, templateId int64, userId int64) (models.ParserTemplateResponse, error)
+	GetAccountParserTemplate(ctx context.Context, accountId int64, userId int64) (*models.ParserTemplateResponse, error)
+	ListParserTemplates(ctx context.Context, userId int64) ([]models.ParserTemplateResponse, error)
+	UpdateParserTemplate(ctx context.Context, templateId int64, userId int64, input models.UpdateParserTemplateInput) (models.ParserTemplateResponse, error)
+	DeleteParserTemplate(ctx context.Context, templateId int64, userId int64) error
+}
+
+type ParserTemplateService struct {
+	repo           repository.ParserTemplateRepositoryInterface
+	accountService AccountServiceInterface
+}
+
+func NewParserTemplateService(repo repository.ParserTemplateRepositoryInterface, accountService AccountServiceInterface) ParserTemplateServiceInterface {
+	return &ParserTemplateService{repo: repo, accountService: accountService}
+}
+
+func (s *ParserTemplateService) CreateParserTemplate(ctx context.Context, input models.CreateParserTemplateInput) (models.ParserTemplateResponse, error) {
+	if err := parser.ValidateStatementMetadata(string(input.Metadata)); err != nil {
+		return models.ParserTemplateResponse{}, customErrors.NewParserTemplateInvalidMetadataError(err)
+	}
+	if input.AccountId != nil {
+		if _, err := s.accountService.GetAccountById(ctx, *input.AccountId, input.CreatedBy); err != nil {
+			return models.ParserTemplateResponse{}, err
+		}
+	}
+	return s.repo.CreateParserTemplate(ctx, input)
+}
+
+func (s *ParserTemplateService) GetParserTemplateById(ctx context.Context, templateId int64, userId int64) (models.ParserTemplateResponse, error) {
+	return s.repo.GetParserTemplateById(ctx, templateId, userId)
+}
+
+// GetAccountParserTemplate returns the template bound to an account, or nil
+// when statements of the account have no saved metadata.
+func (s *ParserTemplateService) GetAccountParserTemplate(ctx context.Context, accountId int64, userId int64) (*models.ParserTemplateResponse, error) {
+	return s.repo.GetParserTemplateByAccountId(ctx, accountId, userId)
+}
+
+func (s *ParserTemplateService) ListParserTemplates(ctx context.Context, userId int64) ([]models.ParserTemplateResponse, error) {
+	return s.repo.ListParserTemplates(ctx, userId)
+}
+
+func (s *ParserTemplateService) UpdateParserTemplate(ctx context.Context, templateId int64, userId int64, input models.UpdateParserTemplateInput) (models.ParserTemplateResponse, error) {
+	if input.Name == nil && input.AccountId == nil && input.Metadata == nil {
+		return models.ParserTemplateResponse{}, customErrors.NoFieldsToUpdateError()
+	}
+	if input.Metadata != nil {
+		if err := parser.ValidateStatementMetadata(string(*input.Metadata)); err != nil {
+			return models.ParserTemplateResponse{}, customErrors.NewParserTemplateInvalidMetadataError(err)
+		}
+	}
+	if input.AccountId != nil && *input.AccountId != 0 {
+		if _, err := s.accountService.GetAccountById(ctx, *input.AccountId, userId); err != nil {
+			return models.ParserTemplateResponse{}, err
+		}
+	}
+	return s.repo.UpdateParserTemplate(ctx, templateId, userId, input)
+}
+
+func (s *ParserTemplateService) DeleteParserTemplate(ctx context.Context, templateId int64, userId int64) error {
+	return s.repo.DeleteParserTemplate(ctx, templateId, userId)
+}
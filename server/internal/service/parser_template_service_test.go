@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	customErrors "expenses/internal/errors"
+	mock "expenses/internal/mock/repository"
+	"expenses/internal/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParserTemplateService", func() {
+	var (
+		templateService ParserTemplateServiceInterface
+		accountService  AccountServiceInterface
+		ctx             context.Context
+		userId          int64
+		accountId       int64
+		metadata        json.RawMessage
+	)
+
+	expectAuthError := func(err error, message string) {
+		var authErr *customErrors.AuthError
+		Expect(errors.As(err, &authErr)).To(BeTrue())
+		Expect(authErr.Message).To(Equal(message))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		userId = 1
+		accountService = NewAccountService(mock.NewMockAccountRepository())
+		templateService = NewParserTemplateService(mock.NewMockParserTemplateRepository(), accountService)
+		account, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+			Name:      "Co-op Bank",
+			BankType:  models.BankTypeOthers,
+			Currency:  models.CurrencyINR,
+			CreatedBy: userId,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		accountId = account.Id
+		metadata = json.RawMessage(`{"skip_rows": 1, "column_mapping": {"txn_date": "Date", "name": "Narration", "amount": "Amount"}}`)
+	})
+
+	Describe("CreateParserTemplate", func() {
+		It("should create a template bound to an account", func() {
+			template, err := templateService.CreateParserTemplate(ctx, models.CreateParserTemplateInput{
+				Name:      "Co-op export",
+				AccountId: &accountId,
+				Metadata:  metadata,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(template.Id).To(BeNumerically(">", 0))
+			Expect(*template.AccountId).To(Equal(accountId))
+
+			bound, err := templateService.GetAccountParserTemplate(ctx, accountId, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bound.Id).To(Equal(template.Id))
+		})
+
+		It("should reject metadata that does not map the required fields", func() {
+			_, err := templateService.CreateParserTemplate(ctx, models.CreateParserTemplateInput{
+				Name:      "Broken",
+				Metadata:  json.RawMessage(`{"column_mapping": {"name": "Narration"}}`),
+				CreatedBy: userId,
+			})
+			expectAuthError(err, "invalid parser template metadata")
+		})
+
+		It("should reject an account of another user", func() {
+			_, err := templateService.CreateParserTemplate(ctx, models.CreateParserTemplateInput{
+				Name:      "Someone else's",
+				AccountId: &accountId,
+				Metadata:  metadata,
+				CreatedBy: userId + 1,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should allow only one template per account", func() {
+			_, err := templateService.CreateParserTemplate(ctx, models.CreateParserTemplateInput{
+				Name: "First", AccountId: &accountId, Metadata: metadata, CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = templateService.CreateParserTemplate(ctx, models.CreateParserTemplateInput{
+				Name: "Second", AccountId: &accountId, Metadata: metadata, CreatedBy: userId,
+			})
+			expectAuthError(err, "account already has a parser template")
+		})
+	})
+
+	Describe("UpdateParserTemplate", func() {
+		var template models.ParserTemplateResponse
+
+		BeforeEach(func() {
+			var err error
+			template, err = templateService.CreateParserTemplate(ctx, models.CreateParserTemplateInput{
+				Name: "Co-op export", AccountId: &accountId, Metadata: metadata, CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should rename a template and replace its metadata", func() {
+			name := "Co-op export v2"
+			newMetadata := json.RawMessage(`{"column_mapping": {"txn_date": "Date", "name": "Narration", "credit": "Cr", "debit": "Dr"}}`)
+			updated, err := templateService.UpdateParserTemplate(ctx, template.Id, userId, models.UpdateParserTemplateInput{
+				Name:     &name,
+				Metadata: &newMetadata,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Name).To(Equal(name))
+			Expect(updated.Metadata).To(MatchJSON(newMetadata))
+			Expect(*updated.AccountId).To(Equal(accountId))
+		})
+
+		It("should unbind the template from its account", func() {
+			unbind := int64(0)
+			updated, err := templateService.UpdateParserTemplate(ctx, template.Id, userId, models.UpdateParserTemplateInput{AccountId: &unbind})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.AccountId).To(BeNil())
+
+			bound, err := templateService.GetAccountParserTemplate(ctx, accountId, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bound).To(BeNil())
+		})
+
+		It("should reject an update without fields", func() {
+			_, err := templateService.UpdateParserTemplate(ctx, template.Id, userId, models.UpdateParserTemplateInput{})
+			expectAuthError(err, "no fields to update")
+		})
+
+		It("should not update a template of another user", func() {
+			name := "Hijacked"
+			_, err := templateService.UpdateParserTemplate(ctx, template.Id, userId+1, models.UpdateParserTemplateInput{Name: &name})
+			expectAuthError(err, "parser template not found")
+		})
+	})
+
+	Describe("DeleteParserTemplate", func() {
+		It("should delete a template", func() {
+			template, err := templateService.CreateParserTemplate(ctx, models.CreateParserTemplateInput{
+				Name: "Temporary", Metadata: metadata, CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(templateService.DeleteParserTemplate(ctx, template.Id, userId)).To(Succeed())
+			_, err = templateService.GetParserTemplateById(ctx, template.Id, userId)
+			expectAuthError(err, "parser template not found")
+
+			templates, err := templateService.ListParserTemplates(ctx, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(templates).To(BeEmpty())
+		})
+	})
+})
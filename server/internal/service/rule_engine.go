@@ -2,8 +2,11 @@ package service
 
 import (
 	"expenses/internal/models"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type TransferInfo struct {
@@ -24,6 +27,10 @@ type RuleEngine struct {
 	categories map[int64]models.CategoryResponse
 	accounts   map[int64]models.AccountResponse
 	rules      []models.DescribeRuleResponse
+	// patterns holds the compiled pattern of every regex condition, keyed by
+	// the condition value. Patterns that do not compile are stored as nil and
+	// never match.
+	patterns map[string]*regexp.Regexp
 }
 
 func NewRuleEngine(categories []models.CategoryResponse, accounts []models.AccountResponse, rules []models.DescribeRuleResponse) *RuleEngine {
@@ -37,15 +44,49 @@ func NewRuleEngine(categories []models.CategoryResponse, accounts []models.Accou
 		accountMap[account.Id] = account
 	}
 
+	// Patterns are compiled once here rather than for every transaction the
+	// rules are evaluated against.
+	patterns := make(map[string]*regexp.Regexp)
+	for _, rule := range rules {
+		compilePatterns(patterns, rule.Conditions, rule.ConditionGroups)
+	}
+
+	// Rules run in ascending priority; rules with the same priority keep the
+	// order they were given in.
+	ordered := make([]models.DescribeRuleResponse, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Rule.Priority < ordered[j].Rule.Priority
+	})
+
 	return &RuleEngine{
 		categories: categoryMap,
 		accounts:   accountMap,
-		rules:      rules,
+		rules:      ordered,
+		patterns:   patterns,
 	}
 }
 
-// ProcessTransaction evaluates a transaction against all rules in the engine.
-// It returns a Changeset if any rule applies, otherwise it returns nil.
+// compilePatterns adds the patterns of the regex conditions in conditions and
+// in the nested groups to patterns.
+func compilePatterns(patterns map[string]*regexp.Regexp, conditions []models.RuleConditionResponse, groups []models.RuleConditionGroupResponse) {
+	for _, condition := range conditions {
+		if condition.ConditionOperator != models.OperatorRegex {
+			continue
+		}
+		if _, ok := patterns[condition.ConditionValue]; ok {
+			continue
+		}
+		patterns[condition.ConditionValue], _ = regexp.Compile("(?i)" + condition.ConditionValue)
+	}
+	for _, group := range groups {
+		compilePatterns(patterns, group.Conditions, group.Groups)
+	}
+}
+
+// ProcessTransaction evaluates a transaction against all rules in the engine in
+// priority order. It returns a Changeset if any rule applies, otherwise it
+// returns nil.
 func (e *RuleEngine) ProcessTransaction(transaction models.TransactionResponse) *Changeset {
 	changeset := &Changeset{
 		TransactionId: transaction.Id,
@@ -125,6 +166,12 @@ func (e *RuleEngine) ProcessTransaction(transaction models.TransactionResponse)
 		if ruleApplied {
 			changeset.AppliedRules = append(changeset.AppliedRules, rule.Rule.Id)
 		}
+
+		// A matching rule with stop processing set is the last rule to touch
+		// the transaction, whether or not it changed anything.
+		if rule.Rule.StopProcessing {
+			break
+		}
 	}
 
 	if !hasChanges {
@@ -135,36 +182,65 @@ func (e *RuleEngine) ProcessTransaction(transaction models.TransactionResponse)
 }
 
 // evaluateConditions dispatches condition evaluation based on the rule's logic.
+// The logic of the rule combines its top level conditions and condition groups.
 func (e *RuleEngine) evaluateConditions(rule models.DescribeRuleResponse, transaction models.TransactionResponse) bool {
-	if len(rule.Conditions) == 0 {
+	if len(rule.Conditions) == 0 && len(rule.ConditionGroups) == 0 {
 		return false // A rule must have at least one condition.
 	}
 
 	if rule.Rule.ConditionLogic == models.ConditionLogicOr {
-		return e.evaluateOrConditions(rule.Conditions, transaction)
+		return e.evaluateOrConditions(rule.Conditions, rule.ConditionGroups, transaction)
 	}
 
 	// Default to AND logic
-	return e.evaluateAndConditions(rule.Conditions, transaction)
+	return e.evaluateAndConditions(rule.Conditions, rule.ConditionGroups, transaction)
+}
+
+// evaluateGroup evaluates a condition group with its own logic. Empty groups
+// never match.
+func (e *RuleEngine) evaluateGroup(group models.RuleConditionGroupResponse, transaction models.TransactionResponse) bool {
+	if len(group.Conditions) == 0 && len(group.Groups) == 0 {
+		return false
+	}
+
+	switch group.Logic {
+	case models.ConditionLogicOr:
+		return e.evaluateOrConditions(group.Conditions, group.Groups, transaction)
+	case models.ConditionLogicNot:
+		// NOT matches when none of its children match.
+		return !e.evaluateOrConditions(group.Conditions, group.Groups, transaction)
+	default:
+		return e.evaluateAndConditions(group.Conditions, group.Groups, transaction)
+	}
 }
 
-// evaluateAndConditions checks if all conditions are met for a transaction.
-func (e *RuleEngine) evaluateAndConditions(conditions []models.RuleConditionResponse, transaction models.TransactionResponse) bool {
+// evaluateAndConditions checks if all conditions and groups are met for a transaction.
+func (e *RuleEngine) evaluateAndConditions(conditions []models.RuleConditionResponse, groups []models.RuleConditionGroupResponse, transaction models.TransactionResponse) bool {
 	for _, condition := range conditions {
 		if !e.evaluateCondition(condition, transaction) {
 			return false // For AND, if any condition is false, the whole thing is false.
 		}
 	}
+	for _, group := range groups {
+		if !e.evaluateGroup(group, transaction) {
+			return false
+		}
+	}
 	return true // If loop finishes, all conditions were met.
 }
 
-// evaluateOrConditions checks if at least one condition is met for a transaction.
-func (e *RuleEngine) evaluateOrConditions(conditions []models.RuleConditionResponse, transaction models.TransactionResponse) bool {
+// evaluateOrConditions checks if at least one condition or group is met for a transaction.
+func (e *RuleEngine) evaluateOrConditions(conditions []models.RuleConditionResponse, groups []models.RuleConditionGroupResponse, transaction models.TransactionResponse) bool {
 	for _, condition := range conditions {
 		if e.evaluateCondition(condition, transaction) {
 			return true // For OR, if any condition is true, the whole thing is true.
 		}
 	}
+	for _, group := range groups {
+		if e.evaluateGroup(group, transaction) {
+			return true
+		}
+	}
 	return false // If loop finishes, no conditions were met.
 }
 
@@ -175,6 +251,14 @@ func (e *RuleEngine) evaluateCondition(condition models.RuleConditionResponse, t
 		return e.evaluateCategoryCondition(condition, transaction.CategoryIds)
 	case models.RuleFieldTransfer:
 		return e.evaluateTransferCondition(condition, transaction.AccountId)
+	case models.RuleFieldAccount:
+		return e.evaluateAccountCondition(condition, transaction.AccountId)
+	case models.RuleFieldDate:
+		return e.evaluateDateCondition(condition, transaction.Date)
+	case models.RuleFieldDayOfMonth:
+		return e.evaluateDayOfMonthCondition(condition, transaction.Date.Day())
+	case models.RuleFieldWeekday:
+		return e.evaluateWeekdayCondition(condition, transaction.Date.Weekday())
 	default:
 		return e.evaluateStandardFieldCondition(condition, transaction)
 	}
@@ -206,6 +290,8 @@ func (e *RuleEngine) evaluateAmountCondition(condition models.RuleConditionRespo
 	switch condition.ConditionOperator {
 	case models.OperatorEquals:
 		return amount == conditionAmount
+	case models.OperatorNotEquals:
+		return amount != conditionAmount
 	case models.OperatorGreater:
 		return amount > conditionAmount
 	case models.OperatorLower:
@@ -214,12 +300,26 @@ func (e *RuleEngine) evaluateAmountCondition(condition models.RuleConditionRespo
 	return false
 }
 
+// evaluateStringCondition compares text fields ignoring case.
 func (e *RuleEngine) evaluateStringCondition(condition models.RuleConditionResponse, value string) bool {
+	lowerValue := strings.ToLower(value)
+	lowerCondition := strings.ToLower(condition.ConditionValue)
 	switch condition.ConditionOperator {
 	case models.OperatorEquals:
 		return strings.EqualFold(value, condition.ConditionValue)
+	case models.OperatorNotEquals:
+		return !strings.EqualFold(value, condition.ConditionValue)
 	case models.OperatorContains:
-		return strings.Contains(strings.ToLower(value), strings.ToLower(condition.ConditionValue))
+		return strings.Contains(lowerValue, lowerCondition)
+	case models.OperatorNotContains:
+		return !strings.Contains(lowerValue, lowerCondition)
+	case models.OperatorStartsWith:
+		return strings.HasPrefix(lowerValue, lowerCondition)
+	case models.OperatorEndsWith:
+		return strings.HasSuffix(lowerValue, lowerCondition)
+	case models.OperatorRegex:
+		pattern := e.patterns[condition.ConditionValue]
+		return pattern != nil && pattern.MatchString(value)
 	}
 	return false
 }
@@ -230,8 +330,11 @@ func (e *RuleEngine) evaluateCategoryCondition(condition models.RuleConditionRes
 		return false
 	}
 
-	if condition.ConditionOperator == models.OperatorEquals {
+	switch condition.ConditionOperator {
+	case models.OperatorEquals:
 		return e.hasCategory(categoryIds, conditionCategoryId)
+	case models.OperatorNotEquals:
+		return !e.hasCategory(categoryIds, conditionCategoryId)
 	}
 	return false
 }
@@ -242,8 +345,88 @@ func (e *RuleEngine) evaluateTransferCondition(condition models.RuleConditionRes
 		return false
 	}
 
-	if condition.ConditionOperator == models.OperatorEquals {
+	switch condition.ConditionOperator {
+	case models.OperatorEquals:
 		return accountId == conditionAccountId
+	case models.OperatorNotEquals:
+		return accountId != conditionAccountId
+	}
+	return false
+}
+
+func (e *RuleEngine) evaluateAccountCondition(condition models.RuleConditionResponse, accountId int64) bool {
+	conditionAccountId, err := strconv.ParseInt(condition.ConditionValue, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	switch condition.ConditionOperator {
+	case models.OperatorEquals:
+		return accountId == conditionAccountId
+	case models.OperatorNotEquals:
+		return accountId != conditionAccountId
+	}
+	return false
+}
+
+// evaluateDateCondition compares the day of the transaction, ignoring its
+// time, with the dates of the condition.
+func (e *RuleEngine) evaluateDateCondition(condition models.RuleConditionResponse, date time.Time) bool {
+	var bounds []time.Time
+	for _, part := range strings.Split(condition.ConditionValue, ",") {
+		bound, err := time.Parse(models.RuleDateLayout, strings.TrimSpace(part))
+		if err != nil {
+			return false
+		}
+		bounds = append(bounds, bound)
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch condition.ConditionOperator {
+	case models.OperatorBefore:
+		return len(bounds) == 1 && day.Before(bounds[0])
+	case models.OperatorAfter:
+		return len(bounds) == 1 && day.After(bounds[0])
+	case models.OperatorBetween:
+		return len(bounds) == 2 && !day.Before(bounds[0]) && !day.After(bounds[1])
+	}
+	return false
+}
+
+func (e *RuleEngine) evaluateDayOfMonthCondition(condition models.RuleConditionResponse, day int) bool {
+	var bounds []int
+	for _, part := range strings.Split(condition.ConditionValue, ",") {
+		bound, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return false
+		}
+		bounds = append(bounds, bound)
+	}
+
+	switch condition.ConditionOperator {
+	case models.OperatorEquals:
+		return len(bounds) == 1 && day == bounds[0]
+	case models.OperatorNotEquals:
+		return len(bounds) == 1 && day != bounds[0]
+	case models.OperatorGreater:
+		return len(bounds) == 1 && day > bounds[0]
+	case models.OperatorLower:
+		return len(bounds) == 1 && day < bounds[0]
+	case models.OperatorBetween:
+		return len(bounds) == 2 && day >= bounds[0] && day <= bounds[1]
+	}
+	return false
+}
+
+// evaluateWeekdayCondition compares the weekday of the transaction with a
+// weekday named in English, such as monday.
+func (e *RuleEngine) evaluateWeekdayCondition(condition models.RuleConditionResponse, weekday time.Weekday) bool {
+	matches := strings.EqualFold(weekday.String(), strings.TrimSpace(condition.ConditionValue))
+	switch condition.ConditionOperator {
+	case models.OperatorEquals:
+		return matches
+	case models.OperatorNotEquals:
+		return !matches
 	}
 	return false
 }
@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"expenses/internal/errors"
 	"expenses/internal/models"
 	"expenses/internal/repository"
+	"expenses/internal/validator"
 	"expenses/pkg/logger"
 	"fmt"
 	"time"
@@ -11,7 +14,8 @@ import (
 
 type RuleEngineServiceInterface interface {
 	ExecuteRules(ctx context.Context, userId int64, request models.ExecuteRulesRequest) (models.ExecuteRulesResponse, error)
-	ExecuteRulesInBackground(ctx context.Context, userId int64, request models.ExecuteRulesRequest)
+	ExecuteStatementRules(ctx context.Context, userId int64, statementId int64, transactionIds []int64) error
+	PreviewRules(ctx context.Context, userId int64, request models.PreviewRulesRequest) (models.PreviewRulesResponse, error)
 }
 
 type ruleEngineService struct {
@@ -19,6 +23,9 @@ type ruleEngineService struct {
 	transactionRepo repository.TransactionRepositoryInterface
 	categoryRepo    repository.CategoryRepositoryInterface
 	accountRepo     repository.AccountRepositoryInterface
+	jobQueue        JobQueueInterface
+	eventBus        StatementEventBusInterface
+	validator       *validator.RuleValidator
 }
 
 func NewRuleEngineService(
@@ -26,36 +33,243 @@ func NewRuleEngineService(
 	transactionRepo repository.TransactionRepositoryInterface,
 	categoryRepo repository.CategoryRepositoryInterface,
 	accountRepo repository.AccountRepositoryInterface,
+	jobQueue JobQueueInterface,
+	eventBus StatementEventBusInterface,
 ) RuleEngineServiceInterface {
-	return &ruleEngineService{
+	s := &ruleEngineService{
 		ruleRepo:        ruleRepo,
 		transactionRepo: transactionRepo,
 		categoryRepo:    categoryRepo,
 		accountRepo:     accountRepo,
+		jobQueue:        jobQueue,
+		eventBus:        eventBus,
+		validator:       &validator.RuleValidator{},
 	}
+	jobQueue.RegisterHandler(models.JobTypeExecuteRules, s.handleExecuteRulesJob)
+	return s
 }
 
+// ExecuteRules queues a rule run for the user. The rules are applied by a job
+// worker, so the response is always empty.
 func (s *ruleEngineService) ExecuteRules(ctx context.Context, userId int64, request models.ExecuteRulesRequest) (models.ExecuteRulesResponse, error) {
-	go s.ExecuteRulesInBackground(context.Background(), userId, request)
-	logger.Infof("Rule execution started in background for user %d", userId)
+	_, err := s.jobQueue.Enqueue(ctx, models.JobTypeExecuteRules, nil, models.ExecuteRulesJobPayload{
+		UserId:  userId,
+		Request: request,
+	})
+	if err != nil {
+		return models.ExecuteRulesResponse{}, err
+	}
+	logger.Infof("Rule execution queued for user %d", userId)
 	return models.ExecuteRulesResponse{}, nil
 }
 
-func (s *ruleEngineService) ExecuteRulesInBackground(ctx context.Context, userId int64, request models.ExecuteRulesRequest) {
+// ExecuteStatementRules queues a rule run for the transactions imported from
+// a statement. Watchers of the statement get a rules event once it finishes.
+// A statement that imported nothing has no rules to run, and an empty list
+// would otherwise run the rules over all of the user's transactions.
+func (s *ruleEngineService) ExecuteStatementRules(ctx context.Context, userId int64, statementId int64, transactionIds []int64) error {
+	if len(transactionIds) == 0 {
+		s.publishRulesEvent(statementId, models.Job{}, models.ExecuteRulesResponse{}, nil)
+		return nil
+	}
+	_, err := s.jobQueue.Enqueue(ctx, models.JobTypeExecuteRules, &statementId, models.ExecuteRulesJobPayload{
+		UserId:      userId,
+		Request:     models.ExecuteRulesRequest{TransactionIds: &transactionIds},
+		StatementId: &statementId,
+	})
+	if err != nil {
+		return err
+	}
+	logger.Infof("Rule execution queued for statement %d of user %d", statementId, userId)
+	return nil
+}
+
+// defaultPreviewLimit is the number of most recent transactions rules are
+// previewed against when the request names neither transactions nor a limit.
+const defaultPreviewLimit = 100
+
+// PreviewRules evaluates an unsaved rule, or saved rules, against a bounded set
+// of transactions and returns the changes they would make without applying
+// them.
+func (s *ruleEngineService) PreviewRules(ctx context.Context, userId int64, request models.PreviewRulesRequest) (models.PreviewRulesResponse, error) {
+	response := models.PreviewRulesResponse{Changesets: []models.RuleChangeset{}}
+	if request.Rule != nil && request.RuleIds != nil && len(*request.RuleIds) > 0 {
+		return response, errors.NewRuleInvalidPreviewError(fmt.Errorf("either rule or rule_ids can be set"))
+	}
+
+	var rules []models.DescribeRuleResponse
+	var err error
+	switch {
+	case request.Rule != nil:
+		if err := s.validator.Validate(*request.Rule); err != nil {
+			return response, err
+		}
+		rules = []models.DescribeRuleResponse{unsavedRule(*request.Rule)}
+	case request.RuleIds != nil && len(*request.RuleIds) > 0:
+		rules, err = s.fetchSpecificRules(ctx, userId, *request.RuleIds)
+	default:
+		rules, err = s.fetchAllUserRules(ctx, userId)
+	}
+	if err != nil {
+		return response, fmt.Errorf("rule preview for user %d failed to fetch rules: %w", userId, err)
+	}
+	response.TotalRules = len(rules)
+	if len(rules) == 0 {
+		return response, nil
+	}
+
+	categories, err := s.categoryRepo.ListCategories(ctx, userId)
+	if err != nil {
+		return response, fmt.Errorf("rule preview for user %d failed to fetch categories: %w", userId, err)
+	}
+	accounts, err := s.accountRepo.ListAccounts(ctx, userId)
+	if err != nil {
+		return response, fmt.Errorf("rule preview for user %d failed to fetch accounts: %w", userId, err)
+	}
+
+	var transactions []models.TransactionResponse
+	if request.TransactionIds != nil && len(*request.TransactionIds) > 0 {
+		transactions, err = s.fetchSpecificTransactions(ctx, userId, *request.TransactionIds)
+	} else {
+		limit := request.Limit
+		if limit <= 0 {
+			limit = defaultPreviewLimit
+		}
+		transactions, err = s.fetchTransactionPage(ctx, userId, 1, limit)
+	}
+	if err != nil {
+		return response, fmt.Errorf("rule preview for user %d failed to fetch transactions: %w", userId, err)
+	}
+
+	engine := NewRuleEngine(categories, accounts, rules)
+	for _, changeset := range s.processTransactions(engine, transactions) {
+		response.Changesets = append(response.Changesets, s.toRuleChangeset(changeset))
+	}
+	response.ProcessedTxns = len(transactions)
+	return response, nil
+}
+
+// unsavedRule describes a rule that has not been created, so it can be run by
+// the rule engine. Its id is 0.
+func unsavedRule(req models.CreateRuleRequest) models.DescribeRuleResponse {
+	rule := models.DescribeRuleResponse{
+		Rule: models.RuleResponse{
+			Name:           req.Rule.Name,
+			Description:    req.Rule.Description,
+			ConditionLogic: models.ConditionLogicAnd,
+			EffectiveFrom:  req.Rule.EffectiveFrom,
+			StopProcessing: req.Rule.StopProcessing,
+			CreatedBy:      req.Rule.CreatedBy,
+		},
+		Actions:         make([]models.RuleActionResponse, 0, len(req.Actions)),
+		Conditions:      unsavedConditions(req.Conditions),
+		ConditionGroups: unsavedConditionGroups(req.ConditionGroups),
+	}
+	if req.Rule.ConditionLogic != nil {
+		rule.Rule.ConditionLogic = *req.Rule.ConditionLogic
+	}
+	for _, action := range req.Actions {
+		rule.Actions = append(rule.Actions, models.RuleActionResponse{
+			ActionType:  action.ActionType,
+			ActionValue: action.ActionValue,
+		})
+	}
+	return rule
+}
+
+func unsavedConditions(conditions []models.CreateRuleConditionRequest) []models.RuleConditionResponse {
+	result := make([]models.RuleConditionResponse, 0, len(conditions))
+	for _, condition := range conditions {
+		result = append(result, models.RuleConditionResponse{
+			ConditionType:     condition.ConditionType,
+			ConditionValue:    condition.ConditionValue,
+			ConditionOperator: condition.ConditionOperator,
+		})
+	}
+	return result
+}
+
+func unsavedConditionGroups(groups []models.CreateRuleConditionGroupRequest) []models.RuleConditionGroupResponse {
+	result := make([]models.RuleConditionGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, models.RuleConditionGroupResponse{
+			Logic:      group.Logic,
+			Conditions: unsavedConditions(group.Conditions),
+			Groups:     unsavedConditionGroups(group.Groups),
+		})
+	}
+	return result
+}
+
+func (s *ruleEngineService) toRuleChangeset(changeset *Changeset) models.RuleChangeset {
+	result := models.RuleChangeset{
+		TransactionId:     changeset.TransactionId,
+		NameUpdate:        changeset.NameUpdate,
+		DescriptionUpdate: changeset.DescUpdate,
+		CategoryAdds:      changeset.CategoryAdds,
+		AppliedRules:      changeset.AppliedRules,
+		UpdatedFields:     s.getUpdatedFields(changeset),
+	}
+	if changeset.TransferInfo != nil {
+		result.Transfer = &models.RuleTransfer{
+			AccountId: changeset.TransferInfo.AccountId,
+			Amount:    changeset.TransferInfo.Amount,
+		}
+	}
+	return result
+}
+
+func (s *ruleEngineService) handleExecuteRulesJob(ctx context.Context, job models.Job) error {
+	var payload models.ExecuteRulesJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		// A payload that cannot be read will not get better on retry.
+		logger.Errorf("Dropping rule execution job %d with invalid payload: %v", job.Id, err)
+		return nil
+	}
+	result, err := s.executeRules(ctx, payload.UserId, payload.Request)
+	if payload.StatementId != nil {
+		s.publishRulesEvent(*payload.StatementId, job, result, err)
+	}
+	return err
+}
+
+// publishRulesEvent tells watchers of a statement how the rule run for its
+// transactions ended. Failures are only reported once no retry follows.
+func (s *ruleEngineService) publishRulesEvent(statementId int64, job models.Job, result models.ExecuteRulesResponse, err error) {
+	event := models.StatementEvent{
+		Type:        models.StatementEventRules,
+		StatementId: statementId,
+		Status:      models.StatementStatusDone,
+	}
+	if err != nil {
+		if !job.IsLastAttempt() {
+			return
+		}
+		msg := fmt.Sprintf("Failed to apply rules: %v", err)
+		event.Status = models.StatementStatusError
+		event.Message = &msg
+	} else {
+		modified := len(result.Modified)
+		event.ModifiedCount = &modified
+	}
+	s.eventBus.Publish(event)
+}
+
+// executeRules applies the user's rules to their transactions. Errors from
+// loading data are returned so the job is retried.
+func (s *ruleEngineService) executeRules(ctx context.Context, userId int64, request models.ExecuteRulesRequest) (models.ExecuteRulesResponse, error) {
 	logger.Infof("Executing rules for user %d", userId)
 
 	// Step 1: Fetch all categories
 	categories, err := s.categoryRepo.ListCategories(ctx, userId)
 	if err != nil {
-		logger.Errorf("Rule execution for user %d failed to fetch categories: %v", userId, err)
-		return
+		return models.ExecuteRulesResponse{}, fmt.Errorf("rule execution for user %d failed to fetch categories: %w", userId, err)
 	}
 
 	// Step 1.5: Fetch all accounts
 	accounts, err := s.accountRepo.ListAccounts(ctx, userId)
 	if err != nil {
-		logger.Errorf("Rule execution for user %d failed to fetch accounts: %v", userId, err)
-		return
+		return models.ExecuteRulesResponse{}, fmt.Errorf("rule execution for user %d failed to fetch accounts: %w", userId, err)
 	}
 
 	// Step 2: Fetch rules - use specific rules if provided, otherwise fetch all
@@ -66,13 +280,12 @@ func (s *ruleEngineService) ExecuteRulesInBackground(ctx context.Context, userId
 		rules, err = s.fetchAllUserRules(ctx, userId)
 	}
 	if err != nil {
-		logger.Errorf("Rule execution for user %d failed to fetch rules: %v", userId, err)
-		return
+		return models.ExecuteRulesResponse{}, fmt.Errorf("rule execution for user %d failed to fetch rules: %w", userId, err)
 	}
 
 	if len(rules) == 0 {
 		logger.Infof("No rules found for user %d, skipping execution.", userId)
-		return
+		return models.ExecuteRulesResponse{}, nil
 	}
 
 	// Create rule engine with categories, accounts and rules
@@ -90,8 +303,7 @@ func (s *ruleEngineService) ExecuteRulesInBackground(ctx context.Context, userId
 	if request.TransactionIds != nil && len(*request.TransactionIds) > 0 {
 		transactions, err := s.fetchSpecificTransactions(ctx, userId, *request.TransactionIds)
 		if err != nil {
-			logger.Errorf("Rule execution for user %d failed to fetch specific transactions: %v", userId, err)
-			return
+			return models.ExecuteRulesResponse{}, fmt.Errorf("rule execution for user %d failed to fetch specific transactions: %w", userId, err)
 		}
 
 		changesets := s.processTransactions(engine, transactions)
@@ -102,8 +314,7 @@ func (s *ruleEngineService) ExecuteRulesInBackground(ctx context.Context, userId
 		for {
 			transactions, err := s.fetchTransactionPage(ctx, userId, page, pageSize)
 			if err != nil {
-				logger.Errorf("Rule execution for user %d failed to fetch transactions page %d: %v", userId, page, err)
-				return
+				return models.ExecuteRulesResponse{}, fmt.Errorf("rule execution for user %d failed to fetch transactions page %d: %w", userId, page, err)
 			}
 
 			if len(transactions) == 0 {
@@ -124,12 +335,16 @@ func (s *ruleEngineService) ExecuteRulesInBackground(ctx context.Context, userId
 	// Step 4: Apply changesets
 	modified, err := s.applyChangesets(ctx, userId, allChangesets)
 	if err != nil {
-		logger.Errorf("Rule execution for user %d failed to apply changesets: %v", userId, err)
-		return
+		return models.ExecuteRulesResponse{}, fmt.Errorf("rule execution for user %d failed to apply changesets: %w", userId, err)
 	}
 
 	logger.Infof("Rule execution completed for user %d: %d modified, %d total processed",
 		userId, len(modified), totalProcessed)
+	return models.ExecuteRulesResponse{
+		Modified:      modified,
+		TotalRules:    len(rules),
+		ProcessedTxns: totalProcessed,
+	}, nil
 }
 
 func (s *ruleEngineService) buildRuleResponse(ctx context.Context, rule models.RuleResponse) (*models.DescribeRuleResponse, error) {
@@ -143,10 +358,17 @@ func (s *ruleEngineService) buildRuleResponse(ctx context.Context, rule models.R
 		return nil, fmt.Errorf("failed to get conditions for rule %d: %w", rule.Id, err)
 	}
 
+	groups, err := s.ruleRepo.ListRuleConditionGroupsByRuleId(ctx, rule.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get condition groups for rule %d: %w", rule.Id, err)
+	}
+	topLevel, conditionGroups := nestConditionGroups(conditions, groups)
+
 	return &models.DescribeRuleResponse{
-		Rule:       rule,
-		Actions:    actions,
-		Conditions: conditions,
+		Rule:            rule,
+		Actions:         actions,
+		Conditions:      topLevel,
+		ConditionGroups: conditionGroups,
 	}, nil
 }
 
@@ -304,6 +526,9 @@ func (s *ruleEngineService) createTransferTransaction(ctx context.Context, userI
 			Date:        originalTransaction.Date,
 			CreatedBy:   userId,
 			AccountId:   transferInfo.AccountId,
+			// Link the transfer to its source so that reverting the source
+			// (e.g. undoing a statement import) removes it as well.
+			SourceTransactionId: &originalTransaction.Id,
 		},
 		CategoryIds: originalTransaction.CategoryIds, // Inherit categories from original transaction
 	}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	mock_database "expenses/internal/mock/database"
 	repository "expenses/internal/mock/repository"
 	"expenses/internal/models"
 	"fmt"
@@ -18,6 +19,7 @@ var _ = Describe("RuleEngineService", func() {
 		mockTxnRepo      *repository.MockTransactionRepository
 		mockCategoryRepo *repository.MockCategoryRepository
 		mockAccountRepo  *repository.MockAccountRepository
+		jobQueue         JobQueueInterface
 		ctx              context.Context
 		userId           int64
 	)
@@ -32,7 +34,13 @@ var _ = Describe("RuleEngineService", func() {
 		mockCategoryRepo = repository.NewMockCategoryRepository()
 		mockAccountRepo = repository.NewMockAccountRepository()
 
-		service = NewRuleEngineService(mockRuleRepo, mockTxnRepo, mockCategoryRepo, mockAccountRepo)
+		jobQueue = NewJobQueue(repository.NewMockJobRepository(), testJobConfig())
+		service = NewRuleEngineService(mockRuleRepo, mockTxnRepo, mockCategoryRepo, mockAccountRepo, jobQueue, NewStatementEventBus())
+		jobQueue.Start()
+	})
+
+	AfterEach(func() {
+		jobQueue.Stop()
 	})
 
 	Describe("ExecuteRules - Basic Cases", func() {
@@ -266,6 +274,125 @@ var _ = Describe("RuleEngineService", func() {
 		})
 	})
 
+	Describe("ExecuteStatementRules", func() {
+		It("should not queue a rule run for a statement that imported nothing", func() {
+			jobRepo := repository.NewMockJobRepository()
+			eventBus := NewStatementEventBus()
+			ruleEngine := NewRuleEngineService(mockRuleRepo, mockTxnRepo, mockCategoryRepo, mockAccountRepo, NewJobQueue(jobRepo, testJobConfig()), eventBus)
+			events, unsubscribe := eventBus.Subscribe(7)
+			defer unsubscribe()
+
+			err := ruleEngine.ExecuteStatementRules(ctx, userId, 7, []int64{})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, queued := jobRepo.GetJob(1)
+			Expect(queued).To(BeFalse())
+			var event models.StatementEvent
+			Eventually(events).Should(Receive(&event))
+			Expect(event.Type).To(Equal(models.StatementEventRules))
+			Expect(event.Status).To(Equal(models.StatementStatusDone))
+			Expect(*event.ModifiedCount).To(Equal(0))
+		})
+	})
+
+	Describe("PreviewRules", func() {
+		var (
+			grocery models.TransactionResponse
+			rent    models.TransactionResponse
+		)
+		createTransaction := func(name string, amount float64) models.TransactionResponse {
+			txn, err := mockTxnRepo.CreateTransaction(ctx, models.CreateBaseTransactionInput{
+				Name:      name,
+				Amount:    &amount,
+				Date:      time.Now(),
+				CreatedBy: userId,
+				AccountId: 1,
+			}, []int64{})
+			Expect(err).NotTo(HaveOccurred())
+			return txn
+		}
+		groceryRule := func() *models.CreateRuleRequest {
+			return &models.CreateRuleRequest{
+				Rule: models.CreateBaseRuleRequest{
+					Name:          "Groceries",
+					EffectiveFrom: time.Now().Add(-48 * time.Hour),
+					CreatedBy:     userId,
+				},
+				Actions: []models.CreateRuleActionRequest{
+					{ActionType: models.RuleFieldName, ActionValue: "Groceries"},
+				},
+				Conditions: []models.CreateRuleConditionRequest{
+					{ConditionType: models.RuleFieldName, ConditionValue: "grocery", ConditionOperator: models.OperatorContains},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			grocery = createTransaction("Grocery Store", 50)
+			rent = createTransaction("Rent", 1000)
+		})
+
+		It("should preview an unsaved rule without changing transactions", func() {
+			response, err := service.PreviewRules(ctx, userId, models.PreviewRulesRequest{Rule: groceryRule()})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.TotalRules).To(Equal(1))
+			Expect(response.ProcessedTxns).To(Equal(2))
+			Expect(response.Changesets).To(HaveLen(1))
+			Expect(response.Changesets[0].TransactionId).To(Equal(grocery.Id))
+			Expect(*response.Changesets[0].NameUpdate).To(Equal("Groceries"))
+			Expect(response.Changesets[0].AppliedRules).To(Equal([]int64{0}))
+			Expect(response.Changesets[0].UpdatedFields).To(Equal([]models.RuleFieldType{models.RuleFieldName}))
+
+			unchanged, err := mockTxnRepo.GetTransactionById(ctx, grocery.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unchanged.Name).To(Equal("Grocery Store"))
+		})
+
+		It("should preview saved rules by id", func() {
+			created, err := NewRuleService(mockRuleRepo, mockTxnRepo, mock_database.NewMockDatabaseManager()).CreateRule(ctx, *groceryRule())
+			Expect(err).NotTo(HaveOccurred())
+
+			ruleIds := []int64{created.Rule.Id}
+			txnIds := []int64{grocery.Id, rent.Id}
+			response, err := service.PreviewRules(ctx, userId, models.PreviewRulesRequest{RuleIds: &ruleIds, TransactionIds: &txnIds})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Changesets).To(HaveLen(1))
+			Expect(response.Changesets[0].AppliedRules).To(Equal([]int64{created.Rule.Id}))
+		})
+
+		It("should only evaluate the most recent transactions up to the limit", func() {
+			response, err := service.PreviewRules(ctx, userId, models.PreviewRulesRequest{Rule: groceryRule(), Limit: 1})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.ProcessedTxns).To(Equal(1))
+		})
+
+		It("should reject an invalid unsaved rule", func() {
+			rule := groceryRule()
+			rule.Conditions[0].ConditionOperator = models.OperatorGreater
+
+			_, err := service.PreviewRules(ctx, userId, models.PreviewRulesRequest{Rule: rule})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject a request with both a rule and rule ids", func() {
+			ruleIds := []int64{1}
+			_, err := service.PreviewRules(ctx, userId, models.PreviewRulesRequest{Rule: groceryRule(), RuleIds: &ruleIds})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("either rule or rule_ids"))
+		})
+
+		It("should return no changesets when the user has no rules", func() {
+			response, err := service.PreviewRules(ctx, userId, models.PreviewRulesRequest{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.TotalRules).To(Equal(0))
+			Expect(response.Changesets).To(BeEmpty())
+		})
+	})
+
 	Describe("Helper Functions", func() {
 		Describe("getUpdatedFields", func() {
 			It("should return correct updated fields", func() {
@@ -432,6 +432,71 @@ var _ = Describe("RuleEngine", func() {
 				Expect(result).To(BeNil())
 			})
 		})
+
+		Context("text operators", func() {
+			nameRule := func(op models.RuleOperator, value string) []models.DescribeRuleResponse {
+				return []models.DescribeRuleResponse{
+					{
+						Rule: models.RuleResponse{
+							Id:            1,
+							Name:          "Name rule",
+							EffectiveFrom: time.Now().Add(-24 * time.Hour),
+						},
+						Conditions: []models.RuleConditionResponse{
+							{
+								ConditionType:     models.RuleFieldName,
+								ConditionValue:    value,
+								ConditionOperator: op,
+							},
+						},
+						Actions: []models.RuleActionResponse{
+							{
+								ActionType:  models.RuleFieldCategory,
+								ActionValue: "1",
+							},
+						},
+					},
+				}
+			}
+			matches := func(op models.RuleOperator, value string, name string) bool {
+				engine = NewRuleEngine(categories, []models.AccountResponse{}, nameRule(op, value))
+				transaction.Name = name
+				return engine.ProcessTransaction(transaction) != nil
+			}
+
+			It("should match starts_with and ends_with ignoring case", func() {
+				Expect(matches(models.OperatorStartsWith, "upi/", "UPI/123/SWIGGY")).To(BeTrue())
+				Expect(matches(models.OperatorStartsWith, "swiggy", "UPI/123/SWIGGY")).To(BeFalse())
+				Expect(matches(models.OperatorEndsWith, "Swiggy", "UPI/123/SWIGGY")).To(BeTrue())
+				Expect(matches(models.OperatorEndsWith, "upi", "UPI/123/SWIGGY")).To(BeFalse())
+			})
+
+			It("should match not_contains and not_equals as exclusions", func() {
+				Expect(matches(models.OperatorNotContains, "refund", "Amazon order")).To(BeTrue())
+				Expect(matches(models.OperatorNotContains, "REFUND", "Amazon refund")).To(BeFalse())
+				Expect(matches(models.OperatorNotEquals, "amazon", "Amazon order")).To(BeTrue())
+				Expect(matches(models.OperatorNotEquals, "amazon order", "Amazon Order")).To(BeFalse())
+			})
+
+			It("should match regex patterns ignoring case", func() {
+				Expect(matches(models.OperatorRegex, "UPI/.*/SWIGGY", "upi/9876/swiggy bangalore")).To(BeTrue())
+				Expect(matches(models.OperatorRegex, `^NEFT-\d{4}$`, "NEFT-1234")).To(BeTrue())
+				Expect(matches(models.OperatorRegex, "UPI/.*/SWIGGY", "UPI/9876/ZOMATO")).To(BeFalse())
+			})
+
+			It("should never match a regex pattern that does not compile", func() {
+				Expect(matches(models.OperatorRegex, "UPI/(.*", "UPI/(.*")).To(BeFalse())
+			})
+
+			It("should compile each regex pattern once", func() {
+				rules = append(nameRule(models.OperatorRegex, "swiggy|zomato"), nameRule(models.OperatorRegex, "swiggy|zomato")...)
+				rules = append(rules, nameRule(models.OperatorRegex, "[bad")...)
+				engine = NewRuleEngine(categories, []models.AccountResponse{}, rules)
+				Expect(engine.patterns).To(HaveLen(2))
+				Expect(engine.patterns["swiggy|zomato"]).NotTo(BeNil())
+				Expect(engine.patterns["[bad"]).To(BeNil())
+			})
+		})
 	})
 
 	Describe("Condition Evaluation - Description", func() {
@@ -550,6 +615,99 @@ var _ = Describe("RuleEngine", func() {
 			result := engine.ProcessTransaction(transaction)
 			Expect(result).To(BeNil())
 		})
+
+		It("should match transactions without the category for not_equals", func() {
+			rules[0].Conditions[0].ConditionOperator = models.OperatorNotEquals
+			engine = NewRuleEngine(categories, []models.AccountResponse{}, rules)
+
+			Expect(engine.ProcessTransaction(transaction)).To(BeNil())
+			transaction.CategoryIds = []int64{2}
+			result := engine.ProcessTransaction(transaction)
+			Expect(result).NotTo(BeNil())
+			Expect(result.CategoryAdds).To(ContainElement(int64(3)))
+		})
+	})
+
+	Describe("Condition Evaluation - Account and Date", func() {
+		ruleWith := func(conditions ...models.RuleConditionResponse) []models.DescribeRuleResponse {
+			return []models.DescribeRuleResponse{
+				{
+					Rule: models.RuleResponse{
+						Id:            1,
+						Name:          "Rent rule",
+						EffectiveFrom: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					},
+					Conditions: conditions,
+					Actions: []models.RuleActionResponse{
+						{
+							ActionType:  models.RuleFieldCategory,
+							ActionValue: "1",
+						},
+					},
+				},
+			}
+		}
+		condition := func(conditionType models.RuleFieldType, op models.RuleOperator, value string) models.RuleConditionResponse {
+			return models.RuleConditionResponse{
+				ConditionType:     conditionType,
+				ConditionValue:    value,
+				ConditionOperator: op,
+			}
+		}
+		matchesOn := func(date time.Time, conditions ...models.RuleConditionResponse) bool {
+			engine = NewRuleEngine(categories, []models.AccountResponse{}, ruleWith(conditions...))
+			transaction.Date = date
+			return engine.ProcessTransaction(transaction) != nil
+		}
+		// A Wednesday.
+		march13 := time.Date(2024, 3, 13, 18, 30, 0, 0, time.UTC)
+
+		It("should match the account of the transaction", func() {
+			Expect(matchesOn(march13, condition(models.RuleFieldAccount, models.OperatorEquals, "1"))).To(BeTrue())
+			Expect(matchesOn(march13, condition(models.RuleFieldAccount, models.OperatorEquals, "2"))).To(BeFalse())
+			Expect(matchesOn(march13, condition(models.RuleFieldAccount, models.OperatorNotEquals, "2"))).To(BeTrue())
+		})
+
+		It("should compare dates ignoring the time of day", func() {
+			Expect(matchesOn(march13, condition(models.RuleFieldDate, models.OperatorBefore, "2024-03-14"))).To(BeTrue())
+			Expect(matchesOn(march13, condition(models.RuleFieldDate, models.OperatorBefore, "2024-03-13"))).To(BeFalse())
+			Expect(matchesOn(march13, condition(models.RuleFieldDate, models.OperatorAfter, "2024-03-12"))).To(BeTrue())
+			Expect(matchesOn(march13, condition(models.RuleFieldDate, models.OperatorAfter, "2024-03-13"))).To(BeFalse())
+		})
+
+		It("should include both ends of a date range", func() {
+			Expect(matchesOn(march13, condition(models.RuleFieldDate, models.OperatorBetween, "2024-03-13,2024-03-31"))).To(BeTrue())
+			Expect(matchesOn(march13, condition(models.RuleFieldDate, models.OperatorBetween, "2024-03-01,2024-03-13"))).To(BeTrue())
+			Expect(matchesOn(march13, condition(models.RuleFieldDate, models.OperatorBetween, "2024-04-01,2024-04-30"))).To(BeFalse())
+			Expect(matchesOn(march13, condition(models.RuleFieldDate, models.OperatorBetween, "2024-03-01"))).To(BeFalse())
+		})
+
+		It("should match the day of the month", func() {
+			Expect(matchesOn(march13, condition(models.RuleFieldDayOfMonth, models.OperatorEquals, "13"))).To(BeTrue())
+			Expect(matchesOn(march13, condition(models.RuleFieldDayOfMonth, models.OperatorGreater, "13"))).To(BeFalse())
+			Expect(matchesOn(march13, condition(models.RuleFieldDayOfMonth, models.OperatorLower, "14"))).To(BeTrue())
+			Expect(matchesOn(march13, condition(models.RuleFieldDayOfMonth, models.OperatorBetween, "1,5"))).To(BeFalse())
+			Expect(matchesOn(march13, condition(models.RuleFieldDayOfMonth, models.OperatorBetween, "10,15"))).To(BeTrue())
+		})
+
+		It("should match the weekday by name ignoring case", func() {
+			Expect(matchesOn(march13, condition(models.RuleFieldWeekday, models.OperatorEquals, "Wednesday"))).To(BeTrue())
+			Expect(matchesOn(march13, condition(models.RuleFieldWeekday, models.OperatorEquals, "monday"))).To(BeFalse())
+			Expect(matchesOn(march13, condition(models.RuleFieldWeekday, models.OperatorNotEquals, "saturday"))).To(BeTrue())
+		})
+
+		It("should combine account, day of month and amount conditions", func() {
+			conditions := []models.RuleConditionResponse{
+				condition(models.RuleFieldAccount, models.OperatorEquals, "1"),
+				condition(models.RuleFieldDayOfMonth, models.OperatorBetween, "1,5"),
+				condition(models.RuleFieldAmount, models.OperatorGreater, "10000"),
+			}
+			transaction.Amount = 25000
+			Expect(matchesOn(time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), conditions...)).To(BeTrue())
+			Expect(matchesOn(march13, conditions...)).To(BeFalse())
+			transaction.AccountId = 2
+			Expect(matchesOn(time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), conditions...)).To(BeFalse())
+		})
 	})
 
 	Describe("Multiple Conditions (AND Logic)", func() {
@@ -1362,6 +1520,191 @@ var _ = Describe("RuleEngine", func() {
 		})
 	})
 
+	Describe("Rule Priority and Stop Processing", func() {
+		nameRule := func(id int64, priority int, stop bool, conditionValue string, newName string, categoryId string) models.DescribeRuleResponse {
+			return models.DescribeRuleResponse{
+				Rule: models.RuleResponse{
+					Id:             id,
+					Name:           "Rule " + newName,
+					EffectiveFrom:  time.Now().Add(-24 * time.Hour),
+					Priority:       priority,
+					StopProcessing: stop,
+				},
+				Conditions: []models.RuleConditionResponse{
+					{
+						ConditionType:     models.RuleFieldName,
+						ConditionValue:    conditionValue,
+						ConditionOperator: models.OperatorContains,
+					},
+				},
+				Actions: []models.RuleActionResponse{
+					{ActionType: models.RuleFieldName, ActionValue: newName},
+					{ActionType: models.RuleFieldCategory, ActionValue: categoryId},
+				},
+			}
+		}
+
+		It("should run rules in priority order regardless of the order they were given in", func() {
+			rules = []models.DescribeRuleResponse{
+				nameRule(1, 2, false, "Test", "Low Priority", "1"),
+				nameRule(2, 1, false, "Test", "High Priority", "2"),
+			}
+			engine = NewRuleEngine(categories, []models.AccountResponse{}, rules)
+
+			result := engine.ProcessTransaction(transaction)
+			Expect(result).NotTo(BeNil())
+			Expect(*result.NameUpdate).To(Equal("High Priority"))
+			Expect(result.AppliedRules).To(Equal([]int64{2, 1}))
+			Expect(result.CategoryAdds).To(Equal([]int64{2, 1}))
+			// The rules passed in are left in their order.
+			Expect(rules[0].Rule.Id).To(Equal(int64(1)))
+		})
+
+		It("should keep later rules from touching a transaction once a stop processing rule matches", func() {
+			rules = []models.DescribeRuleResponse{
+				nameRule(1, 1, true, "Test", "Stopped Here", "1"),
+				nameRule(2, 2, false, "Test", "Never Applied", "2"),
+			}
+			engine = NewRuleEngine(categories, []models.AccountResponse{}, rules)
+
+			result := engine.ProcessTransaction(transaction)
+			Expect(result).NotTo(BeNil())
+			Expect(*result.NameUpdate).To(Equal("Stopped Here"))
+			Expect(result.AppliedRules).To(Equal([]int64{1}))
+			Expect(result.CategoryAdds).To(Equal([]int64{1}))
+		})
+
+		It("should not stop processing when the stop processing rule does not match", func() {
+			rules = []models.DescribeRuleResponse{
+				nameRule(1, 1, true, "Other", "Stopped Here", "1"),
+				nameRule(2, 2, false, "Test", "Applied", "2"),
+			}
+			engine = NewRuleEngine(categories, []models.AccountResponse{}, rules)
+
+			result := engine.ProcessTransaction(transaction)
+			Expect(result).NotTo(BeNil())
+			Expect(*result.NameUpdate).To(Equal("Applied"))
+			Expect(result.AppliedRules).To(Equal([]int64{2}))
+		})
+
+		It("should stop processing even when the matching rule changes nothing", func() {
+			transaction.CategoryIds = []int64{1}
+			stopRule := nameRule(1, 1, true, "Test", "ignored", "1")
+			stopRule.Actions = stopRule.Actions[1:]
+			rules = []models.DescribeRuleResponse{
+				stopRule,
+				nameRule(2, 2, false, "Test", "Never Applied", "2"),
+			}
+			engine = NewRuleEngine(categories, []models.AccountResponse{}, rules)
+
+			Expect(engine.ProcessTransaction(transaction)).To(BeNil())
+		})
+	})
+
+	Describe("Condition Groups", func() {
+		condition := func(conditionType models.RuleFieldType, op models.RuleOperator, value string) models.RuleConditionResponse {
+			return models.RuleConditionResponse{
+				ConditionType:     conditionType,
+				ConditionValue:    value,
+				ConditionOperator: op,
+			}
+		}
+		matches := func(logic models.ConditionLogic, conditions []models.RuleConditionResponse, groups []models.RuleConditionGroupResponse) bool {
+			rules = []models.DescribeRuleResponse{
+				{
+					Rule: models.RuleResponse{
+						Id:             1,
+						Name:           "Grouped rule",
+						ConditionLogic: logic,
+						EffectiveFrom:  time.Now().Add(-24 * time.Hour),
+					},
+					Conditions:      conditions,
+					ConditionGroups: groups,
+					Actions: []models.RuleActionResponse{
+						{
+							ActionType:  models.RuleFieldCategory,
+							ActionValue: "3",
+						},
+					},
+				},
+			}
+			engine = NewRuleEngine(categories, []models.AccountResponse{}, rules)
+			return engine.ProcessTransaction(transaction) != nil
+		}
+		// (name contains AMZN OR name contains AMAZON) AND amount > 500
+		amazonGroup := models.RuleConditionGroupResponse{
+			Logic: models.ConditionLogicOr,
+			Conditions: []models.RuleConditionResponse{
+				condition(models.RuleFieldName, models.OperatorContains, "AMZN"),
+				condition(models.RuleFieldName, models.OperatorContains, "AMAZON"),
+			},
+		}
+		largeAmount := []models.RuleConditionResponse{
+			condition(models.RuleFieldAmount, models.OperatorGreater, "500"),
+		}
+
+		It("should combine top level conditions with groups using the rule logic", func() {
+			transaction.Name = "AMZN Mktp"
+			transaction.Amount = 750
+			Expect(matches(models.ConditionLogicAnd, largeAmount, []models.RuleConditionGroupResponse{amazonGroup})).To(BeTrue())
+
+			transaction.Name = "Amazon Pay"
+			Expect(matches(models.ConditionLogicAnd, largeAmount, []models.RuleConditionGroupResponse{amazonGroup})).To(BeTrue())
+
+			transaction.Amount = 100
+			Expect(matches(models.ConditionLogicAnd, largeAmount, []models.RuleConditionGroupResponse{amazonGroup})).To(BeFalse())
+			Expect(matches(models.ConditionLogicOr, largeAmount, []models.RuleConditionGroupResponse{amazonGroup})).To(BeTrue())
+
+			transaction.Name = "Flipkart"
+			transaction.Amount = 750
+			Expect(matches(models.ConditionLogicAnd, largeAmount, []models.RuleConditionGroupResponse{amazonGroup})).To(BeFalse())
+		})
+
+		It("should match a NOT group when none of its children match", func() {
+			notRefund := models.RuleConditionGroupResponse{
+				Logic: models.ConditionLogicNot,
+				Conditions: []models.RuleConditionResponse{
+					condition(models.RuleFieldName, models.OperatorContains, "refund"),
+					condition(models.RuleFieldName, models.OperatorContains, "reversal"),
+				},
+			}
+			transaction.Name = "Swiggy order"
+			Expect(matches(models.ConditionLogicAnd, nil, []models.RuleConditionGroupResponse{notRefund})).To(BeTrue())
+
+			transaction.Name = "Swiggy refund"
+			Expect(matches(models.ConditionLogicAnd, nil, []models.RuleConditionGroupResponse{notRefund})).To(BeFalse())
+		})
+
+		It("should evaluate nested groups", func() {
+			// amount > 500 AND NOT (AMZN OR AMAZON)
+			nested := models.RuleConditionGroupResponse{
+				Logic:  models.ConditionLogicNot,
+				Groups: []models.RuleConditionGroupResponse{amazonGroup},
+			}
+			transaction.Amount = 750
+			transaction.Name = "Flipkart"
+			Expect(matches(models.ConditionLogicAnd, largeAmount, []models.RuleConditionGroupResponse{nested})).To(BeTrue())
+
+			transaction.Name = "AMZN Mktp"
+			Expect(matches(models.ConditionLogicAnd, largeAmount, []models.RuleConditionGroupResponse{nested})).To(BeFalse())
+		})
+
+		It("should never match an empty group", func() {
+			empty := models.RuleConditionGroupResponse{Logic: models.ConditionLogicNot}
+			Expect(matches(models.ConditionLogicAnd, nil, []models.RuleConditionGroupResponse{empty})).To(BeFalse())
+		})
+
+		It("should compile regex conditions inside groups", func() {
+			regexGroup := models.RuleConditionGroupResponse{
+				Logic: models.ConditionLogicAnd,
+				Conditions: []models.RuleConditionResponse{
+					condition(models.RuleFieldName, models.OperatorRegex, "^test\\s+trans"),
+				},
+			}
+			Expect(matches(models.ConditionLogicAnd, nil, []models.RuleConditionGroupResponse{regexGroup})).To(BeTrue())
+		})
+	})
+
 	Describe("Complex Scenarios", func() {
 		Context("multiple rules with mixed actions", func() {
 			BeforeEach(func() {
@@ -2,11 +2,14 @@ package service
 
 import (
 	"context"
+	"expenses/internal/errors"
 	"expenses/internal/models"
 	"expenses/internal/repository"
 	"expenses/internal/validator"
 	database "expenses/pkg/database/manager"
 	"expenses/pkg/logger"
+	"fmt"
+	"slices"
 )
 
 type RuleServiceInterface interface {
@@ -18,6 +21,7 @@ type RuleServiceInterface interface {
 	UpdateRuleCondition(ctx context.Context, id int64, ruleId int64, ruleReq models.UpdateRuleConditionRequest, userId int64) (models.RuleConditionResponse, error)
 	PutRuleActions(ctx context.Context, ruleId int64, req models.PutRuleActionsRequest, userId int64) (models.PutRuleActionsResponse, error)
 	PutRuleConditions(ctx context.Context, ruleId int64, req models.PutRuleConditionsRequest, userId int64) (models.PutRuleConditionsResponse, error)
+	ReorderRules(ctx context.Context, userId int64, req models.ReorderRulesRequest) ([]models.RuleResponse, error)
 	DeleteRule(ctx context.Context, id int64, userId int64) error
 }
 
@@ -37,6 +41,13 @@ func NewRuleService(ruleRepo repository.RuleRepositoryInterface, transactionRepo
 	}
 }
 
+// rulePriorityLockKey is the advisory lock held while the priorities of the
+// rules of a user are assigned. Locks on single rules are keyed by the rule
+// id, so the user id is negated to keep the keys apart.
+func rulePriorityLockKey(userId int64) int64 {
+	return -userId
+}
+
 func (s *ruleService) CreateRule(ctx context.Context, ruleReq models.CreateRuleRequest) (models.DescribeRuleResponse, error) {
 	logger.Debugf("Creating rule for user %d", ruleReq.Rule.CreatedBy)
 	var ruleResponse models.DescribeRuleResponse
@@ -44,7 +55,14 @@ func (s *ruleService) CreateRule(ctx context.Context, ruleReq models.CreateRuleR
 		return ruleResponse, err
 	}
 
-	err := s.db.WithTxn(ctx, func(txCtx context.Context) error {
+	// Rules created at the same time would otherwise get the same priority.
+	err := s.db.WithLock(ctx, rulePriorityLockKey(ruleReq.Rule.CreatedBy), func(txCtx context.Context) error {
+		priority, err := s.ruleRepo.GetNextRulePriority(txCtx, ruleReq.Rule.CreatedBy)
+		if err != nil {
+			return err
+		}
+		ruleReq.Rule.Priority = priority
+
 		rule, err := s.ruleRepo.CreateRule(txCtx, ruleReq.Rule)
 		if err != nil {
 			return err
@@ -55,6 +73,7 @@ func (s *ruleService) CreateRule(ctx context.Context, ruleReq models.CreateRuleR
 		}
 		for i := range ruleReq.Conditions {
 			ruleReq.Conditions[i].RuleId = rule.Id
+			ruleReq.Conditions[i].GroupId = nil
 		}
 
 		actions, err := s.ruleRepo.CreateRuleActions(txCtx, ruleReq.Actions)
@@ -66,9 +85,15 @@ func (s *ruleService) CreateRule(ctx context.Context, ruleReq models.CreateRuleR
 		if err != nil {
 			return err
 		}
+
+		groups, err := s.ruleRepo.CreateRuleConditionGroups(txCtx, rule.Id, ruleReq.ConditionGroups)
+		if err != nil {
+			return err
+		}
 		ruleResponse.Rule = rule
 		ruleResponse.Actions = actions
 		ruleResponse.Conditions = conditions
+		ruleResponse.ConditionGroups = groups
 		return nil
 	})
 
@@ -99,7 +124,11 @@ func (s *ruleService) GetRuleById(ctx context.Context, id int64, userId int64) (
 	if err != nil {
 		return ruleResponse, err
 	}
-	ruleResponse.Conditions = conditions
+	groups, err := s.ruleRepo.ListRuleConditionGroupsByRuleId(ctx, id)
+	if err != nil {
+		return ruleResponse, err
+	}
+	ruleResponse.Conditions, ruleResponse.ConditionGroups = nestConditionGroups(conditions, groups)
 
 	logger.Debugf("Rule %d fetched successfully", id)
 	return ruleResponse, nil
@@ -170,6 +199,33 @@ func (s *ruleService) UpdateRuleCondition(ctx context.Context, id int64, ruleId
 	if err != nil {
 		return models.RuleConditionResponse{}, err
 	}
+	// Checks such as the operator suiting the type need the fields the update
+	// leaves out, so the condition is validated as it will be stored.
+	conditions, err := s.ruleRepo.ListRuleConditionsByRuleId(ctx, rule.Id)
+	if err != nil {
+		return models.RuleConditionResponse{}, err
+	}
+	idx := slices.IndexFunc(conditions, func(c models.RuleConditionResponse) bool { return c.Id == id })
+	if idx < 0 {
+		return models.RuleConditionResponse{}, errors.NewRuleConditionNotFoundError(fmt.Errorf("rule condition %d not found", id))
+	}
+	merged := models.CreateRuleConditionRequest{
+		ConditionType:     conditions[idx].ConditionType,
+		ConditionValue:    conditions[idx].ConditionValue,
+		ConditionOperator: conditions[idx].ConditionOperator,
+	}
+	if ruleReq.ConditionType != nil {
+		merged.ConditionType = *ruleReq.ConditionType
+	}
+	if ruleReq.ConditionValue != nil {
+		merged.ConditionValue = *ruleReq.ConditionValue
+	}
+	if ruleReq.ConditionOperator != nil {
+		merged.ConditionOperator = *ruleReq.ConditionOperator
+	}
+	if err := s.validator.ValidateCondition(merged); err != nil {
+		return models.RuleConditionResponse{}, err
+	}
 	ruleCondition, err := s.ruleRepo.UpdateRuleCondition(ctx, id, rule.Id, ruleReq)
 	if err != nil {
 		return models.RuleConditionResponse{}, err
@@ -177,6 +233,42 @@ func (s *ruleService) UpdateRuleCondition(ctx context.Context, id int64, ruleId
 	return ruleCondition, nil
 }
 
+// ReorderRules sets the order rules run in and returns all the rules of the
+// user in their new order.
+func (s *ruleService) ReorderRules(ctx context.Context, userId int64, req models.ReorderRulesRequest) ([]models.RuleResponse, error) {
+	logger.Debugf("Reordering %d rules for user %d", len(req.RuleIds), userId)
+	seen := make(map[int64]bool, len(req.RuleIds))
+	for _, ruleId := range req.RuleIds {
+		if seen[ruleId] {
+			return nil, errors.NewRuleInvalidOrderError(fmt.Errorf("rule %d is listed more than once", ruleId))
+		}
+		seen[ruleId] = true
+	}
+
+	var rules []models.RuleResponse
+	err := s.db.WithLock(ctx, rulePriorityLockKey(userId), func(txCtx context.Context) error {
+		for _, ruleId := range req.RuleIds {
+			if _, err := s.ruleRepo.GetRule(txCtx, ruleId, userId); err != nil {
+				return err
+			}
+		}
+		if err := s.ruleRepo.ReorderRules(txCtx, userId, req.RuleIds); err != nil {
+			return err
+		}
+		listed, err := s.ruleRepo.ListRules(txCtx, userId, models.RuleListQuery{})
+		if err != nil {
+			return err
+		}
+		rules = listed.Rules
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Rules reordered successfully for user %d", userId)
+	return rules, nil
+}
+
 func (s *ruleService) DeleteRule(ctx context.Context, id int64, userId int64) error {
 	logger.Debugf("Deleting rule %d for user %d", id, userId)
 	err := s.db.WithTxn(ctx, func(txCtx context.Context) error {
@@ -230,12 +322,61 @@ func (s *ruleService) PutRuleConditions(ctx context.Context, ruleId int64, req m
 	if err != nil {
 		return response, err
 	}
-	conditions, err := s.ruleRepo.PutRuleConditions(ctx, ruleId, req.Conditions)
+	conditions, groups, err := s.ruleRepo.PutRuleConditions(ctx, ruleId, req.Conditions, req.ConditionGroups)
 	if err != nil {
 		return response, err
 	}
 	response.Conditions = conditions
+	response.ConditionGroups = groups
 
 	logger.Debugf("Rule conditions updated successfully for rule %d", ruleId)
 	return response, nil
 }
+
+// nestConditionGroups splits the conditions of a rule into its top level
+// conditions and the tree of its condition groups, built from the flat groups
+// listed by the repository.
+func nestConditionGroups(conditions []models.RuleConditionResponse, groups []models.RuleConditionGroupResponse) ([]models.RuleConditionResponse, []models.RuleConditionGroupResponse) {
+	if len(groups) == 0 {
+		return conditions, []models.RuleConditionGroupResponse{}
+	}
+
+	topLevel := []models.RuleConditionResponse{}
+	groupConditions := make(map[int64][]models.RuleConditionResponse)
+	for _, condition := range conditions {
+		if condition.GroupId == nil {
+			topLevel = append(topLevel, condition)
+			continue
+		}
+		groupConditions[*condition.GroupId] = append(groupConditions[*condition.GroupId], condition)
+	}
+
+	var roots []models.RuleConditionGroupResponse
+	children := make(map[int64][]models.RuleConditionGroupResponse)
+	for _, group := range groups {
+		if group.ParentId == nil {
+			roots = append(roots, group)
+			continue
+		}
+		children[*group.ParentId] = append(children[*group.ParentId], group)
+	}
+
+	var nest func(group models.RuleConditionGroupResponse) models.RuleConditionGroupResponse
+	nest = func(group models.RuleConditionGroupResponse) models.RuleConditionGroupResponse {
+		group.Conditions = groupConditions[group.Id]
+		if group.Conditions == nil {
+			group.Conditions = []models.RuleConditionResponse{}
+		}
+		group.Groups = make([]models.RuleConditionGroupResponse, 0, len(children[group.Id]))
+		for _, child := range children[group.Id] {
+			group.Groups = append(group.Groups, nest(child))
+		}
+		return group
+	}
+
+	tree := make([]models.RuleConditionGroupResponse, 0, len(roots))
+	for _, root := range roots {
+		tree = append(tree, nest(root))
+	}
+	return topLevel, tree
+}
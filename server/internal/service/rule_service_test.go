@@ -599,6 +599,38 @@ var _ = Describe("RuleService", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should validate a value update against the stored condition type", func() {
+			val := "abc"
+			update := models.UpdateRuleConditionRequest{ConditionValue: &val}
+			_, err := ruleService.UpdateRuleCondition(ctx, condId, created.Rule.Id, update, user1)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should validate an operator update against the stored condition type", func() {
+			op := models.OperatorContains
+			update := models.UpdateRuleConditionRequest{ConditionOperator: &op}
+			_, err := ruleService.UpdateRuleCondition(ctx, condId, created.Rule.Id, update, user1)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should validate the stored value when the operator becomes regex", func() {
+			nameRule, err := ruleService.CreateRule(ctx, models.CreateRuleRequest{
+				Rule: models.CreateBaseRuleRequest{
+					Name:          "Name Rule",
+					EffectiveFrom: now,
+					CreatedBy:     user1,
+				},
+				Actions:    []models.CreateRuleActionRequest{{ActionType: models.RuleFieldAmount, ActionValue: "100"}},
+				Conditions: []models.CreateRuleConditionRequest{{ConditionType: models.RuleFieldName, ConditionValue: "coffee (", ConditionOperator: models.OperatorContains}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			op := models.OperatorRegex
+			update := models.UpdateRuleConditionRequest{ConditionOperator: &op}
+			_, err = ruleService.UpdateRuleCondition(ctx, nameRule.Conditions[0].Id, nameRule.Rule.Id, update, user1)
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should return error for condition belonging to different rule", func() {
 			// Create another rule and condition
 			input := models.CreateRuleRequest{
@@ -1177,6 +1209,167 @@ var _ = Describe("RuleService", func() {
 		})
 	})
 
+	Describe("Rule priority", func() {
+		createRule := func(name string, userId int64) models.DescribeRuleResponse {
+			created, err := ruleService.CreateRule(ctx, models.CreateRuleRequest{
+				Rule:       models.CreateBaseRuleRequest{Name: name, EffectiveFrom: now, CreatedBy: userId},
+				Actions:    []models.CreateRuleActionRequest{{ActionType: models.RuleFieldCategory, ActionValue: "1"}},
+				Conditions: []models.CreateRuleConditionRequest{{ConditionType: models.RuleFieldAmount, ConditionValue: "100", ConditionOperator: models.OperatorEquals}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return created
+		}
+		names := func(rules []models.RuleResponse) []string {
+			var result []string
+			for _, rule := range rules {
+				result = append(result, rule.Name)
+			}
+			return result
+		}
+
+		It("should run new rules after the existing rules of the user", func() {
+			first := createRule("First", user1)
+			second := createRule("Second", user1)
+			other := createRule("Other user", user2)
+			Expect(first.Rule.Priority).To(Equal(1))
+			Expect(second.Rule.Priority).To(Equal(2))
+			Expect(other.Rule.Priority).To(Equal(1))
+		})
+
+		It("should reorder rules and keep unlisted rules after the listed ones", func() {
+			a := createRule("A", user1)
+			b := createRule("B", user1)
+			c := createRule("C", user1)
+
+			rules, err := ruleService.ReorderRules(ctx, user1, models.ReorderRulesRequest{RuleIds: []int64{c.Rule.Id, a.Rule.Id}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(rules)).To(Equal([]string{"C", "A", "B"}))
+			Expect(rules[2].Priority).To(Equal(3))
+
+			listed, err := ruleService.ListRules(ctx, user1, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(listed.Rules)).To(Equal([]string{"C", "A", "B"}))
+			Expect(b.Rule.Id).To(Equal(rules[2].Id))
+		})
+
+		It("should reject duplicate rule ids", func() {
+			a := createRule("A", user1)
+			_, err := ruleService.ReorderRules(ctx, user1, models.ReorderRulesRequest{RuleIds: []int64{a.Rule.Id, a.Rule.Id}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("more than once"))
+		})
+
+		It("should not reorder rules of another user", func() {
+			a := createRule("A", user1)
+			other := createRule("Other user", user2)
+			_, err := ruleService.ReorderRules(ctx, user1, models.ReorderRulesRequest{RuleIds: []int64{other.Rule.Id, a.Rule.Id}})
+			Expect(err).To(HaveOccurred())
+
+			rule, err := ruleService.GetRuleById(ctx, other.Rule.Id, user2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rule.Rule.Priority).To(Equal(1))
+		})
+
+		It("should update the stop processing flag", func() {
+			a := createRule("A", user1)
+			Expect(a.Rule.StopProcessing).To(BeFalse())
+			stop := true
+			updated, err := ruleService.UpdateRule(ctx, a.Rule.Id, models.UpdateRuleRequest{StopProcessing: &stop}, user1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.StopProcessing).To(BeTrue())
+		})
+	})
+
+	Describe("Condition groups", func() {
+		amountOver := models.CreateRuleConditionRequest{ConditionType: models.RuleFieldAmount, ConditionValue: "500", ConditionOperator: models.OperatorGreater}
+		nameContains := func(value string) models.CreateRuleConditionRequest {
+			return models.CreateRuleConditionRequest{ConditionType: models.RuleFieldName, ConditionValue: value, ConditionOperator: models.OperatorContains}
+		}
+		groups := []models.CreateRuleConditionGroupRequest{
+			{
+				Logic:      models.ConditionLogicOr,
+				Conditions: []models.CreateRuleConditionRequest{nameContains("AMZN"), nameContains("AMAZON")},
+				Groups: []models.CreateRuleConditionGroupRequest{
+					{Logic: models.ConditionLogicNot, Conditions: []models.CreateRuleConditionRequest{nameContains("refund")}},
+				},
+			},
+		}
+		createGrouped := func() models.DescribeRuleResponse {
+			created, err := ruleService.CreateRule(ctx, models.CreateRuleRequest{
+				Rule:            models.CreateBaseRuleRequest{Name: "Amazon", EffectiveFrom: now, CreatedBy: user1},
+				Actions:         []models.CreateRuleActionRequest{{ActionType: models.RuleFieldCategory, ActionValue: "1"}},
+				Conditions:      []models.CreateRuleConditionRequest{amountOver},
+				ConditionGroups: groups,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return created
+		}
+
+		It("should create and describe nested condition groups", func() {
+			created := createGrouped()
+			Expect(created.ConditionGroups).To(HaveLen(1))
+			Expect(created.ConditionGroups[0].Conditions).To(HaveLen(2))
+			Expect(created.ConditionGroups[0].Groups).To(HaveLen(1))
+
+			described, err := ruleService.GetRuleById(ctx, created.Rule.Id, user1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(described.Conditions).To(HaveLen(1))
+			Expect(described.Conditions[0].GroupId).To(BeNil())
+			Expect(described.ConditionGroups).To(HaveLen(1))
+
+			group := described.ConditionGroups[0]
+			Expect(group.Logic).To(Equal(models.ConditionLogicOr))
+			Expect(group.ParentId).To(BeNil())
+			Expect(group.Conditions).To(HaveLen(2))
+			Expect(group.Groups).To(HaveLen(1))
+			Expect(group.Groups[0].Logic).To(Equal(models.ConditionLogicNot))
+			Expect(*group.Groups[0].ParentId).To(Equal(group.Id))
+			Expect(group.Groups[0].Conditions[0].ConditionValue).To(Equal("refund"))
+		})
+
+		It("should describe flat rules with no condition groups", func() {
+			created, err := ruleService.CreateRule(ctx, models.CreateRuleRequest{
+				Rule:       models.CreateBaseRuleRequest{Name: "Flat", EffectiveFrom: now, CreatedBy: user1},
+				Actions:    []models.CreateRuleActionRequest{{ActionType: models.RuleFieldCategory, ActionValue: "1"}},
+				Conditions: []models.CreateRuleConditionRequest{amountOver},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			described, err := ruleService.GetRuleById(ctx, created.Rule.Id, user1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(described.Conditions).To(HaveLen(1))
+			Expect(described.ConditionGroups).To(BeEmpty())
+		})
+
+		It("should replace condition groups on put", func() {
+			created := createGrouped()
+			resp, err := ruleService.PutRuleConditions(ctx, created.Rule.Id, models.PutRuleConditionsRequest{
+				ConditionGroups: []models.CreateRuleConditionGroupRequest{
+					{Logic: models.ConditionLogicAnd, Conditions: []models.CreateRuleConditionRequest{nameContains("UBER"), amountOver}},
+				},
+			}, user1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Conditions).To(BeEmpty())
+			Expect(resp.ConditionGroups).To(HaveLen(1))
+
+			described, err := ruleService.GetRuleById(ctx, created.Rule.Id, user1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(described.Conditions).To(BeEmpty())
+			Expect(described.ConditionGroups).To(HaveLen(1))
+			Expect(described.ConditionGroups[0].Conditions).To(HaveLen(2))
+			Expect(described.ConditionGroups[0].Groups).To(BeEmpty())
+		})
+
+		It("should reject empty condition groups", func() {
+			_, err := ruleService.CreateRule(ctx, models.CreateRuleRequest{
+				Rule:            models.CreateBaseRuleRequest{Name: "Empty", EffectiveFrom: now, CreatedBy: user1},
+				Actions:         []models.CreateRuleActionRequest{{ActionType: models.RuleFieldCategory, ActionValue: "1"}},
+				ConditionGroups: []models.CreateRuleConditionGroupRequest{{Logic: models.ConditionLogicAnd}},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("PutRuleConditions - Additional Edge Cases", func() {
 		var created models.DescribeRuleResponse
 
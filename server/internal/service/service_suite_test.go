@@ -1,6 +1,7 @@
 package service
 
 import (
+	"expenses/internal/config"
 	"testing"
 	"time"
 
@@ -20,3 +21,13 @@ var _ = BeforeSuite(func() {
 	SetDefaultConsistentlyDuration(1 * time.Second)
 	SetDefaultConsistentlyPollingInterval(100 * time.Millisecond)
 })
+
+// testJobConfig runs jobs quickly so tests can wait for them with Eventually.
+func testJobConfig() *config.Config {
+	return &config.Config{
+		JobWorkers:           1,
+		JobMaxAttempts:       3,
+		JobPollInterval:      10 * time.Millisecond,
+		JobVisibilityTimeout: time.Second,
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"expenses/internal/models"
+	"expenses/pkg/logger"
+	"sync"
+)
+
+const statementEventBuffer = 16
+
+type StatementEventBusInterface interface {
+	Publish(event models.StatementEvent)
+	Subscribe(statementId int64) (<-chan models.StatementEvent, func())
+}
+
+// StatementEventBus fans out statement progress to the clients watching a
+// statement. It only reaches subscribers in the same process, so events of a
+// statement processed by another server are not seen here.
+type StatementEventBus struct {
+	mu          sync.RWMutex
+	subscribers map[int64]map[chan models.StatementEvent]struct{}
+}
+
+func NewStatementEventBus() StatementEventBusInterface {
+	return &StatementEventBus{
+		subscribers: make(map[int64]map[chan models.StatementEvent]struct{}),
+	}
+}
+
+// Publish sends the event to every subscriber of its statement without
+// blocking. Subscribers that fall behind miss the event.
+func (b *StatementEventBus) Publish(event models.StatementEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subscribers[event.StatementId] {
+		select {
+		case ch <- event:
+		default:
+			logger.Warnf("Dropping %s event for statement %d, subscriber is not keeping up", event.Type, event.StatementId)
+		}
+	}
+}
+
+// Subscribe returns the events published for a statement from now on. The
+// returned function ends the subscription and closes the channel.
+func (b *StatementEventBus) Subscribe(statementId int64) (<-chan models.StatementEvent, func()) {
+	ch := make(chan models.StatementEvent, statementEventBuffer)
+	b.mu.Lock()
+	if b.subscribers[statementId] == nil {
+		b.subscribers[statementId] = make(map[chan models.StatementEvent]struct{})
+	}
+	b.subscribers[statementId][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers[statementId], ch)
+			if len(b.subscribers[statementId]) == 0 {
+				delete(b.subscribers, statementId)
+			}
+			close(ch)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"expenses/internal/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StatementEventBus", func() {
+	var bus StatementEventBusInterface
+
+	BeforeEach(func() {
+		bus = NewStatementEventBus()
+	})
+
+	It("should deliver events to the subscribers of the statement only", func() {
+		events, unsubscribe := bus.Subscribe(1)
+		defer unsubscribe()
+		otherEvents, unsubscribeOther := bus.Subscribe(2)
+		defer unsubscribeOther()
+
+		bus.Publish(models.StatementEvent{Type: models.StatementEventStatus, StatementId: 1, Status: models.StatementStatusProcessing})
+
+		Expect(events).To(Receive(HaveField("Status", models.StatementStatusProcessing)))
+		Consistently(otherEvents).ShouldNot(Receive())
+	})
+
+	It("should close the channel when unsubscribing", func() {
+		events, unsubscribe := bus.Subscribe(1)
+		unsubscribe()
+		unsubscribe()
+
+		Expect(events).To(BeClosed())
+		bus.Publish(models.StatementEvent{Type: models.StatementEventStatus, StatementId: 1})
+	})
+
+	It("should not block on subscribers that fall behind", func() {
+		events, unsubscribe := bus.Subscribe(1)
+		defer unsubscribe()
+
+		for i := 0; i < statementEventBuffer+5; i++ {
+			bus.Publish(models.StatementEvent{Type: models.StatementEventProgress, StatementId: 1})
+		}
+		Expect(events).To(HaveLen(statementEventBuffer))
+	})
+
+	It("should only treat failed, reverted and rule events as final", func() {
+		Expect(models.StatementEvent{Type: models.StatementEventStatus, Status: models.StatementStatusDone}.IsFinal()).To(BeFalse())
+		Expect(models.StatementEvent{Type: models.StatementEventStatus, Status: models.StatementStatusError}.IsFinal()).To(BeTrue())
+		Expect(models.StatementEvent{Type: models.StatementEventStatus, Status: models.StatementStatusReverted}.IsFinal()).To(BeTrue())
+		Expect(models.StatementEvent{Type: models.StatementEventProgress}.IsFinal()).To(BeFalse())
+		Expect(models.StatementEvent{Type: models.StatementEventRules}.IsFinal()).To(BeTrue())
+	})
+})
@@ -1,15 +1,24 @@
 package service
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"expenses/internal/config"
 	customErrors "expenses/internal/errors"
 	"expenses/internal/models"
 	"expenses/internal/parser"
 	"expenses/internal/repository"
 	"expenses/internal/validator"
 	"expenses/pkg/logger"
+	"expenses/pkg/storage"
+	"expenses/pkg/utils"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
+	"time"
 
 	"context"
 )
@@ -17,8 +26,16 @@ import (
 type StatementServiceInterface interface {
 	ParseStatement(ctx context.Context, input models.ParseStatementInput, userId int64) (models.StatementResponse, error)
 	GetStatementStatus(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, error)
+	RevertStatement(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, error)
+	ReprocessStatement(ctx context.Context, statementId int64, userId int64, input models.ReprocessStatementInput) (models.StatementResponse, error)
+	GetStatementFile(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, []byte, error)
+	GetStatementRowErrors(ctx context.Context, statementId int64, userId int64) ([]models.StatementRowErrorResponse, error)
 	ListStatements(ctx context.Context, userId int64, query models.StatementListQuery) (models.PaginatedStatementResponse, error)
-	PreviewStatement(ctx context.Context, fileBytes []byte, fileName string, skipRows int, rowSize int, password string) (*models.StatementPreview, error)
+	PreviewStatement(ctx context.Context, fileBytes []byte, fileName string, skipRows int, rowSize int, password string, sheet models.SheetSelector) (*models.StatementPreview, error)
+	RecoverStaleStatements(ctx context.Context) error
+	WatchStatement(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, <-chan models.StatementEvent, func(), error)
+	ListUpcomingCardDues(ctx context.Context, userId int64) ([]models.CardDueResponse, error)
+	ListParsers(ctx context.Context) []models.ParserInfo
 }
 
 type StatementService struct {
@@ -27,6 +44,14 @@ type StatementService struct {
 	txService          TransactionServiceInterface
 	statementValidator *validator.StatementValidator
 	ruleEngineService  RuleEngineServiceInterface
+	jobQueue           JobQueueInterface
+	storage            storage.BlobStorage
+	eventBus           StatementEventBusInterface
+	templateService    ParserTemplateServiceInterface
+	categoryService    CategoryServiceInterface
+	// secret seals statement passwords in job payloads. It is shared by all
+	// servers, so any of them can run a queued statement.
+	secret []byte
 }
 
 func NewStatementService(
@@ -35,14 +60,28 @@ func NewStatementService(
 	ruleEngineService RuleEngineServiceInterface,
 	statementValidator *validator.StatementValidator,
 	txService TransactionServiceInterface,
+	jobQueue JobQueueInterface,
+	storage storage.BlobStorage,
+	eventBus StatementEventBusInterface,
+	templateService ParserTemplateServiceInterface,
+	categoryService CategoryServiceInterface,
+	cfg *config.Config,
 ) StatementServiceInterface {
-	return &StatementService{
+	s := &StatementService{
 		repo:               repo,
 		accountService:     accountService,
 		txService:          txService,
 		statementValidator: statementValidator,
 		ruleEngineService:  ruleEngineService,
+		jobQueue:           jobQueue,
+		storage:            storage,
+		eventBus:           eventBus,
+		templateService:    templateService,
+		categoryService:    categoryService,
+		secret:             cfg.JWTSecret,
 	}
+	jobQueue.RegisterHandler(models.JobTypeParseStatement, s.handleParseStatementJob)
+	return s
 }
 
 func (s *StatementService) ParseStatement(ctx context.Context, input models.ParseStatementInput, userId int64) (models.StatementResponse, error) {
@@ -50,18 +89,24 @@ func (s *StatementService) ParseStatement(ctx context.Context, input models.Pars
 		return models.StatementResponse{}, err
 	}
 
-	if strings.HasSuffix(strings.ToLower(input.OriginalFilename), ".xlsx") {
-		if protected := parser.IsExcelPasswordProtectedBytes(input.FileBytes); protected && input.Password == "" {
-			return models.StatementResponse{}, customErrors.NewStatementPasswordRequiredError(errors.New("statement password required"))
-		}
-		if err := parser.ValidateWorkbookPassword(input.FileBytes, input.Password); err != nil {
-			return models.StatementResponse{}, err
-		}
+	if err := validateStatementPassword(input.FileBytes, input.OriginalFilename, input.Password); err != nil {
+		return models.StatementResponse{}, err
 	}
 
 	fileType := "csv"
-	if strings.HasSuffix(strings.ToLower(input.OriginalFilename), ".xls") || strings.HasSuffix(strings.ToLower(input.OriginalFilename), ".xlsx") {
+	lowerFileName := strings.ToLower(input.OriginalFilename)
+	if strings.HasSuffix(lowerFileName, ".xls") || strings.HasSuffix(lowerFileName, ".xlsx") {
 		fileType = "excel"
+	} else if strings.HasSuffix(lowerFileName, ".ofx") || strings.HasSuffix(lowerFileName, ".qfx") {
+		fileType = "ofx"
+	} else if strings.HasSuffix(lowerFileName, ".pdf") {
+		fileType = "pdf"
+	} else if strings.HasSuffix(lowerFileName, ".xml") {
+		fileType = "xml"
+	} else if strings.HasSuffix(lowerFileName, ".sta") || strings.HasSuffix(lowerFileName, ".mt940") || strings.HasSuffix(lowerFileName, ".940") {
+		fileType = "mt940"
+	} else if strings.HasSuffix(lowerFileName, ".qif") {
+		fileType = "qif"
 	}
 
 	account, err := s.accountService.GetAccountById(ctx, input.AccountId, userId)
@@ -69,6 +114,30 @@ func (s *StatementService) ParseStatement(ctx context.Context, input models.Pars
 		return models.StatementResponse{}, err
 	}
 
+	hash := sha256.Sum256(input.FileBytes)
+	contentHash := hex.EncodeToString(hash[:])
+
+	// The same monthly file is easily uploaded twice, so refuse files that were
+	// already imported into the account unless the upload is forced.
+	existing, err := s.repo.GetStatementByContentHash(ctx, userId, account.Id, contentHash)
+	if err != nil {
+		return models.StatementResponse{}, err
+	}
+	var duplicateOf *int64
+	if existing != nil {
+		if !input.Force {
+			return models.StatementResponse{}, customErrors.NewStatementAlreadyUploadedError(
+				fmt.Errorf("file matches statement %d", existing.Id), *existing)
+		}
+		duplicateOf = &existing.Id
+	}
+
+	// Keep the original file so the statement can be downloaded and reprocessed.
+	fileKey := fmt.Sprintf("statements/%d/%s", userId, contentHash)
+	if err := s.storage.Put(ctx, fileKey, input.FileBytes); err != nil {
+		return models.StatementResponse{}, customErrors.NewStatementFileStoreError(err)
+	}
+
 	// Create a statement record in the database.
 	createStatement := models.CreateStatementInput{
 		AccountId:        account.Id,
@@ -76,6 +145,15 @@ func (s *StatementService) ParseStatement(ctx context.Context, input models.Pars
 		OriginalFilename: input.OriginalFilename,
 		FileType:         fileType,
 		Status:           models.StatementStatusPending,
+		ContentHash:      &contentHash,
+		FileKey:          &fileKey,
+		DuplicateOf:      duplicateOf,
+	}
+	if input.BankType != "" {
+		createStatement.BankType = &input.BankType
+	}
+	if input.Metadata != "" {
+		createStatement.Metadata = &input.Metadata
 	}
 
 	statement, err := s.repo.CreateStatement(ctx, createStatement)
@@ -83,29 +161,231 @@ func (s *StatementService) ParseStatement(ctx context.Context, input models.Pars
 		return models.StatementResponse{}, err
 	}
 
-	// Process the statement asynchronously.
-	go s.processStatementAsync(context.Background(), statement.Id, input, userId)
+	if err := s.queueStatement(ctx, statement, userId, input); err != nil {
+		errMsg := fmt.Sprintf("Failed to queue statement: %v", err)
+		_, _ = s.updateStatus(ctx, statement.Id, models.UpdateStatementStatusInput{
+			Status:  models.StatementStatusError,
+			Message: &errMsg,
+		})
+		return models.StatementResponse{}, err
+	}
 	return statement, nil
 }
 
-// processStatementAsync processes the statement in a separate goroutine.
-func (s *StatementService) processStatementAsync(ctx context.Context, statementId int64, input models.ParseStatementInput, userId int64) {
+// validateStatementPassword checks up front that a protected workbook or PDF
+// can be opened, so a missing password is reported to the uploader instead of
+// failing the import later.
+func validateStatementPassword(fileBytes []byte, fileName string, password string) error {
+	if strings.HasSuffix(strings.ToLower(fileName), ".xlsx") {
+		if protected := parser.IsExcelPasswordProtectedBytes(fileBytes); protected && password == "" {
+			return customErrors.NewStatementPasswordRequiredError(errors.New("statement password required"))
+		}
+		if err := parser.ValidateWorkbookPassword(fileBytes, password); err != nil {
+			return err
+		}
+	}
+
+	if parser.IsPDF(fileBytes) {
+		if err := parser.ValidatePDFPassword(fileBytes, password); err != nil {
+			if errors.Is(err, parser.ErrPDFPasswordRequired) {
+				return customErrors.NewStatementPasswordRequiredError(err)
+			}
+			return customErrors.NewStatementBadRequestError(err)
+		}
+	}
+	return nil
+}
+
+// queueStatement queues the statement to be parsed by a job worker. The job
+// reads the file from storage rather than carrying it, and carries the
+// password only in sealed form.
+func (s *StatementService) queueStatement(ctx context.Context, statement models.StatementResponse, userId int64, input models.ParseStatementInput) error {
+	jobInput := models.ParseStatementJobInput{
+		FileName:         input.FileName,
+		AccountId:        input.AccountId,
+		OriginalFilename: input.OriginalFilename,
+		BankType:         input.BankType,
+		Metadata:         input.Metadata,
+		Force:            input.Force,
+		Reprocess:        input.Reprocess,
+	}
+	if input.Password != "" {
+		sealed, err := utils.SealString(s.secret, input.Password)
+		if err != nil {
+			return fmt.Errorf("failed to seal statement password: %w", err)
+		}
+		jobInput.SealedPassword = sealed
+	}
+	_, err := s.jobQueue.Enqueue(ctx, models.JobTypeParseStatement, &statement.Id, models.ParseStatementJobPayload{
+		StatementId: statement.Id,
+		UserId:      userId,
+		FileKey:     *statement.FileKey,
+		Input:       jobInput,
+	})
+	return err
+}
+
+// RecoverStaleStatements fails statements that were left pending or
+// processing without a job to finish them, for example by a server that was
+// stopped before statements were processed through the job queue.
+func (s *StatementService) RecoverStaleStatements(ctx context.Context) error {
+	count, err := s.repo.FailStaleStatements(ctx, "Processing was interrupted, please upload the statement again")
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		logger.Warnf("Marked %d interrupted statements as failed", count)
+	}
+	return nil
+}
+
+func (s *StatementService) handleParseStatementJob(ctx context.Context, job models.Job) error {
+	var payload models.ParseStatementJobPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		// A payload that cannot be read will not get better on retry.
+		logger.Errorf("Dropping statement job %d with invalid payload: %v", job.Id, err)
+		return nil
+	}
+	fileBytes, err := s.storage.Get(ctx, payload.FileKey)
+	if err != nil {
+		return s.retryOrFail(ctx, job, payload.StatementId, fmt.Sprintf("Failed to read statement file: %v", err))
+	}
+	input := models.ParseStatementInput{
+		FileBytes:        fileBytes,
+		FileName:         payload.Input.FileName,
+		AccountId:        payload.Input.AccountId,
+		OriginalFilename: payload.Input.OriginalFilename,
+		BankType:         payload.Input.BankType,
+		Metadata:         payload.Input.Metadata,
+		Force:            payload.Input.Force,
+		Reprocess:        payload.Input.Reprocess,
+	}
+	// A password sealed with another secret cannot be opened. The statement
+	// then fails as needing a password, so it can be reprocessed with one.
+	if payload.Input.SealedPassword != "" {
+		password, err := utils.OpenString(s.secret, payload.Input.SealedPassword)
+		if err != nil {
+			logger.Warnf("Failed to open the password of statement job %d: %v", job.Id, err)
+		}
+		input.Password = password
+	}
+	return s.processStatement(ctx, job, payload.StatementId, input, payload.UserId)
+}
+
+// claimUnlinkedTransactions moves the transactions an earlier attempt of the
+// job inserted without linking them from the duplicates to the inserted rows.
+// Only unlinked rows added since the job was queued that match a parsed row
+// are claimed, so transactions from elsewhere are left alone.
+func (s *StatementService) claimUnlinkedTransactions(ctx context.Context, job models.Job, accountId int64, userId int64, parsedTxs []models.CreateTransactionInput, created *models.BulkCreateTransactionsResult) error {
+	if created.Duplicates == 0 {
+		return nil
+	}
+	unlinked, err := s.repo.ListUnlinkedTransactions(ctx, accountId, userId, job.CreatedAt)
+	if err != nil {
+		return err
+	}
+	inserted := make(map[int64]bool, len(created.Inserted))
+	for _, txn := range created.Inserted {
+		inserted[txn.Id] = true
+	}
+	pending := make(map[string]int, len(parsedTxs))
+	for _, txn := range parsedTxs {
+		pending[statementTxnKey(txn.Date, txn.Name, txn.Description, *txn.Amount)]++
+	}
+	for _, txn := range created.Inserted {
+		pending[storedTxnKey(txn.TransactionBaseResponse)]--
+	}
+	for _, txn := range unlinked {
+		if created.Duplicates == 0 {
+			break
+		}
+		key := storedTxnKey(txn)
+		if inserted[txn.Id] || pending[key] <= 0 {
+			continue
+		}
+		pending[key]--
+		created.Inserted = append(created.Inserted, models.TransactionResponse{TransactionBaseResponse: txn})
+		created.Duplicates--
+	}
+	return nil
+}
+
+// statementTxnKey identifies a transaction the way duplicates are detected.
+func statementTxnKey(date time.Time, name string, description string, amount float64) string {
+	return fmt.Sprintf("%s|%s|%s|%.2f", date.Format("2006-01-02"), name, description, amount)
+}
+
+func storedTxnKey(txn models.TransactionBaseResponse) string {
+	description := ""
+	if txn.Description != nil {
+		description = *txn.Description
+	}
+	return statementTxnKey(txn.Date, txn.Name, description, txn.Amount)
+}
+
+// retryOrFail returns an error so the job is retried, and fails the statement
+// once the job is out of attempts.
+func (s *StatementService) retryOrFail(ctx context.Context, job models.Job, statementId int64, errMsg string) error {
+	if job.IsLastAttempt() {
+		_, _ = s.updateStatus(ctx, statementId, models.UpdateStatementStatusInput{
+			Status:  models.StatementStatusError,
+			Message: &errMsg,
+		})
+	}
+	return errors.New(errMsg)
+}
+
+// processStatement parses the statement file and imports its transactions.
+// Problems with the file are recorded on the statement. Errors that may go
+// away, such as a failing database, are returned so the job is retried; the
+// statement only fails once the job is out of attempts.
+func (s *StatementService) processStatement(ctx context.Context, job models.Job, statementId int64, input models.ParseStatementInput, userId int64) error {
 	logger.Debugf("Processing statement ID %d for account ID %d by user ID %d", statementId, input.AccountId, userId)
-	_, _ = s.repo.UpdateStatementStatus(ctx, statementId, models.UpdateStatementStatusInput{
+
+	_, _ = s.updateStatus(ctx, statementId, models.UpdateStatementStatusInput{
 		Status: models.StatementStatusProcessing,
 	})
 
+	// The previous import is only replaced once the job runs, so a statement
+	// that could not be queued keeps its transactions. Clearing it again on a
+	// retry is harmless.
+	if input.Reprocess {
+		deleted, err := s.clearStatementImport(ctx, statementId, userId)
+		if err != nil {
+			return s.retryOrFail(ctx, job, statementId, fmt.Sprintf("Failed to clear previous import: %v", err))
+		}
+		logger.Debugf("Deleted %d transactions of the previous import of statement ID %d", deleted, statementId)
+	}
+
 	parserType := input.BankType
+	metadata := input.Metadata
+	// Statements uploaded without parser options are read with the template
+	// saved for the account, if there is one.
+	if parserType == "" && metadata == "" {
+		template, err := s.templateService.GetAccountParserTemplate(ctx, input.AccountId, userId)
+		if err != nil {
+			return s.retryOrFail(ctx, job, statementId, fmt.Sprintf("Failed to fetch parser template: %v", err))
+		}
+		if template != nil {
+			logger.Debugf("Using parser template %d for statement ID %d", template.Id, statementId)
+			parserType = string(models.BankTypeOthers)
+			metadata = string(template.Metadata)
+		}
+	}
+	// Files uploaded with column mappings always go through the custom parser
+	// configured for the account, so only sniff the format when neither a bank
+	// type nor metadata was given.
+	if parserType == "" && metadata == "" {
+		if detection, ok := parser.DetectParser(input.FileBytes, input.OriginalFilename, input.Password); ok {
+			logger.Debugf("Detected statement format %s with confidence %.2f for statement ID %d", detection.BankType, detection.Confidence, statementId)
+			parserType = string(detection.BankType)
+		}
+	}
 	if parserType == "" {
 		logger.Debugf("No bank type provided, fetching account details for account ID %d", input.AccountId)
 		account, err := s.accountService.GetAccountById(ctx, input.AccountId, userId)
 		if err != nil {
-			errMsg := fmt.Sprintf("Failed to fetch account: %v", err)
-			_, _ = s.repo.UpdateStatementStatus(ctx, statementId, models.UpdateStatementStatusInput{
-				Status:  models.StatementStatusError,
-				Message: &errMsg,
-			})
-			return
+			return s.retryOrFail(ctx, job, statementId, fmt.Sprintf("Failed to fetch account: %v", err))
 		}
 		parserType = string(account.BankType)
 	}
@@ -114,28 +394,63 @@ func (s *StatementService) processStatementAsync(ctx context.Context, statementI
 	parserImpl, ok := parser.GetParser(models.BankType(parserType))
 	if !ok {
 		errMsg := fmt.Sprintf("No parser available for bank type: %s", parserType)
-		_, _ = s.repo.UpdateStatementStatus(ctx, statementId, models.UpdateStatementStatusInput{
+		_, _ = s.updateStatus(ctx, statementId, models.UpdateStatementStatusInput{
 			Status:  models.StatementStatusError,
 			Message: &errMsg,
 		})
-		return
+		return nil
 	}
 
 	logger.Debugf("Using parser: %T for bank type: %s", parserImpl, parserType)
-	parsedTxs, err := parserImpl.Parse(input.FileBytes, input.Metadata, input.OriginalFilename, input.Password)
+	detectedFormat := parserType
+	_, _ = s.updateStatus(ctx, statementId, models.UpdateStatementStatusInput{
+		Status:         models.StatementStatusProcessing,
+		DetectedFormat: &detectedFormat,
+	})
+	parsedTxs, categoryNames, rowErrors, err := parser.ParseWithCategories(parserImpl, input.FileBytes, metadata, input.OriginalFilename, input.Password)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to parse statement: %v", err)
-		if errors.Is(err, parser.ErrWorkbookPasswordRequired) {
+		if errors.Is(err, parser.ErrWorkbookPasswordRequired) || errors.Is(err, parser.ErrPDFPasswordRequired) {
 			errMsg = "statement password required"
 		}
-		_, _ = s.repo.UpdateStatementStatus(ctx, statementId, models.UpdateStatementStatusInput{
+		_, _ = s.updateStatus(ctx, statementId, models.UpdateStatementStatusInput{
 			Status:  models.StatementStatusError,
 			Message: &errMsg,
 		})
-		return
+		return nil
+	}
+
+	logger.Debugf("Parsed %d transactions from statement ID %d, %d rows failed", len(parsedTxs), statementId, len(rowErrors))
+	parsedCount, parseFailedCount := len(parsedTxs), len(rowErrors)
+	s.eventBus.Publish(models.StatementEvent{
+		Type:        models.StatementEventProgress,
+		StatementId: statementId,
+		Status:      models.StatementStatusProcessing,
+		ParsedCount: &parsedCount,
+		FailedCount: &parseFailedCount,
+	})
+
+	var balances models.StatementBalances
+	if balanceParser, ok := parserImpl.(parser.BalanceParser); ok {
+		balances, err = balanceParser.ParseBalances(input.FileBytes)
+		if err != nil {
+			logger.Warnf("Failed to read balances from statement ID %d: %v", statementId, err)
+		}
 	}
 
-	logger.Debugf("Parsed %d transactions from statement ID %d", len(parsedTxs), statementId)
+	var cardSummary models.CardStatementSummary
+	if summaryParser, ok := parserImpl.(parser.CardSummaryParser); ok {
+		cardSummary, err = summaryParser.ParseCardSummary(input.FileBytes, input.Password)
+		if err != nil {
+			logger.Warnf("Failed to read card summary from statement ID %d: %v", statementId, err)
+		}
+	}
+
+	if categoryNames != nil {
+		if err := s.mapCategoryNames(ctx, categoryNames, parsedTxs, userId); err != nil {
+			logger.Warnf("Failed to map categories of statement ID %d: %v", statementId, err)
+		}
+	}
 
 	// Prepare all transactions for bulk insert
 	for i := range parsedTxs {
@@ -143,20 +458,39 @@ func (s *StatementService) processStatementAsync(ctx context.Context, statementI
 		parsedTxs[i].CreatedBy = userId
 	}
 
-	// Create all transactions in bulk
-	transactions, err := s.txService.CreateTransactions(ctx, parsedTxs)
+	// Create all transactions in bulk, skipping the ones that already exist
+	created, err := s.txService.CreateTransactions(ctx, parsedTxs)
 	if err != nil {
-		errMsg := fmt.Sprintf("failed to create transactions: %v", err)
-		_, _ = s.repo.UpdateStatementStatus(ctx, statementId, models.UpdateStatementStatusInput{
-			Status:  models.StatementStatusError,
-			Message: &errMsg,
+		return s.retryOrFail(ctx, job, statementId, fmt.Sprintf("failed to create transactions: %v", err))
+	}
+	// A retry sees the rows an earlier attempt inserted as duplicates. The
+	// ones that were never linked are claimed again so that reverting the
+	// statement still removes them, and the row errors are written afresh.
+	if job.Attempts > 1 {
+		if err := s.claimUnlinkedTransactions(ctx, job, input.AccountId, userId, parsedTxs, &created); err != nil {
+			return s.retryOrFail(ctx, job, statementId, fmt.Sprintf("failed to find transactions of an earlier attempt: %v", err))
+		}
+		if err := s.repo.DeleteStatementRowErrors(ctx, statementId); err != nil {
+			return s.retryOrFail(ctx, job, statementId, fmt.Sprintf("failed to clear row errors: %v", err))
+		}
+	}
+
+	// Rows that parsed but could not be inserted are reported with the parse
+	// errors. Their position in the file is no longer known.
+	for _, failure := range created.Failed {
+		txn := parsedTxs[failure.Index]
+		rowErrors = append(rowErrors, models.StatementRowError{
+			RawRow: fmt.Sprintf("%s,%s,%.2f", txn.Date.Format("2006-01-02"), txn.Name, *txn.Amount),
+			Reason: fmt.Sprintf("failed to insert transaction: %v", failure.Err),
 		})
-		return
+	}
+	if err := s.repo.CreateStatementRowErrors(ctx, statementId, rowErrors); err != nil {
+		logger.Errorf("failed to save row errors for statement %d: %v", statementId, err)
 	}
 
 	// Extract transaction IDs
-	txnIds := make([]int64, len(transactions))
-	for i, tx := range transactions {
+	txnIds := make([]int64, len(created.Inserted))
+	for i, tx := range created.Inserted {
 		txnIds[i] = tx.Id
 	}
 
@@ -164,26 +498,155 @@ func (s *StatementService) processStatementAsync(ctx context.Context, statementI
 	err = s.repo.CreateStatementTxns(ctx, statementId, txnIds)
 	if err != nil {
 		logger.Errorf("failed to link transactions to statement %d: %v", statementId, err)
-		errMsg := fmt.Sprintf("failed to link transactions: %v", err)
-		_, _ = s.repo.UpdateStatementStatus(ctx, statementId, models.UpdateStatementStatusInput{
-			Status:  models.StatementStatusError,
-			Message: &errMsg,
-		})
-		return
+		return s.retryOrFail(ctx, job, statementId, fmt.Sprintf("failed to link transactions: %v", err))
 	}
 
-	msg := fmt.Sprintf("Processed %d transactions, 0 failed", len(transactions))
-	status := models.StatementStatusDone
-	s.ruleEngineService.ExecuteRulesInBackground(ctx, userId, models.ExecuteRulesRequest{
-		TransactionIds: &txnIds,
-	})
-	_, err = s.repo.UpdateStatementStatus(ctx, statementId, models.UpdateStatementStatusInput{
-		Status:  status,
-		Message: &msg,
+	insertedCount := len(created.Inserted)
+	duplicateCount := created.Duplicates
+	failedCount := len(rowErrors)
+	msg := fmt.Sprintf("Processed %d transactions: %d inserted, %d duplicates, %d failed",
+		insertedCount+duplicateCount+failedCount, insertedCount, duplicateCount, failedCount)
+	if mismatch := reconcileBalances(balances, parsedTxs); mismatch != "" {
+		msg = fmt.Sprintf("%s; %s", msg, mismatch)
+	}
+	if gap := s.findStatementGap(ctx, statementId, input.AccountId, userId, balances, parsedTxs); gap != "" {
+		msg = fmt.Sprintf("%s; %s", msg, gap)
+	}
+	_, err = s.updateStatus(ctx, statementId, models.UpdateStatementStatusInput{
+		Status:         models.StatementStatusDone,
+		Message:        &msg,
+		OpeningBalance: balances.OpeningBalance,
+		ClosingBalance: balances.ClosingBalance,
+		InsertedCount:  &insertedCount,
+		DuplicateCount: &duplicateCount,
+		FailedCount:    &failedCount,
+
+		StatementPeriodStart: cardSummary.StatementPeriodStart,
+		StatementPeriodEnd:   cardSummary.StatementPeriodEnd,
+		TotalDue:             cardSummary.TotalDue,
+		MinimumDue:           cardSummary.MinimumDue,
+		PaymentDueDate:       cardSummary.PaymentDueDate,
 	})
 	if err != nil {
 		logger.Errorf("Failed to update statement status for ID %d: %v", statementId, err)
 	}
+
+	// Rules are queued after the statement is done so that watchers see the
+	// rules event last.
+	if err := s.ruleEngineService.ExecuteStatementRules(ctx, userId, statementId, txnIds); err != nil {
+		logger.Errorf("Failed to queue rules for statement %d: %v", statementId, err)
+		errMsg := fmt.Sprintf("Failed to queue rules: %v", err)
+		s.eventBus.Publish(models.StatementEvent{
+			Type:        models.StatementEventRules,
+			StatementId: statementId,
+			Status:      models.StatementStatusError,
+			Message:     &errMsg,
+		})
+	}
+	return nil
+}
+
+// mapCategoryNames sets the categories of transactions parsed from a format
+// that names them, such as QIF. Names are matched against the user's existing
+// categories, ignoring case; a name written as Parent:Child that matches no
+// category is matched by its last part. Categories that do not exist are not
+// created. names holds the names of each transaction at its index.
+func (s *StatementService) mapCategoryNames(ctx context.Context, names [][]string, txns []models.CreateTransactionInput, userId int64) error {
+	categories, err := s.categoryService.ListCategories(ctx, userId)
+	if err != nil {
+		return err
+	}
+	categoryIds := make(map[string]int64, len(categories))
+	for _, category := range categories {
+		categoryIds[strings.ToLower(strings.TrimSpace(category.Name))] = category.Id
+	}
+
+	for i, txnNames := range names {
+		for _, name := range txnNames {
+			categoryId, ok := categoryIds[strings.ToLower(name)]
+			if !ok {
+				parts := strings.Split(name, ":")
+				categoryId, ok = categoryIds[strings.ToLower(strings.TrimSpace(parts[len(parts)-1]))]
+			}
+			if ok && !slices.Contains(txns[i].CategoryIds, categoryId) {
+				txns[i].CategoryIds = append(txns[i].CategoryIds, categoryId)
+			}
+		}
+	}
+	return nil
+}
+
+// updateStatus saves the status of a statement and tells its watchers.
+func (s *StatementService) updateStatus(ctx context.Context, statementId int64, input models.UpdateStatementStatusInput) (models.StatementResponse, error) {
+	statement, err := s.repo.UpdateStatementStatus(ctx, statementId, input)
+	if err != nil {
+		return statement, err
+	}
+	s.eventBus.Publish(models.NewStatementStatusEvent(statement))
+	return statement, nil
+}
+
+// reconcileBalances checks that the parsed transactions account for the
+// difference between the opening and closing balances reported by the file.
+// It returns a description of the mismatch, or an empty string when the
+// balances agree or are not available.
+func reconcileBalances(balances models.StatementBalances, txns []models.CreateTransactionInput) string {
+	if balances.OpeningBalance == nil || balances.ClosingBalance == nil {
+		return ""
+	}
+	// Debits are positive amounts, so they reduce the balance.
+	expected := *balances.OpeningBalance
+	for _, txn := range txns {
+		if txn.Amount != nil {
+			expected -= *txn.Amount
+		}
+	}
+	if math.Abs(expected-*balances.ClosingBalance) < 0.005 {
+		return ""
+	}
+	return fmt.Sprintf("closing balance mismatch: expected %.2f from transactions, statement reports %.2f", expected, *balances.ClosingBalance)
+}
+
+// findStatementGap compares the opening balance of the statement with the
+// closing balance of the account's previous statement. When they differ, the
+// transactions between the two statements are missing from both. It returns
+// a description of the gap, or an empty string when there is none or it
+// cannot be checked.
+func (s *StatementService) findStatementGap(ctx context.Context, statementId int64, accountId int64, userId int64, balances models.StatementBalances, txns []models.CreateTransactionInput) string {
+	if balances.OpeningBalance == nil || len(txns) == 0 {
+		return ""
+	}
+	firstDate := txns[0].Date
+	for _, txn := range txns[1:] {
+		if txn.Date.Before(firstDate) {
+			firstDate = txn.Date
+		}
+	}
+	previous, err := s.repo.GetPreviousStatement(ctx, statementId, accountId, userId, firstDate)
+	if err != nil {
+		logger.Warnf("Failed to find the statement before statement %d: %v", statementId, err)
+		return ""
+	}
+	if previous == nil || math.Abs(previous.ClosingBalance-*balances.OpeningBalance) < 0.005 {
+		return ""
+	}
+	return fmt.Sprintf("opening balance %.2f does not match closing balance %.2f of statement %d, transactions between %s and %s may be missing",
+		*balances.OpeningBalance, previous.ClosingBalance, previous.StatementId,
+		previous.LastTransactionDate.Format("2006-01-02"), firstDate.Format("2006-01-02"))
+}
+
+// WatchStatement subscribes to the progress of a statement. It returns the
+// current state of the statement along with the events that follow it; the
+// returned function ends the subscription.
+func (s *StatementService) WatchStatement(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, <-chan models.StatementEvent, func(), error) {
+	// Subscribe before reading the statement so no transition is missed.
+	events, unsubscribe := s.eventBus.Subscribe(statementId)
+	statement, err := s.GetStatementStatus(ctx, statementId, userId)
+	if err != nil {
+		unsubscribe()
+		return models.StatementResponse{}, nil, nil, err
+	}
+	return statement, events, unsubscribe, nil
 }
 
 func (s *StatementService) GetStatementStatus(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, error) {
@@ -193,6 +656,209 @@ func (s *StatementService) GetStatementStatus(ctx context.Context, statementId i
 	return s.repo.GetStatementByID(ctx, statementId, userId)
 }
 
+// RevertStatement undoes a statement import: it deletes every transaction the
+// statement created, along with the transfers rules created from them, and
+// marks the statement as reverted.
+func (s *StatementService) RevertStatement(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, error) {
+	statement, err := s.GetStatementStatus(ctx, statementId, userId)
+	if err != nil {
+		return models.StatementResponse{}, err
+	}
+	switch statement.Status {
+	case models.StatementStatusPending, models.StatementStatusProcessing:
+		return models.StatementResponse{}, customErrors.NewStatementStillProcessingError(fmt.Errorf("statement %d is %s", statementId, statement.Status))
+	case models.StatementStatusReverted:
+		return models.StatementResponse{}, customErrors.NewStatementAlreadyRevertedError(fmt.Errorf("statement %d is already reverted", statementId))
+	}
+
+	txnIds, err := s.repo.ListStatementTransactionIds(ctx, statementId)
+	if err != nil {
+		return models.StatementResponse{}, err
+	}
+	deleted, err := s.txService.DeleteTransactions(ctx, txnIds, userId)
+	if err != nil {
+		return models.StatementResponse{}, err
+	}
+	logger.Infof("Deleted %d transactions while reverting statement %d", deleted, statementId)
+
+	msg := fmt.Sprintf("Reverted import, deleted %d transactions", deleted)
+	return s.updateStatus(ctx, statementId, models.UpdateStatementStatusInput{
+		Status:  models.StatementStatusReverted,
+		Message: &msg,
+	})
+}
+
+// ReprocessStatement parses the stored file of a statement again, with the
+// current parsers and optionally new parser options. The queued job deletes
+// the transactions of the previous import before importing the file afresh.
+func (s *StatementService) ReprocessStatement(ctx context.Context, statementId int64, userId int64, input models.ReprocessStatementInput) (models.StatementResponse, error) {
+	statement, err := s.GetStatementStatus(ctx, statementId, userId)
+	if err != nil {
+		return models.StatementResponse{}, err
+	}
+	if statement.Status == models.StatementStatusPending || statement.Status == models.StatementStatusProcessing {
+		return models.StatementResponse{}, customErrors.NewStatementStillProcessingError(fmt.Errorf("statement %d is %s", statementId, statement.Status))
+	}
+	// A failed or reverted import no longer holds its file, which may have
+	// been uploaded again since. Only one of them can be imported.
+	if (statement.Status == models.StatementStatusError || statement.Status == models.StatementStatusReverted) &&
+		statement.DuplicateOf == nil && statement.ContentHash != nil {
+		existing, err := s.repo.GetStatementByContentHash(ctx, userId, statement.AccountId, *statement.ContentHash)
+		if err != nil {
+			return models.StatementResponse{}, err
+		}
+		if existing != nil {
+			return models.StatementResponse{}, customErrors.NewStatementAlreadyUploadedError(
+				fmt.Errorf("file matches statement %d", existing.Id), *existing)
+		}
+	}
+	fileBytes, err := s.readStatementFile(ctx, statement)
+	if err != nil {
+		return models.StatementResponse{}, err
+	}
+	if err := validateStatementPassword(fileBytes, statement.OriginalFilename, input.Password); err != nil {
+		return models.StatementResponse{}, err
+	}
+
+	previous := statement
+	msg := "Queued for reprocessing"
+	statement, err = s.updateStatus(ctx, statementId, models.UpdateStatementStatusInput{
+		Status:   models.StatementStatusPending,
+		Message:  &msg,
+		BankType: input.BankType,
+		Metadata: input.Metadata,
+	})
+	if err != nil {
+		return models.StatementResponse{}, err
+	}
+
+	parseInput := models.ParseStatementInput{
+		AccountId:        statement.AccountId,
+		OriginalFilename: statement.OriginalFilename,
+		Password:         input.Password,
+		Reprocess:        true,
+	}
+	if statement.BankType != nil {
+		parseInput.BankType = *statement.BankType
+	}
+	if statement.Metadata != nil {
+		parseInput.Metadata = *statement.Metadata
+	}
+	if err := s.queueStatement(ctx, statement, userId, parseInput); err != nil {
+		// Nothing was deleted yet, so the statement goes back to how it was.
+		errMsg := fmt.Sprintf("Failed to queue statement for reprocessing: %v", err)
+		_, _ = s.updateStatus(ctx, statementId, models.UpdateStatementStatusInput{
+			Status:   previous.Status,
+			Message:  &errMsg,
+			BankType: previous.BankType,
+			Metadata: previous.Metadata,
+		})
+		return models.StatementResponse{}, err
+	}
+	return statement, nil
+}
+
+// clearStatementImport deletes the transactions of a previous import of the
+// statement and forgets its links and row errors.
+func (s *StatementService) clearStatementImport(ctx context.Context, statementId int64, userId int64) (int64, error) {
+	txnIds, err := s.repo.ListStatementTransactionIds(ctx, statementId)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := s.txService.DeleteTransactions(ctx, txnIds, userId)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.repo.ResetStatementImport(ctx, statementId); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// GetStatementFile returns the statement together with the file it was
+// imported from.
+func (s *StatementService) GetStatementFile(ctx context.Context, statementId int64, userId int64) (models.StatementResponse, []byte, error) {
+	statement, err := s.GetStatementStatus(ctx, statementId, userId)
+	if err != nil {
+		return models.StatementResponse{}, nil, err
+	}
+	fileBytes, err := s.readStatementFile(ctx, statement)
+	if err != nil {
+		return models.StatementResponse{}, nil, err
+	}
+	return statement, fileBytes, nil
+}
+
+// readStatementFile loads the stored file of a statement. Statements uploaded
+// before files were kept have none.
+func (s *StatementService) readStatementFile(ctx context.Context, statement models.StatementResponse) ([]byte, error) {
+	if statement.FileKey == nil {
+		return nil, customErrors.NewStatementFileNotFoundError(fmt.Errorf("statement %d has no stored file", statement.Id))
+	}
+	fileBytes, err := s.storage.Get(ctx, *statement.FileKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, customErrors.NewStatementFileNotFoundError(err)
+		}
+		return nil, err
+	}
+	return fileBytes, nil
+}
+
+// GetStatementRowErrors returns the rows of a statement that could not be
+// imported, after checking that the statement belongs to the user.
+func (s *StatementService) GetStatementRowErrors(ctx context.Context, statementId int64, userId int64) ([]models.StatementRowErrorResponse, error) {
+	if _, err := s.GetStatementStatus(ctx, statementId, userId); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStatementRowErrors(ctx, statementId)
+}
+
+// ListParsers returns the statement formats that can be uploaded.
+func (s *StatementService) ListParsers(ctx context.Context) []models.ParserInfo {
+	return parser.ListParsers()
+}
+
+// ListUpcomingCardDues returns the latest bill of every card account that is
+// due today or later, soonest first.
+func (s *StatementService) ListUpcomingCardDues(ctx context.Context, userId int64) ([]models.CardDueResponse, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	statements, err := s.repo.ListUpcomingCardStatements(ctx, userId, today)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.accountService.ListAccounts(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	accountNames := make(map[int64]string, len(accounts))
+	for _, account := range accounts {
+		accountNames[account.Id] = account.Name
+	}
+
+	dues := make([]models.CardDueResponse, 0, len(statements))
+	for _, statement := range statements {
+		name, ok := accountNames[statement.AccountId]
+		if !ok {
+			// The account was deleted after the statement was imported.
+			continue
+		}
+		dues = append(dues, models.CardDueResponse{
+			StatementId:          statement.Id,
+			AccountId:            statement.AccountId,
+			AccountName:          name,
+			StatementPeriodStart: statement.StatementPeriodStart,
+			StatementPeriodEnd:   statement.StatementPeriodEnd,
+			TotalDue:             statement.TotalDue,
+			MinimumDue:           statement.MinimumDue,
+			PaymentDueDate:       *statement.PaymentDueDate,
+		})
+	}
+	return dues, nil
+}
+
 func (s *StatementService) ListStatements(ctx context.Context, userId int64, query models.StatementListQuery) (models.PaginatedStatementResponse, error) {
 	if query.Page < 1 {
 		query.Page = 1
@@ -217,7 +883,7 @@ func (s *StatementService) ListStatements(ctx context.Context, userId int64, que
 	}, nil
 }
 
-func (s *StatementService) PreviewStatement(ctx context.Context, fileBytes []byte, fileName string, skipRows int, rowSize int, password string) (*models.StatementPreview, error) {
+func (s *StatementService) PreviewStatement(ctx context.Context, fileBytes []byte, fileName string, skipRows int, rowSize int, password string, sheet models.SheetSelector) (*models.StatementPreview, error) {
 	if rowSize == 0 {
 		rowSize = 10
 	}
@@ -233,8 +899,11 @@ func (s *StatementService) PreviewStatement(ctx context.Context, fileBytes []byt
 	}
 
 	p := parser.CustomParser{}
-	preview, err := p.Preview(fileBytes, fileName, skipRows, rowSize, password)
+	preview, err := p.Preview(fileBytes, fileName, skipRows, rowSize, password, sheet)
 	if err != nil {
+		if errors.Is(err, parser.ErrInvalidSheetSelection) {
+			return nil, customErrors.NewStatementBadRequestError(err)
+		}
 		return nil, err
 	}
 	return preview, nil
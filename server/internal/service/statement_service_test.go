@@ -2,12 +2,19 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	customErrors "expenses/internal/errors"
 	mockDatabase "expenses/internal/mock/database"
 	repository "expenses/internal/mock/repository"
 	"expenses/internal/models"
 	"expenses/internal/validator"
+	"expenses/pkg/storage"
 	"expenses/pkg/utils"
+	"fmt"
+	"net/http"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -17,10 +24,15 @@ import (
 var _ = Describe("StatementService", func() {
 	var (
 		mockRepo          *repository.MockStatementRepository
+		mockTxnRepo       *repository.MockTransactionRepository
 		service           StatementService
 		txnService        TransactionServiceInterface
 		accountService    AccountServiceInterface
 		ruleEngineService RuleEngineServiceInterface
+		jobQueue          JobQueueInterface
+		eventBus          StatementEventBusInterface
+		templateService   ParserTemplateServiceInterface
+		categoryService   CategoryServiceInterface
 		userId            int64
 		ctx               context.Context
 	)
@@ -28,14 +40,20 @@ var _ = Describe("StatementService", func() {
 	BeforeEach(func() {
 		ctx = context.Background()
 		mockRepo = repository.NewMockStatementRepository()
-		mockTxnRepo := repository.NewMockTransactionRepository()
+		mockTxnRepo = repository.NewMockTransactionRepository()
 		mockCategoryRepo := repository.NewMockCategoryRepository()
 		mockAccountRepo := repository.NewMockAccountRepository()
 		mockDbManager := mockDatabase.NewMockDatabaseManager()
 		mockRuleRepo := repository.NewMockRuleRepository()
 		txnService = NewTransactionService(mockTxnRepo, mockCategoryRepo, mockAccountRepo, mockDbManager)
 		accountService = NewAccountService(mockAccountRepo)
-		ruleEngineService = NewRuleEngineService(mockRuleRepo, mockTxnRepo, mockCategoryRepo, mockAccountRepo)
+		fileStorage, err := storage.NewLocalStorage(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		jobQueue = NewJobQueue(repository.NewMockJobRepository(), testJobConfig())
+		eventBus = NewStatementEventBus()
+		templateService = NewParserTemplateService(repository.NewMockParserTemplateRepository(), accountService)
+		categoryService = NewCategoryService(mockCategoryRepo)
+		ruleEngineService = NewRuleEngineService(mockRuleRepo, mockTxnRepo, mockCategoryRepo, mockAccountRepo, jobQueue, eventBus)
 
 		service = StatementService{
 			repo:               mockRepo,
@@ -43,10 +61,22 @@ var _ = Describe("StatementService", func() {
 			txService:          txnService,
 			accountService:     accountService,
 			ruleEngineService:  ruleEngineService,
+			jobQueue:           jobQueue,
+			storage:            fileStorage,
+			eventBus:           eventBus,
+			templateService:    templateService,
+			categoryService:    categoryService,
+			secret:             []byte("test-secret"),
 		}
+		jobQueue.RegisterHandler(models.JobTypeParseStatement, service.handleParseStatementJob)
+		jobQueue.Start()
 		userId = 42
 	})
 
+	AfterEach(func() {
+		jobQueue.Stop()
+	})
+
 	Describe("CreateStatement and ListStatements", func() {
 		It("should create and list statements with pagination", func() {
 			// Create 7 statements
@@ -549,7 +579,7 @@ var _ = Describe("StatementService", func() {
 					Date:        date,
 					AccountId:   acc.Id,
 					CreatedBy:   userId,
-					Description: "WDL TFR UPI/DR/221356312527/RITIK S/SBIN/rs6321908@/UPI",
+					Description: "WDL TFR UPI/DR/221356312527/RITIK S/SBIN/rs6321908@/UPI (Ref: 123456)",
 				},
 				CategoryIds: []int64{},
 			}
@@ -579,8 +609,11 @@ var _ = Describe("StatementService", func() {
 
 			result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
 			Expect(result.Message).NotTo(BeNil())
-			// Should show some failures due to duplicates
-			Expect(*result.Message).To(ContainSubstring("failed"))
+			// The existing transaction is skipped and the rest is imported
+			Expect(*result.Message).To(Equal("Processed 2 transactions: 1 inserted, 1 duplicates, 0 failed"))
+			Expect(*result.InsertedCount).To(Equal(1))
+			Expect(*result.DuplicateCount).To(Equal(1))
+			Expect(*result.FailedCount).To(Equal(0))
 		})
 
 		It("should handle all transactions failing during insertion", func() {
@@ -734,6 +767,372 @@ var _ = Describe("StatementService", func() {
 			Expect(*result.Message).To(ContainSubstring("Processed"))
 		})
 
+		It("should require the password for an encrypted PDF statement", func() {
+			acc, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "HDFC Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			fileBytes := utils.CreatePDFFile([][]string{
+				{"Date", "Narration", "Chq./Ref.No.", "Value Dt", "Withdrawal Amt.", "Deposit Amt.", "Closing Balance"},
+				{"01/08/22", "UPI-SHOP-123", "0000123", "01/08/22", "100.00", "", "900.00"},
+			}, "secret")
+			input := models.ParseStatementInput{
+				FileBytes:        fileBytes,
+				FileName:         "statement.pdf",
+				AccountId:        acc.Id,
+				OriginalFilename: "statement.pdf",
+			}
+			_, err = service.ParseStatement(ctx, input, userId)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("statement password required"))
+
+			input.Password = "wrong"
+			_, err = service.ParseStatement(ctx, input, userId)
+			Expect(err).To(HaveOccurred())
+
+			input.Password = "secret"
+			resp, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.FileType).To(Equal("pdf"))
+
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+				return result.Status
+			}, "2s", "100ms").Should(Equal(models.StatementStatusDone))
+
+			result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+			Expect(*result.Message).To(ContainSubstring("Processed 1 transactions"))
+		})
+
+		It("should keep the statement password out of the stored job payload", func() {
+			acc, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "HDFC Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Queue on a stopped queue so the stored job can be inspected.
+			jobRepo := repository.NewMockJobRepository()
+			service.jobQueue = NewJobQueue(jobRepo, testJobConfig())
+
+			fileBytes := utils.CreatePDFFile([][]string{
+				{"Date", "Narration", "Chq./Ref.No.", "Value Dt", "Withdrawal Amt.", "Deposit Amt.", "Closing Balance"},
+				{"01/08/22", "UPI-SHOP-123", "0000123", "01/08/22", "100.00", "", "900.00"},
+			}, "secret")
+			resp, err := service.ParseStatement(ctx, models.ParseStatementInput{
+				FileBytes:        fileBytes,
+				FileName:         "statement.pdf",
+				AccountId:        acc.Id,
+				OriginalFilename: "statement.pdf",
+				Password:         "secret",
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			job, ok := jobRepo.GetJob(1)
+			Expect(ok).To(BeTrue())
+			Expect(*job.ReferenceId).To(Equal(resp.Id))
+			Expect(string(job.Payload)).NotTo(ContainSubstring("secret"))
+			var payload models.ParseStatementJobPayload
+			Expect(json.Unmarshal(job.Payload, &payload)).To(Succeed())
+			Expect(payload.Input.SealedPassword).NotTo(BeEmpty())
+
+			// Another server sharing the secret can run the job.
+			other := &StatementService{
+				repo:               mockRepo,
+				statementValidator: validator.NewStatementValidator(),
+				txService:          txnService,
+				accountService:     accountService,
+				ruleEngineService:  ruleEngineService,
+				jobQueue:           service.jobQueue,
+				storage:            service.storage,
+				eventBus:           eventBus,
+				templateService:    templateService,
+				categoryService:    categoryService,
+				secret:             []byte("test-secret"),
+			}
+			job.Attempts = 1
+			Expect(other.handleParseStatementJob(ctx, job)).To(Succeed())
+			result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+			Expect(result.Status).To(Equal(models.StatementStatusDone))
+		})
+
+		It("should fail a protected statement whose password cannot be opened", func() {
+			acc, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "HDFC Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			jobRepo := repository.NewMockJobRepository()
+			service.jobQueue = NewJobQueue(jobRepo, testJobConfig())
+
+			fileBytes := utils.CreatePDFFile([][]string{
+				{"Date", "Narration", "Chq./Ref.No.", "Value Dt", "Withdrawal Amt.", "Deposit Amt.", "Closing Balance"},
+				{"01/08/22", "UPI-SHOP-123", "0000123", "01/08/22", "100.00", "", "900.00"},
+			}, "secret")
+			resp, err := service.ParseStatement(ctx, models.ParseStatementInput{
+				FileBytes:        fileBytes,
+				FileName:         "statement.pdf",
+				AccountId:        acc.Id,
+				OriginalFilename: "statement.pdf",
+				Password:         "secret",
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The secret was changed since the job was queued.
+			service.secret = []byte("rotated-secret")
+			job, ok := jobRepo.GetJob(1)
+			Expect(ok).To(BeTrue())
+			job.Attempts = 1
+			Expect(service.handleParseStatementJob(ctx, job)).To(Succeed())
+
+			result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+			Expect(result.Status).To(Equal(models.StatementStatusError))
+			Expect(*result.Message).To(ContainSubstring("statement password required"))
+		})
+
+		It("should detect the statement format regardless of the account bank type", func() {
+			accInput := models.CreateAccountInput{
+				Name:      "Unknown Bank",
+				BankType:  models.BankTypeOthers,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			}
+			acc, err := accountService.CreateAccount(ctx, accInput)
+			Expect(err).NotTo(HaveOccurred())
+
+			content := "Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n" +
+				"01/08/22,UPI-SHOP-123,01/08/22,100.00,0.00,0000123,900.00\n"
+			input := models.ParseStatementInput{
+				FileBytes:        []byte(content),
+				FileName:         "statement.txt",
+				AccountId:        acc.Id,
+				OriginalFilename: "statement.txt",
+			}
+			resp, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+				return result.Status
+			}, "2s", "100ms").Should(Equal(models.StatementStatusDone))
+
+			result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+			Expect(result.DetectedFormat).NotTo(BeNil())
+			Expect(*result.DetectedFormat).To(Equal(string(models.BankTypeHDFC)))
+		})
+
+		It("should store statement balances and flag a mismatch for MT940 files", func() {
+			accInput := models.CreateAccountInput{
+				Name:      "EUR Account",
+				BankType:  models.BankTypeOthers,
+				Currency:  models.CurrencyUSD,
+				CreatedBy: userId,
+			}
+			acc, err := accountService.CreateAccount(ctx, accInput)
+			Expect(err).NotTo(HaveOccurred())
+
+			content := ":20:STMT\n:60F:C240301EUR1000,00\n:61:240305D100,00NTRFNONREF\n:86:Rent\n:62F:C240331EUR850,00"
+			input := models.ParseStatementInput{
+				FileBytes:        []byte(content),
+				FileName:         "statement.sta",
+				AccountId:        acc.Id,
+				OriginalFilename: "statement.sta",
+				BankType:         string(models.BankTypeMT940),
+			}
+			resp, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.FileType).To(Equal("mt940"))
+
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+				return result.Status
+			}, "2s", "100ms").Should(Equal(models.StatementStatusDone))
+
+			result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+			Expect(*result.OpeningBalance).To(BeNumerically("==", 1000.00))
+			Expect(*result.ClosingBalance).To(BeNumerically("==", 850.00))
+			Expect(*result.Message).To(ContainSubstring("closing balance mismatch: expected 900.00"))
+		})
+
+		It("should flag a gap between consecutive MT940 statements", func() {
+			mockRepo.SetTransactionRepository(mockTxnRepo)
+			acc, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "EUR Account",
+				BankType:  models.BankTypeOthers,
+				Currency:  models.CurrencyUSD,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			importFile := func(name string, content string) models.StatementResponse {
+				resp, err := service.ParseStatement(ctx, models.ParseStatementInput{
+					FileBytes:        []byte(content),
+					FileName:         name,
+					AccountId:        acc.Id,
+					OriginalFilename: name,
+					BankType:         string(models.BankTypeMT940),
+				}, userId)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(func() models.StatementStatus {
+					result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+					return result.Status
+				}, "2s", "100ms").Should(Equal(models.StatementStatusDone))
+				result, err := service.GetStatementStatus(ctx, resp.Id, userId)
+				Expect(err).NotTo(HaveOccurred())
+				return result
+			}
+
+			march := importFile("march.sta", ":20:STMT\n:60F:C240301EUR1000,00\n:61:240305D100,00NTRFNONREF\n:86:Rent\n:62F:C240331EUR900,00")
+			Expect(*march.Message).NotTo(ContainSubstring("may be missing"))
+
+			april := importFile("april.sta", ":20:STMT\n:60F:C240401EUR900,00\n:61:240405D100,00NTRFNONREF\n:86:Rent\n:62F:C240430EUR800,00")
+			Expect(*april.Message).NotTo(ContainSubstring("may be missing"))
+
+			june := importFile("june.sta", ":20:STMT\n:60F:C240601EUR700,00\n:61:240605D100,00NTRFNONREF\n:86:Rent\n:62F:C240630EUR600,00")
+			Expect(*june.Message).To(ContainSubstring(fmt.Sprintf(
+				"opening balance 700.00 does not match closing balance 800.00 of statement %d, transactions between 2024-04-05 and 2024-06-05 may be missing", april.Id)))
+		})
+
+		It("should map the categories of QIF files to existing categories by name", func() {
+			acc, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "Old Checking",
+				BankType:  models.BankTypeOthers,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			groceries, err := categoryService.CreateCategory(ctx, models.CreateCategoryInput{Name: "Groceries", CreatedBy: userId})
+			Expect(err).NotTo(HaveOccurred())
+			rent, err := categoryService.CreateCategory(ctx, models.CreateCategoryInput{Name: "Rent", CreatedBy: userId})
+			Expect(err).NotTo(HaveOccurred())
+
+			content := "!Type:Bank\n" +
+				"D01/05/2024\nT-1200.00\nPLandlord\nLHousing:Rent\n^\n" +
+				"D01/06/2024\nT-40.00\nPSupermarket\nLgroceries/Home\n^\n" +
+				"D01/07/2024\nT-15.00\nPCinema\nLEntertainment\n^\n"
+			resp, err := service.ParseStatement(ctx, models.ParseStatementInput{
+				FileBytes:        []byte(content),
+				AccountId:        acc.Id,
+				OriginalFilename: "history.qif",
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.FileType).To(Equal("qif"))
+
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+				return result.Status
+			}, "2s", "100ms").Should(Equal(models.StatementStatusDone))
+
+			txns, err := txnService.ListTransactions(ctx, userId, models.TransactionListQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txns.Transactions).To(HaveLen(3))
+			categoriesByName := make(map[string][]int64)
+			for _, txn := range txns.Transactions {
+				categoriesByName[txn.Name] = txn.CategoryIds
+			}
+			Expect(categoriesByName["Landlord"]).To(Equal([]int64{rent.Id}))
+			Expect(categoriesByName["Supermarket"]).To(Equal([]int64{groceries.Id}))
+			Expect(categoriesByName["Cinema"]).To(BeEmpty())
+		})
+
+		It("should record the rows that failed to parse", func() {
+			accInput := models.CreateAccountInput{
+				Name:      "HDFC Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			}
+			acc, err := accountService.CreateAccount(ctx, accInput)
+			Expect(err).NotTo(HaveOccurred())
+
+			content := "Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n" +
+				"01/08/22,UPI-SHOP-123,01/08/22,100.00,0.00,0000123,900.00\n" +
+				"02/08/22,UPI-SHOP-456,02/08/22,abc,0.00,0000456,800.00\n"
+			input := models.ParseStatementInput{
+				FileBytes:        []byte(content),
+				FileName:         "statement.txt",
+				AccountId:        acc.Id,
+				OriginalFilename: "statement.txt",
+			}
+			resp, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+				return result.Status
+			}, "2s", "100ms").Should(Equal(models.StatementStatusDone))
+
+			result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+			Expect(*result.Message).To(Equal("Processed 2 transactions: 1 inserted, 0 duplicates, 1 failed"))
+			Expect(*result.FailedCount).To(Equal(1))
+
+			rowErrors, err := service.GetStatementRowErrors(ctx, resp.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rowErrors).To(HaveLen(1))
+			Expect(rowErrors[0].StatementId).To(Equal(resp.Id))
+			Expect(rowErrors[0].LineNumber).To(Equal(3))
+			Expect(rowErrors[0].RawRow).To(ContainSubstring("UPI-SHOP-456"))
+
+			_, err = service.GetStatementRowErrors(ctx, resp.Id, userId+1)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should link the transactions of a failed attempt when the job is retried", func() {
+			acc, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "HDFC Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			mockRepo.SetTransactionRepository(mockTxnRepo)
+			jobRepo := repository.NewMockJobRepository()
+			service.jobQueue = NewJobQueue(jobRepo, testJobConfig())
+
+			content := "Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n" +
+				"01/08/22,UPI-SHOP-123,01/08/22,100.00,0.00,0000123,900.00\n" +
+				"02/08/22,UPI-SHOP-456,02/08/22,abc,0.00,0000456,800.00\n" +
+				"03/08/22,UPI-SHOP-789,03/08/22,50.00,0.00,0000789,750.00\n"
+			resp, err := service.ParseStatement(ctx, models.ParseStatementInput{
+				FileBytes:        []byte(content),
+				FileName:         "statement.txt",
+				AccountId:        acc.Id,
+				OriginalFilename: "statement.txt",
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+			job, ok := jobRepo.GetJob(1)
+			Expect(ok).To(BeTrue())
+
+			mockRepo.SetLinkError(errors.New("connection reset"))
+			job.Attempts = 1
+			Expect(service.handleParseStatementJob(ctx, job)).NotTo(Succeed())
+
+			mockRepo.SetLinkError(nil)
+			job.Attempts = 2
+			Expect(service.handleParseStatementJob(ctx, job)).To(Succeed())
+
+			result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+			Expect(result.Status).To(Equal(models.StatementStatusDone))
+			Expect(*result.Message).To(Equal("Processed 3 transactions: 2 inserted, 0 duplicates, 1 failed"))
+
+			txnIds, err := mockRepo.ListStatementTransactionIds(ctx, resp.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txnIds).To(HaveLen(2))
+
+			rowErrors, err := service.GetStatementRowErrors(ctx, resp.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rowErrors).To(HaveLen(1))
+		})
+
 		It("should handle statement with metadata", func() {
 			accInput := models.CreateAccountInput{
 				Name:      "Test Account",
@@ -770,6 +1169,633 @@ var _ = Describe("StatementService", func() {
 		})
 	})
 
+	Describe("RecoverStaleStatements", func() {
+		It("should fail statements left processing by a previous run", func() {
+			stale, err := mockRepo.CreateStatement(ctx, models.CreateStatementInput{
+				AccountId:        1,
+				CreatedBy:        userId,
+				OriginalFilename: "stale.csv",
+				FileType:         "csv",
+				Status:           models.StatementStatusProcessing,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			done, err := mockRepo.CreateStatement(ctx, models.CreateStatementInput{
+				AccountId:        1,
+				CreatedBy:        userId,
+				OriginalFilename: "done.csv",
+				FileType:         "csv",
+				Status:           models.StatementStatusDone,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.RecoverStaleStatements(ctx)).To(Succeed())
+
+			result, err := service.GetStatementStatus(ctx, stale.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(models.StatementStatusError))
+			Expect(*result.Message).To(ContainSubstring("interrupted"))
+			result, err = service.GetStatementStatus(ctx, done.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(models.StatementStatusDone))
+		})
+	})
+
+	Describe("RevertStatement", func() {
+		var acc models.AccountResponse
+
+		BeforeEach(func() {
+			var err error
+			acc, err = accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "HDFC Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		importStatement := func() models.StatementResponse {
+			content := "Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n" +
+				"01/08/22,UPI-SHOP-123,01/08/22,100.00,0.00,0000123,900.00\n" +
+				"02/08/22,UPI-SALARY-456,02/08/22,0.00,500.00,0000456,1400.00\n"
+			resp, err := service.ParseStatement(ctx, models.ParseStatementInput{
+				FileBytes:        []byte(content),
+				FileName:         "statement.txt",
+				AccountId:        acc.Id,
+				OriginalFilename: "statement.txt",
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, resp.Id, userId)
+				return result.Status
+			}, "2s", "100ms").Should(Equal(models.StatementStatusDone))
+			return resp
+		}
+
+		It("should delete the imported transactions and their transfers", func() {
+			statement := importStatement()
+			imported, err := txnService.ListTransactions(ctx, userId, models.TransactionListQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imported.Transactions).To(HaveLen(2))
+
+			savings, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "Savings",
+				BankType:  models.BankTypeOthers,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			sourceId := imported.Transactions[0].Id
+			amount := -imported.Transactions[0].Amount
+			_, err = txnService.CreateTransaction(ctx, models.CreateTransactionInput{
+				CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+					Name:                "Transfer from import",
+					Amount:              &amount,
+					Date:                imported.Transactions[0].Date,
+					CreatedBy:           userId,
+					AccountId:           savings.Id,
+					SourceTransactionId: &sourceId,
+				},
+				CategoryIds: []int64{},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			manualAmount := 42.0
+			_, err = txnService.CreateTransaction(ctx, models.CreateTransactionInput{
+				CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+					Name:      "Manual entry",
+					Amount:    &manualAmount,
+					Date:      imported.Transactions[0].Date,
+					CreatedBy: userId,
+					AccountId: acc.Id,
+				},
+				CategoryIds: []int64{},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			reverted, err := service.RevertStatement(ctx, statement.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reverted.Status).To(Equal(models.StatementStatusReverted))
+			Expect(*reverted.Message).To(Equal("Reverted import, deleted 3 transactions"))
+
+			remaining, err := txnService.ListTransactions(ctx, userId, models.TransactionListQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remaining.Transactions).To(HaveLen(1))
+			Expect(remaining.Transactions[0].Name).To(Equal("Manual entry"))
+		})
+
+		It("should not revert a statement twice", func() {
+			statement := importStatement()
+			_, err := service.RevertStatement(ctx, statement.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = service.RevertStatement(ctx, statement.Id, userId)
+			var authErr *customErrors.AuthError
+			Expect(errors.As(err, &authErr)).To(BeTrue())
+			Expect(authErr.Message).To(Equal("statement is already reverted"))
+		})
+
+		It("should not revert a statement that is still being processed", func() {
+			statement, err := mockRepo.CreateStatement(ctx, models.CreateStatementInput{
+				AccountId:        acc.Id,
+				CreatedBy:        userId,
+				OriginalFilename: "pending.csv",
+				FileType:         "csv",
+				Status:           models.StatementStatusPending,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = service.RevertStatement(ctx, statement.Id, userId)
+			var authErr *customErrors.AuthError
+			Expect(errors.As(err, &authErr)).To(BeTrue())
+			Expect(authErr.Message).To(Equal("statement is still being processed"))
+		})
+
+		It("should error when reverting a statement of another user", func() {
+			statement := importStatement()
+			_, err := service.RevertStatement(ctx, statement.Id, userId+1)
+			Expect(err).To(HaveOccurred())
+
+			result, _ := service.GetStatementStatus(ctx, statement.Id, userId)
+			Expect(result.Status).To(Equal(models.StatementStatusDone))
+		})
+	})
+
+	Describe("Parser templates", func() {
+		var acc models.AccountResponse
+		content := "Txn Date,Particulars,Withdrawal\n" +
+			"2024-01-15,Grocery Store,250.00\n" +
+			"2024-01-16,Fuel,1000.00\n"
+
+		BeforeEach(func() {
+			var err error
+			acc, err = accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "Co-op Account",
+				BankType:  models.BankTypeOthers,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = templateService.CreateParserTemplate(ctx, models.CreateParserTemplateInput{
+				Name:      "Co-op export",
+				AccountId: &acc.Id,
+				Metadata:  json.RawMessage(`{"column_mapping": {"txn_date": "Txn Date", "name": "Particulars", "amount": "Withdrawal"}}`),
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should read statements uploaded without metadata with the account's template", func() {
+			statement, err := service.ParseStatement(ctx, models.ParseStatementInput{
+				FileBytes:        []byte(content),
+				AccountId:        acc.Id,
+				OriginalFilename: "export.csv",
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, statement.Id, userId)
+				return result.Status
+			}, "2s", "100ms").Should(Equal(models.StatementStatusDone))
+			result, err := service.GetStatementStatus(ctx, statement.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*result.InsertedCount).To(Equal(2))
+			Expect(*result.DetectedFormat).To(Equal(string(models.BankTypeOthers)))
+		})
+
+		It("should prefer the metadata sent with the upload", func() {
+			statement, err := service.ParseStatement(ctx, models.ParseStatementInput{
+				FileBytes:        []byte(content),
+				AccountId:        acc.Id,
+				OriginalFilename: "export.csv",
+				Metadata:         `{"column_mapping": {"txn_date": "Txn Date", "name": "Particulars", "amount": "Missing"}}`,
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, statement.Id, userId)
+				return result.Status
+			}, "2s", "100ms").Should(Equal(models.StatementStatusError))
+		})
+	})
+
+	Describe("WatchStatement", func() {
+		var acc models.AccountResponse
+
+		BeforeEach(func() {
+			var err error
+			acc, err = accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "Watched Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should stream the progress of a statement until its rules have run", func() {
+			// Hold the workers back so the statement is watched before it is processed.
+			jobQueue.Stop()
+			statement, err := service.ParseStatement(ctx, models.ParseStatementInput{
+				FileBytes: []byte("Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n" +
+					"01/08/22,UPI-SHOP-123,01/08/22,100.00,0.00,0000123,900.00\n" +
+					"02/08/22,UPI-SALARY-456,02/08/22,0.00,500.00,0000456,1400.00\n"),
+				AccountId:        acc.Id,
+				OriginalFilename: "watched.csv",
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			current, events, unsubscribe, err := service.WatchStatement(ctx, statement.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			defer unsubscribe()
+			Expect(current.Status).To(Equal(models.StatementStatusPending))
+			jobQueue.Start()
+
+			var received []models.StatementEvent
+			Eventually(func() bool {
+				select {
+				case event := <-events:
+					received = append(received, event)
+					return event.IsFinal()
+				default:
+					return false
+				}
+			}, "2s", "10ms").Should(BeTrue())
+
+			Expect(received[0].Type).To(Equal(models.StatementEventStatus))
+			Expect(received[0].Status).To(Equal(models.StatementStatusProcessing))
+
+			var progress, done *models.StatementEvent
+			for i := range received {
+				switch {
+				case received[i].Type == models.StatementEventProgress:
+					progress = &received[i]
+				case received[i].Status == models.StatementStatusDone && received[i].Type == models.StatementEventStatus:
+					done = &received[i]
+				}
+			}
+			Expect(progress).NotTo(BeNil())
+			Expect(*progress.ParsedCount).To(Equal(2))
+			Expect(done).NotTo(BeNil())
+			Expect(*done.InsertedCount).To(Equal(2))
+
+			rules := received[len(received)-1]
+			Expect(rules.Type).To(Equal(models.StatementEventRules))
+			Expect(rules.Status).To(Equal(models.StatementStatusDone))
+			Expect(*rules.ModifiedCount).To(Equal(0))
+		})
+
+		It("should not watch statements of other users", func() {
+			statement, err := mockRepo.CreateStatement(ctx, models.CreateStatementInput{
+				AccountId:        acc.Id,
+				CreatedBy:        userId,
+				OriginalFilename: "private.csv",
+				FileType:         "csv",
+				Status:           models.StatementStatusPending,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, _, err = service.WatchStatement(ctx, statement.Id, userId+1)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Duplicate statement files", func() {
+		var (
+			acc   models.AccountResponse
+			input models.ParseStatementInput
+		)
+
+		BeforeEach(func() {
+			var err error
+			acc, err = accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "Duplicate Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			input = models.ParseStatementInput{
+				FileBytes: []byte("Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n" +
+					"01/08/22,UPI-SHOP-123,01/08/22,100.00,0.00,0000123,900.00\n"),
+				AccountId:        acc.Id,
+				OriginalFilename: "august.csv",
+			}
+		})
+
+		It("should reject a file that was already uploaded to the account", func() {
+			first, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			input.OriginalFilename = "august_copy.csv"
+			_, err = service.ParseStatement(ctx, input, userId)
+			var authErr *customErrors.AuthError
+			Expect(errors.As(err, &authErr)).To(BeTrue())
+			Expect(authErr.Status).To(Equal(http.StatusConflict))
+			Expect(authErr.Message).To(Equal("statement file was already uploaded"))
+			Expect(authErr.Data.(models.StatementResponse).Id).To(Equal(first.Id))
+		})
+
+		It("should accept the same file for another account", func() {
+			_, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			other, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "Other Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			input.AccountId = other.Id
+			statement, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statement.DuplicateOf).To(BeNil())
+		})
+
+		It("should accept the file again after its import failed", func() {
+			input.BankType = "unknown_bank"
+			failed, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, failed.Id, userId)
+				return result.Status
+			}, "2s", "50ms").Should(Equal(models.StatementStatusError))
+
+			input.BankType = ""
+			statement, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statement.DuplicateOf).To(BeNil())
+
+			// The failed import cannot be brought back next to the new one.
+			_, err = service.ReprocessStatement(ctx, failed.Id, userId, models.ReprocessStatementInput{})
+			var authErr *customErrors.AuthError
+			Expect(errors.As(err, &authErr)).To(BeTrue())
+			Expect(authErr.Status).To(Equal(http.StatusConflict))
+		})
+
+		It("should upload the file again when forced", func() {
+			first, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			input.Force = true
+			statement, err := service.ParseStatement(ctx, input, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*statement.DuplicateOf).To(Equal(first.Id))
+			Expect(*statement.ContentHash).To(Equal(*first.ContentHash))
+		})
+	})
+
+	Describe("Card statement dues", func() {
+		var card models.AccountResponse
+
+		cardStatement := func(dueDate time.Time, total string) []byte {
+			return utils.CreateXLSXFile([][]string{
+				{"Total Payment Due", "Minimum Payment Due", "Statement Period", "Payment Due Date", ""},
+				{total, "500.00", "15 Oct 2025 - 14 Nov 2025", dueDate.Format("02 Jan 2006"), ""},
+				{"Date", "Transaction Details", "", "Amount (INR)", "Debit/Credit"},
+				{"14 Nov '25", "Restro,MUMBAI", "", "₹ " + total, "Debit"},
+			})
+		}
+		upload := func(accountId int64, fileBytes []byte, fileName string) models.StatementResponse {
+			statement, err := service.ParseStatement(ctx, models.ParseStatementInput{
+				FileBytes:        fileBytes,
+				AccountId:        accountId,
+				OriginalFilename: fileName,
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, statement.Id, userId)
+				return result.Status
+			}, "2s", "100ms").Should(Equal(models.StatementStatusDone))
+			result, err := service.GetStatementStatus(ctx, statement.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			return result
+		}
+		today := func() time.Time {
+			now := time.Now()
+			return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		}
+
+		BeforeEach(func() {
+			var err error
+			card, err = accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "Axis Card",
+				BankType:  models.BankTypeAxisCredit,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should save the billing details of card statements", func() {
+			dueDate := today().AddDate(0, 0, 10)
+			statement := upload(card.Id, cardStatement(dueDate, "7,977.00"), "november.xlsx")
+			Expect(*statement.TotalDue).To(Equal(7977.00))
+			Expect(*statement.MinimumDue).To(Equal(500.00))
+			Expect(*statement.StatementPeriodStart).To(Equal(time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)))
+			Expect(*statement.StatementPeriodEnd).To(Equal(time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC)))
+			Expect(*statement.PaymentDueDate).To(Equal(dueDate))
+		})
+
+		It("should not set billing details for bank statements", func() {
+			acc, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "HDFC Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			statement := upload(acc.Id, []byte("Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n"+
+				"01/08/22,UPI-SHOP-123,01/08/22,100.00,0.00,0000123,900.00\n"), "august.csv")
+			Expect(statement.PaymentDueDate).To(BeNil())
+			Expect(statement.TotalDue).To(BeNil())
+		})
+
+		It("should list the latest unpaid bill of every card, soonest first", func() {
+			other, err := accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "Second Card",
+				BankType:  models.BankTypeAxisCredit,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			upload(card.Id, cardStatement(today().AddDate(0, 0, -20), "100.00"), "october.xlsx")
+			latest := upload(card.Id, cardStatement(today().AddDate(0, 0, 12), "200.00"), "november.xlsx")
+			otherDue := upload(other.Id, cardStatement(today(), "300.00"), "other.xlsx")
+
+			dues, err := service.ListUpcomingCardDues(ctx, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dues).To(HaveLen(2))
+			Expect(dues[0].StatementId).To(Equal(otherDue.Id))
+			Expect(dues[0].AccountName).To(Equal("Second Card"))
+			Expect(*dues[0].TotalDue).To(Equal(300.00))
+			Expect(dues[1].StatementId).To(Equal(latest.Id))
+			Expect(dues[1].AccountId).To(Equal(card.Id))
+			Expect(dues[1].PaymentDueDate).To(Equal(today().AddDate(0, 0, 12)))
+		})
+
+		It("should leave out bills that are past due or reverted", func() {
+			upload(card.Id, cardStatement(today().AddDate(0, 0, -1), "100.00"), "october.xlsx")
+			reverted := upload(card.Id, cardStatement(today().AddDate(0, 0, 5), "200.00"), "november.xlsx")
+			_, err := service.RevertStatement(ctx, reverted.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			dues, err := service.ListUpcomingCardDues(ctx, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dues).To(BeEmpty())
+		})
+
+		It("should not list the bills of other users", func() {
+			upload(card.Id, cardStatement(today().AddDate(0, 0, 3), "100.00"), "november.xlsx")
+			dues, err := service.ListUpcomingCardDues(ctx, userId+1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dues).To(BeEmpty())
+		})
+	})
+
+	Describe("ReprocessStatement", func() {
+		var (
+			acc     models.AccountResponse
+			content string
+		)
+
+		BeforeEach(func() {
+			var err error
+			acc, err = accountService.CreateAccount(ctx, models.CreateAccountInput{
+				Name:      "HDFC Account",
+				BankType:  models.BankTypeHDFC,
+				Currency:  models.CurrencyINR,
+				CreatedBy: userId,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			content = "Date,Narration,Value Dat,Debit Amount,Credit Amount,Chq/Ref Number,Closing Balance\n" +
+				"01/08/22,UPI-SHOP-123,01/08/22,100.00,0.00,0000123,900.00\n" +
+				"02/08/22,UPI-SALARY-456,02/08/22,0.00,500.00,0000456,1400.00\n"
+		})
+
+		waitForStatus := func(statementId int64) models.StatementResponse {
+			Eventually(func() models.StatementStatus {
+				result, _ := service.GetStatementStatus(ctx, statementId, userId)
+				return result.Status
+			}, "2s", "100ms").Should(Equal(models.StatementStatusDone))
+			result, err := service.GetStatementStatus(ctx, statementId, userId)
+			Expect(err).NotTo(HaveOccurred())
+			return result
+		}
+
+		importStatement := func() models.StatementResponse {
+			resp, err := service.ParseStatement(ctx, models.ParseStatementInput{
+				FileBytes:        []byte(content),
+				AccountId:        acc.Id,
+				OriginalFilename: "statement.csv",
+			}, userId)
+			Expect(err).NotTo(HaveOccurred())
+			return waitForStatus(resp.Id)
+		}
+
+		It("should keep the uploaded file with its content hash", func() {
+			statement := importStatement()
+			hash := sha256.Sum256([]byte(content))
+			Expect(*statement.ContentHash).To(Equal(hex.EncodeToString(hash[:])))
+
+			file, fileBytes, err := service.GetStatementFile(ctx, statement.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.OriginalFilename).To(Equal("statement.csv"))
+			Expect(string(fileBytes)).To(Equal(content))
+		})
+
+		It("should import a reverted statement again", func() {
+			statement := importStatement()
+			_, err := service.RevertStatement(ctx, statement.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+
+			queued, err := service.ReprocessStatement(ctx, statement.Id, userId, models.ReprocessStatementInput{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queued.Status).To(Equal(models.StatementStatusPending))
+
+			result := waitForStatus(statement.Id)
+			Expect(*result.InsertedCount).To(Equal(2))
+			transactions, err := txnService.ListTransactions(ctx, userId, models.TransactionListQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transactions.Transactions).To(HaveLen(2))
+		})
+
+		It("should replace the previous import when reprocessing with a new bank type", func() {
+			statement := importStatement()
+			bankType := string(models.BankTypeHDFC)
+
+			queued, err := service.ReprocessStatement(ctx, statement.Id, userId, models.ReprocessStatementInput{BankType: &bankType})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*queued.Message).To(Equal("Queued for reprocessing"))
+			Expect(*queued.BankType).To(Equal(bankType))
+
+			result := waitForStatus(statement.Id)
+			Expect(*result.InsertedCount).To(Equal(2))
+			Expect(*result.DuplicateCount).To(Equal(0))
+			transactions, err := txnService.ListTransactions(ctx, userId, models.TransactionListQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transactions.Transactions).To(HaveLen(2))
+		})
+
+		It("should keep the previous import when the statement cannot be queued", func() {
+			statement := importStatement()
+			jobRepo := repository.NewMockJobRepository()
+			jobRepo.SetCreateError(errors.New("queue unavailable"))
+			service.jobQueue = NewJobQueue(jobRepo, testJobConfig())
+
+			_, err := service.ReprocessStatement(ctx, statement.Id, userId, models.ReprocessStatementInput{})
+			Expect(err).To(HaveOccurred())
+
+			result, err := service.GetStatementStatus(ctx, statement.Id, userId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(models.StatementStatusDone))
+			Expect(*result.Message).To(ContainSubstring("queue unavailable"))
+			transactions, err := txnService.ListTransactions(ctx, userId, models.TransactionListQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transactions.Transactions).To(HaveLen(2))
+			txnIds, err := mockRepo.ListStatementTransactionIds(ctx, statement.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(txnIds).To(HaveLen(2))
+		})
+
+		It("should not reprocess a statement that is still being processed", func() {
+			statement, err := mockRepo.CreateStatement(ctx, models.CreateStatementInput{
+				AccountId:        acc.Id,
+				CreatedBy:        userId,
+				OriginalFilename: "pending.csv",
+				FileType:         "csv",
+				Status:           models.StatementStatusProcessing,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = service.ReprocessStatement(ctx, statement.Id, userId, models.ReprocessStatementInput{})
+			var authErr *customErrors.AuthError
+			Expect(errors.As(err, &authErr)).To(BeTrue())
+			Expect(authErr.Message).To(Equal("statement is still being processed"))
+		})
+
+		It("should report statements without a stored file", func() {
+			statement, err := mockRepo.CreateStatement(ctx, models.CreateStatementInput{
+				AccountId:        acc.Id,
+				CreatedBy:        userId,
+				OriginalFilename: "old.csv",
+				FileType:         "csv",
+				Status:           models.StatementStatusDone,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = service.ReprocessStatement(ctx, statement.Id, userId, models.ReprocessStatementInput{})
+			var authErr *customErrors.AuthError
+			Expect(errors.As(err, &authErr)).To(BeTrue())
+			Expect(authErr.Message).To(Equal("statement file not found"))
+
+			_, _, err = service.GetStatementFile(ctx, statement.Id, userId)
+			Expect(errors.As(err, &authErr)).To(BeTrue())
+			Expect(authErr.Message).To(Equal("statement file not found"))
+		})
+	})
+
 	Describe("Input Validation Edge Cases", func() {
 		It("should error when getting statement with negative ID", func() {
 			_, err := service.GetStatementStatus(ctx, -1, userId)
@@ -824,7 +1850,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
@@ -839,7 +1865,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 2
 				rowSize := 10
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
@@ -854,7 +1880,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 2
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
@@ -869,7 +1895,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 1 // Use 1 instead of 0 since validator requires positive rowSize
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
@@ -882,7 +1908,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
@@ -900,7 +1926,7 @@ var _ = Describe("StatementService", func() {
 				rowSize := 10
 
 				// Note: This test might fail with actual XLS parsing, but validates the flow
-				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				// We expect this to either succeed or fail with a parsing error, not a validation error
 				if err != nil {
 					Expect(err.Error()).NotTo(ContainSubstring("file is required"))
@@ -916,7 +1942,7 @@ var _ = Describe("StatementService", func() {
 				rowSize := 10
 
 				// Note: This test might fail with actual XLSX parsing, but validates the flow
-				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				// We expect this to either succeed or fail with a parsing error, not a validation error
 				if err != nil {
 					Expect(err.Error()).NotTo(ContainSubstring("file is required"))
@@ -925,6 +1951,26 @@ var _ = Describe("StatementService", func() {
 			})
 		})
 
+		Describe("Workbook sheets", func() {
+			It("should list the sheets and preview the selected one", func() {
+				fileBytes := utils.CreateXLSXFile([][]string{{"Date", "Description", "Amount"}})
+				index := 0
+				preview, err := service.PreviewStatement(ctx, fileBytes, "test.xlsx", 0, 10, "", models.SheetSelector{SheetIndex: &index})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(preview.Sheets).To(Equal([]string{"Sheet1"}))
+				Expect(preview.Sheet).To(Equal("Sheet1"))
+				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
+			})
+
+			It("should return a bad request for a sheet that is not in the workbook", func() {
+				fileBytes := utils.CreateXLSXFile([][]string{{"Date", "Description", "Amount"}})
+				_, err := service.PreviewStatement(ctx, fileBytes, "test.xlsx", 0, 10, "", models.SheetSelector{SheetName: "Ledger"})
+				var authErr *customErrors.AuthError
+				Expect(errors.As(err, &authErr)).To(BeTrue())
+				Expect(authErr.Status).To(Equal(http.StatusBadRequest))
+			})
+		})
+
 		Describe("Edge cases", func() {
 			It("should return empty preview when skipRows exceeds file length", func() {
 				fileBytes := []byte("Date,Description,Amount\n2023-01-01,Test Transaction,100.00")
@@ -932,7 +1978,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 10
 				rowSize := 10
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(HaveLen(0))
@@ -945,7 +1991,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).To(HaveOccurred())
 				Expect(preview).To(BeNil())
 			})
@@ -956,7 +2002,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
@@ -969,7 +2015,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
@@ -982,7 +2028,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
@@ -997,7 +2043,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).To(HaveOccurred())
 				Expect(errors.Unwrap(err).Error()).To(ContainSubstring("file is required"))
 			})
@@ -1008,7 +2054,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).To(HaveOccurred())
 				Expect(errors.Unwrap(err).Error()).To(ContainSubstring("filename cannot be empty"))
 			})
@@ -1019,7 +2065,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).To(HaveOccurred())
 				Expect(errors.Unwrap(err).Error()).To(ContainSubstring("filename cannot be empty"))
 			})
@@ -1034,7 +2080,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				_, err := service.PreviewStatement(ctx, largeContent, fileName, skipRows, rowSize, "")
+				_, err := service.PreviewStatement(ctx, largeContent, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).To(HaveOccurred())
 				Expect(errors.Unwrap(err).Error()).To(ContainSubstring("file size must be less than 5MB"))
 			})
@@ -1045,7 +2091,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).To(HaveOccurred())
 				Expect(errors.Unwrap(err).Error()).To(ContainSubstring("file must be CSV or Excel format"))
 			})
@@ -1056,7 +2102,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := -1
 				rowSize := 10
 
-				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).To(HaveOccurred())
 				Expect(errors.Unwrap(err).Error()).To(ContainSubstring("skipRows cannot be negative"))
 			})
@@ -1067,7 +2113,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := -5
 
-				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).To(HaveOccurred())
 				Expect(errors.Unwrap(err).Error()).To(ContainSubstring("rowSize must be positive"))
 			})
@@ -1078,7 +2124,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 0
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).ToNot(HaveOccurred())
 				Expect(preview).ToNot(BeNil())
 			})
@@ -1089,16 +2135,16 @@ var _ = Describe("StatementService", func() {
 				rowSize := 10
 
 				// Test CSV
-				_, err := service.PreviewStatement(ctx, fileBytes, "test.csv", skipRows, rowSize, "")
+				_, err := service.PreviewStatement(ctx, fileBytes, "test.csv", skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 
 				// Test CSV with uppercase
-				_, err = service.PreviewStatement(ctx, fileBytes, "test.CSV", skipRows, rowSize, "")
+				_, err = service.PreviewStatement(ctx, fileBytes, "test.CSV", skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 
 				// Test XLS (will likely fail parsing but should pass validation)
 				xlsBytes := []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
-				_, err = service.PreviewStatement(ctx, xlsBytes, "test.xls", skipRows, rowSize, "")
+				_, err = service.PreviewStatement(ctx, xlsBytes, "test.xls", skipRows, rowSize, "", models.SheetSelector{})
 				// Should not fail with validation error
 				if err != nil {
 					underlyingErr := errors.Unwrap(err)
@@ -1109,7 +2155,7 @@ var _ = Describe("StatementService", func() {
 
 				// Test XLSX (will likely fail parsing but should pass validation)
 				xlsxBytes := []byte{0x50, 0x4B, 0x03, 0x04}
-				_, err = service.PreviewStatement(ctx, xlsxBytes, "test.xlsx", skipRows, rowSize, "")
+				_, err = service.PreviewStatement(ctx, xlsxBytes, "test.xlsx", skipRows, rowSize, "", models.SheetSelector{})
 				// Should not fail with validation error
 				if err != nil {
 					underlyingErr := errors.Unwrap(err)
@@ -1127,7 +2173,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 10
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
@@ -1140,7 +2186,7 @@ var _ = Describe("StatementService", func() {
 				skipRows := 0
 				rowSize := 1000
 
-				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+				preview, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(preview).NotTo(BeNil())
 				Expect(preview.Headers).To(Equal([]string{"Date", "Description", "Amount"}))
@@ -1154,7 +2200,7 @@ var _ = Describe("StatementService", func() {
 
 				testCases := []string{"test.Csv", "test.CSV", "test.cSv", "TEST.CSV"}
 				for _, fileName := range testCases {
-					_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "")
+					_, err := service.PreviewStatement(ctx, fileBytes, fileName, skipRows, rowSize, "", models.SheetSelector{})
 					Expect(err).NotTo(HaveOccurred())
 				}
 			})
@@ -4,6 +4,7 @@ import (
 	"context"
 	customErrors "expenses/internal/errors"
 	"expenses/internal/models"
+	"expenses/internal/parser"
 	"expenses/internal/repository"
 	database "expenses/pkg/database/manager"
 	"expenses/pkg/utils"
@@ -13,11 +14,13 @@ import (
 
 type TransactionServiceInterface interface {
 	CreateTransaction(ctx context.Context, input models.CreateTransactionInput) (models.TransactionResponse, error)
-	CreateTransactions(ctx context.Context, inputs []models.CreateTransactionInput) ([]models.TransactionResponse, error)
+	CreateTransactions(ctx context.Context, inputs []models.CreateTransactionInput) (models.BulkCreateTransactionsResult, error)
 	GetTransactionById(ctx context.Context, transactionId int64, userId int64) (models.TransactionResponse, error)
 	UpdateTransaction(ctx context.Context, transactionId int64, userId int64, input models.UpdateTransactionInput) (models.TransactionResponse, error)
 	DeleteTransaction(ctx context.Context, transactionId int64, userId int64) error
+	DeleteTransactions(ctx context.Context, transactionIds []int64, userId int64) (int64, error)
 	ListTransactions(ctx context.Context, userId int64, query models.TransactionListQuery) (models.PaginatedTransactionsResponse, error)
+	ExportTransactionsQIF(ctx context.Context, userId int64, query models.TransactionExportQuery) (models.AccountResponse, []byte, error)
 }
 
 type TransactionService struct {
@@ -51,9 +54,13 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, input models
 	return s.repo.CreateTransaction(ctx, transactionInput, input.CategoryIds)
 }
 
-func (s *TransactionService) CreateTransactions(ctx context.Context, inputs []models.CreateTransactionInput) ([]models.TransactionResponse, error) {
+// CreateTransactions inserts transactions in bulk. Transactions that already
+// exist are skipped and transactions that fail validation or insertion are
+// reported in the result; only an invalid account or category fails the call.
+func (s *TransactionService) CreateTransactions(ctx context.Context, inputs []models.CreateTransactionInput) (models.BulkCreateTransactionsResult, error) {
+	result := models.BulkCreateTransactionsResult{Inserted: []models.TransactionResponse{}}
 	if len(inputs) == 0 {
-		return []models.TransactionResponse{}, nil
+		return result, nil
 	}
 
 	// Optimize validation by collecting unique accounts and categories
@@ -61,11 +68,14 @@ func (s *TransactionService) CreateTransactions(ctx context.Context, inputs []mo
 	uniqueCategoryIds := make(map[int64]bool)
 
 	// Validate all transactions and collect unique IDs
-	for _, input := range inputs {
+	valid := make([]int, 0, len(inputs))
+	for i, input := range inputs {
 		// Validate date
 		if err := s.validateDateNotInFuture(input.Date); err != nil {
-			return nil, err
+			result.Failed = append(result.Failed, models.BulkCreateTransactionFailure{Index: i, Err: err})
+			continue
 		}
+		valid = append(valid, i)
 
 		// Collect unique account IDs
 		uniqueAccountIds[input.AccountId] = input.CreatedBy
@@ -79,7 +89,7 @@ func (s *TransactionService) CreateTransactions(ctx context.Context, inputs []mo
 	// Validate each unique account only once
 	for accountId, userId := range uniqueAccountIds {
 		if err := s.validateAccountExists(ctx, accountId, userId); err != nil {
-			return nil, err
+			return result, err
 		}
 	}
 
@@ -98,19 +108,29 @@ func (s *TransactionService) CreateTransactions(ctx context.Context, inputs []mo
 		}
 
 		if err := s.validateCategoryExists(ctx, categoryIdSlice, userId); err != nil {
-			return nil, err
+			return result, err
 		}
 	}
 
 	// Convert to base transaction inputs
-	baseInputs := make([]models.CreateBaseTransactionInput, len(inputs))
-	categoryIds := make([][]int64, len(inputs))
-	for i, input := range inputs {
-		utils.ConvertStruct(&input, &baseInputs[i])
-		categoryIds[i] = input.CategoryIds
+	baseInputs := make([]models.CreateBaseTransactionInput, len(valid))
+	categoryIds := make([][]int64, len(valid))
+	for i, idx := range valid {
+		utils.ConvertStruct(&inputs[idx], &baseInputs[i])
+		categoryIds[i] = inputs[idx].CategoryIds
 	}
 
-	return s.repo.CreateTransactions(ctx, baseInputs, categoryIds)
+	created, err := s.repo.CreateTransactions(ctx, baseInputs, categoryIds)
+	if err != nil {
+		return result, err
+	}
+	result.Inserted = created.Inserted
+	result.Duplicates = created.Duplicates
+	// Report failures by their position in the original input.
+	for _, failure := range created.Failed {
+		result.Failed = append(result.Failed, models.BulkCreateTransactionFailure{Index: valid[failure.Index], Err: failure.Err})
+	}
+	return result, nil
 }
 
 func (s *TransactionService) GetTransactionById(ctx context.Context, transactionId int64, userId int64) (models.TransactionResponse, error) {
@@ -161,6 +181,12 @@ func (s *TransactionService) DeleteTransaction(ctx context.Context, transactionI
 	return s.repo.DeleteTransaction(ctx, transactionId, userId)
 }
 
+// DeleteTransactions deletes the given transactions and the transfers rules
+// created from them.
+func (s *TransactionService) DeleteTransactions(ctx context.Context, transactionIds []int64, userId int64) (int64, error) {
+	return s.repo.DeleteTransactions(ctx, transactionIds, userId)
+}
+
 // ListTransactions returns paginated, sorted, and filtered transactions for a user
 func (s *TransactionService) ListTransactions(ctx context.Context, userId int64, query models.TransactionListQuery) (models.PaginatedTransactionsResponse, error) {
 	if query.Page < 1 {
@@ -173,6 +199,47 @@ func (s *TransactionService) ListTransactions(ctx context.Context, userId int64,
 	return s.repo.ListTransactions(ctx, userId, query)
 }
 
+// ExportTransactionsQIF returns the transactions of an account in a date range
+// as a QIF file, oldest first, along with the account.
+func (s *TransactionService) ExportTransactionsQIF(ctx context.Context, userId int64, query models.TransactionExportQuery) (models.AccountResponse, []byte, error) {
+	account, err := s.accountRepo.GetAccountById(ctx, query.AccountId, userId)
+	if err != nil {
+		return account, nil, err
+	}
+
+	categories, err := s.categoryRepo.ListCategories(ctx, userId)
+	if err != nil {
+		return account, nil, err
+	}
+	categoryNames := make(map[int64]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.Id] = category.Name
+	}
+
+	listQuery := models.TransactionListQuery{
+		Page:      1,
+		PageSize:  100,
+		SortBy:    "date",
+		SortOrder: "asc",
+		AccountId: &account.Id,
+		DateFrom:  query.DateFrom,
+		DateTo:    query.DateTo,
+	}
+	var transactions []models.TransactionResponse
+	for {
+		page, err := s.repo.ListTransactions(ctx, userId, listQuery)
+		if err != nil {
+			return account, nil, err
+		}
+		transactions = append(transactions, page.Transactions...)
+		if len(page.Transactions) == 0 || len(transactions) >= page.Total {
+			break
+		}
+		listQuery.Page++
+	}
+	return account, parser.WriteQIF(account.BankType, transactions, categoryNames), nil
+}
+
 // validateCreateTransaction performs business rule validation for create operations
 func (s *TransactionService) validateCreateTransaction(ctx context.Context, input models.CreateTransactionInput) error {
 	if err := s.validateDateNotInFuture(input.Date); err != nil {
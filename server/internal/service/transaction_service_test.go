@@ -165,6 +165,55 @@ var _ = Describe("TransactionService", func() {
 		})
 	})
 
+	Describe("CreateTransactions", func() {
+		newInput := func(name string, amount float64, date time.Time) models.CreateTransactionInput {
+			return models.CreateTransactionInput{
+				CreateBaseTransactionInput: models.CreateBaseTransactionInput{
+					Name:        name,
+					Description: name,
+					Amount:      &amount,
+					Date:        date,
+					CreatedBy:   userId,
+					AccountId:   acc1.Id,
+				},
+				CategoryIds: []int64{},
+			}
+		}
+
+		It("should skip existing transactions and insert the rest", func() {
+			existing := newInput("Groceries", 100.0, testDate)
+			existing.Description = "Weekly groceries"
+			result, err := transactionService.CreateTransactions(ctx, []models.CreateTransactionInput{
+				existing,
+				newInput("Coffee", 4.5, testDate),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Inserted).To(HaveLen(1))
+			Expect(result.Inserted[0].Name).To(Equal("Coffee"))
+			Expect(result.Duplicates).To(Equal(1))
+			Expect(result.Failed).To(BeEmpty())
+		})
+
+		It("should report rows that fail validation by their input index", func() {
+			result, err := transactionService.CreateTransactions(ctx, []models.CreateTransactionInput{
+				newInput("Coffee", 4.5, testDate),
+				newInput("Future", 10.0, time.Now().AddDate(0, 0, 2)),
+				newInput("Tea", 3.0, testDate),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Inserted).To(HaveLen(2))
+			Expect(result.Failed).To(HaveLen(1))
+			Expect(result.Failed[0].Index).To(Equal(1))
+		})
+
+		It("should fail the whole import if the account does not exist", func() {
+			input := newInput("Coffee", 4.5, testDate)
+			input.AccountId = 999
+			_, err := transactionService.CreateTransactions(ctx, []models.CreateTransactionInput{input})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("UpdateTransaction", func() {
 		var createdTx models.TransactionResponse
 
@@ -680,6 +729,26 @@ var _ = Describe("TransactionService", func() {
 		})
 	})
 
+	Describe("ExportTransactionsQIF", func() {
+		It("should export the transactions of an account within the date range", func() {
+			dateFrom := testDate.AddDate(0, 0, 1)
+			account, qif, err := transactionService.ExportTransactionsQIF(ctx, userId, models.TransactionExportQuery{
+				AccountId: acc1.Id,
+				DateFrom:  &dateFrom,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(account.Name).To(Equal("HDFC"))
+			Expect(string(qif)).To(Equal("!Type:Bank\n" +
+				"D01/03/2023\nT-200.00\nPElectricity bill\nMMonthly bill\nLBills\n^\n" +
+				"D01/05/2023\nT-25.00\nPCash withdrawal\nMATM withdrawal\n^\n"))
+		})
+
+		It("should return error for an account of another user", func() {
+			_, _, err := transactionService.ExportTransactionsQIF(ctx, userId+1, models.TransactionExportQuery{AccountId: acc1.Id})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("TransactionService validation helpers", func() {
 		var (
 			transactionService *TransactionService
@@ -4,10 +4,25 @@ import (
 	"expenses/internal/errors"
 	"expenses/internal/models"
 	"fmt"
+	"regexp/syntax"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// maxRegexInstructions bounds the size of the compiled program of a regex
+// condition. Repeats such as \w{1000} pass the length limit of condition
+// values but expand into very large programs that every transaction would be
+// matched against.
+const maxRegexInstructions = 2000
+
+// maxConditionGroupDepth bounds the nesting of condition groups, and
+// maxRuleConditions the number of conditions of a rule across all its groups.
+const (
+	maxConditionGroupDepth = 5
+	maxRuleConditions      = 50
+)
+
 // RuleValidator centralizes all rule-related validation logic.
 type RuleValidator struct{}
 
@@ -43,9 +58,25 @@ func (v *RuleValidator) ValidateUpdateCondition(cond models.UpdateRuleConditionR
 			return err
 		}
 	}
+	if cond.ConditionOperator != nil && *cond.ConditionOperator == models.OperatorRegex && cond.ConditionValue != nil {
+		if err := v.validateRegex(*cond.ConditionValue); err != nil {
+			return err
+		}
+	}
+	if cond.ConditionType != nil && cond.ConditionOperator != nil && cond.ConditionValue != nil {
+		if err := v.validateValueCount(*cond.ConditionType, *cond.ConditionOperator, *cond.ConditionValue); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// ValidateCondition validates a whole condition, such as an existing condition
+// with an update applied to it.
+func (v *RuleValidator) ValidateCondition(cond models.CreateRuleConditionRequest) error {
+	return v.validateCondition(cond)
+}
+
 // ValidateUpdate validates an UpdateRuleRequest.
 func (v *RuleValidator) ValidateUpdate(rule models.UpdateRuleRequest) error {
 	if rule.EffectiveFrom != nil {
@@ -65,13 +96,8 @@ func (v *RuleValidator) Validate(rule models.CreateRuleRequest) error {
 			return err
 		}
 	}
-	if len(rule.Conditions) == 0 {
-		return errors.NewRuleNoConditionsError(fmt.Errorf("at least one condition is required"))
-	}
-	for _, condition := range rule.Conditions {
-		if err := v.validateCondition(condition); err != nil {
-			return err
-		}
+	if err := v.validateConditions(rule.Conditions, rule.ConditionGroups); err != nil {
+		return err
 	}
 	if err := v.validateEffectiveDate(rule.Rule.EffectiveFrom); err != nil {
 		return err
@@ -96,10 +122,27 @@ func (v *RuleValidator) validateRuleType(actionType models.RuleFieldType, value
 		if _, err := strconv.ParseFloat(value, 64); err != nil {
 			return errors.NewRuleInvalidConditionValueError(err)
 		}
-	case models.RuleFieldCategory, models.RuleFieldTransfer:
+	case models.RuleFieldCategory, models.RuleFieldTransfer, models.RuleFieldAccount:
 		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
 			return errors.NewRuleInvalidConditionValueError(fmt.Errorf("value for %s must be a valid ID", actionType))
 		}
+	case models.RuleFieldDate:
+		for _, part := range strings.Split(value, ",") {
+			if _, err := time.Parse(models.RuleDateLayout, strings.TrimSpace(part)); err != nil {
+				return errors.NewRuleInvalidConditionValueError(fmt.Errorf("value for %s must be a date in YYYY-MM-DD format", actionType))
+			}
+		}
+	case models.RuleFieldDayOfMonth:
+		for _, part := range strings.Split(value, ",") {
+			day, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || day < 1 || day > 31 {
+				return errors.NewRuleInvalidConditionValueError(fmt.Errorf("value for %s must be a day between 1 and 31", actionType))
+			}
+		}
+	case models.RuleFieldWeekday:
+		if !isWeekday(value) {
+			return errors.NewRuleInvalidConditionValueError(fmt.Errorf("value for %s must be a weekday such as monday", actionType))
+		}
 	case models.RuleFieldName, models.RuleFieldDescription:
 		// Already a string, but you could add length or charset checks here if needed.
 		if value == "" {
@@ -125,7 +168,8 @@ func (v *RuleValidator) validateAction(action models.CreateRuleActionRequest) er
 // ValidateConditionType checks if the condition type is valid.
 func (v *RuleValidator) validateConditionType(conditionType models.RuleFieldType) error {
 	switch conditionType {
-	case models.RuleFieldName, models.RuleFieldDescription, models.RuleFieldAmount, models.RuleFieldCategory, models.RuleFieldTransfer:
+	case models.RuleFieldName, models.RuleFieldDescription, models.RuleFieldAmount, models.RuleFieldCategory, models.RuleFieldTransfer,
+		models.RuleFieldAccount, models.RuleFieldDate, models.RuleFieldDayOfMonth, models.RuleFieldWeekday:
 		return nil
 	default:
 		return errors.NewRuleInvalidConditionTypeError(fmt.Errorf("condition type %s is not valid", conditionType))
@@ -136,15 +180,28 @@ func (v *RuleValidator) validateConditionType(conditionType models.RuleFieldType
 func (v *RuleValidator) validateOperator(op models.RuleOperator, fieldType models.RuleFieldType) error {
 	switch fieldType {
 	case models.RuleFieldAmount:
-		if op == models.OperatorEquals || op == models.OperatorGreater || op == models.OperatorLower {
+		switch op {
+		case models.OperatorEquals, models.OperatorNotEquals, models.OperatorGreater, models.OperatorLower:
 			return nil
 		}
 	case models.RuleFieldName, models.RuleFieldDescription:
-		if op == models.OperatorEquals || op == models.OperatorContains {
+		switch op {
+		case models.OperatorEquals, models.OperatorNotEquals, models.OperatorContains, models.OperatorNotContains,
+			models.OperatorStartsWith, models.OperatorEndsWith, models.OperatorRegex:
 			return nil
 		}
-	case models.RuleFieldCategory, models.RuleFieldTransfer:
-		if op == models.OperatorEquals {
+	case models.RuleFieldCategory, models.RuleFieldTransfer, models.RuleFieldAccount, models.RuleFieldWeekday:
+		if op == models.OperatorEquals || op == models.OperatorNotEquals {
+			return nil
+		}
+	case models.RuleFieldDate:
+		switch op {
+		case models.OperatorBefore, models.OperatorAfter, models.OperatorBetween:
+			return nil
+		}
+	case models.RuleFieldDayOfMonth:
+		switch op {
+		case models.OperatorEquals, models.OperatorNotEquals, models.OperatorGreater, models.OperatorLower, models.OperatorBetween:
 			return nil
 		}
 	}
@@ -162,6 +219,71 @@ func (v *RuleValidator) validateCondition(cond models.CreateRuleConditionRequest
 	if err := v.validateOperator(cond.ConditionOperator, cond.ConditionType); err != nil {
 		return err
 	}
+	if cond.ConditionOperator == models.OperatorRegex {
+		if err := v.validateRegex(cond.ConditionValue); err != nil {
+			return err
+		}
+	}
+	if err := v.validateValueCount(cond.ConditionType, cond.ConditionOperator, cond.ConditionValue); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateValueCount checks that date and day of month conditions hold two
+// ordered values for the between operator and a single value otherwise.
+func (v *RuleValidator) validateValueCount(conditionType models.RuleFieldType, op models.RuleOperator, value string) error {
+	if conditionType != models.RuleFieldDate && conditionType != models.RuleFieldDayOfMonth {
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	if op != models.OperatorBetween {
+		if len(parts) != 1 {
+			return errors.NewRuleInvalidConditionValueError(fmt.Errorf("value for %s must be a single value for operator %s", conditionType, op))
+		}
+		return nil
+	}
+	if len(parts) != 2 {
+		return errors.NewRuleInvalidConditionValueError(fmt.Errorf("value for %s must be two values separated by a comma for operator %s", conditionType, op))
+	}
+	// Dates in YYYY-MM-DD format order the same way as their text.
+	from, to := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if conditionType == models.RuleFieldDayOfMonth {
+		fromDay, _ := strconv.Atoi(from)
+		toDay, _ := strconv.Atoi(to)
+		if fromDay > toDay {
+			return errors.NewRuleInvalidConditionValueError(fmt.Errorf("range for %s must start before it ends", conditionType))
+		}
+	} else if from > to {
+		return errors.NewRuleInvalidConditionValueError(fmt.Errorf("range for %s must start before it ends", conditionType))
+	}
+	return nil
+}
+
+func isWeekday(value string) bool {
+	value = strings.TrimSpace(value)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRegex checks that the pattern of a regex condition compiles and is
+// small enough to be matched against every transaction.
+func (v *RuleValidator) validateRegex(pattern string) error {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return errors.NewRuleInvalidConditionValueError(fmt.Errorf("invalid regex: %w", err))
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return errors.NewRuleInvalidConditionValueError(fmt.Errorf("invalid regex: %w", err))
+	}
+	if len(prog.Inst) > maxRegexInstructions {
+		return errors.NewRuleInvalidConditionValueError(fmt.Errorf("regex is too complex"))
+	}
 	return nil
 }
 
@@ -188,13 +310,60 @@ func (v *RuleValidator) ValidatePutActions(req models.PutRuleActionsRequest) err
 
 // ValidatePutConditions validates a PutRuleConditionsRequest.
 func (v *RuleValidator) ValidatePutConditions(req models.PutRuleConditionsRequest) error {
-	if len(req.Conditions) == 0 {
+	return v.validateConditions(req.Conditions, req.ConditionGroups)
+}
+
+// validateConditions validates the top level conditions of a rule and its
+// condition groups.
+func (v *RuleValidator) validateConditions(conditions []models.CreateRuleConditionRequest, groups []models.CreateRuleConditionGroupRequest) error {
+	if len(conditions) == 0 && len(groups) == 0 {
 		return errors.NewRuleNoConditionsError(fmt.Errorf("at least one condition is required"))
 	}
-	for _, condition := range req.Conditions {
+	for _, condition := range conditions {
 		if err := v.validateCondition(condition); err != nil {
 			return err
 		}
 	}
+	count := len(conditions)
+	for _, group := range groups {
+		groupCount, err := v.validateConditionGroup(group, 1)
+		if err != nil {
+			return err
+		}
+		count += groupCount
+	}
+	if count > maxRuleConditions {
+		return errors.NewRuleInvalidConditionGroupError(fmt.Errorf("a rule can have at most %d conditions", maxRuleConditions))
+	}
 	return nil
 }
+
+// validateConditionGroup validates a condition group and its nested groups at
+// the given depth. It returns the number of conditions in the group.
+func (v *RuleValidator) validateConditionGroup(group models.CreateRuleConditionGroupRequest, depth int) (int, error) {
+	if depth > maxConditionGroupDepth {
+		return 0, errors.NewRuleInvalidConditionGroupError(fmt.Errorf("condition groups can be nested at most %d levels deep", maxConditionGroupDepth))
+	}
+	switch group.Logic {
+	case models.ConditionLogicAnd, models.ConditionLogicOr, models.ConditionLogicNot:
+	default:
+		return 0, errors.NewRuleInvalidConditionGroupError(fmt.Errorf("logic %s is not valid", group.Logic))
+	}
+	if len(group.Conditions) == 0 && len(group.Groups) == 0 {
+		return 0, errors.NewRuleInvalidConditionGroupError(fmt.Errorf("a condition group must have at least one condition or group"))
+	}
+	for _, condition := range group.Conditions {
+		if err := v.validateCondition(condition); err != nil {
+			return 0, err
+		}
+	}
+	count := len(group.Conditions)
+	for _, child := range group.Groups {
+		childCount, err := v.validateConditionGroup(child, depth+1)
+		if err != nil {
+			return 0, err
+		}
+		count += childCount
+	}
+	return count, nil
+}
@@ -131,6 +131,176 @@ var _ = Describe("RuleValidator", func() {
 			req := models.UpdateRuleConditionRequest{}
 			Expect(v.ValidateUpdateCondition(req)).To(Succeed())
 		})
+
+		It("accepts the text operators for name and description", func() {
+			val := "swiggy"
+			for _, typ := range []models.RuleFieldType{models.RuleFieldName, models.RuleFieldDescription} {
+				for _, op := range []models.RuleOperator{
+					models.OperatorNotEquals, models.OperatorNotContains, models.OperatorStartsWith,
+					models.OperatorEndsWith, models.OperatorRegex,
+				} {
+					req := models.UpdateRuleConditionRequest{
+						ConditionType:     &typ,
+						ConditionValue:    &val,
+						ConditionOperator: &op,
+					}
+					Expect(v.ValidateUpdateCondition(req)).To(Succeed())
+				}
+			}
+		})
+
+		It("rejects the text operators for amounts", func() {
+			typ := models.RuleFieldAmount
+			val := "100"
+			for _, op := range []models.RuleOperator{models.OperatorStartsWith, models.OperatorRegex} {
+				req := models.UpdateRuleConditionRequest{
+					ConditionType:     &typ,
+					ConditionValue:    &val,
+					ConditionOperator: &op,
+				}
+				Expect(v.ValidateUpdateCondition(req)).ToNot(Succeed())
+			}
+		})
+
+		It("rejects regex patterns that do not compile", func() {
+			op := models.OperatorRegex
+			val := "UPI/(.*/SWIGGY"
+			req := models.UpdateRuleConditionRequest{ConditionValue: &val, ConditionOperator: &op}
+			Expect(v.ValidateUpdateCondition(req)).ToNot(Succeed())
+		})
+	})
+
+	Describe("Account, date, day of month and weekday conditions", func() {
+		condition := func(typ models.RuleFieldType, op models.RuleOperator, value string) models.CreateRuleConditionRequest {
+			return models.CreateRuleConditionRequest{
+				ConditionType:     typ,
+				ConditionValue:    value,
+				ConditionOperator: op,
+			}
+		}
+
+		It("accepts valid conditions", func() {
+			Expect(v.validateCondition(condition(models.RuleFieldAccount, models.OperatorEquals, "3"))).To(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldDate, models.OperatorBefore, "2024-03-01"))).To(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldDate, models.OperatorBetween, "2024-01-01, 2024-03-31"))).To(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldDayOfMonth, models.OperatorBetween, "1,5"))).To(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldDayOfMonth, models.OperatorGreater, "25"))).To(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldWeekday, models.OperatorEquals, "Saturday"))).To(Succeed())
+		})
+
+		It("rejects invalid values", func() {
+			Expect(v.validateCondition(condition(models.RuleFieldAccount, models.OperatorEquals, "main"))).ToNot(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldDate, models.OperatorAfter, "01/03/2024"))).ToNot(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldDayOfMonth, models.OperatorEquals, "32"))).ToNot(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldWeekday, models.OperatorEquals, "someday"))).ToNot(Succeed())
+		})
+
+		It("rejects operators that do not apply", func() {
+			Expect(v.validateCondition(condition(models.RuleFieldDate, models.OperatorEquals, "2024-03-01"))).ToNot(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldWeekday, models.OperatorBetween, "monday"))).ToNot(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldAccount, models.OperatorContains, "1"))).ToNot(Succeed())
+		})
+
+		It("checks the number and order of range values", func() {
+			Expect(v.validateCondition(condition(models.RuleFieldDayOfMonth, models.OperatorBetween, "5"))).ToNot(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldDayOfMonth, models.OperatorBetween, "10,5"))).ToNot(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldDayOfMonth, models.OperatorEquals, "1,5"))).ToNot(Succeed())
+			Expect(v.validateCondition(condition(models.RuleFieldDate, models.OperatorBetween, "2024-03-31,2024-01-01"))).ToNot(Succeed())
+		})
+
+		It("does not accept the new types as actions", func() {
+			Expect(v.validateAction(models.CreateRuleActionRequest{ActionType: models.RuleFieldDate, ActionValue: "2024-03-01"})).ToNot(Succeed())
+		})
+	})
+
+	Describe("Regex conditions", func() {
+		condition := func(pattern string) models.CreateRuleConditionRequest {
+			return models.CreateRuleConditionRequest{
+				ConditionType:     models.RuleFieldName,
+				ConditionValue:    pattern,
+				ConditionOperator: models.OperatorRegex,
+			}
+		}
+
+		It("accepts valid patterns", func() {
+			Expect(v.validateCondition(condition("UPI/.*/SWIGGY"))).To(Succeed())
+			Expect(v.validateCondition(condition(`^NEFT-\d{6}`))).To(Succeed())
+		})
+
+		It("rejects patterns that do not compile", func() {
+			err := v.validateCondition(condition("[a-z"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid regex"))
+		})
+
+		It("rejects patterns that compile into too large a program", func() {
+			err := v.validateCondition(condition(`\w{1000}\d{1000}x{500}`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("regex is too complex"))
+		})
+	})
+
+	Describe("Condition groups", func() {
+		nameContains := models.CreateRuleConditionRequest{
+			ConditionType:     models.RuleFieldName,
+			ConditionValue:    "AMZN",
+			ConditionOperator: models.OperatorContains,
+		}
+		nest := func(depth int) models.CreateRuleConditionGroupRequest {
+			group := models.CreateRuleConditionGroupRequest{
+				Logic:      models.ConditionLogicAnd,
+				Conditions: []models.CreateRuleConditionRequest{nameContains},
+			}
+			for i := 1; i < depth; i++ {
+				group = models.CreateRuleConditionGroupRequest{
+					Logic:  models.ConditionLogicNot,
+					Groups: []models.CreateRuleConditionGroupRequest{group},
+				}
+			}
+			return group
+		}
+		validate := func(groups ...models.CreateRuleConditionGroupRequest) error {
+			return v.ValidatePutConditions(models.PutRuleConditionsRequest{ConditionGroups: groups})
+		}
+
+		It("accepts rules with only condition groups", func() {
+			Expect(validate(nest(1))).To(Succeed())
+			Expect(validate(nest(5))).To(Succeed())
+		})
+
+		It("rejects groups nested too deeply", func() {
+			Expect(validate(nest(6))).ToNot(Succeed())
+		})
+
+		It("rejects empty groups and unknown logic", func() {
+			Expect(validate(models.CreateRuleConditionGroupRequest{Logic: models.ConditionLogicOr})).ToNot(Succeed())
+			Expect(validate(models.CreateRuleConditionGroupRequest{
+				Logic:      "XOR",
+				Conditions: []models.CreateRuleConditionRequest{nameContains},
+			})).ToNot(Succeed())
+		})
+
+		It("validates the conditions inside groups", func() {
+			Expect(validate(models.CreateRuleConditionGroupRequest{
+				Logic: models.ConditionLogicOr,
+				Conditions: []models.CreateRuleConditionRequest{
+					{ConditionType: models.RuleFieldAmount, ConditionValue: "abc", ConditionOperator: models.OperatorEquals},
+				},
+			})).ToNot(Succeed())
+		})
+
+		It("limits the number of conditions across groups", func() {
+			group := models.CreateRuleConditionGroupRequest{Logic: models.ConditionLogicOr}
+			for range 30 {
+				group.Conditions = append(group.Conditions, nameContains)
+			}
+			Expect(validate(group)).To(Succeed())
+			Expect(validate(group, group)).ToNot(Succeed())
+		})
+
+		It("still requires a condition or group", func() {
+			Expect(v.ValidatePutConditions(models.PutRuleConditionsRequest{})).ToNot(Succeed())
+		})
 	})
 
 	Describe("Validate (CreateRuleRequest)", func() {
@@ -26,8 +26,8 @@ func (v *StatementValidator) ValidateStatementUpload(accountId int64, fileBytes
 		return apierrors.NewStatementBadRequestError(errors.New("file size must be less than 5MB"))
 	}
 	trimmedFileName := strings.ToLower(strings.TrimSpace(fileName))
-	if !strings.HasSuffix(trimmedFileName, ".csv") && !strings.HasSuffix(trimmedFileName, ".xls") && !strings.HasSuffix(trimmedFileName, ".xlsx") && !strings.HasSuffix(trimmedFileName, ".txt") {
-		return apierrors.NewStatementBadRequestError(errors.New("file must be CSV or Excel format (.csv, .xls, .xlsx, .txt)"))
+	if !hasAnySuffix(trimmedFileName, ".csv", ".xls", ".xlsx", ".txt", ".pdf", ".ofx", ".qfx", ".xml", ".sta", ".mt940", ".940", ".qif") {
+		return apierrors.NewStatementBadRequestError(errors.New("file must be CSV, Excel, PDF, OFX, camt.053, MT940 or QIF format (.csv, .xls, .xlsx, .txt, .pdf, .ofx, .qfx, .xml, .sta, .mt940, .940, .qif)"))
 	}
 	return nil
 }
@@ -63,3 +63,12 @@ func (v *StatementValidator) ValidateStatementPreview(fileBytes []byte, fileName
 	}
 	return nil
 }
+
+func hasAnySuffix(fileName string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(fileName, suffix) {
+			return true
+		}
+	}
+	return false
+}
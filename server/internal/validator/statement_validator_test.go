@@ -92,6 +92,25 @@ var _ = Describe("StatementValidator", func() {
 				err := validator.ValidateStatementUpload(accountId, fileBytes, "test.xlsx")
 				Expect(err).NotTo(HaveOccurred())
 			})
+
+			It("should accept .ofx and .qfx files", func() {
+				err := validator.ValidateStatementUpload(accountId, fileBytes, "test.ofx")
+				Expect(err).NotTo(HaveOccurred())
+				err = validator.ValidateStatementUpload(accountId, fileBytes, "test.QFX")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should accept .pdf files", func() {
+				err := validator.ValidateStatementUpload(accountId, fileBytes, "test.pdf")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should accept camt.053, MT940 and QIF files", func() {
+				for _, name := range []string{"test.xml", "test.sta", "test.mt940", "test.940", "test.qif"} {
+					err := validator.ValidateStatementUpload(accountId, fileBytes, name)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			})
 		})
 
 		Context("with edge cases", func() {
@@ -4,6 +4,7 @@
 package wire
 
 import (
+	"context"
 	"expenses/internal/api"
 	"expenses/internal/api/controller"
 	"expenses/internal/config"
@@ -11,25 +12,46 @@ import (
 	"expenses/internal/service"
 	"expenses/internal/validator"
 	"expenses/pkg/database/manager"
+	"expenses/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 )
 
 type Provider struct {
-	Handler   *gin.Engine
-	dbManager manager.DatabaseManager
+	Handler          *gin.Engine
+	dbManager        manager.DatabaseManager
+	jobQueue         service.JobQueueInterface
+	statementService service.StatementServiceInterface
+}
+
+// StartWorkers cleans up statements left behind by a previous run and starts
+// processing queued jobs
+func (p *Provider) StartWorkers(ctx context.Context) error {
+	if err := p.statementService.RecoverStaleStatements(ctx); err != nil {
+		return err
+	}
+	p.jobQueue.Start()
+	return nil
 }
 
 // Close all connections app makes in various places
 func (p *Provider) Close() error {
+	p.jobQueue.Stop()
 	return p.dbManager.Close()
 }
 
-func NewProvider(handler *gin.Engine, dbManager manager.DatabaseManager) *Provider {
+func NewProvider(
+	handler *gin.Engine,
+	dbManager manager.DatabaseManager,
+	jobQueue service.JobQueueInterface,
+	statementService service.StatementServiceInterface,
+) *Provider {
 	return &Provider{
-		Handler:   handler,
-		dbManager: dbManager,
+		Handler:          handler,
+		dbManager:        dbManager,
+		jobQueue:         jobQueue,
+		statementService: statementService,
 	}
 }
 
@@ -41,6 +63,7 @@ func InitializeApplication() (*Provider, error) {
 var ProviderSet = wire.NewSet(
 	NewProvider,
 	manager.NewDatabaseManager,
+	storage.NewBlobStorage,
 	config.NewConfig,
 	api.Init,
 	controllerSet,
@@ -52,8 +75,10 @@ var ProviderSet = wire.NewSet(
 var controllerSet = wire.NewSet(
 	controller.NewAccountController,
 	controller.NewAnalyticsController,
+	controller.NewAppImportController,
 	controller.NewAuthController,
 	controller.NewCategoryController,
+	controller.NewParserTemplateController,
 	controller.NewRuleController,
 	controller.NewStatementController,
 	controller.NewTransactionController,
@@ -62,7 +87,10 @@ var controllerSet = wire.NewSet(
 var repositorySet = wire.NewSet(
 	repository.NewAccountRepository,
 	repository.NewAnalyticsRepository,
+	repository.NewAppImportRepository,
 	repository.NewCategoryRepository,
+	repository.NewJobRepository,
+	repository.NewParserTemplateRepository,
 	repository.NewRuleRepository,
 	repository.NewStatementRepository,
 	repository.NewTransactionRepository,
@@ -72,10 +100,14 @@ var repositorySet = wire.NewSet(
 var serviceSet = wire.NewSet(
 	service.NewAccountService,
 	service.NewAnalyticsService,
+	service.NewAppImportService,
 	service.NewAuthService,
 	service.NewCategoryService,
+	service.NewJobQueue,
+	service.NewParserTemplateService,
 	service.NewRuleEngineService,
 	service.NewRuleService,
+	service.NewStatementEventBus,
 	service.NewStatementService,
 	service.NewTransactionService,
 	service.NewUserService,
@@ -7,6 +7,7 @@
 package wire
 
 import (
+	"context"
 	"expenses/internal/api"
 	"expenses/internal/api/controller"
 	"expenses/internal/config"
@@ -14,6 +15,7 @@ import (
 	"expenses/internal/service"
 	"expenses/internal/validator"
 	"expenses/pkg/database/manager"
+	"expenses/pkg/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 )
@@ -40,47 +42,78 @@ func InitializeApplication() (*Provider, error) {
 	transactionServiceInterface := service.NewTransactionService(transactionRepositoryInterface, categoryRepositoryInterface, accountRepositoryInterface, databaseManager)
 	ruleRepositoryInterface := repository.NewRuleRepository(databaseManager, configConfig)
 	ruleServiceInterface := service.NewRuleService(ruleRepositoryInterface, transactionRepositoryInterface, databaseManager)
-	ruleEngineServiceInterface := service.NewRuleEngineService(ruleRepositoryInterface, transactionRepositoryInterface, categoryRepositoryInterface, accountRepositoryInterface)
+	jobRepositoryInterface := repository.NewJobRepository(databaseManager, configConfig)
+	jobQueueInterface := service.NewJobQueue(jobRepositoryInterface, configConfig)
+	statementEventBusInterface := service.NewStatementEventBus()
+	ruleEngineServiceInterface := service.NewRuleEngineService(ruleRepositoryInterface, transactionRepositoryInterface, categoryRepositoryInterface, accountRepositoryInterface, jobQueueInterface, statementEventBusInterface)
 	statementRepositoryInterface := repository.NewStatementRepository(databaseManager, configConfig)
+	parserTemplateRepositoryInterface := repository.NewParserTemplateRepository(databaseManager, configConfig)
+	parserTemplateServiceInterface := service.NewParserTemplateService(parserTemplateRepositoryInterface, accountServiceInterface)
 	statementValidator := validator.NewStatementValidator()
-	statementServiceInterface := service.NewStatementService(statementRepositoryInterface, accountServiceInterface, ruleEngineServiceInterface, statementValidator, transactionServiceInterface)
+	blobStorage, err := storage.NewBlobStorage(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	statementServiceInterface := service.NewStatementService(statementRepositoryInterface, accountServiceInterface, ruleEngineServiceInterface, statementValidator, transactionServiceInterface, jobQueueInterface, blobStorage, statementEventBusInterface, parserTemplateServiceInterface, categoryServiceInterface, configConfig)
 	analyticsRepositoryInterface := repository.NewAnalyticsRepository(databaseManager, configConfig)
 	analyticsServiceInterface := service.NewAnalyticsService(analyticsRepositoryInterface, accountRepositoryInterface)
-	engine := api.Init(configConfig, authServiceInterface, userServiceInterface, accountServiceInterface, categoryServiceInterface, transactionServiceInterface, ruleServiceInterface, ruleEngineServiceInterface, statementServiceInterface, analyticsServiceInterface)
-	provider := NewProvider(engine, databaseManager)
+	appImportRepositoryInterface := repository.NewAppImportRepository(databaseManager, configConfig)
+	appImportServiceInterface := service.NewAppImportService(appImportRepositoryInterface, accountServiceInterface, categoryServiceInterface, transactionServiceInterface, jobQueueInterface, blobStorage)
+	engine := api.Init(configConfig, authServiceInterface, userServiceInterface, accountServiceInterface, categoryServiceInterface, transactionServiceInterface, ruleServiceInterface, ruleEngineServiceInterface, statementServiceInterface, analyticsServiceInterface, parserTemplateServiceInterface, appImportServiceInterface)
+	provider := NewProvider(engine, databaseManager, jobQueueInterface, statementServiceInterface)
 	return provider, nil
 }
 
 // wire.go:
 
 type Provider struct {
-	Handler   *gin.Engine
-	dbManager manager.DatabaseManager
+	Handler          *gin.Engine
+	dbManager        manager.DatabaseManager
+	jobQueue         service.JobQueueInterface
+	statementService service.StatementServiceInterface
+}
+
+// StartWorkers cleans up statements left behind by a previous run and starts
+// processing queued jobs
+func (p *Provider) StartWorkers(ctx context.Context) error {
+	if err := p.statementService.RecoverStaleStatements(ctx); err != nil {
+		return err
+	}
+	p.jobQueue.Start()
+	return nil
 }
 
 // Close all connections app makes in various places
 func (p *Provider) Close() error {
+	p.jobQueue.Stop()
 	return p.dbManager.Close()
 }
 
-func NewProvider(handler *gin.Engine, dbManager manager.DatabaseManager) *Provider {
+func NewProvider(
+	handler *gin.Engine,
+	dbManager manager.DatabaseManager,
+	jobQueue service.JobQueueInterface,
+	statementService service.StatementServiceInterface,
+) *Provider {
 	return &Provider{
-		Handler:   handler,
-		dbManager: dbManager,
+		Handler:          handler,
+		dbManager:        dbManager,
+		jobQueue:         jobQueue,
+		statementService: statementService,
 	}
 }
 
 var ProviderSet = wire.NewSet(
-	NewProvider, manager.NewDatabaseManager, config.NewConfig, api.Init, controllerSet,
+	NewProvider, manager.NewDatabaseManager, storage.NewBlobStorage, config.NewConfig, api.Init, controllerSet,
 	repositorySet,
 	serviceSet,
 	validatorSet,
 )
 
-var controllerSet = wire.NewSet(controller.NewAccountController, controller.NewAnalyticsController, controller.NewAuthController, controller.NewCategoryController, controller.NewRuleController, controller.NewStatementController, controller.NewTransactionController)
+var controllerSet = wire.NewSet(controller.NewAccountController, controller.NewAnalyticsController, controller.NewAppImportController, controller.NewAuthController, controller.NewCategoryController, controller.NewRuleController, controller.NewStatementController, controller.NewTransactionController)
 
-var repositorySet = wire.NewSet(repository.NewAccountRepository, repository.NewAnalyticsRepository, repository.NewCategoryRepository, repository.NewRuleRepository, repository.NewStatementRepository, repository.NewTransactionRepository, repository.NewUserRepository)
+var repositorySet = wire.NewSet(repository.NewAccountRepository, repository.NewAnalyticsRepository, repository.NewAppImportRepository, repository.NewCategoryRepository, repository.NewJobRepository, repository.NewRuleRepository, repository.NewStatementRepository, repository.NewTransactionRepository, repository.NewUserRepository)
 
-var serviceSet = wire.NewSet(service.NewAccountService, service.NewAnalyticsService, service.NewAuthService, service.NewCategoryService, service.NewRuleEngineService, service.NewRuleService, service.NewStatementService, service.NewTransactionService, service.NewUserService)
+var serviceSet = wire.NewSet(service.NewAccountService, service.NewAnalyticsService, service.NewAppImportService, service.NewAuthService, service.NewCategoryService, service.NewJobQueue, service.NewRuleEngineService, service.NewRuleService, service.NewStatementService, service.NewTransactionService, service.NewUserService)
 
 var validatorSet = wire.NewSet(validator.NewStatementValidator)
@@ -0,0 +1,75 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"expenses/internal/config"
+	"expenses/pkg/storage"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LocalStorage", func() {
+	var (
+		root  string
+		store *storage.LocalStorage
+		ctx   context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		root = GinkgoT().TempDir()
+		store, err = storage.NewLocalStorage(root)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should store and read back a blob", func() {
+		Expect(store.Put(ctx, "statements/1/abc", []byte("hello"))).To(Succeed())
+
+		data, err := store.Get(ctx, "statements/1/abc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("hello"))
+		Expect(filepath.Join(root, "statements", "1", "abc")).To(BeARegularFile())
+	})
+
+	It("should overwrite an existing blob", func() {
+		Expect(store.Put(ctx, "key", []byte("old"))).To(Succeed())
+		Expect(store.Put(ctx, "key", []byte("new"))).To(Succeed())
+
+		data, err := store.Get(ctx, "key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("new"))
+		entries, err := os.ReadDir(root)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should return ErrNotFound for missing blobs", func() {
+		_, err := store.Get(ctx, "missing")
+		Expect(err).To(MatchError(storage.ErrNotFound))
+	})
+
+	It("should delete blobs and ignore missing ones", func() {
+		Expect(store.Put(ctx, "key", []byte("data"))).To(Succeed())
+		Expect(store.Delete(ctx, "key")).To(Succeed())
+		Expect(store.Delete(ctx, "key")).To(Succeed())
+
+		_, err := store.Get(ctx, "key")
+		Expect(err).To(MatchError(storage.ErrNotFound))
+	})
+
+	It("should reject keys outside the root", func() {
+		Expect(store.Put(ctx, "../escape", []byte("data"))).To(HaveOccurred())
+		_, err := store.Get(ctx, "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject unknown backends", func() {
+		_, err := storage.NewBlobStorage(&config.Config{StorageBackend: "s3"})
+		Expect(err).To(MatchError(ContainSubstring("unsupported storage backend")))
+	})
+})
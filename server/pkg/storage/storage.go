@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"expenses/internal/config"
+)
+
+// ErrNotFound is returned when no blob is stored under a key.
+var ErrNotFound = errors.New("blob not found")
+
+const (
+	BackendLocal = "local"
+)
+
+// BlobStorage stores opaque files under slash separated keys.
+type BlobStorage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStorage creates the storage backend selected in the configuration.
+func NewBlobStorage(cfg *config.Config) (BlobStorage, error) {
+	switch cfg.StorageBackend {
+	case BackendLocal:
+		return NewLocalStorage(cfg.StoragePath)
+	default:
+		return nil, fmt.Errorf("unsupported storage backend: %s", cfg.StorageBackend)
+	}
+}
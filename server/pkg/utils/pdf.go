@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rc4"
+	"fmt"
+	"strings"
+)
+
+// pdfPasswordPad is the padding string from the PDF standard security handler.
+var pdfPasswordPad = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+// CreatePDFFile builds a single page, text based PDF with one line per row and
+// every cell drawn at a fixed column position. Empty cells are not drawn, as in
+// real statements. When password is not empty the document is encrypted with
+// 128-bit RC4 using it as the user password.
+func CreatePDFFile(data [][]string, password string) []byte {
+	var content bytes.Buffer
+	for rowIdx, row := range data {
+		y := 560 - rowIdx*14
+		for colIdx, cellValue := range row {
+			if cellValue == "" {
+				continue
+			}
+			x := 30 + colIdx*110
+			fmt.Fprintf(&content, "BT /F1 8 Tf 1 0 0 1 %d %d Tm (%s) Tj ET\n", x, y, escapePDFString(cellValue))
+		}
+	}
+
+	fileId := md5.Sum(content.Bytes())
+	var key, owner, user []byte
+	if password != "" {
+		key, owner, user = pdfEncryptionKeys(password, fileId[:])
+	}
+
+	stream := content.Bytes()
+	if key != nil {
+		stream = rc4Crypt(pdfObjectKey(key, 5), stream)
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 842 595] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+	}
+	if key != nil {
+		objects = append(objects, fmt.Sprintf("<< /Filter /Standard /V 2 /R 3 /Length 128 /P -4 /O <%x> /U <%x> >>", owner, user))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	trailer := fmt.Sprintf("/Size %d /Root 1 0 R /ID [<%x> <%x>]", len(objects)+1, fileId, fileId)
+	if key != nil {
+		trailer += fmt.Sprintf(" /Encrypt %d 0 R", len(objects))
+	}
+	fmt.Fprintf(&buf, "trailer\n<< %s >>\nstartxref\n%d\n%%%%EOF\n", trailer, xref)
+	return buf.Bytes()
+}
+
+func escapePDFString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
+
+// pdfEncryptionKeys computes the file key and the O and U entries for a
+// revision 3 standard security handler (PDF 32000-1:2008, §7.6.3).
+func pdfEncryptionKeys(password string, fileId []byte) ([]byte, []byte, []byte) {
+	padded := padPDFPassword(password)
+
+	// The owner password is not used to open documents; derive O from the
+	// user password.
+	ownerKey := md5.Sum(padded)
+	owner := rc4Crypt(ownerKey[:], padded)
+
+	permissions := int32(-4)
+	h := md5.New()
+	h.Write(padded)
+	h.Write(owner)
+	h.Write([]byte{byte(permissions), byte(permissions >> 8), byte(permissions >> 16), byte(permissions >> 24)})
+	h.Write(fileId)
+	key := h.Sum(nil)
+	for i := 0; i < 50; i++ {
+		sum := md5.Sum(key)
+		key = sum[:]
+	}
+
+	h.Reset()
+	h.Write(pdfPasswordPad)
+	h.Write(fileId)
+	user := rc4Crypt(key, h.Sum(nil))
+	for i := 1; i <= 19; i++ {
+		stepKey := make([]byte, len(key))
+		for j := range key {
+			stepKey[j] = key[j] ^ byte(i)
+		}
+		user = rc4Crypt(stepKey, user)
+	}
+	user = append(user, make([]byte, 16)...)
+
+	return key, owner, user
+}
+
+func padPDFPassword(password string) []byte {
+	padded := append([]byte(password), pdfPasswordPad...)
+	return padded[:32]
+}
+
+func pdfObjectKey(key []byte, objectId int) []byte {
+	h := md5.New()
+	h.Write(key)
+	h.Write([]byte{byte(objectId), byte(objectId >> 8), byte(objectId >> 16), 0, 0})
+	return h.Sum(nil)
+}
+
+func rc4Crypt(key []byte, data []byte) []byte {
+	c, err := rc4.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+	out := make([]byte, len(data))
+	c.XORKeyStream(out, data)
+	return out
+}
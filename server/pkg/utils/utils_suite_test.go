@@ -224,6 +224,26 @@ var _ = Describe("Utils", func() {
 		})
 	})
 
+	Describe("SealString", func() {
+		It("should open a sealed value with the same secret only", func() {
+			sealed, err := SealString([]byte("server-secret"), "statement-password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sealed).NotTo(ContainSubstring("statement-password"))
+
+			text, err := OpenString([]byte("server-secret"), sealed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(text).To(Equal("statement-password"))
+
+			_, err = OpenString([]byte("other-secret"), sealed)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject an empty secret", func() {
+			_, err := SealString(nil, "statement-password")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("ParseDate", func() {
 		Context("with SBI date formats", func() {
 			It("should parse SBI date formats correctly", func() {
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	cfbSectorSize    = 512
+	cfbMiniCutoff    = 4096
+	cfbEndOfChain    = 0xFFFFFFFE
+	cfbFreeSector    = 0xFFFFFFFF
+	cfbFATSector     = 0xFFFFFFFD
+	cfbNoStream      = 0xFFFFFFFF
+	biffMaxRecordLen = 8224
+)
+
+// CreateXLSFile builds a legacy Excel 97-2003 (BIFF8) workbook with a single
+// sheet named Sheet1. Cells holding numbers or YYYY-MM-DD dates are stored as
+// numbers, with a date format for the dates, the way Excel saves them; every
+// other cell is stored as text.
+func CreateXLSFile(data [][]string) []byte {
+	var sst []string
+	sstIndex := make(map[string]int)
+	var cells bytes.Buffer
+	for rowIdx, row := range data {
+		for colIdx, value := range row {
+			if value == "" {
+				continue
+			}
+			header := []uint16{uint16(rowIdx), uint16(colIdx)}
+			if date, err := time.Parse("2006-01-02", value); err == nil {
+				serial := date.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)).Hours() / 24
+				writeBIFFRecord(&cells, 0x0203, biffNumber(header, 1, serial))
+				continue
+			}
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				writeBIFFRecord(&cells, 0x0203, biffNumber(header, 0, number))
+				continue
+			}
+			idx, ok := sstIndex[value]
+			if !ok {
+				idx = len(sst)
+				sstIndex[value] = idx
+				sst = append(sst, value)
+			}
+			var rec bytes.Buffer
+			binary.Write(&rec, binary.LittleEndian, header)
+			binary.Write(&rec, binary.LittleEndian, uint16(0))
+			binary.Write(&rec, binary.LittleEndian, uint32(idx))
+			writeBIFFRecord(&cells, 0x00FD, rec.Bytes())
+		}
+	}
+
+	var globals bytes.Buffer
+	writeBIFFRecord(&globals, 0x0809, biffBOF(0x0005))
+	// XF 0 uses the General format, XF 1 the built-in m/d/yy date format.
+	for _, format := range []uint16{0, 14} {
+		xf := make([]byte, 20)
+		binary.LittleEndian.PutUint16(xf[2:], format)
+		writeBIFFRecord(&globals, 0x00E0, xf)
+	}
+	sheetName := utf16.Encode([]rune("Sheet1"))
+	boundSheet := make([]byte, 8, 8+2*len(sheetName))
+	boundSheet[6] = byte(len(sheetName))
+	boundSheet[7] = 1
+	for _, c := range sheetName {
+		boundSheet = binary.LittleEndian.AppendUint16(boundSheet, c)
+	}
+	boundSheetOffset := globals.Len() + 4
+	writeBIFFRecord(&globals, 0x0085, boundSheet)
+	writeBIFFSST(&globals, sst)
+	writeBIFFRecord(&globals, 0x000A, nil)
+
+	stream := globals.Bytes()
+	binary.LittleEndian.PutUint32(stream[boundSheetOffset:], uint32(len(stream)))
+	writeBIFFRecord(&globals, 0x0809, biffBOF(0x0010))
+	globals.Write(cells.Bytes())
+	writeBIFFRecord(&globals, 0x000A, nil)
+
+	return createCompoundFile("Workbook", globals.Bytes())
+}
+
+func biffBOF(substreamType uint16) []byte {
+	bof := make([]byte, 16)
+	binary.LittleEndian.PutUint16(bof[0:], 0x0600)
+	binary.LittleEndian.PutUint16(bof[2:], substreamType)
+	return bof
+}
+
+func biffNumber(header []uint16, xf uint16, value float64) []byte {
+	var rec bytes.Buffer
+	binary.Write(&rec, binary.LittleEndian, header)
+	binary.Write(&rec, binary.LittleEndian, xf)
+	binary.Write(&rec, binary.LittleEndian, math.Float64bits(value))
+	return rec.Bytes()
+}
+
+func writeBIFFRecord(buf *bytes.Buffer, id uint16, data []byte) {
+	binary.Write(buf, binary.LittleEndian, id)
+	binary.Write(buf, binary.LittleEndian, uint16(len(data)))
+	buf.Write(data)
+}
+
+// writeBIFFSST writes the shared string table as UTF-16 strings, moving to a
+// CONTINUE record between strings once the record is full.
+func writeBIFFSST(buf *bytes.Buffer, strs []string) {
+	record := make([]byte, 8)
+	binary.LittleEndian.PutUint32(record[0:], uint32(len(strs)))
+	binary.LittleEndian.PutUint32(record[4:], uint32(len(strs)))
+	id := uint16(0x00FC)
+	for _, s := range strs {
+		chars := utf16.Encode([]rune(s))
+		entry := binary.LittleEndian.AppendUint16(nil, uint16(len(chars)))
+		entry = append(entry, 1)
+		for _, c := range chars {
+			entry = binary.LittleEndian.AppendUint16(entry, c)
+		}
+		if len(record)+len(entry) > biffMaxRecordLen {
+			writeBIFFRecord(buf, id, record)
+			id = 0x003C
+			record = nil
+		}
+		record = append(record, entry...)
+	}
+	writeBIFFRecord(buf, id, record)
+}
+
+// createCompoundFile wraps a single stream in a version 3 compound file. The
+// stream is padded to the mini stream cutoff so it is stored in regular
+// sectors.
+func createCompoundFile(streamName string, stream []byte) []byte {
+	if len(stream) < cfbMiniCutoff {
+		stream = append(stream, make([]byte, cfbMiniCutoff-len(stream))...)
+	}
+	streamSectors := (len(stream) + cfbSectorSize - 1) / cfbSectorSize
+	entriesPerSector := cfbSectorSize / 4
+	fatSectors := 1
+	for fatSectors*entriesPerSector < fatSectors+1+streamSectors {
+		fatSectors++
+	}
+	dirSector := fatSectors
+	firstStreamSector := fatSectors + 1
+
+	fat := make([]uint32, fatSectors*entriesPerSector)
+	for i := range fat {
+		fat[i] = cfbFreeSector
+	}
+	for i := 0; i < fatSectors; i++ {
+		fat[i] = cfbFATSector
+	}
+	fat[dirSector] = cfbEndOfChain
+	for i := 0; i < streamSectors; i++ {
+		fat[firstStreamSector+i] = uint32(firstStreamSector + i + 1)
+	}
+	fat[firstStreamSector+streamSectors-1] = cfbEndOfChain
+
+	header := make([]byte, cfbSectorSize)
+	copy(header, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1})
+	binary.LittleEndian.PutUint16(header[24:], 0x003E)
+	binary.LittleEndian.PutUint16(header[26:], 0x0003)
+	binary.LittleEndian.PutUint16(header[28:], 0xFFFE)
+	binary.LittleEndian.PutUint16(header[30:], 9)
+	binary.LittleEndian.PutUint16(header[32:], 6)
+	binary.LittleEndian.PutUint32(header[44:], uint32(fatSectors))
+	binary.LittleEndian.PutUint32(header[48:], uint32(dirSector))
+	binary.LittleEndian.PutUint32(header[56:], cfbMiniCutoff)
+	binary.LittleEndian.PutUint32(header[60:], cfbEndOfChain)
+	binary.LittleEndian.PutUint32(header[68:], cfbEndOfChain)
+	for i := 0; i < 109; i++ {
+		sector := uint32(cfbFreeSector)
+		if i < fatSectors {
+			sector = uint32(i)
+		}
+		binary.LittleEndian.PutUint32(header[76+4*i:], sector)
+	}
+
+	directory := make([]byte, cfbSectorSize)
+	writeDirectoryEntry(directory[0:128], "Root Entry", 5, 1, cfbEndOfChain, 0)
+	writeDirectoryEntry(directory[128:256], streamName, 2, cfbNoStream, uint32(firstStreamSector), len(stream))
+	for i := 2; i < 4; i++ {
+		entry := directory[128*i : 128*(i+1)]
+		binary.LittleEndian.PutUint32(entry[68:], cfbNoStream)
+		binary.LittleEndian.PutUint32(entry[72:], cfbNoStream)
+		binary.LittleEndian.PutUint32(entry[76:], cfbNoStream)
+	}
+
+	var file bytes.Buffer
+	file.Write(header)
+	binary.Write(&file, binary.LittleEndian, fat)
+	file.Write(directory)
+	file.Write(stream)
+	if pad := len(stream) % cfbSectorSize; pad != 0 {
+		file.Write(make([]byte, cfbSectorSize-pad))
+	}
+	return file.Bytes()
+}
+
+func writeDirectoryEntry(entry []byte, name string, objectType byte, child uint32, start uint32, size int) {
+	chars := utf16.Encode([]rune(name))
+	for i, c := range chars {
+		binary.LittleEndian.PutUint16(entry[2*i:], c)
+	}
+	binary.LittleEndian.PutUint16(entry[64:], uint16(2*(len(chars)+1)))
+	entry[66] = objectType
+	entry[67] = 1
+	binary.LittleEndian.PutUint32(entry[68:], cfbNoStream)
+	binary.LittleEndian.PutUint32(entry[72:], cfbNoStream)
+	binary.LittleEndian.PutUint32(entry[76:], child)
+	binary.LittleEndian.PutUint32(entry[116:], start)
+	binary.LittleEndian.PutUint32(entry[120:], uint32(size))
+}